@@ -34,17 +34,56 @@ const (
 
 // APIRuleSpec defines the desired state of ApiRule
 type APIRuleSpec struct {
-	// URL on which the service will be visible
+	// URL on which the service will be visible, a leading wildcard label (e.g. *.apps.example.com) exposes the service on
+	// all subdomains
 	// +kubebuilder:validation:MinLength=3
 	// +kubebuilder:validation:MaxLength=256
-	// +kubebuilder:validation:Pattern=^([a-zA-Z0-9][a-zA-Z0-9-_]*\.)*[a-zA-Z0-9]*[a-zA-Z0-9-_]*[[a-zA-Z0-9]+$
+	// +kubebuilder:validation:Pattern=^(\*\.)?([a-zA-Z0-9][a-zA-Z0-9-_]*\.)*[a-zA-Z0-9]*[a-zA-Z0-9-_]*[[a-zA-Z0-9]+$
 	Host *string `json:"host"`
+	// Additional URLs on which the service will be visible
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
 	// Definition of the service to expose
 	// +optional
 	Service *Service `json:"service,omitempty"`
-	// Gateway to be used
+	// Gateway to be used, the reserved gateway mesh exposes the service only within the service mesh. If not defined,
+	// the service is exposed only within the service mesh as well.
 	// +kubebuilder:validation:Pattern=`^[0-9a-z-_]+(\/[0-9a-z-_]+|(\.[0-9a-z-_]+)*)$`
-	Gateway *string `json:"gateway"`
+	// +optional
+	Gateway *string `json:"gateway,omitempty"`
+	// Additional gateways that the service is exposed on, in the form of name or namespace/name. Use the reserved gateway
+	// mesh to additionally expose the service within the service mesh.
+	// +optional
+	Gateways []string `json:"gateways,omitempty"`
+	// Namespaces to which the VirtualService is exported, e.g. "." for the namespace of the APIRule, overwrites the
+	// export configured for the controller if defined. The VirtualService is exported to all namespaces if neither is
+	// defined.
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	ExportTo []string `json:"exportTo,omitempty"`
+	// Disables the CORS policy for all rules, so no CORS headers are advertised
+	// +optional
+	DisableCors *bool `json:"disableCors,omitempty"`
+	// CORS policy for all rules, overwrites the default CORS configuration if defined. The CORS policy of a rule
+	// overwrites the fields it defines.
+	// +optional
+	CorsPolicy *CorsPolicy `json:"corsPolicy,omitempty"`
+	// Preserves the x-forwarded-host header of the requests for all rules instead of setting it to the host of the APIRule
+	// +optional
+	PreserveHost *bool `json:"preserveHost,omitempty"`
+	// Adds the client address to the x-forwarded-for header and sets the x-forwarded-proto header to the scheme of the
+	// requests for all rules
+	// +optional
+	ForwardedHeaders *bool `json:"forwardedHeaders,omitempty"`
+	// Oathkeeper instance that handles the requests of the rules secured by oathkeeper, defaults to the oathkeeper
+	// instance configured for the controller
+	// +optional
+	Oathkeeper *OathkeeperTarget `json:"oathkeeper,omitempty"`
+	// Maintenance mode of the APIRule, which returns a fixed response for the requests of all rules instead of forwarding
+	// them while it is enabled
+	// +optional
+	Maintenance *Maintenance `json:"maintenance,omitempty"`
 	// Rules represents collection of Rule to apply
 	// +kubebuilder:validation:MinItems=1
 	Rules []Rule `json:"rules"`
@@ -62,6 +101,9 @@ type APIRuleStatus struct {
 	RequestAuthenticationStatus *APIRuleResourceStatus `json:"requestAuthenticationStatus,omitempty"`
 	// +optional
 	AuthorizationPolicyStatus *APIRuleResourceStatus `json:"authorizationPolicyStatus,omitempty"`
+	// Virtual Service that was created for the APIRule
+	// +optional
+	VirtualService *ObjectReference `json:"virtualService,omitempty"`
 }
 
 // APIRule is the Schema for the apis ApiRule
@@ -98,10 +140,299 @@ type Service struct {
 	// Port of the service to expose
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=65535
-	Port *uint32 `json:"port"`
+	// +optional
+	Port *uint32 `json:"port,omitempty"`
+	// Name of the service port to expose, which is resolved to the port number of the service. Can be used instead of port.
+	// +optional
+	PortName string `json:"portName,omitempty"`
 	// Defines if the service is internal (in cluster) or external
 	// +optional
 	IsExternal *bool `json:"external,omitempty"`
+	// Originates TLS for the requests to the service, for services that only accept HTTPS on the port
+	// +optional
+	TLS bool `json:"tls,omitempty"`
+	// Protocol of the requests to the service port, defaults to "http" (HTTP/1.1) if not defined
+	// +optional
+	Protocol BackendProtocol `json:"protocol,omitempty"`
+	// Circuit breaking for the requests to the service, which is configured in a DestinationRule for the service
+	// +optional
+	TrafficPolicy *TrafficPolicy `json:"trafficPolicy,omitempty"`
+	// Subsets of the service instances that rules can route to, which are defined in a DestinationRule for the service
+	// +optional
+	Subsets []Subset `json:"subsets,omitempty"`
+}
+
+// Subset is a named group of service instances selected by labels, e.g. a version of the service
+type Subset struct {
+	// Name of the subset that is referenced by rules
+	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	Name string `json:"name"`
+	// Labels of the service instances that belong to the subset
+	// +kubebuilder:validation:MinProperties=1
+	Labels map[string]string `json:"labels"`
+}
+
+// TrafficPolicy configures the circuit breaking and load balancing for the requests to a service
+type TrafficPolicy struct {
+	// Limits of the connections and requests to the service
+	// +optional
+	ConnectionPool *ConnectionPool `json:"connectionPool,omitempty"`
+	// Ejection of unhealthy service instances from the load balancing pool
+	// +optional
+	OutlierDetection *OutlierDetection `json:"outlierDetection,omitempty"`
+	// Distribution of the requests to the service instances
+	// +optional
+	LoadBalancer *LoadBalancer `json:"loadBalancer,omitempty"`
+}
+
+// LoadBalancer defines how the requests are distributed to the instances of a service
+type LoadBalancer struct {
+	// Load balancing algorithm, the requests are distributed round robin if not defined
+	// +optional
+	Algorithm LoadBalancerAlgorithm `json:"algorithm,omitempty"`
+	// Source of the hash key for the consistentHash algorithm, requests with the same hash key are sent to the same
+	// instance, which provides session affinity
+	// +optional
+	ConsistentHash *ConsistentHash `json:"consistentHash,omitempty"`
+}
+
+// LoadBalancerAlgorithm defines the algorithm to select the service instance for a request
+// +kubebuilder:validation:Enum=roundRobin;leastConn;consistentHash
+type LoadBalancerAlgorithm string
+
+const (
+	// LoadBalancerRoundRobin sends the requests to the instances in turn
+	LoadBalancerRoundRobin LoadBalancerAlgorithm = "roundRobin"
+	// LoadBalancerLeastConn sends the requests to the instances with the fewest active requests
+	LoadBalancerLeastConn LoadBalancerAlgorithm = "leastConn"
+	// LoadBalancerConsistentHash sends the requests with the same hash key to the same instance
+	LoadBalancerConsistentHash LoadBalancerAlgorithm = "consistentHash"
+)
+
+// ConsistentHash defines the source of the hash key, exactly one of the fields must be defined
+type ConsistentHash struct {
+	// Name of the request header whose value is the hash key
+	// +optional
+	HTTPHeaderName string `json:"httpHeaderName,omitempty"`
+	// Cookie whose value is the hash key, the cookie is set in the response if the request doesn't contain it
+	// +optional
+	HTTPCookie *HTTPCookie `json:"httpCookie,omitempty"`
+	// Uses the source IP address of the requests as hash key
+	// +optional
+	UseSourceIP bool `json:"useSourceIp,omitempty"`
+}
+
+// HTTPCookie defines the cookie that is used as hash key for consistent hashing
+type HTTPCookie struct {
+	// Name of the cookie
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Path of the cookie
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Lifetime of the cookie in the form of a duration string (e.g. "1h"), a cookie with a lifetime of 0s expires with
+	// the browser session
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	TTL string `json:"ttl"`
+}
+
+// ConnectionPool defines the limits of the connections and requests to a service, no limit is set for undefined fields
+type ConnectionPool struct {
+	// Maximum number of connections to the service
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConnections int32 `json:"maxConnections,omitempty"`
+	// Maximum number of requests that are queued while waiting for a connection
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxPendingRequests int32 `json:"maxPendingRequests,omitempty"`
+	// Maximum number of requests per connection, 1 disables keep-alive
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxRequestsPerConnection int32 `json:"maxRequestsPerConnection,omitempty"`
+}
+
+// OutlierDetection defines when unhealthy service instances are ejected from the load balancing pool
+type OutlierDetection struct {
+	// Number of consecutive 5xx errors after which an instance is ejected
+	// +kubebuilder:validation:Minimum=1
+	Consecutive5xxErrors uint32 `json:"consecutive5xxErrors"`
+	// Interval between the ejection analysis in the form of a duration string (e.g. "10s")
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	Interval *string `json:"interval,omitempty"`
+	// Minimum ejection duration in the form of a duration string (e.g. "30s")
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	BaseEjectionTime *string `json:"baseEjectionTime,omitempty"`
+	// Maximum percentage of instances that can be ejected
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxEjectionPercent int32 `json:"maxEjectionPercent,omitempty"`
+}
+
+// StringMatch defines how a string value is matched, exactly one of the fields must be defined
+type StringMatch struct {
+	// Matches the value exactly
+	// +optional
+	Exact string `json:"exact,omitempty"`
+	// Matches values starting with the prefix
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+	// Matches values against the regular expression
+	// +optional
+	Regex string `json:"regex,omitempty"`
+}
+
+// DirectResponse configures the fixed response that is returned for the requests of a rule without forwarding them to a service.
+type DirectResponse struct {
+	// HTTP status code of the response
+	// +kubebuilder:validation:Minimum=200
+	// +kubebuilder:validation:Maximum=599
+	Status uint32 `json:"status"`
+	// Body of the response, the response has no body if not defined
+	// +optional
+	Body string `json:"body,omitempty"`
+}
+
+// Maintenance configures the response that is returned for the requests of all rules of an APIRule in maintenance.
+type Maintenance struct {
+	// Returns the maintenance response for the requests of all rules if enabled
+	Enabled bool `json:"enabled"`
+	// HTTP status code of the maintenance response, defaults to 503 if not defined
+	// +optional
+	Status *uint32 `json:"status,omitempty"`
+	// Value of the Retry-After header of the maintenance response, either the number of seconds or an HTTP date after
+	// which the client can retry. The response has no Retry-After header if not defined.
+	// +optional
+	RetryAfter *string `json:"retryAfter,omitempty"`
+	// Body of the maintenance response, the response has no body if not defined
+	// +optional
+	Body string `json:"body,omitempty"`
+}
+
+// Redirect configures the HTTP redirect of a rule. Fields that are not set keep the respective value of the request.
+type Redirect struct {
+	// Path that replaces the path of the request URI
+	// +optional
+	URI string `json:"uri,omitempty"`
+	// Scheme that replaces the scheme of the request URI
+	// +kubebuilder:validation:Enum=http;https
+	// +optional
+	Scheme string `json:"scheme,omitempty"`
+	// Authority that replaces the authority of the request URI
+	// +optional
+	Authority string `json:"authority,omitempty"`
+	// HTTP status code of the redirect, defaults to 301 if not defined
+	// +kubebuilder:validation:Enum=301;302
+	// +optional
+	Code uint32 `json:"code,omitempty"`
+}
+
+// Rewrite configures the rewrite of requests of a rule before they are forwarded to the service
+type Rewrite struct {
+	// Path that replaces the path of the request URI, if the path of the rule is matched by prefix only the prefix is replaced
+	// +optional
+	URI string `json:"uri,omitempty"`
+	// Authority that replaces the Host header of the request
+	// +optional
+	Authority string `json:"authority,omitempty"`
+	// Rewrites the path of the request URI with the capture groups of a regular expression, e.g. /user/(\d+) to /u?id=$1.
+	// Requires the uriRegexRewrite of Istio, which isn't supported by the Istio version of the gateway yet, so the
+	// validation rejects it.
+	// +optional
+	URIRegex *RegexRewrite `json:"uriRegex,omitempty"`
+}
+
+// RegexRewrite replaces the parts of the path that are matched by a regular expression
+type RegexRewrite struct {
+	// Regular expression that matches the path, the capture groups can be referenced in the rewrite
+	Match string `json:"match"`
+	// Replacement of the matched path, which references the capture groups of the match with $1, $2 and so on
+	Rewrite string `json:"rewrite"`
+}
+
+// Headers defines the manipulation of the request and response headers of a rule
+type Headers struct {
+	// Header operations that are applied to the request before it is forwarded
+	// +optional
+	Request *HeaderOperations `json:"request,omitempty"`
+	// Header operations that are applied to the response before it is returned to the client
+	// +optional
+	Response *HeaderOperations `json:"response,omitempty"`
+}
+
+// Deprecation defines the dates that are advertised in the deprecation (RFC 9745) and sunset (RFC 8594) response headers
+type Deprecation struct {
+	// Date since when the rule is deprecated in RFC 3339 format, e.g. 2024-01-01T00:00:00Z. The deprecation header is
+	// set to true if not defined.
+	// +optional
+	Since *string `json:"since,omitempty"`
+	// Date after which the rule is no longer available in RFC 3339 format, e.g. 2024-06-30T00:00:00Z. No sunset header
+	// is set if not defined.
+	// +optional
+	Sunset *string `json:"sunset,omitempty"`
+}
+
+// HeaderOperations defines the headers that are set, added or removed
+type HeaderOperations struct {
+	// Overwrites the headers with the given values
+	// +optional
+	Set map[string]string `json:"set,omitempty"`
+	// Appends the given values to the headers
+	// +optional
+	Add map[string]string `json:"add,omitempty"`
+	// Removes the headers with the given names
+	// +optional
+	Remove []string `json:"remove,omitempty"`
+}
+
+// Lua is the script of a rule that implements the envoy_on_request and envoy_on_response functions of the Envoy Lua filter
+type Lua struct {
+	// Source code of the script
+	// +kubebuilder:validation:MinLength=1
+	InlineCode string `json:"inlineCode"`
+}
+
+// Mirror is a service that receives a copy of the traffic of a rule
+type Mirror struct {
+	Service `json:",inline"`
+	// Percentage of the traffic of the rule mirrored to the service, defaults to 100 if not defined
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Percentage *uint32 `json:"mirrorPercentage,omitempty"`
+}
+
+// WeightedService is a service that receives a share of the traffic of a rule
+type WeightedService struct {
+	Service `json:",inline"`
+	// Percentage of the traffic of the rule routed to the service
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight"`
+}
+
+// ProtocolPort is the port of the service the requests of a protocol are routed to
+type ProtocolPort struct {
+	// Protocol of the requests routed to the port
+	Protocol RouteType `json:"protocol"`
+	// Port of the service
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port uint32 `json:"port"`
+}
+
+// PortTimeout is the timeout for the HTTP requests that are routed to a port of the service
+type PortTimeout struct {
+	// Port of the service
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port uint32 `json:"port"`
+	// Timeout for HTTP requests in the form of a duration string (e.g. "30s" or "1m30s")
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	Timeout string `json:"timeout"`
 }
 
 // Rule .
@@ -109,9 +440,76 @@ type Rule struct {
 	// Path to be exposed
 	// +kubebuilder:validation:Pattern=^([0-9a-zA-Z./*()?!\\_-]+)
 	Path string `json:"path"`
+	// Defines how the path is matched, defaults to "regex" if not defined
+	// +optional
+	PathMatchType PathMatchType `json:"pathMatchType,omitempty"`
+	// Defines the protocol of the exposed path, defaults to "http" if not defined
+	// +optional
+	RouteType RouteType `json:"routeType,omitempty"`
 	// Definition of the service to expose, overwrites spec level service if defined
 	// +optional
 	Service *Service `json:"service,omitempty"`
+	// Weighted services the traffic of the rule is split across, overwrites the rule and spec level service if defined.
+	// Only supported for rules with the allow access strategy, the weights must sum up to 100.
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	Destinations []WeightedService `json:"destinations,omitempty"`
+	// Ports of the service the requests are routed to by their protocol, overwrites the port of the rule and spec level
+	// service if defined. gRPC requests are identified by their content type and sent to the service with HTTP/2, all
+	// other requests are routed to the http port. Only supported for rules with the allow or jwt access strategy.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=2
+	// +optional
+	ProtocolPorts []ProtocolPort `json:"protocolPorts,omitempty"`
+	// Redirects the requests instead of routing them to a service, only supported for rules with the allow access strategy
+	// +optional
+	Redirect *Redirect `json:"redirect,omitempty"`
+	// Returns a fixed response instead of routing the requests to a service, e.g. for maintenance pages. Only supported
+	// for rules with the allow access strategy, the rule doesn't need a service if defined.
+	// +optional
+	DirectResponse *DirectResponse `json:"directResponse,omitempty"`
+	// Request headers that must match for the rule to apply, keyed by the header name. Rules are matched in the order
+	// they are defined, so a rule with header matches must be defined before a rule with the same path without them.
+	// Only supported for rules with the allow or jwt access strategy.
+	// +optional
+	MatchHeaders map[string]StringMatch `json:"matchHeaders,omitempty"`
+	// Query parameters that must match for the rule to apply, keyed by the parameter name. Only exact and regex matches
+	// are supported. The same ordering and access strategy restrictions as for the header matches apply.
+	// +optional
+	MatchQueryParams map[string]StringMatch `json:"matchQueryParams,omitempty"`
+	// Rewrites the requests before they are forwarded to the service, only applied for rules with the allow or jwt access strategy
+	// +optional
+	Rewrite *Rewrite `json:"rewrite,omitempty"`
+	// Mirrors the requests to a second service, the responses of the mirrored requests are discarded.
+	// Only supported for rules with the allow access strategy.
+	// +optional
+	Mirror *Mirror `json:"mirror,omitempty"`
+	// Lua script that manipulates the requests and responses of the rule on the ingress gateway, which is configured in
+	// an EnvoyFilter for the route of the rule. Only supported if the Lua filters are enabled for the controller.
+	// +optional
+	Lua *Lua `json:"lua,omitempty"`
+	// Manipulates the request and response headers independent of the mutators
+	// +optional
+	Headers *Headers `json:"headers,omitempty"`
+	// Marks the rule as deprecated, so the deprecation and sunset response headers are added to its responses
+	// +optional
+	Deprecation *Deprecation `json:"deprecation,omitempty"`
+	// Names of request headers that are forwarded unchanged, e.g. the trace context headers traceparent or b3. Neither the
+	// header operations nor the mutators of the rule are applied to them.
+	// +optional
+	PreserveHeaders []string `json:"preserveHeaders,omitempty"`
+	// Matches the path of the requests case-insensitively
+	// +optional
+	IgnorePathCase bool `json:"ignorePathCase,omitempty"`
+	// Removes the path of the rule from the request URI before the request is forwarded to the service, e.g. the request
+	// /service-a/users of the path /service-a is forwarded as /users. Requires the prefix path match type and is only
+	// supported for rules with the allow or jwt access strategy.
+	// +optional
+	StripPrefix bool `json:"stripPrefix,omitempty"`
+	// Name of the subset of the service the requests are routed to. The subset must be defined on the service of the rule
+	// or, if the rule has no service, on the service of the APIRule.
+	// +optional
+	Subset string `json:"subset,omitempty"`
 	// Set of allowed HTTP methods
 	// +kubebuilder:validation:MinItems=1
 	Methods []string `json:"methods"`
@@ -121,6 +519,192 @@ type Rule struct {
 	// Mutators to be used
 	// +optional
 	Mutators []*Mutator `json:"mutators,omitempty"`
+	// CORS policy for the rule, overwrites the default CORS configuration if defined
+	// +optional
+	CorsPolicy *CorsPolicy `json:"corsPolicy,omitempty"`
+	// Timeout for HTTP requests in the form of a duration string (e.g. "30s" or "1m30s"), overwrites the default timeout if defined
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	Timeout *string `json:"timeout,omitempty"`
+	// Timeouts for HTTP requests per port of the service, overwrite the timeout of the rule for the requests routed to the
+	// port. If the requests of the rule are routed to multiple ports with a timeout, the longest timeout is applied.
+	// +optional
+	PortTimeouts []PortTimeout `json:"portTimeouts,omitempty"`
+	// Idle timeout of the connections to the service in the form of a duration string, overwrites the default idle timeout
+	// if defined. Connections without active requests are closed after the idle timeout, which is configured in a
+	// DestinationRule for the service. If rules with different idle timeouts route to the same service, the longest
+	// idle timeout is applied. The idle timeout must not be longer than the timeout of the rule.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	IdleTimeout *string `json:"idleTimeout,omitempty"`
+	// Retry policy for failed HTTP requests, overwrites the default retry policy if defined
+	// +optional
+	Retries *Retries `json:"retries,omitempty"`
+	// Disables the access log for the requests of the rule, e.g. for high-traffic health check paths. The path of the
+	// rule is added to an annotation of the VirtualService that is evaluated by the telemetry configuration.
+	// +optional
+	DisableAccessLog bool `json:"disableAccessLog,omitempty"`
+	// Maximum size of the request headers in bytes, requests with larger headers are rejected. Since the VirtualService
+	// has no field to limit the header size, the limit is added to an annotation of the VirtualService that is evaluated
+	// by an EnvoyFilter of the gateway. Envoy limits the header size in kilobytes, so it must be a multiple of 1024.
+	// +kubebuilder:validation:Minimum=1024
+	// +kubebuilder:validation:Maximum=8388608
+	// +optional
+	MaxRequestHeadersBytes *uint32 `json:"maxRequestHeadersBytes,omitempty"`
+	// Marks the rule as used for long-lived WebSocket connections, so the default timeout is not applied to the route.
+	// A timeout defined on the rule is still applied.
+	// +optional
+	WebSocket bool `json:"webSocket,omitempty"`
+	// Preserves the x-forwarded-host header of the requests instead of setting it to the host of the APIRule, overwrites
+	// the preserveHost setting of the APIRule if defined
+	// +optional
+	PreserveHost *bool `json:"preserveHost,omitempty"`
+	// Disables the rule, so its requests are no longer routed, without removing it from the rules. Rules are enabled by default.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Marks the rule as the default backend that handles all requests that are not matched by another rule. The rule
+	// must use a catch-all path and is always matched last.
+	// +optional
+	DefaultBackend bool `json:"defaultBackend,omitempty"`
+	// Faults that are injected into the requests to test the resilience of clients, no faults are injected if not defined
+	// +optional
+	Fault *Fault `json:"fault,omitempty"`
+	// IP addresses or CIDR ranges of the clients that are allowed to send the requests of the rule, e.g. "10.0.0.0/8".
+	// Requests from other clients are denied by an AuthorizationPolicy for the workload of the service. The client
+	// address is determined by the ingress gateway, e.g. from the X-Forwarded-For header. All clients are allowed if
+	// not defined.
+	// +optional
+	SourceCIDRs []string `json:"sourceCIDRs,omitempty"`
+}
+
+// Fault configures the faults that are injected into the requests of a rule
+type Fault struct {
+	// Delays the requests before they are forwarded
+	// +optional
+	Delay *FaultDelay `json:"delay,omitempty"`
+	// Aborts the requests and returns an error status code
+	// +optional
+	Abort *FaultAbort `json:"abort,omitempty"`
+}
+
+// FaultDelay configures a fixed delay for a percentage of the requests
+type FaultDelay struct {
+	// Fixed delay in the form of a duration string (e.g. "5s")
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	FixedDelay string `json:"fixedDelay"`
+	// Percentage of the requests that are delayed
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percentage uint32 `json:"percentage"`
+}
+
+// FaultAbort configures the abort of a percentage of the requests with an HTTP status code
+type FaultAbort struct {
+	// HTTP status code that is returned for the aborted requests
+	// +kubebuilder:validation:Minimum=200
+	// +kubebuilder:validation:Maximum=599
+	HTTPStatus int32 `json:"httpStatus"`
+	// Percentage of the requests that are aborted
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percentage uint32 `json:"percentage"`
+}
+
+// Retries configures the retry policy for failed HTTP requests of a rule
+type Retries struct {
+	// Number of retries for a request
+	// +kubebuilder:validation:Minimum=1
+	Attempts int `json:"attempts"`
+	// Timeout per retry attempt in the form of a duration string (e.g. "2s")
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	PerTryTimeout *string `json:"perTryTimeout,omitempty"`
+	// Comma-separated list of conditions under which a retry takes place (e.g. "5xx,connect-failure")
+	// +optional
+	RetryOn string `json:"retryOn,omitempty"`
+}
+
+// PathMatchType defines how the path of a rule is matched against the request URI
+// +kubebuilder:validation:Enum=regex;prefix;exact
+type PathMatchType string
+
+const (
+	// PathMatchRegex matches the request URI against the path interpreted as regular expression
+	PathMatchRegex PathMatchType = "regex"
+	// PathMatchPrefix matches request URIs starting with the path
+	PathMatchPrefix PathMatchType = "prefix"
+	// PathMatchExact matches only request URIs equal to the path
+	PathMatchExact PathMatchType = "exact"
+)
+
+// RouteType defines the protocol used for the requests of a rule
+// +kubebuilder:validation:Enum=http;grpc
+type RouteType string
+
+const (
+	// RouteTypeHTTP routes plain HTTP requests
+	RouteTypeHTTP RouteType = "http"
+	// RouteTypeGRPC routes gRPC requests, the path is expected to be the full gRPC service path (e.g. "/my.package.Service/")
+	RouteTypeGRPC RouteType = "grpc"
+)
+
+// BackendProtocol defines the protocol of the requests that are sent to a service
+// +kubebuilder:validation:Enum=http;http2
+type BackendProtocol string
+
+const (
+	// BackendProtocolHTTP sends the requests to the service with HTTP/1.1
+	BackendProtocolHTTP BackendProtocol = "http"
+	// BackendProtocolHTTP2 sends the requests to the service with HTTP/2, which is configured in a DestinationRule for the service
+	BackendProtocolHTTP2 BackendProtocol = "http2"
+)
+
+// CorsPolicy configures CORS for the rules of an APIRule or a single rule. Fields that are not set fall back to the
+// CORS policy of the APIRule and then to the default CORS configuration.
+type CorsPolicy struct {
+	// List of origins that are allowed to perform CORS requests
+	// +optional
+	AllowOrigins []string `json:"allowOrigins,omitempty"`
+	// List of regular expressions matching the origins that are allowed to perform CORS requests
+	// +optional
+	AllowOriginsRegex []string `json:"allowOriginsRegex,omitempty"`
+	// Key of a ConfigMap in the namespace of the APIRule that lists the origins that are allowed to perform CORS requests,
+	// separated by commas or newlines. The origins are added to the allowed origins of the policy.
+	// +optional
+	AllowOriginsFrom *ConfigMapKeyReference `json:"allowOriginsFrom,omitempty"`
+	// List of HTTP methods that are allowed for CORS requests, the wildcard * allows all methods
+	// +optional
+	AllowMethods []string `json:"allowMethods,omitempty"`
+	// List of HTTP headers that are allowed for CORS requests, the wildcard * allows all headers
+	// +optional
+	AllowHeaders []string `json:"allowHeaders,omitempty"`
+	// Duration for which browsers can cache the results of preflight requests in the form of a duration string, e.g.
+	// "10m", overwrites the max age of the default CORS configuration. The max age is not advertised if it is zero, so
+	// browsers cache the results with their own default duration.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// +optional
+	MaxAge *string `json:"maxAge,omitempty"`
+}
+
+// OathkeeperTarget is the oathkeeper service the requests are routed to
+type OathkeeperTarget struct {
+	// Host of the oathkeeper service, e.g. "oathkeeper-proxy.kyma-system.svc.cluster.local"
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+	// Port of the oathkeeper proxy
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port uint32 `json:"port"`
+}
+
+// ConfigMapKeyReference selects a key of a ConfigMap in the namespace of the APIRule
+type ConfigMapKeyReference struct {
+	// Name of the ConfigMap
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Key of the ConfigMap data
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
 }
 
 // APIRuleResourceStatus .
@@ -129,6 +713,12 @@ type APIRuleResourceStatus struct {
 	Description string     `json:"desc,omitempty"`
 }
 
+// ObjectReference identifies a resource that was created for the APIRule
+type ObjectReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
 func init() {
 	SchemeBuilder.Register(&APIRule{}, &APIRuleList{})
 }
@@ -169,6 +759,9 @@ type JwtAuthorization struct {
 type JwtAuthentication struct {
 	Issuer  string `json:"issuer"`
 	JwksUri string `json:"jwksUri"`
+	// Inline JSON Web Key Set of the issuer, can be used instead of the jwksUri
+	// +optional
+	Jwks string `json:"jwks,omitempty"`
 	// +optional
 	FromHeaders []*JwtHeader `json:"fromHeaders,omitempty"`
 	// +optional
@@ -1,6 +1,57 @@
 package v1beta1
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GetMatchKey returns a key that identifies the requests matched by the rule independent of the methods, which are
+// the requests with the path, path match type, route type and case sensitivity of the rule, the header matches and the
+// query parameter matches.
+func (r *Rule) GetMatchKey() string {
+	key := r.Path
+	// The key of a rule with the default regex path matching is the plain path, gRPC rules match the path by prefix by default.
+	pathMatchType := r.PathMatchType
+	if pathMatchType == "" && r.RouteType == RouteTypeGRPC {
+		pathMatchType = PathMatchPrefix
+	}
+	if pathMatchType != "" && pathMatchType != PathMatchRegex {
+		key = fmt.Sprintf("%s:%s", pathMatchType, key)
+	}
+	if r.RouteType == RouteTypeGRPC {
+		key = fmt.Sprintf("%s:%s", RouteTypeGRPC, key)
+	}
+	if r.IgnorePathCase {
+		key = fmt.Sprintf("%s:ignore-case", key)
+	}
+
+	if len(r.MatchHeaders) == 0 && len(r.MatchQueryParams) == 0 {
+		return key
+	}
+
+	// Header names are case-insensitive, query parameter names are not
+	return fmt.Sprintf("%s[%s][%s]", key, getStringMatchesKey(r.MatchHeaders, strings.ToLower), getStringMatchesKey(r.MatchQueryParams, nil))
+}
+
+func getStringMatchesKey(matches map[string]StringMatch, normalizeName func(string) string) string {
+	var keys []string
+	for name, match := range matches {
+		if normalizeName != nil {
+			name = normalizeName(name)
+		}
+		keys = append(keys, fmt.Sprintf("%s=%s|%s|%s", name, match.Exact, match.Prefix, match.Regex))
+	}
+	sort.Strings(keys)
+
+	return strings.Join(keys, ",")
+}
+
+// IsEnabled returns true if the rule is not disabled.
+func (r *Rule) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
 
 func (r *Rule) GetJwtIstioAuthorizations() []*JwtAuthorization {
 	// For Istio JWT we can safely assume that there is only one access strategy
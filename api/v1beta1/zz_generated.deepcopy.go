@@ -107,6 +107,11 @@ func (in *APIRuleSpec) DeepCopyInto(out *APIRuleSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Service != nil {
 		in, out := &in.Service, &out.Service
 		*out = new(Service)
@@ -117,6 +122,46 @@ func (in *APIRuleSpec) DeepCopyInto(out *APIRuleSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExportTo != nil {
+		in, out := &in.ExportTo, &out.ExportTo
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DisableCors != nil {
+		in, out := &in.DisableCors, &out.DisableCors
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CorsPolicy != nil {
+		in, out := &in.CorsPolicy, &out.CorsPolicy
+		*out = new(CorsPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreserveHost != nil {
+		in, out := &in.PreserveHost, &out.PreserveHost
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ForwardedHeaders != nil {
+		in, out := &in.ForwardedHeaders, &out.ForwardedHeaders
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Oathkeeper != nil {
+		in, out := &in.Oathkeeper, &out.Oathkeeper
+		*out = new(OathkeeperTarget)
+		**out = **in
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(Maintenance)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Rules != nil {
 		in, out := &in.Rules, &out.Rules
 		*out = make([]Rule, len(*in))
@@ -168,6 +213,11 @@ func (in *APIRuleStatus) DeepCopyInto(out *APIRuleStatus) {
 		*out = new(APIRuleResourceStatus)
 		**out = **in
 	}
+	if in.VirtualService != nil {
+		in, out := &in.VirtualService, &out.VirtualService
+		*out = new(ObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIRuleStatus.
@@ -200,6 +250,56 @@ func (in *Authenticator) DeepCopy() *Authenticator {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeyReference) DeepCopyInto(out *ConfigMapKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeyReference.
+func (in *ConfigMapKeyReference) DeepCopy() *ConfigMapKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionPool) DeepCopyInto(out *ConnectionPool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionPool.
+func (in *ConnectionPool) DeepCopy() *ConnectionPool {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsistentHash) DeepCopyInto(out *ConsistentHash) {
+	*out = *in
+	if in.HTTPCookie != nil {
+		in, out := &in.HTTPCookie, &out.HTTPCookie
+		*out = new(HTTPCookie)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsistentHash.
+func (in *ConsistentHash) DeepCopy() *ConsistentHash {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsistentHash)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CookieMutatorConfig) DeepCopyInto(out *CookieMutatorConfig) {
 	*out = *in
@@ -222,6 +322,161 @@ func (in *CookieMutatorConfig) DeepCopy() *CookieMutatorConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CorsPolicy) DeepCopyInto(out *CorsPolicy) {
+	*out = *in
+	if in.AllowOrigins != nil {
+		in, out := &in.AllowOrigins, &out.AllowOrigins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowOriginsRegex != nil {
+		in, out := &in.AllowOriginsRegex, &out.AllowOriginsRegex
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowOriginsFrom != nil {
+		in, out := &in.AllowOriginsFrom, &out.AllowOriginsFrom
+		*out = new(ConfigMapKeyReference)
+		**out = **in
+	}
+	if in.AllowMethods != nil {
+		in, out := &in.AllowMethods, &out.AllowMethods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowHeaders != nil {
+		in, out := &in.AllowHeaders, &out.AllowHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CorsPolicy.
+func (in *CorsPolicy) DeepCopy() *CorsPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CorsPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Deprecation) DeepCopyInto(out *Deprecation) {
+	*out = *in
+	if in.Since != nil {
+		in, out := &in.Since, &out.Since
+		*out = new(string)
+		**out = **in
+	}
+	if in.Sunset != nil {
+		in, out := &in.Sunset, &out.Sunset
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Deprecation.
+func (in *Deprecation) DeepCopy() *Deprecation {
+	if in == nil {
+		return nil
+	}
+	out := new(Deprecation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DirectResponse) DeepCopyInto(out *DirectResponse) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DirectResponse.
+func (in *DirectResponse) DeepCopy() *DirectResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(DirectResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Fault) DeepCopyInto(out *Fault) {
+	*out = *in
+	if in.Delay != nil {
+		in, out := &in.Delay, &out.Delay
+		*out = new(FaultDelay)
+		**out = **in
+	}
+	if in.Abort != nil {
+		in, out := &in.Abort, &out.Abort
+		*out = new(FaultAbort)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Fault.
+func (in *Fault) DeepCopy() *Fault {
+	if in == nil {
+		return nil
+	}
+	out := new(Fault)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FaultAbort) DeepCopyInto(out *FaultAbort) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FaultAbort.
+func (in *FaultAbort) DeepCopy() *FaultAbort {
+	if in == nil {
+		return nil
+	}
+	out := new(FaultAbort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FaultDelay) DeepCopyInto(out *FaultDelay) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FaultDelay.
+func (in *FaultDelay) DeepCopy() *FaultDelay {
+	if in == nil {
+		return nil
+	}
+	out := new(FaultDelay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPCookie) DeepCopyInto(out *HTTPCookie) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPCookie.
+func (in *HTTPCookie) DeepCopy() *HTTPCookie {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPCookie)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Handler) DeepCopyInto(out *Handler) {
 	*out = *in
@@ -264,6 +519,65 @@ func (in *HeaderMutatorConfig) DeepCopy() *HeaderMutatorConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeaderOperations) DeepCopyInto(out *HeaderOperations) {
+	*out = *in
+	if in.Set != nil {
+		in, out := &in.Set, &out.Set
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Add != nil {
+		in, out := &in.Add, &out.Add
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Remove != nil {
+		in, out := &in.Remove, &out.Remove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderOperations.
+func (in *HeaderOperations) DeepCopy() *HeaderOperations {
+	if in == nil {
+		return nil
+	}
+	out := new(HeaderOperations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Headers) DeepCopyInto(out *Headers) {
+	*out = *in
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		*out = new(HeaderOperations)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Response != nil {
+		in, out := &in.Response, &out.Response
+		*out = new(HeaderOperations)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Headers.
+func (in *Headers) DeepCopy() *Headers {
+	if in == nil {
+		return nil
+	}
+	out := new(Headers)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JwtAuthentication) DeepCopyInto(out *JwtAuthentication) {
 	*out = *in
@@ -372,6 +686,87 @@ func (in *JwtHeader) DeepCopy() *JwtHeader {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancer) DeepCopyInto(out *LoadBalancer) {
+	*out = *in
+	if in.ConsistentHash != nil {
+		in, out := &in.ConsistentHash, &out.ConsistentHash
+		*out = new(ConsistentHash)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancer.
+func (in *LoadBalancer) DeepCopy() *LoadBalancer {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Lua) DeepCopyInto(out *Lua) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Lua.
+func (in *Lua) DeepCopy() *Lua {
+	if in == nil {
+		return nil
+	}
+	out := new(Lua)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Maintenance) DeepCopyInto(out *Maintenance) {
+	*out = *in
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.RetryAfter != nil {
+		in, out := &in.RetryAfter, &out.RetryAfter
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Maintenance.
+func (in *Maintenance) DeepCopy() *Maintenance {
+	if in == nil {
+		return nil
+	}
+	out := new(Maintenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Mirror) DeepCopyInto(out *Mirror) {
+	*out = *in
+	in.Service.DeepCopyInto(&out.Service)
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(uint32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mirror.
+func (in *Mirror) DeepCopy() *Mirror {
+	if in == nil {
+		return nil
+	}
+	out := new(Mirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Mutator) DeepCopyInto(out *Mutator) {
 	*out = *in
@@ -392,6 +787,161 @@ func (in *Mutator) DeepCopy() *Mutator {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OathkeeperTarget) DeepCopyInto(out *OathkeeperTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OathkeeperTarget.
+func (in *OathkeeperTarget) DeepCopy() *OathkeeperTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(OathkeeperTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectReference.
+func (in *ObjectReference) DeepCopy() *ObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutlierDetection) DeepCopyInto(out *OutlierDetection) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(string)
+		**out = **in
+	}
+	if in.BaseEjectionTime != nil {
+		in, out := &in.BaseEjectionTime, &out.BaseEjectionTime
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutlierDetection.
+func (in *OutlierDetection) DeepCopy() *OutlierDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(OutlierDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PortTimeout) DeepCopyInto(out *PortTimeout) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortTimeout.
+func (in *PortTimeout) DeepCopy() *PortTimeout {
+	if in == nil {
+		return nil
+	}
+	out := new(PortTimeout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtocolPort) DeepCopyInto(out *ProtocolPort) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtocolPort.
+func (in *ProtocolPort) DeepCopy() *ProtocolPort {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtocolPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Redirect) DeepCopyInto(out *Redirect) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Redirect.
+func (in *Redirect) DeepCopy() *Redirect {
+	if in == nil {
+		return nil
+	}
+	out := new(Redirect)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegexRewrite) DeepCopyInto(out *RegexRewrite) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegexRewrite.
+func (in *RegexRewrite) DeepCopy() *RegexRewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(RegexRewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Retries) DeepCopyInto(out *Retries) {
+	*out = *in
+	if in.PerTryTimeout != nil {
+		in, out := &in.PerTryTimeout, &out.PerTryTimeout
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Retries.
+func (in *Retries) DeepCopy() *Retries {
+	if in == nil {
+		return nil
+	}
+	out := new(Retries)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Rewrite) DeepCopyInto(out *Rewrite) {
+	*out = *in
+	if in.URIRegex != nil {
+		in, out := &in.URIRegex, &out.URIRegex
+		*out = new(RegexRewrite)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rewrite.
+func (in *Rewrite) DeepCopy() *Rewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(Rewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Rule) DeepCopyInto(out *Rule) {
 	*out = *in
@@ -400,6 +950,72 @@ func (in *Rule) DeepCopyInto(out *Rule) {
 		*out = new(Service)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]WeightedService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProtocolPorts != nil {
+		in, out := &in.ProtocolPorts, &out.ProtocolPorts
+		*out = make([]ProtocolPort, len(*in))
+		copy(*out, *in)
+	}
+	if in.Redirect != nil {
+		in, out := &in.Redirect, &out.Redirect
+		*out = new(Redirect)
+		**out = **in
+	}
+	if in.DirectResponse != nil {
+		in, out := &in.DirectResponse, &out.DirectResponse
+		*out = new(DirectResponse)
+		**out = **in
+	}
+	if in.MatchHeaders != nil {
+		in, out := &in.MatchHeaders, &out.MatchHeaders
+		*out = make(map[string]StringMatch, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MatchQueryParams != nil {
+		in, out := &in.MatchQueryParams, &out.MatchQueryParams
+		*out = make(map[string]StringMatch, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Rewrite != nil {
+		in, out := &in.Rewrite, &out.Rewrite
+		*out = new(Rewrite)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(Mirror)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Lua != nil {
+		in, out := &in.Lua, &out.Lua
+		*out = new(Lua)
+		**out = **in
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = new(Headers)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deprecation != nil {
+		in, out := &in.Deprecation, &out.Deprecation
+		*out = new(Deprecation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreserveHeaders != nil {
+		in, out := &in.PreserveHeaders, &out.PreserveHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Methods != nil {
 		in, out := &in.Methods, &out.Methods
 		*out = make([]string, len(*in))
@@ -427,6 +1043,56 @@ func (in *Rule) DeepCopyInto(out *Rule) {
 			}
 		}
 	}
+	if in.CorsPolicy != nil {
+		in, out := &in.CorsPolicy, &out.CorsPolicy
+		*out = new(CorsPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(string)
+		**out = **in
+	}
+	if in.PortTimeouts != nil {
+		in, out := &in.PortTimeouts, &out.PortTimeouts
+		*out = make([]PortTimeout, len(*in))
+		copy(*out, *in)
+	}
+	if in.IdleTimeout != nil {
+		in, out := &in.IdleTimeout, &out.IdleTimeout
+		*out = new(string)
+		**out = **in
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(Retries)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxRequestHeadersBytes != nil {
+		in, out := &in.MaxRequestHeadersBytes, &out.MaxRequestHeadersBytes
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.PreserveHost != nil {
+		in, out := &in.PreserveHost, &out.PreserveHost
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Fault != nil {
+		in, out := &in.Fault, &out.Fault
+		*out = new(Fault)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SourceCIDRs != nil {
+		in, out := &in.SourceCIDRs, &out.SourceCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rule.
@@ -462,6 +1128,18 @@ func (in *Service) DeepCopyInto(out *Service) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.TrafficPolicy != nil {
+		in, out := &in.TrafficPolicy, &out.TrafficPolicy
+		*out = new(TrafficPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Subsets != nil {
+		in, out := &in.Subsets, &out.Subsets
+		*out = make([]Subset, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Service.
@@ -473,3 +1151,86 @@ func (in *Service) DeepCopy() *Service {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StringMatch) DeepCopyInto(out *StringMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringMatch.
+func (in *StringMatch) DeepCopy() *StringMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(StringMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Subset) DeepCopyInto(out *Subset) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subset.
+func (in *Subset) DeepCopy() *Subset {
+	if in == nil {
+		return nil
+	}
+	out := new(Subset)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficPolicy) DeepCopyInto(out *TrafficPolicy) {
+	*out = *in
+	if in.ConnectionPool != nil {
+		in, out := &in.ConnectionPool, &out.ConnectionPool
+		*out = new(ConnectionPool)
+		**out = **in
+	}
+	if in.OutlierDetection != nil {
+		in, out := &in.OutlierDetection, &out.OutlierDetection
+		*out = new(OutlierDetection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LoadBalancer != nil {
+		in, out := &in.LoadBalancer, &out.LoadBalancer
+		*out = new(LoadBalancer)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficPolicy.
+func (in *TrafficPolicy) DeepCopy() *TrafficPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WeightedService) DeepCopyInto(out *WeightedService) {
+	*out = *in
+	in.Service.DeepCopyInto(&out.Service)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WeightedService.
+func (in *WeightedService) DeepCopy() *WeightedService {
+	if in == nil {
+		return nil
+	}
+	out := new(WeightedService)
+	in.DeepCopyInto(out)
+	return out
+}
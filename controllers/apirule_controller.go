@@ -31,11 +31,13 @@ import (
 	"github.com/go-logr/logr"
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
 	"github.com/kyma-project/api-gateway/internal/processing"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -53,15 +55,40 @@ type APIRuleReconciler struct {
 	OathkeeperSvc          string
 	OathkeeperSvcPort      uint32
 	CorsConfig             *processing.CorsConfig
+	RetryConfig            *processing.RetryConfig
 	GeneratedObjectsLabels map[string]string
 	ServiceBlockList       map[string][]string
-	DomainAllowList        []string
-	HostBlockList          []string
-	DefaultDomainName      string
+	// RestrictServiceNamespaces rejects APIRules that reference services in other namespaces, unless the namespace is
+	// in ServiceNamespaceAllowList.
+	RestrictServiceNamespaces bool
+	ServiceNamespaceAllowList []string
+	DomainAllowList           []string
+	HostBlockList             []string
+	DefaultDomainName         string
+	StrictHostDomain          bool
+	VSFixedName               bool
+	VSNamePrefix              string
+	VSNameSuffix              string
+	CatchAllPathRegex         string
+	AggregateVSByHost         bool
+	VSExportTo                []string
+	ValidateServices          bool
+	DuplicatePathsMode        processing.DuplicatePathsMode
+	// HTTPTimeout is the default timeout of the routes, no timeout is configured on the routes if it is nil.
+	HTTPTimeout        *time.Duration
+	IdleTimeout        time.Duration
+	LuaFilters         bool
+	LuaFilterNamespace string
+	// MatchedRuleHeader is the name of the response header with the name of the matched route, no header is set if empty.
+	MatchedRuleHeader      string
+	Metrics                processing.ReconciliationMetrics
+	Recorder               record.EventRecorder
 	Scheme                 *runtime.Scheme
 	Config                 *helpers.Config
 	ReconcilePeriod        time.Duration
 	OnErrorReconcilePeriod time.Duration
+	// ownerIndexRegistered is set if the processing.OwnerIndex is registered with the field indexer of the manager.
+	ownerIndexRegistered bool
 }
 
 const (
@@ -106,12 +133,16 @@ func (p isApiGatewayConfigMapPredicate) Generic(e event.GenericEvent) bool {
 //+kubebuilder:rbac:groups=gateway.kyma-project.io,resources=apirules/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=gateway.kyma-project.io,resources=apirules/finalizers,verbs=update
 //+kubebuilder:rbac:groups=networking.istio.io,resources=virtualservices,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.istio.io,resources=destinationrules,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.istio.io,resources=envoyfilters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=oathkeeper.ory.sh,resources=rules,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=security.istio.io,resources=authorizationpolicies,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=security.istio.io,resources=requestauthentications,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="apiextensions.k8s.io",resources=customresourcedefinitions,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *APIRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	r.Log.Info("Starting reconciliation", "namespacedName", req.NamespacedName.String())
@@ -150,15 +181,34 @@ func (r *APIRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	r.Log.Info("Starting ApiRule reconciliation", "jwtHandler", r.Config.JWTHandler)
 
 	c := processing.ReconciliationConfig{
-		OathkeeperSvc:       r.OathkeeperSvc,
-		OathkeeperSvcPort:   r.OathkeeperSvcPort,
-		CorsConfig:          r.CorsConfig,
-		AdditionalLabels:    r.GeneratedObjectsLabels,
-		DefaultDomainName:   r.DefaultDomainName,
-		ServiceBlockList:    r.ServiceBlockList,
-		DomainAllowList:     r.DomainAllowList,
-		HostBlockList:       r.HostBlockList,
-		HTTPTimeoutDuration: helpers.DEFAULT_HTTP_TIMEOUT,
+		OathkeeperSvc:             r.OathkeeperSvc,
+		OathkeeperSvcPort:         r.OathkeeperSvcPort,
+		CorsConfig:                r.CorsConfig,
+		AdditionalLabels:          r.GeneratedObjectsLabels,
+		DefaultDomainName:         r.DefaultDomainName,
+		ServiceBlockList:          r.ServiceBlockList,
+		RestrictServiceNamespaces: r.RestrictServiceNamespaces,
+		ServiceNamespaceAllowList: r.ServiceNamespaceAllowList,
+		DomainAllowList:           r.DomainAllowList,
+		HostBlockList:             r.HostBlockList,
+		HTTPTimeout:               r.HTTPTimeout,
+		IdleTimeout:               r.IdleTimeout,
+		LuaFilters:                r.LuaFilters,
+		LuaFilterNamespace:        r.LuaFilterNamespace,
+		MatchedRuleHeader:         r.MatchedRuleHeader,
+		RetryConfig:               r.RetryConfig,
+		StrictHostDomain:          r.StrictHostDomain,
+		VirtualServiceFixedName:   r.VSFixedName,
+		VirtualServiceNamePrefix:  r.VSNamePrefix,
+		VirtualServiceNameSuffix:  r.VSNameSuffix,
+		CatchAllPathRegex:         r.CatchAllPathRegex,
+		AggregateByHost:           r.AggregateVSByHost,
+		ExportTo:                  r.VSExportTo,
+		ValidateServices:          r.ValidateServices,
+		DuplicatePathsMode:        r.DuplicatePathsMode,
+		Metrics:                   r.Metrics,
+		Recorder:                  r.Recorder,
+		UseOwnerIndex:             r.ownerIndexRegistered,
 	}
 
 	cmd := r.getReconciliation(c)
@@ -232,13 +282,44 @@ func (r *APIRuleReconciler) getReconciliation(config processing.ReconciliationCo
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *APIRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Looking up the Virtual Services by the owner index avoids filtering all cached Virtual Services in each reconciliation.
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &networkingv1beta1.VirtualService{}, processing.OwnerIndex, processing.IndexByOwner); err != nil {
+		return err
+	}
+	r.ownerIndexRegistered = true
+
+	// The CORS origins are resolved from the referenced ConfigMaps in each reconciliation, so the APIRules that reference a
+	// changed ConfigMap are enqueued to apply its origins without waiting for the next periodic reconciliation.
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &gatewayv1beta1.APIRule{}, processing.CorsOriginsIndex, processing.IndexByCorsOrigins); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		// We need to filter for generation changes, because we had an issue that on Azure clusters the APIRules were constantly reconciled.
-		For(&gatewayv1beta1.APIRule{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Annotations like the paused annotation don't change the generation, so annotation changes are reconciled as well.
+		For(&gatewayv1beta1.APIRule{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
 		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestForObject{}, builder.WithPredicates(&isApiGatewayConfigMapPredicate{Log: r.Log})).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.getCorsOriginsApiRules)).
 		Complete(r)
 }
 
+// getCorsOriginsApiRules returns the requests for the APIRules that reference the ConfigMap in their CORS policies.
+func (r *APIRuleReconciler) getCorsOriginsApiRules(configMap client.Object) []reconcile.Request {
+	var apiRules gatewayv1beta1.APIRuleList
+	indexValue := processing.GetCorsOriginsIndexValue(configMap.GetNamespace(), configMap.GetName())
+	if err := r.Client.List(context.Background(), &apiRules, client.MatchingFields{processing.CorsOriginsIndex: indexValue}); err != nil {
+		r.Log.Error(err, "Error listing the APIRules that reference the CORS origins ConfigMap", "configMap", indexValue)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(apiRules.Items))
+	for _, apiRule := range apiRules.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: apiRule.Namespace, Name: apiRule.Name}})
+	}
+
+	return requests
+}
+
 // Updates api status. If there was an error during update, returns the error so that entire reconcile loop is retried. If there is no error, returns a "reconcile success" value.
 func (r *APIRuleReconciler) updateStatusOrRetry(ctx context.Context, api *gatewayv1beta1.APIRule, status processing.ReconciliationStatus) (ctrl.Result, error) {
 	_, updateStatusErr := r.updateStatus(ctx, api, status)
@@ -290,6 +371,13 @@ func (r *APIRuleReconciler) updateStatus(ctx context.Context, api *gatewayv1beta
 	api.Status.AccessRuleStatus = status.AccessRuleStatus
 	api.Status.RequestAuthenticationStatus = status.RequestAuthenticationStatus
 	api.Status.AuthorizationPolicyStatus = status.AuthorizationPolicyStatus
+	// The reference is only known if the Virtual Service was changed, otherwise the previously recorded one is still valid.
+	// The reference of a deleted Virtual Service is cleared, since it no longer exists.
+	if status.VirtualService != nil {
+		api.Status.VirtualService = status.VirtualService
+	} else if status.VirtualServiceDeleted {
+		api.Status.VirtualService = nil
+	}
 
 	r.Log.Info("Updating ApiRule status", "status", api.Status)
 	err := r.Client.Status().Update(ctx, api)
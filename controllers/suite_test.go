@@ -11,11 +11,13 @@ import (
 
 	rulev1alpha1 "github.com/ory/oathkeeper-maester/api/v1alpha1"
 	"istio.io/api/networking/v1beta1"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
 	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 	securityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
@@ -103,6 +105,7 @@ var _ = BeforeSuite(func(specCtx SpecContext) {
 	Expect(gatewayv1beta1.AddToScheme(s)).Should(Succeed())
 	Expect(rulev1alpha1.AddToScheme(s)).Should(Succeed())
 	Expect(networkingv1beta1.AddToScheme(s)).Should(Succeed())
+	Expect(networkingv1alpha3.AddToScheme(s)).Should(Succeed())
 	Expect(securityv1beta1.AddToScheme(s)).Should(Succeed())
 	Expect(corev1.AddToScheme(s)).Should(Succeed())
 
@@ -152,6 +155,7 @@ var _ = BeforeSuite(func(specCtx SpecContext) {
 		},
 		GeneratedObjectsLabels: map[string]string{},
 		Config:                 &helpers.Config{},
+		HTTPTimeout:            pointer.Duration(time.Second * helpers.DEFAULT_HTTP_TIMEOUT),
 
 		ReconcilePeriod:        time.Second * 2,
 		OnErrorReconcilePeriod: time.Second * 2,
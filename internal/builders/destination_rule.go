@@ -0,0 +1,202 @@
+package builders
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"istio.io/api/networking/v1beta1"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+)
+
+// NewDestinationRuleBuilder returns a builder for istio.io/client-go/pkg/apis/networking/v1beta1/DestinationRule type
+func NewDestinationRuleBuilder() *DestinationRuleBuilder {
+	return &DestinationRuleBuilder{
+		value: &networkingv1beta1.DestinationRule{},
+	}
+}
+
+type DestinationRuleBuilder struct {
+	value *networkingv1beta1.DestinationRule
+}
+
+func (dr *DestinationRuleBuilder) Get() *networkingv1beta1.DestinationRule {
+	return dr.value
+}
+
+func (dr *DestinationRuleBuilder) WithGenerateName(val string) *DestinationRuleBuilder {
+	dr.value.Name = ""
+	dr.value.GenerateName = val
+	return dr
+}
+
+func (dr *DestinationRuleBuilder) WithNamespace(val string) *DestinationRuleBuilder {
+	dr.value.Namespace = val
+	return dr
+}
+
+func (dr *DestinationRuleBuilder) WithLabel(key, val string) *DestinationRuleBuilder {
+	if dr.value.Labels == nil {
+		dr.value.Labels = make(map[string]string)
+	}
+	dr.value.Labels[key] = val
+	return dr
+}
+
+func (dr *DestinationRuleBuilder) WithHost(val string) *DestinationRuleBuilder {
+	dr.value.Spec.Host = val
+	return dr
+}
+
+// WithTLSOrigination configures that the sidecar or gateway originates a simple TLS connection to the given port of the host.
+func (dr *DestinationRuleBuilder) WithTLSOrigination(port uint32) *DestinationRuleBuilder {
+	dr.portSettings(port).Tls = &v1beta1.ClientTLSSettings{Mode: v1beta1.ClientTLSSettings_SIMPLE}
+	return dr
+}
+
+// WithHTTP2Upgrade configures that the requests to the given port of the host are upgraded to HTTP/2.
+func (dr *DestinationRuleBuilder) WithHTTP2Upgrade(port uint32) *DestinationRuleBuilder {
+	dr.portSettings(port).ConnectionPool = &v1beta1.ConnectionPoolSettings{
+		Http: &v1beta1.ConnectionPoolSettings_HTTPSettings{H2UpgradePolicy: v1beta1.ConnectionPoolSettings_HTTPSettings_UPGRADE},
+	}
+	return dr
+}
+
+// portSettings returns the traffic policy of the given port, which is added if the port has no traffic policy yet.
+func (dr *DestinationRuleBuilder) portSettings(port uint32) *v1beta1.TrafficPolicy_PortTrafficPolicy {
+	if dr.value.Spec.TrafficPolicy == nil {
+		dr.value.Spec.TrafficPolicy = &v1beta1.TrafficPolicy{}
+	}
+
+	for _, portSettings := range dr.value.Spec.TrafficPolicy.PortLevelSettings {
+		if portSettings.Port.GetNumber() == port {
+			return portSettings
+		}
+	}
+
+	portSettings := &v1beta1.TrafficPolicy_PortTrafficPolicy{Port: &v1beta1.PortSelector{Number: port}}
+	dr.value.Spec.TrafficPolicy.PortLevelSettings = append(dr.value.Spec.TrafficPolicy.PortLevelSettings, portSettings)
+	return portSettings
+}
+
+// WithConnectionPool limits the connections and requests to the host. Limits with a zero value are not set.
+func (dr *DestinationRuleBuilder) WithConnectionPool(maxConnections, maxPendingRequests, maxRequestsPerConnection int32) *DestinationRuleBuilder {
+	connectionPool := dr.connectionPool()
+	if maxConnections > 0 {
+		connectionPool.Tcp = &v1beta1.ConnectionPoolSettings_TCPSettings{MaxConnections: maxConnections}
+	}
+	if maxPendingRequests > 0 || maxRequestsPerConnection > 0 {
+		if connectionPool.Http == nil {
+			connectionPool.Http = &v1beta1.ConnectionPoolSettings_HTTPSettings{}
+		}
+		connectionPool.Http.Http1MaxPendingRequests = maxPendingRequests
+		connectionPool.Http.MaxRequestsPerConnection = maxRequestsPerConnection
+	}
+
+	return dr
+}
+
+// WithIdleTimeout closes the connections to the host that had no active requests for the given duration.
+func (dr *DestinationRuleBuilder) WithIdleTimeout(idleTimeout time.Duration) *DestinationRuleBuilder {
+	connectionPool := dr.connectionPool()
+	if connectionPool.Http == nil {
+		connectionPool.Http = &v1beta1.ConnectionPoolSettings_HTTPSettings{}
+	}
+	connectionPool.Http.IdleTimeout = durationpb.New(idleTimeout)
+
+	return dr
+}
+
+// connectionPool returns the connection pool settings of the host, which are added if the host has none yet.
+func (dr *DestinationRuleBuilder) connectionPool() *v1beta1.ConnectionPoolSettings {
+	if dr.value.Spec.TrafficPolicy == nil {
+		dr.value.Spec.TrafficPolicy = &v1beta1.TrafficPolicy{}
+	}
+	if dr.value.Spec.TrafficPolicy.ConnectionPool == nil {
+		dr.value.Spec.TrafficPolicy.ConnectionPool = &v1beta1.ConnectionPoolSettings{}
+	}
+
+	return dr.value.Spec.TrafficPolicy.ConnectionPool
+}
+
+// WithOutlierDetection configures the ejection of host instances after consecutive 5xx errors. The interval, base
+// ejection time and max ejection percent are not set if they have a zero value, so the Istio defaults apply.
+func (dr *DestinationRuleBuilder) WithOutlierDetection(consecutive5xxErrors uint32, interval, baseEjectionTime time.Duration, maxEjectionPercent int32) *DestinationRuleBuilder {
+	if dr.value.Spec.TrafficPolicy == nil {
+		dr.value.Spec.TrafficPolicy = &v1beta1.TrafficPolicy{}
+	}
+
+	outlierDetection := &v1beta1.OutlierDetection{
+		Consecutive_5XxErrors: wrapperspb.UInt32(consecutive5xxErrors),
+		MaxEjectionPercent:    maxEjectionPercent,
+	}
+	if interval > 0 {
+		outlierDetection.Interval = durationpb.New(interval)
+	}
+	if baseEjectionTime > 0 {
+		outlierDetection.BaseEjectionTime = durationpb.New(baseEjectionTime)
+	}
+
+	dr.value.Spec.TrafficPolicy.OutlierDetection = outlierDetection
+	return dr
+}
+
+// WithSimpleLoadBalancer distributes the requests to the host instances with the given algorithm.
+func (dr *DestinationRuleBuilder) WithSimpleLoadBalancer(algorithm v1beta1.LoadBalancerSettings_SimpleLB) *DestinationRuleBuilder {
+	dr.trafficPolicy().LoadBalancer = &v1beta1.LoadBalancerSettings{
+		LbPolicy: &v1beta1.LoadBalancerSettings_Simple{Simple: algorithm},
+	}
+	return dr
+}
+
+// WithHeaderHashLoadBalancer sends the requests with the same value of the given header to the same host instance.
+func (dr *DestinationRuleBuilder) WithHeaderHashLoadBalancer(headerName string) *DestinationRuleBuilder {
+	return dr.withConsistentHashLoadBalancer(&v1beta1.LoadBalancerSettings_ConsistentHashLB{
+		HashKey: &v1beta1.LoadBalancerSettings_ConsistentHashLB_HttpHeaderName{HttpHeaderName: headerName},
+	})
+}
+
+// WithCookieHashLoadBalancer sends the requests with the same value of the given cookie to the same host instance. The
+// cookie is set in the response with the given path and lifetime if the request doesn't contain it.
+func (dr *DestinationRuleBuilder) WithCookieHashLoadBalancer(name, path string, ttl time.Duration) *DestinationRuleBuilder {
+	return dr.withConsistentHashLoadBalancer(&v1beta1.LoadBalancerSettings_ConsistentHashLB{
+		HashKey: &v1beta1.LoadBalancerSettings_ConsistentHashLB_HttpCookie{
+			HttpCookie: &v1beta1.LoadBalancerSettings_ConsistentHashLB_HTTPCookie{Name: name, Path: path, Ttl: durationpb.New(ttl)},
+		},
+	})
+}
+
+// WithSourceIPHashLoadBalancer sends the requests from the same source IP address to the same host instance.
+func (dr *DestinationRuleBuilder) WithSourceIPHashLoadBalancer() *DestinationRuleBuilder {
+	return dr.withConsistentHashLoadBalancer(&v1beta1.LoadBalancerSettings_ConsistentHashLB{
+		HashKey: &v1beta1.LoadBalancerSettings_ConsistentHashLB_UseSourceIp{UseSourceIp: true},
+	})
+}
+
+func (dr *DestinationRuleBuilder) withConsistentHashLoadBalancer(consistentHash *v1beta1.LoadBalancerSettings_ConsistentHashLB) *DestinationRuleBuilder {
+	dr.trafficPolicy().LoadBalancer = &v1beta1.LoadBalancerSettings{
+		LbPolicy: &v1beta1.LoadBalancerSettings_ConsistentHash{ConsistentHash: consistentHash},
+	}
+	return dr
+}
+
+// trafficPolicy returns the traffic policy of the host, which is added if the host has none yet.
+func (dr *DestinationRuleBuilder) trafficPolicy() *v1beta1.TrafficPolicy {
+	if dr.value.Spec.TrafficPolicy == nil {
+		dr.value.Spec.TrafficPolicy = &v1beta1.TrafficPolicy{}
+	}
+
+	return dr.value.Spec.TrafficPolicy
+}
+
+// WithSubset defines a subset of the host instances with the given labels. A subset with the same name is only defined once.
+func (dr *DestinationRuleBuilder) WithSubset(name string, labels map[string]string) *DestinationRuleBuilder {
+	for _, subset := range dr.value.Spec.Subsets {
+		if subset.Name == name {
+			return dr
+		}
+	}
+
+	dr.value.Spec.Subsets = append(dr.value.Spec.Subsets, &v1beta1.Subset{Name: name, Labels: labels})
+	return dr
+}
@@ -0,0 +1,116 @@
+package builders
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"istio.io/api/networking/v1beta1"
+)
+
+var _ = Describe("Builder for", func() {
+	Describe("DestinationRule", func() {
+		It("should build a DestinationRule with TLS origination for each port once", func() {
+			dr := NewDestinationRuleBuilder().
+				WithGenerateName("test-").
+				WithNamespace("testNs").
+				WithLabel("testLabel", "value").
+				WithHost("example-service.testNs.svc.cluster.local").
+				WithTLSOrigination(8443).
+				WithTLSOrigination(9443).
+				WithTLSOrigination(8443).
+				Get()
+
+			Expect(dr.GenerateName).To(Equal("test-"))
+			Expect(dr.Namespace).To(Equal("testNs"))
+			Expect(dr.Labels).To(HaveKeyWithValue("testLabel", "value"))
+			Expect(dr.Spec.Host).To(Equal("example-service.testNs.svc.cluster.local"))
+			Expect(dr.Spec.TrafficPolicy.PortLevelSettings).To(HaveLen(2))
+			Expect(dr.Spec.TrafficPolicy.PortLevelSettings[0].Port.Number).To(Equal(uint32(8443)))
+			Expect(dr.Spec.TrafficPolicy.PortLevelSettings[0].Tls.Mode).To(Equal(v1beta1.ClientTLSSettings_SIMPLE))
+			Expect(dr.Spec.TrafficPolicy.PortLevelSettings[1].Port.Number).To(Equal(uint32(9443)))
+		})
+
+		It("should build a DestinationRule with HTTP/2 upgrade and TLS origination in the same port settings", func() {
+			dr := NewDestinationRuleBuilder().
+				WithHost("example-service.testNs.svc.cluster.local").
+				WithHTTP2Upgrade(8443).
+				WithTLSOrigination(8443).
+				Get()
+
+			Expect(dr.Spec.TrafficPolicy.PortLevelSettings).To(HaveLen(1))
+			Expect(dr.Spec.TrafficPolicy.PortLevelSettings[0].Port.Number).To(Equal(uint32(8443)))
+			Expect(dr.Spec.TrafficPolicy.PortLevelSettings[0].Tls.Mode).To(Equal(v1beta1.ClientTLSSettings_SIMPLE))
+			Expect(dr.Spec.TrafficPolicy.PortLevelSettings[0].ConnectionPool.Http.H2UpgradePolicy).To(Equal(v1beta1.ConnectionPoolSettings_HTTPSettings_UPGRADE))
+		})
+
+		It("should build a DestinationRule with connection pool and outlier detection", func() {
+			dr := NewDestinationRuleBuilder().
+				WithHost("example-service.testNs.svc.cluster.local").
+				WithConnectionPool(100, 0, 10).
+				WithOutlierDetection(5, 10*time.Second, 0, 50).
+				Get()
+
+			Expect(dr.Spec.TrafficPolicy.PortLevelSettings).To(BeEmpty())
+			Expect(dr.Spec.TrafficPolicy.ConnectionPool.Tcp.MaxConnections).To(Equal(int32(100)))
+			Expect(dr.Spec.TrafficPolicy.ConnectionPool.Http.Http1MaxPendingRequests).To(Equal(int32(0)))
+			Expect(dr.Spec.TrafficPolicy.ConnectionPool.Http.MaxRequestsPerConnection).To(Equal(int32(10)))
+			Expect(dr.Spec.TrafficPolicy.OutlierDetection.Consecutive_5XxErrors.GetValue()).To(Equal(uint32(5)))
+			Expect(dr.Spec.TrafficPolicy.OutlierDetection.Interval.AsDuration()).To(Equal(10 * time.Second))
+			Expect(dr.Spec.TrafficPolicy.OutlierDetection.BaseEjectionTime).To(BeNil())
+			Expect(dr.Spec.TrafficPolicy.OutlierDetection.MaxEjectionPercent).To(Equal(int32(50)))
+		})
+
+		It("should build a DestinationRule with an idle timeout in addition to the connection pool", func() {
+			dr := NewDestinationRuleBuilder().
+				WithHost("example-service.testNs.svc.cluster.local").
+				WithConnectionPool(100, 0, 10).
+				WithIdleTimeout(5 * time.Minute).
+				Get()
+
+			Expect(dr.Spec.TrafficPolicy.ConnectionPool.Tcp.MaxConnections).To(Equal(int32(100)))
+			Expect(dr.Spec.TrafficPolicy.ConnectionPool.Http.MaxRequestsPerConnection).To(Equal(int32(10)))
+			Expect(dr.Spec.TrafficPolicy.ConnectionPool.Http.IdleTimeout.AsDuration()).To(Equal(5 * time.Minute))
+		})
+
+		It("should build a DestinationRule with each subset once", func() {
+			dr := NewDestinationRuleBuilder().
+				WithHost("example-service.testNs.svc.cluster.local").
+				WithSubset("v1", map[string]string{"version": "v1"}).
+				WithSubset("v2", map[string]string{"version": "v2"}).
+				WithSubset("v1", map[string]string{"version": "v1"}).
+				Get()
+
+			Expect(dr.Spec.TrafficPolicy).To(BeNil())
+			Expect(dr.Spec.Subsets).To(HaveLen(2))
+			Expect(dr.Spec.Subsets[0].Name).To(Equal("v1"))
+			Expect(dr.Spec.Subsets[0].Labels).To(HaveKeyWithValue("version", "v1"))
+			Expect(dr.Spec.Subsets[1].Name).To(Equal("v2"))
+		})
+
+		It("should build a DestinationRule with a cookie hash load balancer in addition to the connection pool", func() {
+			dr := NewDestinationRuleBuilder().
+				WithHost("example-service.testNs.svc.cluster.local").
+				WithConnectionPool(100, 0, 0).
+				WithCookieHashLoadBalancer("session", "/", time.Hour).
+				Get()
+
+			Expect(dr.Spec.TrafficPolicy.ConnectionPool.Tcp.MaxConnections).To(Equal(int32(100)))
+			cookie := dr.Spec.TrafficPolicy.LoadBalancer.GetConsistentHash().GetHttpCookie()
+			Expect(cookie.Name).To(Equal("session"))
+			Expect(cookie.Path).To(Equal("/"))
+			Expect(cookie.Ttl.AsDuration()).To(Equal(time.Hour))
+		})
+
+		It("should build a DestinationRule with the last configured load balancer", func() {
+			dr := NewDestinationRuleBuilder().
+				WithHost("example-service.testNs.svc.cluster.local").
+				WithHeaderHashLoadBalancer("x-user").
+				WithSimpleLoadBalancer(v1beta1.LoadBalancerSettings_LEAST_REQUEST).
+				Get()
+
+			Expect(dr.Spec.TrafficPolicy.LoadBalancer.GetConsistentHash()).To(BeNil())
+			Expect(dr.Spec.TrafficPolicy.LoadBalancer.GetSimple()).To(Equal(v1beta1.LoadBalancerSettings_LEAST_REQUEST))
+		})
+	})
+})
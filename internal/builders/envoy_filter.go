@@ -0,0 +1,110 @@
+package builders
+
+import (
+	"google.golang.org/protobuf/types/known/structpb"
+	"istio.io/api/networking/v1alpha3"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+)
+
+const (
+	luaFilterType         = "type.googleapis.com/envoy.extensions.filters.http.lua.v3.Lua"
+	luaPerRouteFilterType = "type.googleapis.com/envoy.extensions.filters.http.lua.v3.LuaPerRoute"
+)
+
+// NewEnvoyFilterBuilder returns a builder for istio.io/client-go/pkg/apis/networking/v1alpha3/EnvoyFilter type
+func NewEnvoyFilterBuilder() *EnvoyFilterBuilder {
+	return &EnvoyFilterBuilder{
+		value: &networkingv1alpha3.EnvoyFilter{},
+	}
+}
+
+type EnvoyFilterBuilder struct {
+	value *networkingv1alpha3.EnvoyFilter
+}
+
+func (ef *EnvoyFilterBuilder) Get() *networkingv1alpha3.EnvoyFilter {
+	return ef.value
+}
+
+func (ef *EnvoyFilterBuilder) WithGenerateName(val string) *EnvoyFilterBuilder {
+	ef.value.Name = ""
+	ef.value.GenerateName = val
+	return ef
+}
+
+func (ef *EnvoyFilterBuilder) WithNamespace(val string) *EnvoyFilterBuilder {
+	ef.value.Namespace = val
+	return ef
+}
+
+func (ef *EnvoyFilterBuilder) WithLabel(key, val string) *EnvoyFilterBuilder {
+	if ef.value.Labels == nil {
+		ef.value.Labels = make(map[string]string)
+	}
+	ef.value.Labels[key] = val
+	return ef
+}
+
+func (ef *EnvoyFilterBuilder) WithWorkloadSelector(labels map[string]string) *EnvoyFilterBuilder {
+	ef.value.Spec.WorkloadSelector = &v1alpha3.WorkloadSelector{Labels: labels}
+	return ef
+}
+
+// WithLuaRouteFilter inserts a Lua filter with the given name before the router of the gateway and configures the
+// script of the filter for the route with the given name. The filter has no script for the other routes, so it
+// doesn't change their requests.
+func (ef *EnvoyFilterBuilder) WithLuaRouteFilter(filterName string, routeName string, inlineCode string) *EnvoyFilterBuilder {
+	// The values only contain strings and maps, which can always be converted to a struct.
+	filter, _ := structpb.NewStruct(map[string]interface{}{
+		"name": filterName,
+		"typed_config": map[string]interface{}{
+			"@type": luaFilterType,
+		},
+	})
+	perRoute, _ := structpb.NewStruct(map[string]interface{}{
+		"typed_per_filter_config": map[string]interface{}{
+			filterName: map[string]interface{}{
+				"@type": luaPerRouteFilterType,
+				"source_code": map[string]interface{}{
+					"inline_string": inlineCode,
+				},
+			},
+		},
+	})
+
+	ef.value.Spec.ConfigPatches = append(ef.value.Spec.ConfigPatches,
+		&v1alpha3.EnvoyFilter_EnvoyConfigObjectPatch{
+			ApplyTo: v1alpha3.EnvoyFilter_HTTP_FILTER,
+			Match: &v1alpha3.EnvoyFilter_EnvoyConfigObjectMatch{
+				Context: v1alpha3.EnvoyFilter_GATEWAY,
+				ObjectTypes: &v1alpha3.EnvoyFilter_EnvoyConfigObjectMatch_Listener{
+					Listener: &v1alpha3.EnvoyFilter_ListenerMatch{
+						FilterChain: &v1alpha3.EnvoyFilter_ListenerMatch_FilterChainMatch{
+							Filter: &v1alpha3.EnvoyFilter_ListenerMatch_FilterMatch{
+								Name:      "envoy.filters.network.http_connection_manager",
+								SubFilter: &v1alpha3.EnvoyFilter_ListenerMatch_SubFilterMatch{Name: "envoy.filters.http.router"},
+							},
+						},
+					},
+				},
+			},
+			Patch: &v1alpha3.EnvoyFilter_Patch{Operation: v1alpha3.EnvoyFilter_Patch_INSERT_BEFORE, Value: filter},
+		},
+		&v1alpha3.EnvoyFilter_EnvoyConfigObjectPatch{
+			ApplyTo: v1alpha3.EnvoyFilter_HTTP_ROUTE,
+			Match: &v1alpha3.EnvoyFilter_EnvoyConfigObjectMatch{
+				Context: v1alpha3.EnvoyFilter_GATEWAY,
+				ObjectTypes: &v1alpha3.EnvoyFilter_EnvoyConfigObjectMatch_RouteConfiguration{
+					RouteConfiguration: &v1alpha3.EnvoyFilter_RouteConfigurationMatch{
+						Vhost: &v1alpha3.EnvoyFilter_RouteConfigurationMatch_VirtualHostMatch{
+							Route: &v1alpha3.EnvoyFilter_RouteConfigurationMatch_RouteMatch{Name: routeName},
+						},
+					},
+				},
+			},
+			Patch: &v1alpha3.EnvoyFilter_Patch{Operation: v1alpha3.EnvoyFilter_Patch_MERGE, Value: perRoute},
+		},
+	)
+
+	return ef
+}
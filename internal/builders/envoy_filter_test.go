@@ -0,0 +1,42 @@
+package builders
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"istio.io/api/networking/v1alpha3"
+)
+
+var _ = Describe("Builder for", func() {
+	Describe("EnvoyFilter", func() {
+		It("should build an EnvoyFilter with a Lua filter for a single route", func() {
+			ef := NewEnvoyFilterBuilder().
+				WithGenerateName("test-").
+				WithNamespace("istio-system").
+				WithLabel("testLabel", "value").
+				WithWorkloadSelector(map[string]string{"istio": "ingressgateway"}).
+				WithLuaRouteFilter("envoy.filters.http.lua.test", "test-route", "function envoy_on_request(handle) end").
+				Get()
+
+			Expect(ef.GenerateName).To(Equal("test-"))
+			Expect(ef.Namespace).To(Equal("istio-system"))
+			Expect(ef.Labels).To(HaveKeyWithValue("testLabel", "value"))
+			Expect(ef.Spec.WorkloadSelector.Labels).To(HaveKeyWithValue("istio", "ingressgateway"))
+			Expect(ef.Spec.ConfigPatches).To(HaveLen(2))
+
+			filterPatch := ef.Spec.ConfigPatches[0]
+			Expect(filterPatch.ApplyTo).To(Equal(v1alpha3.EnvoyFilter_HTTP_FILTER))
+			Expect(filterPatch.Match.Context).To(Equal(v1alpha3.EnvoyFilter_GATEWAY))
+			Expect(filterPatch.Match.GetListener().FilterChain.Filter.SubFilter.Name).To(Equal("envoy.filters.http.router"))
+			Expect(filterPatch.Patch.Operation).To(Equal(v1alpha3.EnvoyFilter_Patch_INSERT_BEFORE))
+			Expect(filterPatch.Patch.Value.Fields["name"].GetStringValue()).To(Equal("envoy.filters.http.lua.test"))
+
+			routePatch := ef.Spec.ConfigPatches[1]
+			Expect(routePatch.ApplyTo).To(Equal(v1alpha3.EnvoyFilter_HTTP_ROUTE))
+			Expect(routePatch.Match.Context).To(Equal(v1alpha3.EnvoyFilter_GATEWAY))
+			Expect(routePatch.Match.GetRouteConfiguration().Vhost.Route.Name).To(Equal("test-route"))
+			Expect(routePatch.Patch.Operation).To(Equal(v1alpha3.EnvoyFilter_Patch_MERGE))
+			perRoute := routePatch.Patch.Value.Fields["typed_per_filter_config"].GetStructValue().Fields["envoy.filters.http.lua.test"].GetStructValue()
+			Expect(perRoute.Fields["source_code"].GetStructValue().Fields["inline_string"].GetStringValue()).To(Equal("function envoy_on_request(handle) end"))
+		})
+	})
+})
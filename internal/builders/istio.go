@@ -90,6 +90,11 @@ func (aps *AuthorizationPolicySpecBuilder) WithSelector(val *apiv1beta1.Workload
 	return aps
 }
 
+func (aps *AuthorizationPolicySpecBuilder) WithAction(val v1beta1.AuthorizationPolicy_Action) *AuthorizationPolicySpecBuilder {
+	aps.value.Action = val
+	return aps
+}
+
 func (aps *AuthorizationPolicySpecBuilder) WithRule(val *v1beta1.Rule) *AuthorizationPolicySpecBuilder {
 	aps.value.Rules = append(aps.value.Rules, val)
 	return aps
@@ -175,6 +180,13 @@ func (rf *FromBuilder) WithOathkeeperProxySource() *FromBuilder {
 	return rf
 }
 
+// WithNotRemoteIpBlocks adds NotRemoteIpBlocks, matching the requests of clients whose address is not in one of the blocks
+func (rf *FromBuilder) WithNotRemoteIpBlocks(val []string) *FromBuilder {
+	source := v1beta1.Source{NotRemoteIpBlocks: val}
+	rf.value.Source = &source
+	return rf
+}
+
 // NewToBuilder returns builder for istio.io/apis/security/v1beta1/Rule_To type
 func NewToBuilder() *ToBuilder {
 	return &ToBuilder{
@@ -351,6 +363,7 @@ func (jr *JwtRuleBuilder) From(val []*gatewayv1beta1.Authenticator) *JwtRuleBuil
 			jwtRule := v1beta1.JWTRule{
 				Issuer:  authentication.Issuer,
 				JwksUri: authentication.JwksUri,
+				Jwks:    authentication.Jwks,
 			}
 			for _, fromHeader := range authentication.FromHeaders {
 				jwtRule.FromHeaders = append(jwtRule.FromHeaders, &v1beta1.JWTHeader{
@@ -397,6 +410,7 @@ type Authentications struct {
 type Authentication struct {
 	Issuer      string       `json:"issuer"`
 	JwksUri     string       `json:"jwksUri"`
+	Jwks        string       `json:"jwks"`
 	FromHeaders []*JwtHeader `json:"fromHeaders"`
 	FromParams  []string     `json:"fromParams"`
 }
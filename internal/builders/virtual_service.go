@@ -1,9 +1,13 @@
 package builders
 
 import (
+	"fmt"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	"istio.io/api/networking/v1beta1"
 	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	"net/http"
+	"strings"
 	"time"
 )
 
@@ -51,6 +55,14 @@ func (vs *virtualService) Label(key, val string) *virtualService {
 	return vs
 }
 
+func (vs *virtualService) Annotation(key, val string) *virtualService {
+	if vs.value.Annotations == nil {
+		vs.value.Annotations = make(map[string]string)
+	}
+	vs.value.Annotations[key] = val
+	return vs
+}
+
 func (vs *virtualService) Spec(val *virtualServiceSpec) *virtualService {
 	vs.value.Spec = *val.Get()
 	return vs
@@ -86,6 +98,11 @@ func (vss *virtualServiceSpec) Gateway(val string) *virtualServiceSpec {
 	return vss
 }
 
+func (vss *virtualServiceSpec) ExportTo(val ...string) *virtualServiceSpec {
+	vss.value.ExportTo = append(vss.value.ExportTo, val...)
+	return vss
+}
+
 func (vss *virtualServiceSpec) HTTP(hr *httpRoute) *virtualServiceSpec {
 	vss.value.Http = append(vss.value.Http, hr.Get())
 	return vss
@@ -106,6 +123,17 @@ func (hr *httpRoute) Get() *v1beta1.HTTPRoute {
 	return hr.value
 }
 
+func (hr *httpRoute) From(val *v1beta1.HTTPRoute) *httpRoute {
+	hr.value = val
+	return hr
+}
+
+// Name sets the name of the route, which is shown in the access logs and config dumps of the proxies.
+func (hr *httpRoute) Name(val string) *httpRoute {
+	hr.value.Name = val
+	return hr
+}
+
 func (hr *httpRoute) Match(mr *matchRequest) *httpRoute {
 	hr.value.Match = append(hr.value.Match, mr.Get())
 	return hr
@@ -116,6 +144,34 @@ func (hr *httpRoute) Route(rd *routeDestination) *httpRoute {
 	return hr
 }
 
+func (hr *httpRoute) Mirror(host string, port uint32) *httpRoute {
+	hr.value.Mirror = &v1beta1.Destination{
+		Host: host,
+		Port: &v1beta1.PortSelector{Number: port},
+	}
+	return hr
+}
+
+func (hr *httpRoute) MirrorPercentage(val float64) *httpRoute {
+	hr.value.MirrorPercentage = &v1beta1.Percent{Value: val}
+	return hr
+}
+
+func (hr *httpRoute) Rewrite(r *httpRewrite) *httpRoute {
+	hr.value.Rewrite = r.Get()
+	return hr
+}
+
+func (hr *httpRoute) Redirect(r *httpRedirect) *httpRoute {
+	hr.value.Redirect = r.Get()
+	return hr
+}
+
+func (hr *httpRoute) DirectResponse(r *httpDirectResponse) *httpRoute {
+	hr.value.DirectResponse = r.Get()
+	return hr
+}
+
 func (hr *httpRoute) CorsPolicy(cc *corsPolicy) *httpRoute {
 	hr.value.CorsPolicy = cc.Get()
 	return hr
@@ -131,6 +187,146 @@ func (hr *httpRoute) Timeout(value time.Duration) *httpRoute {
 	return hr
 }
 
+// Retries sets the retry policy of the route. The per try timeout is only set if it is greater than zero.
+func (hr *httpRoute) Retries(attempts int, perTryTimeout time.Duration, retryOn string) *httpRoute {
+	hr.value.Retries = &v1beta1.HTTPRetry{
+		Attempts: int32(attempts),
+		RetryOn:  retryOn,
+	}
+	if perTryTimeout > 0 {
+		hr.value.Retries.PerTryTimeout = durationpb.New(perTryTimeout)
+	}
+	return hr
+}
+
+func (hr *httpRoute) Fault(f *httpFaultInjection) *httpRoute {
+	hr.value.Fault = f.Get()
+	return hr
+}
+
+// HTTPFaultInjection returns builder for istio.io/api/networking/v1beta1/HTTPFaultInjection type
+func HTTPFaultInjection() *httpFaultInjection {
+	return &httpFaultInjection{
+		value: &v1beta1.HTTPFaultInjection{},
+	}
+}
+
+type httpFaultInjection struct {
+	value *v1beta1.HTTPFaultInjection
+}
+
+func (f *httpFaultInjection) Get() *v1beta1.HTTPFaultInjection {
+	return f.value
+}
+
+// Delay delays the given percentage of the requests by the fixed delay.
+func (f *httpFaultInjection) Delay(fixedDelay time.Duration, percentage float64) *httpFaultInjection {
+	f.value.Delay = &v1beta1.HTTPFaultInjection_Delay{
+		HttpDelayType: &v1beta1.HTTPFaultInjection_Delay_FixedDelay{FixedDelay: durationpb.New(fixedDelay)},
+		Percentage:    &v1beta1.Percent{Value: percentage},
+	}
+	return f
+}
+
+// Abort aborts the given percentage of the requests with the HTTP status.
+func (f *httpFaultInjection) Abort(httpStatus int32, percentage float64) *httpFaultInjection {
+	f.value.Abort = &v1beta1.HTTPFaultInjection_Abort{
+		ErrorType:  &v1beta1.HTTPFaultInjection_Abort_HttpStatus{HttpStatus: httpStatus},
+		Percentage: &v1beta1.Percent{Value: percentage},
+	}
+	return f
+}
+
+// HTTPRedirect returns builder for istio.io/api/networking/v1beta1/HTTPRedirect type
+func HTTPRedirect() *httpRedirect {
+	return &httpRedirect{
+		value: &v1beta1.HTTPRedirect{},
+	}
+}
+
+type httpRedirect struct {
+	value *v1beta1.HTTPRedirect
+}
+
+func (r *httpRedirect) Get() *v1beta1.HTTPRedirect {
+	return r.value
+}
+
+func (r *httpRedirect) Uri(val string) *httpRedirect {
+	r.value.Uri = val
+	return r
+}
+
+func (r *httpRedirect) Scheme(val string) *httpRedirect {
+	r.value.Scheme = val
+	return r
+}
+
+func (r *httpRedirect) Authority(val string) *httpRedirect {
+	r.value.Authority = val
+	return r
+}
+
+func (r *httpRedirect) RedirectCode(val uint32) *httpRedirect {
+	r.value.RedirectCode = val
+	return r
+}
+
+// HTTPDirectResponse returns builder for istio.io/api/networking/v1beta1/HTTPDirectResponse type
+func HTTPDirectResponse() *httpDirectResponse {
+	return &httpDirectResponse{
+		value: &v1beta1.HTTPDirectResponse{},
+	}
+}
+
+type httpDirectResponse struct {
+	value *v1beta1.HTTPDirectResponse
+}
+
+func (r *httpDirectResponse) Get() *v1beta1.HTTPDirectResponse {
+	return r.value
+}
+
+func (r *httpDirectResponse) Status(val uint32) *httpDirectResponse {
+	r.value.Status = val
+	return r
+}
+
+// Body sets the inline body of the response, the response has no body if the value is empty.
+func (r *httpDirectResponse) Body(val string) *httpDirectResponse {
+	if val == "" {
+		r.value.Body = nil
+		return r
+	}
+	r.value.Body = &v1beta1.HTTPBody{Specifier: &v1beta1.HTTPBody_String_{String_: val}}
+	return r
+}
+
+// HTTPRewrite returns builder for istio.io/api/networking/v1beta1/HTTPRewrite type
+func HTTPRewrite() *httpRewrite {
+	return &httpRewrite{
+		value: &v1beta1.HTTPRewrite{},
+	}
+}
+
+type httpRewrite struct {
+	value *v1beta1.HTTPRewrite
+}
+
+func (r *httpRewrite) Get() *v1beta1.HTTPRewrite {
+	return r.value
+}
+
+func (r *httpRewrite) Uri(val string) *httpRewrite {
+	r.value.Uri = val
+	return r
+}
+
+func (r *httpRewrite) Authority(val string) *httpRewrite {
+	r.value.Authority = val
+	return r
+}
+
 // MatchRequest returns builder for istio.io/api/networking/v1beta1/HTTPMatchRequest type
 func MatchRequest() *matchRequest {
 	return &matchRequest{
@@ -146,11 +342,39 @@ func (mr *matchRequest) Get() *v1beta1.HTTPMatchRequest {
 	return mr.value
 }
 
+// Copy returns a builder for a copy of the match request, so a route can match the same requests with another URI.
+func (mr *matchRequest) Copy() *matchRequest {
+	return &matchRequest{
+		value: mr.value.DeepCopy(),
+	}
+}
+
 func (mr *matchRequest) Uri() *stringMatch {
 	mr.value.Uri = &v1beta1.StringMatch{}
 	return &stringMatch{mr.value.Uri, func() *matchRequest { return mr }}
 }
 
+func (mr *matchRequest) Headers(val map[string]*v1beta1.StringMatch) *matchRequest {
+	mr.value.Headers = val
+	return mr
+}
+
+func (mr *matchRequest) QueryParams(val map[string]*v1beta1.StringMatch) *matchRequest {
+	mr.value.QueryParams = val
+	return mr
+}
+
+// IgnoreUriCase sets whether the URI is matched case-insensitively.
+func (mr *matchRequest) IgnoreUriCase(val bool) *matchRequest {
+	mr.value.IgnoreUriCase = val
+	return mr
+}
+
+func (mr *matchRequest) Method() *stringMatch {
+	mr.value.Method = &v1beta1.StringMatch{}
+	return &stringMatch{mr.value.Method, func() *matchRequest { return mr }}
+}
+
 type stringMatch struct {
 	value  *v1beta1.StringMatch
 	parent func() *matchRequest
@@ -166,6 +390,11 @@ func (st *stringMatch) Prefix(val string) *matchRequest {
 	return st.parent()
 }
 
+func (st *stringMatch) Exact(val string) *matchRequest {
+	st.value.MatchType = &v1beta1.StringMatch_Exact{Exact: val}
+	return st.parent()
+}
+
 // RouteDestination returns builder for istio.io/api/networking/v1beta1/HTTPRouteDestination type
 func RouteDestination() *routeDestination {
 	return &routeDestination{&v1beta1.HTTPRouteDestination{
@@ -194,6 +423,16 @@ func (rd *routeDestination) Port(val uint32) *routeDestination {
 	return rd
 }
 
+func (rd *routeDestination) Subset(val string) *routeDestination {
+	rd.value.Destination.Subset = val
+	return rd
+}
+
+func (rd *routeDestination) Weight(val int32) *routeDestination {
+	rd.value.Weight = val
+	return rd
+}
+
 // CorsPolicy returns builder for istio.io/api/networking/v1beta1/CorsPolicy type
 func CorsPolicy() *corsPolicy {
 	return &corsPolicy{
@@ -209,24 +448,40 @@ func (cp *corsPolicy) Get() *v1beta1.CorsPolicy {
 	return cp.value
 }
 
+// AllowHeaders adds the headers that are allowed for CORS requests. The wildcard * allows all headers, so any other
+// header is redundant and the wildcard is set as the only value.
 func (cp *corsPolicy) AllowHeaders(val ...string) *corsPolicy {
 	if len(val) == 0 {
 		cp.value.AllowHeaders = nil
 	} else {
-		cp.value.AllowHeaders = append(cp.value.AllowHeaders, val...)
+		cp.value.AllowHeaders = appendWithWildcard(cp.value.AllowHeaders, val...)
 	}
 	return cp
 }
 
+// AllowMethods adds the methods that are allowed for CORS requests. The wildcard * allows all methods, so any other
+// method is redundant and the wildcard is set as the only value.
 func (cp *corsPolicy) AllowMethods(val ...string) *corsPolicy {
 	if len(val) == 0 {
 		cp.value.AllowMethods = nil
 	} else {
-		cp.value.AllowMethods = append(cp.value.AllowMethods, val...)
+		cp.value.AllowMethods = appendWithWildcard(cp.value.AllowMethods, val...)
 	}
 	return cp
 }
 
+// appendWithWildcard appends the values to the list, unless the list or the values contain the wildcard *. Envoy returns
+// the list as is in the preflight response, where the wildcard is only interpreted by the browser if it's the only value.
+func appendWithWildcard(list []string, val ...string) []string {
+	combined := append(list, val...)
+	for _, v := range combined {
+		if v == "*" {
+			return []string{"*"}
+		}
+	}
+	return combined
+}
+
 func (cp *corsPolicy) AllowOrigins(val ...*v1beta1.StringMatch) *corsPolicy {
 	if len(val) == 0 {
 		cp.value.AllowOrigins = nil
@@ -236,6 +491,35 @@ func (cp *corsPolicy) AllowOrigins(val ...*v1beta1.StringMatch) *corsPolicy {
 	return cp
 }
 
+func (cp *corsPolicy) ExposeHeaders(val ...string) *corsPolicy {
+	if len(val) == 0 {
+		cp.value.ExposeHeaders = nil
+	} else {
+		cp.value.ExposeHeaders = append(cp.value.ExposeHeaders, val...)
+	}
+	return cp
+}
+
+// AllowCredentials sets whether credentials are allowed for CORS requests. The field is only set if credentials are allowed.
+func (cp *corsPolicy) AllowCredentials(val bool) *corsPolicy {
+	if val {
+		cp.value.AllowCredentials = wrapperspb.Bool(true)
+	} else {
+		cp.value.AllowCredentials = nil
+	}
+	return cp
+}
+
+// MaxAge sets how long the results of a preflight request can be cached. The field is only set if it is greater than zero.
+func (cp *corsPolicy) MaxAge(val time.Duration) *corsPolicy {
+	if val > 0 {
+		cp.value.MaxAge = durationpb.New(val)
+	} else {
+		cp.value.MaxAge = nil
+	}
+	return cp
+}
+
 // NewHttpRouteHeadersBuilder returns builder for istio.io/api/networking/v1beta1/Headers type
 func NewHttpRouteHeadersBuilder() HttpRouteHeadersBuilder {
 	return HttpRouteHeadersBuilder{
@@ -260,6 +544,120 @@ func (h HttpRouteHeadersBuilder) SetHostHeader(hostname string) HttpRouteHeaders
 	return h
 }
 
+// SetHostHeaderFromAuthority sets the x-forwarded-host header to the authority of the request, which is resolved by
+// Envoy when the request is forwarded. This is used for routes that serve multiple hosts.
+func (h HttpRouteHeadersBuilder) SetHostHeaderFromAuthority() HttpRouteHeadersBuilder {
+	h.value.Request.Set["x-forwarded-host"] = "%REQ(:AUTHORITY)%"
+	return h
+}
+
+// SetForwardedHeaders appends the address of the client to the x-forwarded-for header and sets the x-forwarded-proto
+// header to the scheme of the request. The values are resolved by Envoy when the request is forwarded.
+func (h HttpRouteHeadersBuilder) SetForwardedHeaders() HttpRouteHeadersBuilder {
+	h.AddRequestHeaders(map[string]string{"x-forwarded-for": "%DOWNSTREAM_REMOTE_ADDRESS_WITHOUT_PORT%"})
+	h.value.Request.Set["x-forwarded-proto"] = "%REQ(:SCHEME)%"
+	return h
+}
+
+// AddRequestHeaders appends the values to the request headers and expects a map of the form "header-name1": "header-value1", "header-name2": "header-value2", ...
+func (h HttpRouteHeadersBuilder) AddRequestHeaders(headers map[string]string) HttpRouteHeadersBuilder {
+	if h.value.Request.Add == nil && len(headers) > 0 {
+		h.value.Request.Add = make(map[string]string)
+	}
+	for name, value := range headers {
+		h.value.Request.Add[name] = value
+	}
+
+	return h
+}
+
+// RemoveRequestHeaders removes the request headers with the given names
+func (h HttpRouteHeadersBuilder) RemoveRequestHeaders(names ...string) HttpRouteHeadersBuilder {
+	h.value.Request.Remove = append(h.value.Request.Remove, names...)
+	return h
+}
+
+// SetResponseHeaders sets the response headers and expects a map of the form "header-name1": "header-value1", "header-name2": "header-value2", ...
+func (h HttpRouteHeadersBuilder) SetResponseHeaders(headers map[string]string) HttpRouteHeadersBuilder {
+	response := h.responseHeaderOperations()
+	if response.Set == nil && len(headers) > 0 {
+		response.Set = make(map[string]string)
+	}
+	for name, value := range headers {
+		response.Set[name] = value
+	}
+
+	return h
+}
+
+// AddResponseHeaders appends the values to the response headers and expects a map of the form "header-name1": "header-value1", "header-name2": "header-value2", ...
+func (h HttpRouteHeadersBuilder) AddResponseHeaders(headers map[string]string) HttpRouteHeadersBuilder {
+	response := h.responseHeaderOperations()
+	if response.Add == nil && len(headers) > 0 {
+		response.Add = make(map[string]string)
+	}
+	for name, value := range headers {
+		response.Add[name] = value
+	}
+
+	return h
+}
+
+// RemoveResponseHeaders removes the response headers with the given names
+func (h HttpRouteHeadersBuilder) RemoveResponseHeaders(names ...string) HttpRouteHeadersBuilder {
+	response := h.responseHeaderOperations()
+	response.Remove = append(response.Remove, names...)
+	return h
+}
+
+// SetDeprecationHeaders sets the deprecation response header to the given date, or to true if no date is given, and the
+// sunset response header to the given sunset date if it is given.
+func (h HttpRouteHeadersBuilder) SetDeprecationHeaders(since *time.Time, sunset *time.Time) HttpRouteHeadersBuilder {
+	headers := map[string]string{"deprecation": "true"}
+	if since != nil {
+		// The deprecation header is a structured field date, which is the number of seconds since the epoch.
+		headers["deprecation"] = fmt.Sprintf("@%d", since.Unix())
+	}
+	if sunset != nil {
+		headers["sunset"] = sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return h.SetResponseHeaders(headers)
+}
+
+// PreserveRequestHeaders drops all request header operations for the headers with the given names, so they are forwarded
+// unchanged. Header names are compared case-insensitively and the operations added afterwards are not dropped.
+func (h HttpRouteHeadersBuilder) PreserveRequestHeaders(names ...string) HttpRouteHeadersBuilder {
+	for _, name := range names {
+		for header := range h.value.Request.Set {
+			if strings.EqualFold(header, name) {
+				delete(h.value.Request.Set, header)
+			}
+		}
+		for header := range h.value.Request.Add {
+			if strings.EqualFold(header, name) {
+				delete(h.value.Request.Add, header)
+			}
+		}
+		remove := h.value.Request.Remove[:0]
+		for _, header := range h.value.Request.Remove {
+			if !strings.EqualFold(header, name) {
+				remove = append(remove, header)
+			}
+		}
+		h.value.Request.Remove = remove
+	}
+
+	return h
+}
+
+func (h HttpRouteHeadersBuilder) responseHeaderOperations() *v1beta1.Headers_HeaderOperations {
+	if h.value.Response == nil {
+		h.value.Response = &v1beta1.Headers_HeaderOperations{}
+	}
+	return h.value.Response
+}
+
 // SetRequestCookies sets the Cookie header and expects a string of the form "cookie-name1=cookie-value1; cookie-name2=cookie-value2; ..."
 func (h HttpRouteHeadersBuilder) SetRequestCookies(cookies string) HttpRouteHeadersBuilder {
 	h.value.Request.Set["Cookie"] = cookies
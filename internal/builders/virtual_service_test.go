@@ -5,6 +5,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"istio.io/api/networking/v1beta1"
 	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 	"time"
 )
@@ -60,6 +61,7 @@ var _ = Describe("Builder for", func() {
 				Gateway(gateway).
 				Gateway(gateway2).
 				HTTP(HTTPRoute().
+					Name("route-0").
 					Match(MatchRequest().Uri().Regex(matchURIRegex)).
 					Match(MatchRequest().Uri().Regex(matchURIRegex2)).
 					Headers(NewHttpRouteHeadersBuilder().SetHostHeader(host).Get()).
@@ -82,6 +84,9 @@ var _ = Describe("Builder for", func() {
 			//Two HTTPRoute elements
 			Expect(result.Http).To(HaveLen(2))
 
+			Expect(result.Http[0].Name).To(Equal("route-0"))
+			Expect(result.Http[1].Name).To(BeEmpty())
+
 			//Two HTTPMatchRequest elements
 			Expect(result.Http[0].Match).To(HaveLen(2))
 			Expect(result.Http[0].Match[0].Uri.GetRegex()).To(Equal(matchURIRegex))
@@ -106,4 +111,236 @@ var _ = Describe("Builder for", func() {
 			Expect(result.Http[1].Route[0].Weight).To(Equal(int32(100)))
 		})
 	})
+
+	Describe("MatchRequest", func() {
+		It("should build the URI matches", func() {
+			Expect(MatchRequest().Uri().Regex("/a.*").Get().Uri.GetRegex()).To(Equal("/a.*"))
+			Expect(MatchRequest().Uri().Prefix("/a").Get().Uri.GetPrefix()).To(Equal("/a"))
+			Expect(MatchRequest().Uri().Exact("/a").Get().Uri.GetExact()).To(Equal("/a"))
+		})
+
+		It("should build the header matches together with the URI match", func() {
+			headers := map[string]*v1beta1.StringMatch{
+				"x-api-version": {MatchType: &v1beta1.StringMatch_Exact{Exact: "v2"}},
+			}
+
+			result := MatchRequest().Uri().Prefix("/a").Headers(headers).Get()
+
+			Expect(result.Uri.GetPrefix()).To(Equal("/a"))
+			Expect(result.Headers).To(Equal(headers))
+		})
+
+		It("should build the query parameter matches together with the URI match", func() {
+			queryParams := map[string]*v1beta1.StringMatch{
+				"version": {MatchType: &v1beta1.StringMatch_Exact{Exact: "2"}},
+			}
+
+			result := MatchRequest().Uri().Prefix("/a").QueryParams(queryParams).Get()
+
+			Expect(result.Uri.GetPrefix()).To(Equal("/a"))
+			Expect(result.QueryParams).To(Equal(queryParams))
+		})
+
+		It("should build the method match together with the URI match", func() {
+			result := MatchRequest().Uri().Prefix("/a").Method().Regex("GET|POST").Get()
+
+			Expect(result.Uri.GetPrefix()).To(Equal("/a"))
+			Expect(result.Method.GetRegex()).To(Equal("GET|POST"))
+		})
+
+		It("should build the URI match ignoring the case", func() {
+			Expect(MatchRequest().Uri().Prefix("/a").Get().IgnoreUriCase).To(BeFalse())
+			Expect(MatchRequest().Uri().Prefix("/a").IgnoreUriCase(true).Get().IgnoreUriCase).To(BeTrue())
+		})
+	})
+
+	Describe("HTTPRedirect", func() {
+		It("should build the redirect", func() {
+			result := HTTPRoute().Redirect(HTTPRedirect().Uri("/new").Scheme("https").Authority("example.com").RedirectCode(301)).Get()
+
+			Expect(result.Redirect.Uri).To(Equal("/new"))
+			Expect(result.Redirect.Scheme).To(Equal("https"))
+			Expect(result.Redirect.Authority).To(Equal("example.com"))
+			Expect(result.Redirect.RedirectCode).To(Equal(uint32(301)))
+		})
+	})
+
+	Describe("HTTPDirectResponse", func() {
+		It("should build the direct response", func() {
+			result := HTTPRoute().DirectResponse(HTTPDirectResponse().Status(503).Body("maintenance")).Get()
+
+			Expect(result.DirectResponse.Status).To(Equal(uint32(503)))
+			Expect(result.DirectResponse.Body.GetString_()).To(Equal("maintenance"))
+		})
+
+		It("should build the direct response without body", func() {
+			result := HTTPDirectResponse().Status(204).Body("").Get()
+
+			Expect(result.Status).To(Equal(uint32(204)))
+			Expect(result.Body).To(BeNil())
+		})
+	})
+
+	Describe("HTTPRewrite", func() {
+		It("should build the rewrite", func() {
+			result := HTTPRoute().Rewrite(HTTPRewrite().Uri("/foo").Authority("example.com")).Get()
+
+			Expect(result.Rewrite.Uri).To(Equal("/foo"))
+			Expect(result.Rewrite.Authority).To(Equal("example.com"))
+		})
+	})
+
+	Describe("HTTPFaultInjection", func() {
+		It("should build the delay and abort", func() {
+			result := HTTPRoute().Fault(HTTPFaultInjection().Delay(5*time.Second, 10).Abort(503, 20)).Get()
+
+			Expect(result.Fault.Delay.GetFixedDelay()).To(Equal(durationpb.New(5 * time.Second)))
+			Expect(result.Fault.Delay.Percentage.Value).To(Equal(float64(10)))
+			Expect(result.Fault.Abort.GetHttpStatus()).To(Equal(int32(503)))
+			Expect(result.Fault.Abort.Percentage.Value).To(Equal(float64(20)))
+		})
+	})
+
+	Describe("HttpRouteHeaders", func() {
+		It("should set the forwarded host header to the authority of the request", func() {
+			result := NewHttpRouteHeadersBuilder().
+				SetHostHeaderFromAuthority().
+				Get()
+
+			Expect(result.Request.Set).To(Equal(map[string]string{"x-forwarded-host": "%REQ(:AUTHORITY)%"}))
+		})
+
+		It("should build the request and response header operations", func() {
+			result := NewHttpRouteHeadersBuilder().
+				SetHostHeader("example.com").
+				AddRequestHeaders(map[string]string{"x-request": "value"}).
+				RemoveRequestHeaders("x-internal").
+				SetResponseHeaders(map[string]string{"x-frame-options": "DENY"}).
+				AddResponseHeaders(map[string]string{"x-response": "value"}).
+				RemoveResponseHeaders("server").
+				Get()
+
+			Expect(result.Request.Set).To(Equal(map[string]string{"x-forwarded-host": "example.com"}))
+			Expect(result.Request.Add).To(Equal(map[string]string{"x-request": "value"}))
+			Expect(result.Request.Remove).To(Equal([]string{"x-internal"}))
+			Expect(result.Response.Set).To(Equal(map[string]string{"x-frame-options": "DENY"}))
+			Expect(result.Response.Add).To(Equal(map[string]string{"x-response": "value"}))
+			Expect(result.Response.Remove).To(Equal([]string{"server"}))
+		})
+
+		It("should not set the response header operations by default", func() {
+			result := NewHttpRouteHeadersBuilder().AddRequestHeaders(nil).Get()
+
+			Expect(result.Request.Add).To(BeNil())
+			Expect(result.Response).To(BeNil())
+		})
+
+		It("should drop the request header operations of the preserved headers", func() {
+			result := NewHttpRouteHeadersBuilder().
+				SetRequestHeaders(map[string]string{"Traceparent": "value", "x-request": "value"}).
+				AddRequestHeaders(map[string]string{"b3": "value"}).
+				RemoveRequestHeaders("traceparent", "x-internal").
+				PreserveRequestHeaders("traceparent", "b3").
+				Get()
+
+			Expect(result.Request.Set).To(Equal(map[string]string{"x-request": "value"}))
+			Expect(result.Request.Add).To(BeEmpty())
+			Expect(result.Request.Remove).To(Equal([]string{"x-internal"}))
+		})
+
+		It("should set the deprecation and sunset response headers", func() {
+			since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			sunset := time.Date(2024, 6, 30, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+			result := NewHttpRouteHeadersBuilder().SetDeprecationHeaders(&since, &sunset).Get()
+
+			Expect(result.Response.Set).To(Equal(map[string]string{
+				"deprecation": "@1704067200",
+				"sunset":      "Sun, 30 Jun 2024 10:00:00 GMT",
+			}))
+		})
+
+		It("should set the deprecation response header to true without a date", func() {
+			result := NewHttpRouteHeadersBuilder().SetDeprecationHeaders(nil, nil).Get()
+
+			Expect(result.Response.Set).To(Equal(map[string]string{"deprecation": "true"}))
+		})
+
+		It("should append the client address and set the scheme for the forwarded headers", func() {
+			result := NewHttpRouteHeadersBuilder().
+				AddRequestHeaders(map[string]string{"x-request": "value"}).
+				SetForwardedHeaders().
+				Get()
+
+			Expect(result.Request.Set).To(Equal(map[string]string{"x-forwarded-proto": "%REQ(:SCHEME)%"}))
+			Expect(result.Request.Add).To(Equal(map[string]string{
+				"x-request":       "value",
+				"x-forwarded-for": "%DOWNSTREAM_REMOTE_ADDRESS_WITHOUT_PORT%",
+			}))
+		})
+	})
+
+	Describe("CorsPolicy", func() {
+		It("should build the policy", func() {
+			result := CorsPolicy().
+				AllowHeaders("header1").
+				ExposeHeaders("header2").
+				AllowCredentials(true).
+				MaxAge(time.Minute).
+				Get()
+
+			Expect(result.AllowHeaders).To(Equal([]string{"header1"}))
+			Expect(result.ExposeHeaders).To(Equal([]string{"header2"}))
+			Expect(result.AllowCredentials.GetValue()).To(BeTrue())
+			Expect(result.MaxAge).To(Equal(durationpb.New(time.Minute)))
+		})
+
+		DescribeTable("should set the wildcard as the only allowed header and method",
+			func(values []string, additionalValues []string, expected []string) {
+				policy := CorsPolicy().AllowHeaders(values...).AllowMethods(values...)
+				if len(additionalValues) > 0 {
+					policy.AllowHeaders(additionalValues...).AllowMethods(additionalValues...)
+				}
+				result := policy.Get()
+
+				Expect(result.AllowHeaders).To(Equal(expected))
+				Expect(result.AllowMethods).To(Equal(expected))
+			},
+			Entry("without wildcard", []string{"GET", "POST"}, []string{"PUT"}, []string{"GET", "POST", "PUT"}),
+			Entry("only wildcard", []string{"*"}, nil, []string{"*"}),
+			Entry("wildcard with other values", []string{"GET", "*"}, nil, []string{"*"}),
+			Entry("wildcard added later", []string{"GET"}, []string{"*"}, []string{"*"}),
+			Entry("values added after wildcard", []string{"*"}, []string{"GET"}, []string{"*"}),
+		)
+
+		It("should not set credentials and max age by default", func() {
+			result := CorsPolicy().AllowCredentials(false).MaxAge(0).Get()
+
+			Expect(result.AllowCredentials).To(BeNil())
+			Expect(result.MaxAge).To(BeNil())
+		})
+	})
+
+	Describe("HTTPRoute", func() {
+		It("should not set retries by default", func() {
+			result := HTTPRoute().Get()
+
+			Expect(result.Retries).To(BeNil())
+		})
+
+		It("should set retries", func() {
+			result := HTTPRoute().Retries(3, time.Second*2, "5xx,connect-failure").Get()
+
+			Expect(result.Retries.Attempts).To(Equal(int32(3)))
+			Expect(result.Retries.PerTryTimeout).To(Equal(durationpb.New(time.Second * 2)))
+			Expect(result.Retries.RetryOn).To(Equal("5xx,connect-failure"))
+		})
+
+		It("should not set per try timeout when it is zero", func() {
+			result := HTTPRoute().Retries(3, 0, "").Get()
+
+			Expect(result.Retries.Attempts).To(Equal(int32(3)))
+			Expect(result.Retries.PerTryTimeout).To(BeNil())
+		})
+	})
 })
@@ -0,0 +1,24 @@
+package helpers
+
+import (
+	"encoding/json"
+)
+
+// AggregatedLabel is set on the VirtualServices that contain the routes of all APIRules in a namespace that share the
+// hosts and gateways. These VirtualServices don't have an owner label, since they are owned by multiple APIRules.
+const AggregatedLabel = "gateway.kyma-project.io/aggregated"
+
+// OwnersAnnotation is set on the aggregated VirtualServices and maps the owner label value of each APIRule to the names
+// of its routes, e.g. {"httpbin.default":["httpbin-0-headers"]}, so the routes of a deleted APIRule can be removed.
+const OwnersAnnotation = "gateway.kyma-project.io/owners"
+
+// GetOwners returns the route names per owner that are listed in the OwnersAnnotation.
+func GetOwners(annotations map[string]string) map[string][]string {
+	owners := make(map[string][]string)
+	if value, ok := annotations[OwnersAnnotation]; ok {
+		// An invalid annotation is treated like a missing one, since it is overwritten in the next reconciliation.
+		_ = json.Unmarshal([]byte(value), &owners)
+	}
+
+	return owners
+}
@@ -5,6 +5,15 @@ import (
 	"strings"
 )
 
+// WildcardHostPrefix is the leftmost label of the wildcard hosts, e.g. *.apps.example.com, which match all subdomains
+// of the domain that follows.
+const WildcardHostPrefix = "*."
+
+// IsWildcardHost returns true if the host matches all subdomains of a domain.
+func IsWildcardHost(host string) bool {
+	return strings.HasPrefix(host, WildcardHostPrefix)
+}
+
 func GetHostWithDomain(host, defaultDomainName string) string {
 	if !HostIncludesDomain(host) {
 		return GetHostWithDefaultDomain(host, defaultDomainName)
@@ -12,8 +21,18 @@ func GetHostWithDomain(host, defaultDomainName string) string {
 	return host
 }
 
+// ResolveHostWithDomain returns the host with the default domain appended if the host does not include a domain. In strict
+// mode an error is returned instead if the host does not include a domain and no default domain is configured.
+func ResolveHostWithDomain(host, defaultDomainName string, strict bool) (string, error) {
+	if strict && !HostIncludesDomain(host) && defaultDomainName == "" {
+		return "", fmt.Errorf("host %s is not fully qualified and no default domain is configured", host)
+	}
+	return GetHostWithDomain(host, defaultDomainName), nil
+}
+
 func HostIncludesDomain(host string) bool {
-	return strings.Contains(host, ".")
+	// The wildcard label is not part of the domain, so *.apps is completed with the default domain like apps.
+	return strings.Contains(strings.TrimPrefix(host, WildcardHostPrefix), ".")
 }
 
 func GetHostWithDefaultDomain(host, defaultDomainName string) string {
@@ -0,0 +1,38 @@
+package helpers
+
+import (
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+)
+
+// MeshGateway is the reserved gateway name that applies the routes to all sidecars in the mesh instead of an ingress gateway.
+const MeshGateway = "mesh"
+
+// GetGateways returns the gateway of the APIRule followed by the additional gateways without duplicates. The mesh
+// gateway is returned as the gateway of an APIRule without a gateway.
+func GetGateways(api *gatewayv1beta1.APIRule) []string {
+	gateway := MeshGateway
+	if HasGateway(api) {
+		gateway = *api.Spec.Gateway
+	}
+
+	gateways := []string{gateway}
+	seen := map[string]bool{gateway: true}
+	for _, gateway := range api.Spec.Gateways {
+		if !seen[gateway] {
+			gateways = append(gateways, gateway)
+			seen[gateway] = true
+		}
+	}
+
+	return gateways
+}
+
+// IsMeshInternal returns true if the APIRule is only exposed within the mesh and not on an ingress gateway.
+func IsMeshInternal(api *gatewayv1beta1.APIRule) bool {
+	return !HasGateway(api) || *api.Spec.Gateway == MeshGateway
+}
+
+// HasGateway returns true if the gateway of the APIRule is defined.
+func HasGateway(api *gatewayv1beta1.APIRule) bool {
+	return api.Spec.Gateway != nil && *api.Spec.Gateway != ""
+}
@@ -0,0 +1,26 @@
+package helpers
+
+import (
+	"strings"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+)
+
+// GetHosts returns all hosts on which the APIRule is exposed. The spec level host is always the first entry, followed by the additional hosts.
+// The hosts are normalized, see NormalizeHost.
+func GetHosts(api *gatewayv1beta1.APIRule) []string {
+	var hosts []string
+	if api.Spec.Host != nil {
+		hosts = append(hosts, NormalizeHost(*api.Spec.Host))
+	}
+	for _, host := range api.Spec.Hosts {
+		hosts = append(hosts, NormalizeHost(host))
+	}
+	return hosts
+}
+
+// NormalizeHost returns the host in lower case and without the trailing dot of a fully qualified domain name, since
+// hosts are matched case-insensitively and without the trailing dot.
+func NormalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
@@ -14,3 +14,8 @@ func FindServiceNamespace(api *gatewayv1beta1.APIRule, rule *gatewayv1beta1.Rule
 	}
 	return api.Namespace
 }
+
+func FindDestinationNamespace(api *gatewayv1beta1.APIRule, destination *gatewayv1beta1.Service) string {
+	// Fallback direction for the destination namespace: Destination > Spec.Service > APIRule
+	return FindServiceNamespace(api, &gatewayv1beta1.Rule{Service: destination})
+}
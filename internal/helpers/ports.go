@@ -0,0 +1,34 @@
+package helpers
+
+import (
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+)
+
+// GetRulePorts returns the ports of the services the requests of the rule are routed to, and false if the port of the
+// service is referenced by its name, since the port is only known after the port name is resolved.
+func GetRulePorts(api *gatewayv1beta1.APIRule, rule *gatewayv1beta1.Rule) (map[uint32]bool, bool) {
+	ports := make(map[uint32]bool)
+	for _, destination := range rule.Destinations {
+		if destination.Port != nil {
+			ports[*destination.Port] = true
+		}
+	}
+	for _, protocolPort := range rule.ProtocolPorts {
+		ports[protocolPort.Port] = true
+	}
+	if len(rule.Destinations) > 0 || len(rule.ProtocolPorts) > 0 {
+		return ports, true
+	}
+
+	// Fallback direction for the upstream service: Rule.Service > Spec.Service
+	service := rule.Service
+	if service == nil {
+		service = api.Spec.Service
+	}
+	if service == nil || service.Port == nil {
+		return ports, false
+	}
+	ports[*service.Port] = true
+
+	return ports, true
+}
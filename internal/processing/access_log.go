@@ -0,0 +1,56 @@
+package processing
+
+import (
+	"encoding/json"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+)
+
+// AccessLogDisabledPathsAnnotation is set on the VirtualService if the access log is disabled for at least one rule.
+// Since the VirtualService has no field to configure the access log, the telemetry configuration (e.g. an Istio Telemetry
+// resource) is expected to read this annotation. The value is a JSON array of the paths of these rules, e.g. ["/healthz"].
+const AccessLogDisabledPathsAnnotation = "gateway.kyma-project.io/access-log-disabled-paths"
+
+// GetAccessLogDisabledPathsAnnotation returns the value of the AccessLogDisabledPathsAnnotation for the rules of the APIRule.
+// An empty value means that the access log is not disabled for any rule and the annotation should not be set.
+func GetAccessLogDisabledPathsAnnotation(api *gatewayv1beta1.APIRule) string {
+	var paths []string
+	for _, rule := range FilterDuplicatePaths(api.Spec.Rules) {
+		if rule.DisableAccessLog {
+			paths = append(paths, rule.Path)
+		}
+	}
+
+	if len(paths) == 0 {
+		return ""
+	}
+
+	value, _ := json.Marshal(paths)
+	return string(value)
+}
+
+// MergeAccessLogDisabledPathsAnnotations returns the value of the AccessLogDisabledPathsAnnotation with the paths of all
+// given values, which is used for the VirtualServices that aggregate the routes of multiple APIRules.
+func MergeAccessLogDisabledPathsAnnotations(values ...string) string {
+	var merged []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		var paths []string
+		if err := json.Unmarshal([]byte(value), &paths); err != nil {
+			continue
+		}
+		for _, path := range paths {
+			if !seen[path] {
+				merged = append(merged, path)
+				seen[path] = true
+			}
+		}
+	}
+
+	if len(merged) == 0 {
+		return ""
+	}
+
+	value, _ := json.Marshal(merged)
+	return string(value)
+}
@@ -0,0 +1,82 @@
+package processing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/helpers"
+	"istio.io/api/networking/v1beta1"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	"k8s.io/utils/strings/slices"
+)
+
+// aggregatedNameHostLength is the length of the part of the first host in the names of the aggregated VirtualServices.
+const aggregatedNameHostLength = 40
+
+// SharesVirtualService returns true if the routes of both APIRules are aggregated into the same VirtualService, which
+// is the case for APIRules in the same namespace with the same hosts and gateways.
+func SharesVirtualService(api *gatewayv1beta1.APIRule, other *gatewayv1beta1.APIRule) bool {
+	return api.Namespace == other.Namespace &&
+		slices.Equal(helpers.GetHosts(api), helpers.GetHosts(other)) &&
+		slices.Equal(helpers.GetGateways(api), helpers.GetGateways(other))
+}
+
+// GetAggregatedVirtualServiceName returns the deterministic name of the VirtualService that aggregates the routes of the
+// APIRules sharing the hosts and gateways of the given APIRule. The name starts with the first host to identify it
+// more easily, and a hash of the hosts and gateways keeps the names of different hosts and gateways distinct.
+func GetAggregatedVirtualServiceName(api *gatewayv1beta1.APIRule) string {
+	key := strings.Join(helpers.GetHosts(api), ",") + "|" + strings.Join(helpers.GetGateways(api), ",")
+	hash := sha256.Sum256([]byte(key))
+
+	host := ""
+	if hosts := helpers.GetHosts(api); len(hosts) > 0 {
+		host = strings.Trim(routeNameInvalidChars.ReplaceAllString(strings.ToLower(hosts[0]), "-"), "-")
+	}
+	if len(host) > aggregatedNameHostLength {
+		host = strings.TrimRight(host[:aggregatedNameHostLength], "-")
+	}
+	if host == "" {
+		host = "apirules"
+	}
+
+	return host + "-" + hex.EncodeToString(hash[:])[:generateNameHashLength]
+}
+
+// GetOwnersAnnotation returns the value of the helpers.OwnersAnnotation for the given route names per owner.
+func GetOwnersAnnotation(owners map[string][]string) string {
+	value, _ := json.Marshal(owners)
+	return string(value)
+}
+
+// RemoveOwner removes the owner and its routes from the aggregated VirtualService. It returns false if no other owner
+// is left, so the VirtualService can be deleted.
+func RemoveOwner(vs *networkingv1beta1.VirtualService, owner string) bool {
+	removed := make(map[string]bool)
+	remaining := make(map[string][]string)
+	for o, names := range helpers.GetOwners(vs.Annotations) {
+		if o != owner {
+			remaining[o] = names
+			continue
+		}
+		for _, name := range names {
+			removed[name] = true
+		}
+	}
+
+	var routes []*v1beta1.HTTPRoute
+	for _, route := range vs.Spec.Http {
+		if !removed[route.Name] {
+			routes = append(routes, route)
+		}
+	}
+	vs.Spec.Http = routes
+	if vs.Annotations == nil {
+		vs.Annotations = make(map[string]string)
+	}
+	vs.Annotations[helpers.OwnersAnnotation] = GetOwnersAnnotation(remaining)
+
+	return len(remaining) > 0
+}
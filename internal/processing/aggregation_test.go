@@ -0,0 +1,102 @@
+package processing
+
+import (
+	"strings"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"istio.io/api/networking/v1beta1"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func apiRuleWithHost(name string, namespace string, host string, gateway string) *gatewayv1beta1.APIRule {
+	return &gatewayv1beta1.APIRule{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       gatewayv1beta1.APIRuleSpec{Host: &host, Gateway: &gateway},
+	}
+}
+
+var _ = Describe("SharesVirtualService", func() {
+	It("should share the VirtualService of APIRules in the same namespace with the same hosts and gateways", func() {
+		api := apiRuleWithHost("first", "default", "example.com", "kyma-system/kyma-gateway")
+
+		Expect(SharesVirtualService(api, apiRuleWithHost("second", "default", "Example.com.", "kyma-system/kyma-gateway"))).To(BeTrue())
+		Expect(SharesVirtualService(api, apiRuleWithHost("second", "other", "example.com", "kyma-system/kyma-gateway"))).To(BeFalse())
+		Expect(SharesVirtualService(api, apiRuleWithHost("second", "default", "other.example.com", "kyma-system/kyma-gateway"))).To(BeFalse())
+		Expect(SharesVirtualService(api, apiRuleWithHost("second", "default", "example.com", "mesh"))).To(BeFalse())
+	})
+})
+
+var _ = Describe("GetAggregatedVirtualServiceName", func() {
+	It("should start with the host and end with a hash of the hosts and gateways", func() {
+		name := GetAggregatedVirtualServiceName(apiRuleWithHost("first", "default", "httpbin.example.com", "kyma-system/kyma-gateway"))
+
+		Expect(name).To(HavePrefix("httpbin-example-com-"))
+		Expect(name).To(HaveLen(len("httpbin-example-com-") + generateNameHashLength))
+		Expect(GetAggregatedVirtualServiceName(apiRuleWithHost("second", "default", "httpbin.example.com", "kyma-system/kyma-gateway"))).To(Equal(name))
+		Expect(GetAggregatedVirtualServiceName(apiRuleWithHost("first", "default", "httpbin.example.com", "mesh"))).ToNot(Equal(name))
+	})
+
+	It("should truncate a long host", func() {
+		name := GetAggregatedVirtualServiceName(apiRuleWithHost("first", "default", strings.Repeat("a", 39)+".example.com", "kyma-system/kyma-gateway"))
+
+		Expect(name).To(HavePrefix(strings.Repeat("a", 39) + "-"))
+		Expect(name).ToNot(HavePrefix(strings.Repeat("a", 39) + "--"))
+		Expect(name).To(HaveLen(39 + 1 + generateNameHashLength))
+	})
+})
+
+var _ = Describe("RemoveOwner", func() {
+	newAggregatedVirtualService := func(owners map[string][]string, routeNames ...string) *networkingv1beta1.VirtualService {
+		vs := &networkingv1beta1.VirtualService{}
+		vs.Annotations = map[string]string{helpers.OwnersAnnotation: GetOwnersAnnotation(owners)}
+		for _, name := range routeNames {
+			vs.Spec.Http = append(vs.Spec.Http, &v1beta1.HTTPRoute{Name: name})
+		}
+		return vs
+	}
+
+	It("should remove the owner and its routes and keep the other owners", func() {
+		// given
+		vs := newAggregatedVirtualService(map[string][]string{
+			"first.default":  {"first-0-img"},
+			"second.default": {"second-0-headers"},
+		}, "first-0-img", "second-0-headers")
+
+		// when
+		remaining := RemoveOwner(vs, "first.default")
+
+		// then
+		Expect(remaining).To(BeTrue())
+		Expect(vs.Spec.Http).To(HaveLen(1))
+		Expect(vs.Spec.Http[0].Name).To(Equal("second-0-headers"))
+		Expect(helpers.GetOwners(vs.Annotations)).To(Equal(map[string][]string{"second.default": {"second-0-headers"}}))
+	})
+
+	It("should return false if no owner is left", func() {
+		// given
+		vs := newAggregatedVirtualService(map[string][]string{"first.default": {"first-0-img"}}, "first-0-img")
+
+		// when
+		remaining := RemoveOwner(vs, "first.default")
+
+		// then
+		Expect(remaining).To(BeFalse())
+		Expect(vs.Spec.Http).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Merge annotations of aggregated VirtualServices", func() {
+	It("should merge the access log disabled paths without duplicates", func() {
+		Expect(MergeAccessLogDisabledPathsAnnotations(`["/healthz"]`, "", `["/healthz","/metrics"]`)).To(Equal(`["/healthz","/metrics"]`))
+		Expect(MergeAccessLogDisabledPathsAnnotations("", "")).To(BeEmpty())
+	})
+
+	It("should merge the max request headers bytes with the lowest limit for the same path", func() {
+		Expect(MergeMaxRequestHeadersBytesAnnotations(`{"/upload":8192}`, "", `{"/upload":4096,"/img":2048}`)).To(Equal(`{"/img":2048,"/upload":4096}`))
+		Expect(MergeMaxRequestHeadersBytesAnnotations("")).To(BeEmpty())
+	})
+})
@@ -4,10 +4,12 @@ import (
 	"context"
 
 	rulev1alpha1 "github.com/ory/oathkeeper-maester/api/v1alpha1"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
 	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 	securityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
 
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/helpers"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -54,6 +56,55 @@ func DeleteAPIRuleSubresources(k8sClient client.Client, ctx context.Context, api
 		}
 	}
 
+	// The aggregated VirtualServices are shared with other APIRules, so only the routes of the APIRule are removed.
+	var aggregatedVsList networkingv1beta1.VirtualServiceList
+	err = k8sClient.List(ctx, &aggregatedVsList, client.InNamespace(apiRule.Namespace), client.MatchingLabels{helpers.AggregatedLabel: "true"})
+	if err != nil {
+		return err
+	}
+	for _, vs := range aggregatedVsList.Items {
+		owner := GetOwnerLabelValue(&apiRule)
+		if _, ok := helpers.GetOwners(vs.Annotations)[owner]; !ok {
+			continue
+		}
+		if RemoveOwner(vs, owner) {
+			log.Log.Info("Removing routes from subresource", "VirtualService", vs.Name)
+			err = k8sClient.Update(ctx, vs)
+		} else {
+			log.Log.Info("Removing subresource", "VirtualService", vs.Name)
+			err = k8sClient.Delete(ctx, vs)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	var drList networkingv1beta1.DestinationRuleList
+	err = k8sClient.List(ctx, &drList, client.MatchingLabels(labels))
+	if err != nil {
+		return err
+	}
+	for _, dr := range drList.Items {
+		log.Log.Info("Removing subresource", "DestinationRule", dr.Name)
+		err := k8sClient.Delete(ctx, dr)
+		if err != nil {
+			return err
+		}
+	}
+
+	var efList networkingv1alpha3.EnvoyFilterList
+	err = k8sClient.List(ctx, &efList, client.MatchingLabels(labels))
+	if err != nil {
+		return err
+	}
+	for _, ef := range efList.Items {
+		log.Log.Info("Removing subresource", "EnvoyFilter", ef.Name)
+		err := k8sClient.Delete(ctx, ef)
+		if err != nil {
+			return err
+		}
+	}
+
 	var ruleList rulev1alpha1.RuleList
 	err = k8sClient.List(ctx, &ruleList, client.MatchingLabels(labels))
 	if err != nil {
@@ -5,10 +5,13 @@ import (
 	"fmt"
 
 	rulev1alpha1 "github.com/ory/oathkeeper-maester/api/v1alpha1"
+	"istio.io/api/networking/v1beta1"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
 	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 	securityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
 
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/helpers"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -84,7 +87,26 @@ var _ = Describe("APIRule subresources deletion", func() {
 			ObjectMeta: notApiRuleObjectMeta,
 		}
 
-		client := testUtils.GetFakeClient(&apiRuleVS, &otherVS, &apiRuleRule, &otherRule, &apiRuleAP, &otherAP, &apiRuleRA, &otherRA)
+		apiRuleDR := networkingv1beta1.DestinationRule{
+			ObjectMeta: apiRuleObjectMeta,
+		}
+
+		otherDR := networkingv1beta1.DestinationRule{
+			ObjectMeta: notApiRuleObjectMeta,
+		}
+
+		// The Envoy Filters are created in the namespace of the ingress gateway.
+		apiRuleEF := networkingv1alpha3.EnvoyFilter{
+			ObjectMeta: apiRuleObjectMeta,
+		}
+		apiRuleEF.Namespace = processing.DefaultLuaFilterNamespace
+
+		otherEF := networkingv1alpha3.EnvoyFilter{
+			ObjectMeta: notApiRuleObjectMeta,
+		}
+		otherEF.Namespace = processing.DefaultLuaFilterNamespace
+
+		client := testUtils.GetFakeClient(&apiRuleVS, &otherVS, &apiRuleRule, &otherRule, &apiRuleAP, &otherAP, &apiRuleRA, &otherRA, &apiRuleDR, &otherDR, &apiRuleEF, &otherEF)
 
 		// when
 		err := processing.DeleteAPIRuleSubresources(client, context.TODO(), *apiRule)
@@ -118,5 +140,65 @@ var _ = Describe("APIRule subresources deletion", func() {
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(raList.Items).To(HaveLen(1))
 		Expect(raList.Items[0].Name).To(Equal("test-other-apirule"))
+
+		drList := networkingv1beta1.DestinationRuleList{}
+		err = client.List(context.TODO(), &drList)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(drList.Items).To(HaveLen(1))
+		Expect(drList.Items[0].Name).To(Equal("test-other-apirule"))
+
+		efList := networkingv1alpha3.EnvoyFilterList{}
+		err = client.List(context.TODO(), &efList)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(efList.Items).To(HaveLen(1))
+		Expect(efList.Items[0].Name).To(Equal("test-other-apirule"))
+	})
+
+	It("should only remove the routes of the APIRule from an aggregated virtual service of multiple APIRules", func() {
+		// given
+		apiRule := testUtils.GetAPIRuleFor(nil)
+		owner := processing.GetOwnerLabelValue(apiRule)
+		aggregatedLabels := map[string]string{helpers.AggregatedLabel: "true"}
+
+		sharedVS := networkingv1beta1.VirtualService{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "shared-vs",
+				Namespace: testUtils.ApiNamespace,
+				Labels:    aggregatedLabels,
+				Annotations: map[string]string{helpers.OwnersAnnotation: processing.GetOwnersAnnotation(map[string][]string{
+					owner:                  {"own-route"},
+					"other.some-namespace": {"other-route"},
+				})},
+			},
+		}
+		sharedVS.Spec.Http = []*v1beta1.HTTPRoute{{Name: "own-route"}, {Name: "other-route"}}
+
+		ownedVS := networkingv1beta1.VirtualService{
+			ObjectMeta: v1.ObjectMeta{
+				Name:        "owned-vs",
+				Namespace:   testUtils.ApiNamespace,
+				Labels:      aggregatedLabels,
+				Annotations: map[string]string{helpers.OwnersAnnotation: processing.GetOwnersAnnotation(map[string][]string{owner: {"own-route"}})},
+			},
+		}
+
+		client := testUtils.GetFakeClient(&sharedVS, &ownedVS)
+
+		// when
+		err := processing.DeleteAPIRuleSubresources(client, context.TODO(), *apiRule)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		// then
+		vsList := networkingv1beta1.VirtualServiceList{}
+		err = client.List(context.TODO(), &vsList)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(vsList.Items).To(HaveLen(1))
+		Expect(vsList.Items[0].Name).To(Equal("shared-vs"))
+		Expect(vsList.Items[0].Spec.Http).To(HaveLen(1))
+		Expect(vsList.Items[0].Spec.Http[0].Name).To(Equal("other-route"))
+		Expect(helpers.GetOwners(vsList.Items[0].Annotations)).To(HaveLen(1))
 	})
 })
@@ -0,0 +1,86 @@
+package processing
+
+import (
+	"time"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"istio.io/api/networking/v1beta1"
+)
+
+// GetRuleCorsConfig returns the CORS configuration that applies to the given rule of the APIRule. The configuration is
+// resolved field by field with the following precedence:
+//  1. the CORS policy of the rule
+//  2. the CORS policy of the APIRule spec
+//  3. the default configuration
+//
+// If none of them defines the allowed methods, the methods of the rules that are merged into the route of the rule are
+// allowed.
+func GetRuleCorsConfig(api *gatewayv1beta1.APIRule, rule gatewayv1beta1.Rule, defaultConfig *CorsConfig) *CorsConfig {
+	config := &CorsConfig{}
+	if defaultConfig != nil {
+		*config = *defaultConfig
+	}
+
+	applyCorsPolicy(config, api.Spec.CorsPolicy)
+	applyCorsPolicy(config, rule.CorsPolicy)
+
+	if len(config.AllowMethods) == 0 {
+		config.AllowMethods = getRouteMethods(api, rule)
+	}
+
+	return config
+}
+
+// getRouteMethods returns the union of the methods of the enabled rules that match the same requests as the given rule,
+// since they are merged into a single route.
+func getRouteMethods(api *gatewayv1beta1.APIRule, rule gatewayv1beta1.Rule) []string {
+	var methods []string
+	seen := make(map[string]bool)
+	for _, r := range FilterDisabledRules(api.Spec.Rules) {
+		if r.GetMatchKey() != rule.GetMatchKey() {
+			continue
+		}
+		for _, method := range r.Methods {
+			if !seen[method] {
+				seen[method] = true
+				methods = append(methods, method)
+			}
+		}
+	}
+
+	return methods
+}
+
+// applyCorsPolicy overwrites the fields of the configuration that are defined in the CORS policy.
+func applyCorsPolicy(config *CorsConfig, policy *gatewayv1beta1.CorsPolicy) {
+	if policy == nil {
+		return
+	}
+
+	if len(policy.AllowOrigins) > 0 || len(policy.AllowOriginsRegex) > 0 {
+		config.AllowOrigins = nil
+		for _, origin := range policy.AllowOrigins {
+			config.AllowOrigins = append(config.AllowOrigins, &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Exact{Exact: origin}})
+		}
+		for _, origin := range policy.AllowOriginsRegex {
+			config.AllowOrigins = append(config.AllowOrigins, &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Regex{Regex: origin}})
+		}
+	}
+	if len(policy.AllowMethods) > 0 {
+		config.AllowMethods = policy.AllowMethods
+	}
+	if len(policy.AllowHeaders) > 0 {
+		config.AllowHeaders = policy.AllowHeaders
+	}
+	// The max age is validated to be a duration, so an invalid value is never applied.
+	if policy.MaxAge != nil {
+		if maxAge, err := time.ParseDuration(*policy.MaxAge); err == nil {
+			config.MaxAge = maxAge
+		}
+	}
+}
+
+// IsCorsDisabled returns true if the CORS policy is disabled for all rules of the APIRule.
+func IsCorsDisabled(api *gatewayv1beta1.APIRule) bool {
+	return api.Spec.DisableCors != nil && *api.Spec.DisableCors
+}
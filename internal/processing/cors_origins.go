@@ -0,0 +1,115 @@
+package processing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CorsOriginsIndex is the name of the field index of the APIRules by the ConfigMaps referenced by their CORS policies. The
+// ConfigMaps are indexed by namespace and name, so a changed ConfigMap can be mapped to the APIRules that reference it.
+const CorsOriginsIndex = "spec.corsPolicy.allowOriginsFrom"
+
+// ResolveCorsOrigins returns the APIRule with the origins of the ConfigMaps referenced by the CORS policies of the spec
+// and the rules added to the allowed origins. The given APIRule is not changed, if no ConfigMap is referenced it is returned as is.
+func ResolveCorsOrigins(ctx context.Context, k8sClient client.Client, api *gatewayv1beta1.APIRule) (*gatewayv1beta1.APIRule, error) {
+	if !hasCorsOriginsReference(api) {
+		return api, nil
+	}
+
+	resolved := api.DeepCopy()
+	if policy := resolved.Spec.CorsPolicy; policy != nil && policy.AllowOriginsFrom != nil {
+		origins, err := getCorsOrigins(ctx, k8sClient, policy.AllowOriginsFrom, resolved.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		policy.AllowOrigins = append(policy.AllowOrigins, origins...)
+	}
+
+	for i := range resolved.Spec.Rules {
+		rule := &resolved.Spec.Rules[i]
+		if rule.CorsPolicy == nil || rule.CorsPolicy.AllowOriginsFrom == nil {
+			continue
+		}
+
+		origins, err := getCorsOrigins(ctx, k8sClient, rule.CorsPolicy.AllowOriginsFrom, resolved.Namespace)
+		if err != nil {
+			return nil, NewRuleError(rule.Path, err)
+		}
+		rule.CorsPolicy.AllowOrigins = append(rule.CorsPolicy.AllowOrigins, origins...)
+	}
+
+	return resolved, nil
+}
+
+func hasCorsOriginsReference(api *gatewayv1beta1.APIRule) bool {
+	if api.Spec.CorsPolicy != nil && api.Spec.CorsPolicy.AllowOriginsFrom != nil {
+		return true
+	}
+	for _, rule := range api.Spec.Rules {
+		if rule.CorsPolicy != nil && rule.CorsPolicy.AllowOriginsFrom != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IndexByCorsOrigins extracts the values of the CorsOriginsIndex from the ConfigMaps referenced by the CORS policies of the
+// spec and the rules of the APIRule.
+func IndexByCorsOrigins(obj client.Object) []string {
+	api, ok := obj.(*gatewayv1beta1.APIRule)
+	if !ok {
+		return nil
+	}
+
+	var configMaps []string
+	addConfigMap := func(policy *gatewayv1beta1.CorsPolicy) {
+		if policy == nil || policy.AllowOriginsFrom == nil {
+			return
+		}
+		configMap := GetCorsOriginsIndexValue(api.Namespace, policy.AllowOriginsFrom.Name)
+		if !slices.Contains(configMaps, configMap) {
+			configMaps = append(configMaps, configMap)
+		}
+	}
+
+	addConfigMap(api.Spec.CorsPolicy)
+	for _, rule := range api.Spec.Rules {
+		addConfigMap(rule.CorsPolicy)
+	}
+
+	return configMaps
+}
+
+// GetCorsOriginsIndexValue returns the value of the CorsOriginsIndex for the ConfigMap with the given namespace and name.
+func GetCorsOriginsIndexValue(namespace, name string) string {
+	return types.NamespacedName{Namespace: namespace, Name: name}.String()
+}
+
+func getCorsOrigins(ctx context.Context, k8sClient client.Client, ref *gatewayv1beta1.ConfigMapKeyReference, namespace string) ([]string, error) {
+	var cm corev1.ConfigMap
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, &cm); err != nil {
+		return nil, fmt.Errorf("failed to get the CORS origins from configmap %s/%s: %w", namespace, ref.Name, err)
+	}
+
+	value, ok := cm.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("configmap %s/%s doesn't define the CORS origins key %s", namespace, ref.Name, ref.Key)
+	}
+
+	var origins []string
+	for _, origin := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+
+	return origins, nil
+}
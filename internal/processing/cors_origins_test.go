@@ -0,0 +1,182 @@
+package processing_test
+
+import (
+	"context"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/processing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ResolveCorsOrigins", func() {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cors-origins", Namespace: "some-namespace"},
+		Data:       map[string]string{"origins": "https://a.example.com,\n https://b.example.com\n\n"},
+	}
+
+	apiRule := func(policy *gatewayv1beta1.CorsPolicy) *gatewayv1beta1.APIRule {
+		return &gatewayv1beta1.APIRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-apirule", Namespace: "some-namespace"},
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Rules: []gatewayv1beta1.Rule{{Path: "/headers", CorsPolicy: policy}},
+			},
+		}
+	}
+
+	It("should add the origins of the referenced ConfigMap to the allowed origins of the rule", func() {
+		// given
+		client := fake.NewClientBuilder().WithObjects(configMap).Build()
+		api := apiRule(&gatewayv1beta1.CorsPolicy{
+			AllowOrigins:     []string{"https://inline.example.com"},
+			AllowOriginsFrom: &gatewayv1beta1.ConfigMapKeyReference{Name: "cors-origins", Key: "origins"},
+		})
+
+		// when
+		resolved, err := processing.ResolveCorsOrigins(context.TODO(), client, api)
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resolved.Spec.Rules[0].CorsPolicy.AllowOrigins).To(Equal([]string{"https://inline.example.com", "https://a.example.com", "https://b.example.com"}))
+		Expect(api.Spec.Rules[0].CorsPolicy.AllowOrigins).To(Equal([]string{"https://inline.example.com"}))
+	})
+
+	It("should return the APIRule unchanged when no ConfigMap is referenced", func() {
+		// given
+		client := fake.NewClientBuilder().Build()
+		api := apiRule(&gatewayv1beta1.CorsPolicy{AllowOrigins: []string{"https://inline.example.com"}})
+
+		// when
+		resolved, err := processing.ResolveCorsOrigins(context.TODO(), client, api)
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resolved).To(BeIdenticalTo(api))
+	})
+
+	It("should return a rule error when the ConfigMap doesn't exist", func() {
+		// given
+		client := fake.NewClientBuilder().Build()
+		api := apiRule(&gatewayv1beta1.CorsPolicy{
+			AllowOriginsFrom: &gatewayv1beta1.ConfigMapKeyReference{Name: "cors-origins", Key: "origins"},
+		})
+
+		// when
+		_, err := processing.ResolveCorsOrigins(context.TODO(), client, api)
+
+		// then
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("rule with path /headers: failed to get the CORS origins from configmap some-namespace/cors-origins"))
+	})
+
+	It("should return a rule error when the ConfigMap doesn't define the key", func() {
+		// given
+		client := fake.NewClientBuilder().WithObjects(configMap).Build()
+		api := apiRule(&gatewayv1beta1.CorsPolicy{
+			AllowOriginsFrom: &gatewayv1beta1.ConfigMapKeyReference{Name: "cors-origins", Key: "other"},
+		})
+
+		// when
+		_, err := processing.ResolveCorsOrigins(context.TODO(), client, api)
+
+		// then
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(Equal("rule with path /headers: configmap some-namespace/cors-origins doesn't define the CORS origins key other"))
+	})
+
+	It("should add the origins of the ConfigMap referenced by the spec CORS policy to its allowed origins", func() {
+		// given
+		client := fake.NewClientBuilder().WithObjects(configMap).Build()
+		api := apiRule(nil)
+		api.Spec.CorsPolicy = &gatewayv1beta1.CorsPolicy{
+			AllowOriginsFrom: &gatewayv1beta1.ConfigMapKeyReference{Name: "cors-origins", Key: "origins"},
+		}
+
+		// when
+		resolved, err := processing.ResolveCorsOrigins(context.TODO(), client, api)
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resolved.Spec.CorsPolicy.AllowOrigins).To(Equal([]string{"https://a.example.com", "https://b.example.com"}))
+		Expect(resolved.Spec.Rules[0].CorsPolicy).To(BeNil())
+		Expect(api.Spec.CorsPolicy.AllowOrigins).To(BeEmpty())
+	})
+})
+
+var _ = Describe("IndexByCorsOrigins", func() {
+	It("should index the APIRule by the ConfigMaps referenced by the CORS policies of the spec and the rules", func() {
+		// given
+		apiRule := &gatewayv1beta1.APIRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-apirule", Namespace: "some-namespace"},
+			Spec: gatewayv1beta1.APIRuleSpec{
+				CorsPolicy: &gatewayv1beta1.CorsPolicy{AllowOriginsFrom: &gatewayv1beta1.ConfigMapKeyReference{Name: "spec-origins", Key: "origins"}},
+				Rules: []gatewayv1beta1.Rule{
+					{Path: "/headers", CorsPolicy: &gatewayv1beta1.CorsPolicy{AllowOriginsFrom: &gatewayv1beta1.ConfigMapKeyReference{Name: "rule-origins", Key: "origins"}}},
+					{Path: "/status", CorsPolicy: &gatewayv1beta1.CorsPolicy{AllowOriginsFrom: &gatewayv1beta1.ConfigMapKeyReference{Name: "rule-origins", Key: "other"}}},
+					{Path: "/ip"},
+				},
+			},
+		}
+
+		// when
+		values := processing.IndexByCorsOrigins(apiRule)
+
+		// then
+		Expect(values).To(ConsistOf("some-namespace/spec-origins", "some-namespace/rule-origins"))
+	})
+
+	It("should not index the APIRule when no ConfigMap is referenced", func() {
+		// given
+		apiRule := &gatewayv1beta1.APIRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-apirule", Namespace: "some-namespace"},
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Rules: []gatewayv1beta1.Rule{{Path: "/headers", CorsPolicy: &gatewayv1beta1.CorsPolicy{AllowOrigins: []string{"https://example.com"}}}},
+			},
+		}
+
+		// when
+		values := processing.IndexByCorsOrigins(apiRule)
+
+		// then
+		Expect(values).To(BeEmpty())
+	})
+
+	It("should list the APIRules that reference the ConfigMap by the index", func() {
+		// given
+		referencing := &gatewayv1beta1.APIRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "referencing", Namespace: "some-namespace"},
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Rules: []gatewayv1beta1.Rule{{Path: "/headers", CorsPolicy: &gatewayv1beta1.CorsPolicy{AllowOriginsFrom: &gatewayv1beta1.ConfigMapKeyReference{Name: "cors-origins", Key: "origins"}}}},
+			},
+		}
+		otherNamespace := referencing.DeepCopy()
+		otherNamespace.Namespace = "other-namespace"
+		notReferencing := &gatewayv1beta1.APIRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "not-referencing", Namespace: "some-namespace"},
+			Spec:       gatewayv1beta1.APIRuleSpec{Rules: []gatewayv1beta1.Rule{{Path: "/headers"}}},
+		}
+
+		scheme := runtime.NewScheme()
+		Expect(gatewayv1beta1.AddToScheme(scheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(referencing, otherNamespace, notReferencing).
+			WithIndex(&gatewayv1beta1.APIRule{}, processing.CorsOriginsIndex, processing.IndexByCorsOrigins).
+			Build()
+
+		// when
+		var apiRules gatewayv1beta1.APIRuleList
+		err := k8sClient.List(context.TODO(), &apiRules, client.MatchingFields{processing.CorsOriginsIndex: processing.GetCorsOriginsIndexValue("some-namespace", "cors-origins")})
+
+		// then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(apiRules.Items).To(HaveLen(1))
+		Expect(apiRules.Items[0].Name).To(Equal("referencing"))
+		Expect(apiRules.Items[0].Namespace).To(Equal("some-namespace"))
+	})
+})
@@ -0,0 +1,178 @@
+package processing
+
+import (
+	"time"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"istio.io/api/networking/v1beta1"
+)
+
+var _ = Describe("GetRuleCorsConfig", func() {
+	defaultConfig := &CorsConfig{
+		AllowOrigins: []*v1beta1.StringMatch{{MatchType: &v1beta1.StringMatch_Regex{Regex: ".*"}}},
+		AllowMethods: []string{"GET", "POST"},
+		AllowHeaders: []string{"header1"},
+	}
+
+	It("should fall back to the default config when rule has no CORS policy", func() {
+		config := GetRuleCorsConfig(&gatewayv1beta1.APIRule{}, gatewayv1beta1.Rule{}, defaultConfig)
+
+		Expect(config).To(Equal(defaultConfig))
+	})
+
+	It("should override all fields of the default config with the rule CORS policy", func() {
+		rule := gatewayv1beta1.Rule{
+			CorsPolicy: &gatewayv1beta1.CorsPolicy{
+				AllowOrigins: []string{"https://example.com"},
+				AllowMethods: []string{"PUT"},
+				AllowHeaders: []string{"header2", "header3"},
+			},
+		}
+
+		config := GetRuleCorsConfig(&gatewayv1beta1.APIRule{}, rule, defaultConfig)
+
+		Expect(config.AllowOrigins).To(HaveLen(1))
+		Expect(config.AllowOrigins[0].GetExact()).To(Equal("https://example.com"))
+		Expect(config.AllowMethods).To(Equal([]string{"PUT"}))
+		Expect(config.AllowHeaders).To(Equal([]string{"header2", "header3"}))
+	})
+
+	It("should combine exact and regex origins of the rule CORS policy", func() {
+		rule := gatewayv1beta1.Rule{
+			CorsPolicy: &gatewayv1beta1.CorsPolicy{
+				AllowOrigins:      []string{"https://example.com"},
+				AllowOriginsRegex: []string{`https://.*\.example\.com`},
+			},
+		}
+
+		config := GetRuleCorsConfig(&gatewayv1beta1.APIRule{}, rule, defaultConfig)
+
+		Expect(config.AllowOrigins).To(HaveLen(2))
+		Expect(config.AllowOrigins[0].GetExact()).To(Equal("https://example.com"))
+		Expect(config.AllowOrigins[1].GetRegex()).To(Equal(`https://.*\.example\.com`))
+		Expect(config.AllowMethods).To(Equal(defaultConfig.AllowMethods))
+	})
+
+	It("should only override the fields set in the rule CORS policy", func() {
+		rule := gatewayv1beta1.Rule{
+			CorsPolicy: &gatewayv1beta1.CorsPolicy{
+				AllowMethods: []string{"DELETE"},
+			},
+		}
+
+		config := GetRuleCorsConfig(&gatewayv1beta1.APIRule{}, rule, defaultConfig)
+
+		Expect(config.AllowOrigins).To(Equal(defaultConfig.AllowOrigins))
+		Expect(config.AllowMethods).To(Equal([]string{"DELETE"}))
+		Expect(config.AllowHeaders).To(Equal(defaultConfig.AllowHeaders))
+		Expect(defaultConfig.AllowMethods).To(Equal([]string{"GET", "POST"}))
+	})
+
+	DescribeTable("should resolve each field with the precedence rule, spec and default config",
+		func(specPolicy *gatewayv1beta1.CorsPolicy, rulePolicy *gatewayv1beta1.CorsPolicy, expectedOrigin string, expectedMethods []string, expectedHeaders []string) {
+			api := &gatewayv1beta1.APIRule{Spec: gatewayv1beta1.APIRuleSpec{CorsPolicy: specPolicy}}
+			rule := gatewayv1beta1.Rule{CorsPolicy: rulePolicy}
+
+			config := GetRuleCorsConfig(api, rule, defaultConfig)
+
+			Expect(config.AllowOrigins).To(HaveLen(1))
+			Expect(config.AllowOrigins[0].GetExact() + config.AllowOrigins[0].GetRegex()).To(Equal(expectedOrigin))
+			Expect(config.AllowMethods).To(Equal(expectedMethods))
+			Expect(config.AllowHeaders).To(Equal(expectedHeaders))
+		},
+		Entry("neither spec nor rule policy", nil, nil, ".*", []string{"GET", "POST"}, []string{"header1"}),
+		Entry("only spec policy",
+			&gatewayv1beta1.CorsPolicy{AllowOrigins: []string{"https://spec.com"}, AllowMethods: []string{"PUT"}, AllowHeaders: []string{"spec-header"}},
+			nil, "https://spec.com", []string{"PUT"}, []string{"spec-header"}),
+		Entry("only rule policy",
+			nil,
+			&gatewayv1beta1.CorsPolicy{AllowOrigins: []string{"https://rule.com"}, AllowMethods: []string{"DELETE"}, AllowHeaders: []string{"rule-header"}},
+			"https://rule.com", []string{"DELETE"}, []string{"rule-header"}),
+		Entry("spec and rule policy define all fields",
+			&gatewayv1beta1.CorsPolicy{AllowOrigins: []string{"https://spec.com"}, AllowMethods: []string{"PUT"}, AllowHeaders: []string{"spec-header"}},
+			&gatewayv1beta1.CorsPolicy{AllowOrigins: []string{"https://rule.com"}, AllowMethods: []string{"DELETE"}, AllowHeaders: []string{"rule-header"}},
+			"https://rule.com", []string{"DELETE"}, []string{"rule-header"}),
+		Entry("spec and rule policy define different fields",
+			&gatewayv1beta1.CorsPolicy{AllowOrigins: []string{"https://spec.com"}},
+			&gatewayv1beta1.CorsPolicy{AllowMethods: []string{"DELETE"}},
+			"https://spec.com", []string{"DELETE"}, []string{"header1"}),
+		Entry("empty spec policy",
+			&gatewayv1beta1.CorsPolicy{},
+			&gatewayv1beta1.CorsPolicy{AllowHeaders: []string{"rule-header"}},
+			".*", []string{"GET", "POST"}, []string{"rule-header"}),
+	)
+
+	Describe("allowed methods", func() {
+		configWithoutMethods := &CorsConfig{
+			AllowOrigins: []*v1beta1.StringMatch{{MatchType: &v1beta1.StringMatch_Regex{Regex: ".*"}}},
+		}
+
+		It("should allow the methods of the rule if no allowed methods are configured", func() {
+			rule := gatewayv1beta1.Rule{Path: "/headers", Methods: []string{"GET", "PUT"}}
+			api := &gatewayv1beta1.APIRule{Spec: gatewayv1beta1.APIRuleSpec{Rules: []gatewayv1beta1.Rule{rule}}}
+
+			config := GetRuleCorsConfig(api, rule, configWithoutMethods)
+
+			Expect(config.AllowMethods).To(Equal([]string{"GET", "PUT"}))
+			Expect(configWithoutMethods.AllowMethods).To(BeEmpty())
+		})
+
+		It("should allow the union of the methods of the enabled rules merged into the same route", func() {
+			disabled := false
+			rule := gatewayv1beta1.Rule{Path: "/headers", Methods: []string{"GET", "PUT"}}
+			api := &gatewayv1beta1.APIRule{Spec: gatewayv1beta1.APIRuleSpec{Rules: []gatewayv1beta1.Rule{
+				rule,
+				{Path: "/headers", Methods: []string{"PUT", "DELETE"}},
+				{Path: "/headers", Methods: []string{"PATCH"}, Enabled: &disabled},
+				{Path: "/other", Methods: []string{"POST"}},
+			}}}
+
+			config := GetRuleCorsConfig(api, rule, configWithoutMethods)
+
+			Expect(config.AllowMethods).To(Equal([]string{"GET", "PUT", "DELETE"}))
+		})
+
+		DescribeTable("should prefer the explicitly allowed methods over the methods of the rule",
+			func(defaultConfig *CorsConfig, specPolicy *gatewayv1beta1.CorsPolicy, rulePolicy *gatewayv1beta1.CorsPolicy, expectedMethods []string) {
+				rule := gatewayv1beta1.Rule{Path: "/headers", Methods: []string{"GET", "PUT"}, CorsPolicy: rulePolicy}
+				api := &gatewayv1beta1.APIRule{Spec: gatewayv1beta1.APIRuleSpec{CorsPolicy: specPolicy, Rules: []gatewayv1beta1.Rule{rule}}}
+
+				config := GetRuleCorsConfig(api, rule, defaultConfig)
+
+				Expect(config.AllowMethods).To(Equal(expectedMethods))
+			},
+			Entry("default config", defaultConfig, nil, nil, []string{"GET", "POST"}),
+			Entry("spec policy", configWithoutMethods, &gatewayv1beta1.CorsPolicy{AllowMethods: []string{"POST"}}, nil, []string{"POST"}),
+			Entry("rule policy", configWithoutMethods, nil, &gatewayv1beta1.CorsPolicy{AllowMethods: []string{"DELETE"}}, []string{"DELETE"}),
+		)
+	})
+
+	Describe("max age", func() {
+		configWithMaxAge := &CorsConfig{
+			AllowOrigins: []*v1beta1.StringMatch{{MatchType: &v1beta1.StringMatch_Regex{Regex: ".*"}}},
+			AllowMethods: []string{"GET"},
+			MaxAge:       time.Hour,
+		}
+		ptr := func(s string) *string { return &s }
+
+		DescribeTable("should resolve the max age with the precedence rule, spec and default config",
+			func(specPolicy *gatewayv1beta1.CorsPolicy, rulePolicy *gatewayv1beta1.CorsPolicy, expectedMaxAge time.Duration) {
+				rule := gatewayv1beta1.Rule{Path: "/headers", CorsPolicy: rulePolicy}
+				api := &gatewayv1beta1.APIRule{Spec: gatewayv1beta1.APIRuleSpec{CorsPolicy: specPolicy, Rules: []gatewayv1beta1.Rule{rule}}}
+
+				config := GetRuleCorsConfig(api, rule, configWithMaxAge)
+
+				Expect(config.MaxAge).To(Equal(expectedMaxAge))
+				Expect(configWithMaxAge.MaxAge).To(Equal(time.Hour))
+			},
+			Entry("default config", nil, nil, time.Hour),
+			Entry("spec policy", &gatewayv1beta1.CorsPolicy{MaxAge: ptr("30m")}, nil, 30*time.Minute),
+			Entry("rule policy", nil, &gatewayv1beta1.CorsPolicy{MaxAge: ptr("10s")}, 10*time.Second),
+			Entry("rule policy overrides spec policy", &gatewayv1beta1.CorsPolicy{MaxAge: ptr("30m")}, &gatewayv1beta1.CorsPolicy{MaxAge: ptr("10s")}, 10*time.Second),
+			Entry("rule policy without max age", &gatewayv1beta1.CorsPolicy{MaxAge: ptr("30m")}, &gatewayv1beta1.CorsPolicy{AllowHeaders: []string{"header"}}, 30*time.Minute),
+			Entry("rule policy disables the max age", nil, &gatewayv1beta1.CorsPolicy{MaxAge: ptr("0s")}, time.Duration(0)),
+		)
+	})
+})
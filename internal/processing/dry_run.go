@@ -0,0 +1,121 @@
+package processing
+
+import (
+	"context"
+	"errors"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var errReadOnly = errors.New("the cluster can't be changed in a dry run")
+
+// DryRunReconcile evaluates the reconciliation of the APIRule like Reconcile, but doesn't apply any change to the cluster,
+// so it can be used to reject invalid APIRules before they are stored, e.g. in a validating webhook. The processors only
+// get read access to the cluster. Errors that would fail the reconciliation are returned as failures, the error is only
+// returned if the validation itself couldn't be done.
+func DryRunReconcile(ctx context.Context, k8sClient client.Client, cmd ReconciliationCommand, apiRule *gatewayv1beta1.APIRule) ([]validation.Failure, error) {
+	readOnly := readOnlyClient{Client: k8sClient}
+
+	failures, err := cmd.Validate(ctx, readOnly, apiRule)
+	if err != nil || len(failures) > 0 {
+		return failures, err
+	}
+
+	resolvedApiRule, err := ResolveServicePortNames(ctx, readOnly, apiRule)
+	if err != nil {
+		return []validation.Failure{toFailure(err)}, nil
+	}
+
+	resolvedApiRule, err = ResolveCorsOrigins(ctx, readOnly, resolvedApiRule)
+	if err != nil {
+		return []validation.Failure{toFailure(err)}, nil
+	}
+
+	resolvedApiRule = ExpandProtocolPorts(NormalizeRuleMethods(resolvedApiRule))
+
+	for _, processor := range cmd.GetProcessors() {
+		if _, err := processor.EvaluateReconciliation(ctx, readOnly, resolvedApiRule); err != nil {
+			if fieldFailures := toFieldFailures(err); len(fieldFailures) > 0 {
+				failures = append(failures, fieldFailures...)
+			} else {
+				failures = append(failures, toFailure(err))
+			}
+		}
+	}
+
+	return failures, nil
+}
+
+func toFailure(err error) validation.Failure {
+	var ruleErr *RuleError
+	if errors.As(err, &ruleErr) {
+		return validation.Failure{AttributePath: ".spec.rules", Message: err.Error()}
+	}
+
+	return validation.Failure{AttributePath: ".spec", Message: err.Error()}
+}
+
+// readOnlyClient rejects all requests that would change the cluster.
+type readOnlyClient struct {
+	client.Client
+}
+
+func (c readOnlyClient) Create(_ context.Context, _ client.Object, _ ...client.CreateOption) error {
+	return errReadOnly
+}
+
+func (c readOnlyClient) Update(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+	return errReadOnly
+}
+
+func (c readOnlyClient) Patch(_ context.Context, _ client.Object, _ client.Patch, _ ...client.PatchOption) error {
+	return errReadOnly
+}
+
+func (c readOnlyClient) Delete(_ context.Context, _ client.Object, _ ...client.DeleteOption) error {
+	return errReadOnly
+}
+
+func (c readOnlyClient) DeleteAllOf(_ context.Context, _ client.Object, _ ...client.DeleteAllOfOption) error {
+	return errReadOnly
+}
+
+func (c readOnlyClient) Status() client.SubResourceWriter {
+	return readOnlySubResourceWriter{}
+}
+
+func (c readOnlyClient) SubResource(subResource string) client.SubResourceClient {
+	return readOnlySubResourceClient{SubResourceClient: c.Client.SubResource(subResource)}
+}
+
+type readOnlySubResourceClient struct {
+	client.SubResourceClient
+}
+
+func (c readOnlySubResourceClient) Create(_ context.Context, _ client.Object, _ client.Object, _ ...client.SubResourceCreateOption) error {
+	return errReadOnly
+}
+
+func (c readOnlySubResourceClient) Update(_ context.Context, _ client.Object, _ ...client.SubResourceUpdateOption) error {
+	return errReadOnly
+}
+
+func (c readOnlySubResourceClient) Patch(_ context.Context, _ client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+	return errReadOnly
+}
+
+type readOnlySubResourceWriter struct{}
+
+func (w readOnlySubResourceWriter) Create(_ context.Context, _ client.Object, _ client.Object, _ ...client.SubResourceCreateOption) error {
+	return errReadOnly
+}
+
+func (w readOnlySubResourceWriter) Update(_ context.Context, _ client.Object, _ ...client.SubResourceUpdateOption) error {
+	return errReadOnly
+}
+
+func (w readOnlySubResourceWriter) Patch(_ context.Context, _ client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+	return errReadOnly
+}
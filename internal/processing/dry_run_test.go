@@ -0,0 +1,167 @@
+package processing_test
+
+import (
+	"context"
+	"fmt"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/builders"
+	"github.com/kyma-project/api-gateway/internal/processing"
+	"github.com/kyma-project/api-gateway/internal/validation"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("DryRunReconcile", func() {
+	newClient := func(objs ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		Expect(networkingv1beta1.AddToScheme(scheme)).To(Succeed())
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	It("should return the validation failures of an invalid APIRule", func() {
+		// given
+		failures := []validation.Failure{{AttributePath: "some.path", Message: "The value is not allowed"}}
+		processorCalled := false
+		p := MockReconciliationProcessor{
+			evaluate: func() ([]*processing.ObjectChange, error) {
+				processorCalled = true
+				return nil, nil
+			},
+		}
+		cmd := MockReconciliationCommand{
+			validateMock:   func() ([]validation.Failure, error) { return failures, nil },
+			processorMocks: func() []processing.ReconciliationProcessor { return []processing.ReconciliationProcessor{p} },
+		}
+
+		// when
+		result, err := processing.DryRunReconcile(context.TODO(), newClient(), cmd, &gatewayv1beta1.APIRule{})
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result).To(Equal(failures))
+		Expect(processorCalled).To(BeFalse())
+	})
+
+	It("should return the error when the validation can't be done", func() {
+		// given
+		cmd := MockReconciliationCommand{
+			validateMock: func() ([]validation.Failure, error) { return nil, fmt.Errorf("error during validation") },
+		}
+
+		// when
+		_, err := processing.DryRunReconcile(context.TODO(), newClient(), cmd, &gatewayv1beta1.APIRule{})
+
+		// then
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should return the processor errors as failures", func() {
+		// given
+		p := MockReconciliationProcessor{
+			evaluate: func() ([]*processing.ObjectChange, error) {
+				return nil, processing.NewRuleError("/path", fmt.Errorf("invalid cookie mutator"))
+			},
+		}
+		cmd := MockReconciliationCommand{
+			validateMock:   func() ([]validation.Failure, error) { return []validation.Failure{}, nil },
+			processorMocks: func() []processing.ReconciliationProcessor { return []processing.ReconciliationProcessor{p} },
+		}
+
+		// when
+		result, err := processing.DryRunReconcile(context.TODO(), newClient(), cmd, &gatewayv1beta1.APIRule{})
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result).To(Equal([]validation.Failure{{AttributePath: ".spec.rules", Message: "rule with path /path: invalid cookie mutator"}}))
+	})
+
+	It("should return the field errors of the processors as failures of the fields", func() {
+		// given
+		p := MockReconciliationProcessor{
+			evaluate: func() ([]*processing.ObjectChange, error) {
+				return nil, processing.FieldErrors{
+					processing.NewRuleError("/a", processing.NewFieldError(".spec.rules[0].timeout", fmt.Errorf("invalid timeout"))),
+					processing.NewRuleError("/b", processing.NewFieldError(".spec.rules[1].retries", fmt.Errorf("invalid retries"))),
+				}
+			},
+		}
+		cmd := MockReconciliationCommand{
+			validateMock:   func() ([]validation.Failure, error) { return []validation.Failure{}, nil },
+			processorMocks: func() []processing.ReconciliationProcessor { return []processing.ReconciliationProcessor{p} },
+		}
+
+		// when
+		result, err := processing.DryRunReconcile(context.TODO(), newClient(), cmd, &gatewayv1beta1.APIRule{})
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result).To(Equal([]validation.Failure{
+			{AttributePath: ".spec.rules[0].timeout", Message: "rule with path /a: invalid timeout"},
+			{AttributePath: ".spec.rules[1].retries", Message: "rule with path /b: invalid retries"},
+		}))
+	})
+
+	It("should not apply the changes of the processors to the cluster", func() {
+		// given
+		existingVs := builders.VirtualService().Name("existing").Namespace("some-namespace").Get()
+		c := newClient(existingVs)
+		p := MockReconciliationProcessor{
+			evaluate: func() ([]*processing.ObjectChange, error) {
+				return []*processing.ObjectChange{
+					processing.NewObjectCreateAction(builders.VirtualService().Name("test").Namespace("some-namespace").Get()),
+					processing.NewObjectDeleteAction(existingVs),
+				}, nil
+			},
+		}
+		cmd := MockReconciliationCommand{
+			validateMock:   func() ([]validation.Failure, error) { return []validation.Failure{}, nil },
+			processorMocks: func() []processing.ReconciliationProcessor { return []processing.ReconciliationProcessor{p} },
+		}
+
+		// when
+		result, err := processing.DryRunReconcile(context.TODO(), c, cmd, &gatewayv1beta1.APIRule{})
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result).To(BeEmpty())
+
+		var vsList networkingv1beta1.VirtualServiceList
+		Expect(c.List(context.TODO(), &vsList)).To(Succeed())
+		Expect(vsList.Items).To(HaveLen(1))
+		Expect(vsList.Items[0].Name).To(Equal("existing"))
+	})
+
+	It("should only provide read access to the cluster to the processors", func() {
+		// given
+		c := newClient()
+		p := writingReconciliationProcessor{obj: builders.VirtualService().Name("test").Namespace("some-namespace").Get()}
+		cmd := MockReconciliationCommand{
+			validateMock:   func() ([]validation.Failure, error) { return []validation.Failure{}, nil },
+			processorMocks: func() []processing.ReconciliationProcessor { return []processing.ReconciliationProcessor{p} },
+		}
+
+		// when
+		result, err := processing.DryRunReconcile(context.TODO(), c, cmd, &gatewayv1beta1.APIRule{})
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result).To(Equal([]validation.Failure{{AttributePath: ".spec", Message: "the cluster can't be changed in a dry run"}}))
+
+		var vsList networkingv1beta1.VirtualServiceList
+		Expect(c.List(context.TODO(), &vsList)).To(Succeed())
+		Expect(vsList.Items).To(BeEmpty())
+	})
+})
+
+type writingReconciliationProcessor struct {
+	obj client.Object
+}
+
+func (r writingReconciliationProcessor) EvaluateReconciliation(ctx context.Context, c client.Client, _ *gatewayv1beta1.APIRule) ([]*processing.ObjectChange, error) {
+	return nil, c.Create(ctx, r.obj)
+}
@@ -0,0 +1,45 @@
+package processing
+
+import (
+	"fmt"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+)
+
+// DuplicatePathsMode defines how rules that match the same requests as a previous rule are handled.
+type DuplicatePathsMode string
+
+const (
+	// DuplicatePathsFilter merges the duplicated rules into the route of the previous rule without reporting them.
+	DuplicatePathsFilter DuplicatePathsMode = "filter"
+	// DuplicatePathsReport merges the duplicated rules like DuplicatePathsFilter and reports them as a warning in the
+	// status of the APIRule.
+	DuplicatePathsReport DuplicatePathsMode = "report"
+)
+
+// DetectDuplicatePaths returns the paths of the enabled rules that are filtered by FilterDuplicatePaths, since they match
+// the same requests as a previous rule. Each path is only returned once.
+func DetectDuplicatePaths(rules []gatewayv1beta1.Rule) []string {
+	var paths []string
+	matched := make(map[string]bool)
+	reported := make(map[string]bool)
+	for _, rule := range FilterDisabledRules(rules) {
+		key := rule.GetMatchKey()
+		if matched[key] && !reported[key] {
+			reported[key] = true
+			paths = append(paths, rule.Path)
+		}
+		matched[key] = true
+	}
+
+	return paths
+}
+
+func generateDuplicatePathsWarning(paths []string) string {
+	description := "Warning: The following rules match the same requests as a previous rule and are merged into its route:"
+	for _, path := range paths {
+		description += fmt.Sprintf("\n%s", path)
+	}
+
+	return description
+}
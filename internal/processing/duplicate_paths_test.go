@@ -0,0 +1,45 @@
+package processing
+
+import (
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DetectDuplicatePaths", func() {
+	It("should detect each duplicated path once", func() {
+		rules := []gatewayv1beta1.Rule{
+			{Path: "/headers", Methods: []string{"GET"}},
+			{Path: "/headers", Methods: []string{"POST"}},
+			{Path: "/headers", Methods: []string{"PUT"}},
+			{Path: "/img"},
+		}
+
+		duplicates := DetectDuplicatePaths(rules)
+
+		Expect(duplicates).To(Equal([]string{"/headers"}))
+	})
+
+	It("should not detect rules with the same path but different header matches", func() {
+		rules := []gatewayv1beta1.Rule{
+			{Path: "/headers", MatchHeaders: map[string]gatewayv1beta1.StringMatch{"x-version": {Exact: "v2"}}},
+			{Path: "/headers"},
+		}
+
+		duplicates := DetectDuplicatePaths(rules)
+
+		Expect(duplicates).To(BeEmpty())
+	})
+
+	It("should not detect a duplicate of a disabled rule", func() {
+		disabled := false
+		rules := []gatewayv1beta1.Rule{
+			{Path: "/headers", Enabled: &disabled},
+			{Path: "/headers"},
+		}
+
+		duplicates := DetectDuplicatePaths(rules)
+
+		Expect(duplicates).To(BeEmpty())
+	})
+})
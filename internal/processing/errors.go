@@ -0,0 +1,105 @@
+package processing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/validation"
+)
+
+// RuleError is returned if the reconciliation of a single rule of an APIRule fails. It carries the path of the rule
+// that caused the error, so the reason can be reported in the status of the APIRule.
+type RuleError struct {
+	Path string
+	Err  error
+}
+
+func NewRuleError(path string, err error) *RuleError {
+	return &RuleError{
+		Path: path,
+		Err:  err,
+	}
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("rule with path %s: %s", e.Path, e.Err)
+}
+
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
+// FieldError is returned if a field of an APIRule can't be processed. It carries the path of the field in the notation
+// of the validation failures, e.g. .spec.rules[2].timeout, so the error can be mapped to the field in the status. The
+// path is not part of the error message, since it is reported as the attribute of the failure.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func NewFieldError(field string, err error) *FieldError {
+	return &FieldError{
+		Field: field,
+		Err:   err,
+	}
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors collects the errors of multiple fields, so all invalid fields of an APIRule are reported at once.
+type FieldErrors []error
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (e FieldErrors) Unwrap() []error {
+	return e
+}
+
+// GetRuleField returns the path of the rule in the rules of the APIRule, e.g. .spec.rules[2]. The rule is identified by
+// its matches, since the rules are filtered and sorted before they are processed. If the rule is not found, e.g. because
+// it was expanded from the protocol ports of another rule, the path of the rules is returned.
+func GetRuleField(api *gatewayv1beta1.APIRule, rule gatewayv1beta1.Rule) string {
+	for i, r := range api.Spec.Rules {
+		if r.IsEnabled() && r.GetMatchKey() == rule.GetMatchKey() {
+			return fmt.Sprintf(".spec.rules[%d]", i)
+		}
+	}
+
+	return ".spec.rules"
+}
+
+// toFieldFailures returns a validation failure for each field error of the error, or nil if the error is not caused by
+// invalid fields only.
+func toFieldFailures(err error) []validation.Failure {
+	errs := []error{err}
+	var fieldErrs FieldErrors
+	if errors.As(err, &fieldErrs) {
+		errs = fieldErrs
+	}
+
+	var failures []validation.Failure
+	for _, e := range errs {
+		var fieldErr *FieldError
+		if !errors.As(e, &fieldErr) {
+			return nil
+		}
+		failures = append(failures, validation.Failure{AttributePath: fieldErr.Field, Message: e.Error()})
+	}
+
+	return failures
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/mitchellh/hashstructure/v2"
+	"istio.io/api/security/v1beta1"
 	securityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -30,7 +31,16 @@ func GetAuthorizationPolicyHash(ap *securityv1beta1.AuthorizationPolicy) (string
 
 	var hashTo uint64
 	if len(ap.Spec.Rules) > 0 && ap.Spec.Rules[0].To != nil {
-		hash, err := hashstructure.Hash(ap.Spec.Rules[0].To, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
+		var to interface{} = ap.Spec.Rules[0].To
+		// Policies with another action than ALLOW, e.g. the DENY policies of source restrictions, can have the same
+		// operations as the ALLOW policies of the rule. The action is only hashed for them to keep the existing hashes.
+		if ap.Spec.Action != v1beta1.AuthorizationPolicy_ALLOW {
+			to = struct {
+				To     interface{}
+				Action string
+			}{ap.Spec.Rules[0].To, ap.Spec.Action.String()}
+		}
+		hash, err := hashstructure.Hash(to, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
 		if err != nil {
 			return "", err
 		}
@@ -0,0 +1,55 @@
+package processing
+
+import (
+	"encoding/json"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+)
+
+// MaxRequestHeadersBytesAnnotation is set on the VirtualService if the request header size is limited for at least one
+// rule. Since the VirtualService has no field to limit the header size, an EnvoyFilter of the gateway is expected to read
+// this annotation. The value is a JSON object of the paths of these rules and their limit in bytes, e.g. {"/upload":8192}.
+const MaxRequestHeadersBytesAnnotation = "gateway.kyma-project.io/max-request-headers-bytes"
+
+// GetMaxRequestHeadersBytesAnnotation returns the value of the MaxRequestHeadersBytesAnnotation for the rules of the
+// APIRule. An empty value means that the header size is not limited for any rule and the annotation should not be set.
+func GetMaxRequestHeadersBytesAnnotation(api *gatewayv1beta1.APIRule) string {
+	limits := make(map[string]uint32)
+	for _, rule := range FilterDuplicatePaths(FilterDisabledRules(api.Spec.Rules)) {
+		if rule.MaxRequestHeadersBytes != nil {
+			limits[rule.Path] = *rule.MaxRequestHeadersBytes
+		}
+	}
+
+	if len(limits) == 0 {
+		return ""
+	}
+
+	value, _ := json.Marshal(limits)
+	return string(value)
+}
+
+// MergeMaxRequestHeadersBytesAnnotations returns the value of the MaxRequestHeadersBytesAnnotation with the limits of all
+// given values, which is used for the VirtualServices that aggregate the routes of multiple APIRules. If the same path
+// is limited by multiple values, the lowest limit is applied.
+func MergeMaxRequestHeadersBytesAnnotations(values ...string) string {
+	merged := make(map[string]uint32)
+	for _, value := range values {
+		var limits map[string]uint32
+		if err := json.Unmarshal([]byte(value), &limits); err != nil {
+			continue
+		}
+		for path, limit := range limits {
+			if current, ok := merged[path]; !ok || limit < current {
+				merged[path] = limit
+			}
+		}
+	}
+
+	if len(merged) == 0 {
+		return ""
+	}
+
+	value, _ := json.Marshal(merged)
+	return string(value)
+}
@@ -0,0 +1,64 @@
+package processing
+
+import (
+	"time"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/builders"
+)
+
+// IsHostPreserved returns true if the forwarded host header of the requests must not be set for the rule. The setting of the
+// rule takes precedence over the setting of the APIRule.
+func IsHostPreserved(api *gatewayv1beta1.APIRule, rule gatewayv1beta1.Rule) bool {
+	if rule.PreserveHost != nil {
+		return *rule.PreserveHost
+	}
+
+	return api.Spec.PreserveHost != nil && *api.Spec.PreserveHost
+}
+
+// HasForwardedHeaders returns true if the x-forwarded-for and x-forwarded-proto headers must be set for the requests.
+func HasForwardedHeaders(api *gatewayv1beta1.APIRule) bool {
+	return api.Spec.ForwardedHeaders != nil && *api.Spec.ForwardedHeaders
+}
+
+// ApplyRuleHeaders adds the deprecation headers and the request and response header operations defined on the rule to the
+// headers builder. The header operations of the rule overwrite the deprecation headers.
+func ApplyRuleHeaders(headersBuilder builders.HttpRouteHeadersBuilder, rule gatewayv1beta1.Rule) builders.HttpRouteHeadersBuilder {
+	if rule.Deprecation != nil {
+		headersBuilder.SetDeprecationHeaders(parseDeprecationDate(rule.Deprecation.Since), parseDeprecationDate(rule.Deprecation.Sunset))
+	}
+
+	if rule.Headers == nil {
+		return headersBuilder
+	}
+
+	if request := rule.Headers.Request; request != nil {
+		headersBuilder.SetRequestHeaders(request.Set).
+			AddRequestHeaders(request.Add).
+			RemoveRequestHeaders(request.Remove...)
+	}
+
+	if response := rule.Headers.Response; response != nil {
+		headersBuilder.SetResponseHeaders(response.Set).
+			AddResponseHeaders(response.Add).
+			RemoveResponseHeaders(response.Remove...)
+	}
+
+	return headersBuilder
+}
+
+// parseDeprecationDate returns the date of the deprecation of a rule, or nil if the date is not defined. Invalid dates
+// are reported by the validation of the APIRule.
+func parseDeprecationDate(value *string) *time.Time {
+	if value == nil {
+		return nil
+	}
+
+	date, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil
+	}
+
+	return &date
+}
@@ -1,17 +1,43 @@
 package processing
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 
 	gatewayv1alpha1 "github.com/kyma-project/api-gateway/api/v1alpha1"
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"istio.io/api/networking/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// OwnerIndex is the name of the field index of the generated objects by the value of their owner label. The cache of the
+// manager resolves a label selector by filtering all cached objects, while a field index is a direct lookup.
+const OwnerIndex = "metadata.labels.owner"
+
+// maxGenerateNameLength is the length to which the apiserver truncates the generateName of an object before it appends
+// five random characters, which keeps the generated name within the DNS limit.
+const maxGenerateNameLength = 58
+
+// generateNameHashLength is the length of the hash that keeps truncated generateNames of different APIRules distinct.
+const generateNameHashLength = 8
+
+// MaxVirtualServiceNameAffixLength is the maximum combined length of the prefix and suffix of the generated names of
+// the VirtualServices, so a truncated name still contains a part of the APIRule name.
+const MaxVirtualServiceNameAffixLength = 32
+
 var (
 	//OwnerLabel .
 	OwnerLabel = fmt.Sprintf("%s.%s", "apirule", gatewayv1beta1.GroupVersion.String())
 	//OwnerLabelv1alpha1 .
 	OwnerLabelv1alpha1 = fmt.Sprintf("%s.%s", "apirule", gatewayv1alpha1.GroupVersion.String())
+
+	routeNameInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+	nameAffixChars        = regexp.MustCompile(`^[a-z0-9-]*$`)
 )
 
 func HasJwtRule(api *gatewayv1beta1.APIRule) bool {
@@ -44,18 +70,158 @@ func IsSecured(rule gatewayv1beta1.Rule) bool {
 	return false
 }
 
+// ShouldRouteDirectlyToService returns true if the requests of the rule are routed directly to the service by the
+// Istio handler, otherwise they are routed to oathkeeper. Access strategies that don't need oathkeeper must be added here.
+func ShouldRouteDirectlyToService(rule gatewayv1beta1.Rule) bool {
+	switch {
+	case IsJwtSecured(rule):
+		// The JWT is validated by the RequestAuthentication and AuthorizationPolicy of the service.
+		return true
+	case !IsSecured(rule):
+		// Rules with only the allow access strategy and no mutators have nothing to be handled by oathkeeper.
+		return true
+	default:
+		return false
+	}
+}
+
+// GetVirtualServiceFixedName returns the deterministic name of the VirtualService that is created for the APIRule.
+func GetVirtualServiceFixedName(api *gatewayv1beta1.APIRule) string {
+	return fmt.Sprintf("%s-vs", api.ObjectMeta.Name)
+}
+
+// GetVirtualServiceGenerateName returns the generateName of the VirtualService that is created for the APIRule, which is
+// the name of the APIRule with the given prefix and suffix. The apiserver would cut off the end of a too long
+// generateName, so it is truncated here and a hash of the full name is added instead to keep the suffix and the
+// names of different APIRules distinct.
+func GetVirtualServiceGenerateName(api *gatewayv1beta1.APIRule, prefix string, suffix string) string {
+	name := prefix + api.ObjectMeta.Name + suffix
+	if len(name)+1 <= maxGenerateNameLength {
+		return name + "-"
+	}
+
+	hash := sha256.Sum256([]byte(name))
+	// The APIRule name is a DNS subdomain, so a dot must not be followed by the dash that separates the hash.
+	truncated := strings.TrimRight(name[:maxGenerateNameLength-len(suffix)-generateNameHashLength-2], ".-")
+	return fmt.Sprintf("%s-%s%s-", truncated, hex.EncodeToString(hash[:])[:generateNameHashLength], suffix)
+}
+
+// ValidateVirtualServiceNameAffixes returns an error if the prefix and suffix of the generated names of the
+// VirtualServices would not result in valid names.
+func ValidateVirtualServiceNameAffixes(prefix string, suffix string) error {
+	if !nameAffixChars.MatchString(prefix) || !nameAffixChars.MatchString(suffix) {
+		return errors.New("the prefix and suffix of the VirtualService names may only contain lowercase alphanumeric characters and dashes")
+	}
+	if len(prefix)+len(suffix) > MaxVirtualServiceNameAffixLength {
+		return fmt.Errorf("the prefix and suffix of the VirtualService names must not be longer than %d characters combined", MaxVirtualServiceNameAffixLength)
+	}
+
+	return nil
+}
+
+// GetOathkeeperTarget returns the host and port of the oathkeeper service the requests of the APIRule are routed to. The
+// target of the APIRule overrides the given default target.
+func GetOathkeeperTarget(api *gatewayv1beta1.APIRule, defaultHost string, defaultPort uint32) (string, uint32) {
+	if api.Spec.Oathkeeper != nil {
+		return api.Spec.Oathkeeper.Host, api.Spec.Oathkeeper.Port
+	}
+
+	return defaultHost, defaultPort
+}
+
+// GetRouteName returns the name of the HTTP route for the rule at the given index of the routes of the VirtualService. The
+// index makes the name unique within the VirtualService, the path is only added to identify the rule more easily. The
+// routes of all VirtualServices share the route configuration of the gateway and the EnvoyFilters of the Lua scripts
+// select the routes by name, so the name starts with the name and namespace of the APIRule like its owner label.
+func GetRouteName(api *gatewayv1beta1.APIRule, rule gatewayv1beta1.Rule, index int) string {
+	name := fmt.Sprintf("%s.%d", GetOwnerLabelValue(api), index)
+	if path := strings.Trim(routeNameInvalidChars.ReplaceAllString(strings.ToLower(rule.Path), "-"), "-"); path != "" {
+		name = fmt.Sprintf("%s-%s", name, path)
+	}
+
+	return name
+}
+
+// DefaultMatchedRuleHeader is the name of the response header with the name of the route of the matched rule, if no
+// other name is configured.
+const DefaultMatchedRuleHeader = "x-matched-rule"
+
 func GetOwnerLabels(api *gatewayv1beta1.APIRule) map[string]string {
 	labels := make(map[string]string)
-	labels[OwnerLabelv1alpha1] = fmt.Sprintf("%s.%s", api.ObjectMeta.Name, api.ObjectMeta.Namespace)
+	labels[OwnerLabelv1alpha1] = GetOwnerLabelValue(api)
 	return labels
 }
 
+// GetOwnerLabelValue returns the value of the owner labels of the objects generated for the APIRule, which is also the
+// value of the OwnerIndex. The creators and the lookup of the actual state use it, so the owner labels can't drift apart.
+func GetOwnerLabelValue(api *gatewayv1beta1.APIRule) string {
+	return fmt.Sprintf("%s.%s", api.ObjectMeta.Name, api.ObjectMeta.Namespace)
+}
+
+// IndexByOwner extracts the value of the OwnerIndex from the owner label of the object.
+func IndexByOwner(obj client.Object) []string {
+	owner, ok := obj.GetLabels()[OwnerLabelv1alpha1]
+	if !ok {
+		return nil
+	}
+	return []string{owner}
+}
+
+// GetRuleService returns the service of the rule, or the service on spec level of the APIRule if the rule doesn't define
+// one. An error is returned instead of the service if neither defines a service name and port, which is rejected by the
+// validation of the APIRule.
+func GetRuleService(api *gatewayv1beta1.APIRule, rule gatewayv1beta1.Rule) (*gatewayv1beta1.Service, error) {
+	service := rule.Service
+	if service == nil {
+		service = api.Spec.Service
+	}
+
+	switch {
+	case service == nil:
+		return nil, errors.New("no service defined for the rule and no service defined on spec level")
+	case service.Name == nil || *service.Name == "":
+		return nil, errors.New("service doesn't define a name")
+	case service.Port == nil:
+		return nil, fmt.Errorf("service %s doesn't define a port", *service.Name)
+	}
+
+	return service, nil
+}
+
+// PausedAnnotation pauses the APIRule if it is set to "true". The VirtualService of a paused APIRule is removed, so its
+// requests are no longer served, and it is created again once the annotation is removed.
+const PausedAnnotation = "gateway.kyma-project.io/paused"
+
+// IsPaused returns true if the APIRule is paused by the PausedAnnotation.
+func IsPaused(api *gatewayv1beta1.APIRule) bool {
+	return api.Annotations[PausedAnnotation] == "true"
+}
+
+// GetExportTo returns the namespaces to which the VirtualService of the APIRule is exported. The export of the APIRule
+// takes precedence over the default export, the VirtualService is exported to all namespaces if both are empty.
+func GetExportTo(api *gatewayv1beta1.APIRule, defaultExportTo []string) []string {
+	if len(api.Spec.ExportTo) > 0 {
+		return api.Spec.ExportTo
+	}
+
+	return defaultExportTo
+}
+
+// FilterDisabledRules returns the rules without the disabled rules.
+func FilterDisabledRules(rules []gatewayv1beta1.Rule) []gatewayv1beta1.Rule {
+	return FilterGeneric(rules, func(rule gatewayv1beta1.Rule) bool {
+		return rule.IsEnabled()
+	})
+}
+
+// FilterDuplicatePaths returns the rules without the rules that match the same requests as a previous rule, which are
+// rules with the same path, path match type, route type, case sensitivity, header and query parameter matches.
 func FilterDuplicatePaths(rules []gatewayv1beta1.Rule) []gatewayv1beta1.Rule {
 	duplicates := make(map[string]bool)
 	var filteredRules []gatewayv1beta1.Rule
 	for _, rule := range rules {
-		if _, exists := duplicates[rule.Path]; !exists {
-			duplicates[rule.Path] = true
+		if _, exists := duplicates[rule.GetMatchKey()]; !exists {
+			duplicates[rule.GetMatchKey()] = true
 			filteredRules = append(filteredRules, rule)
 		}
 	}
@@ -63,6 +229,132 @@ func FilterDuplicatePaths(rules []gatewayv1beta1.Rule) []gatewayv1beta1.Rule {
 	return filteredRules
 }
 
+// SortRulesBySpecificity returns the rules ordered so that catch-all rules are matched last and don't shadow the more
+// specific rules. The default backend rule is matched after all other catch-all rules. The order of rules with the same
+// specificity is preserved.
+func SortRulesBySpecificity(rules []gatewayv1beta1.Rule) []gatewayv1beta1.Rule {
+	sorted := make([]gatewayv1beta1.Rule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return specificityRank(sorted[i]) < specificityRank(sorted[j])
+	})
+
+	return sorted
+}
+
+// GetRoutedRules returns the enabled rules of the APIRule in the order of the routes of the VirtualService, so the index
+// of a rule is the index that GetRouteName expects for its route.
+func GetRoutedRules(api *gatewayv1beta1.APIRule) []gatewayv1beta1.Rule {
+	return SortRulesBySpecificity(FilterDuplicatePaths(FilterDisabledRules(api.Spec.Rules)))
+}
+
+func specificityRank(rule gatewayv1beta1.Rule) int {
+	switch {
+	case rule.DefaultBackend:
+		return 2
+	case IsCatchAllPath(rule):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// IsCatchAllPath returns true if the path of the rule matches all requests.
+func IsCatchAllPath(rule gatewayv1beta1.Rule) bool {
+	if rule.DefaultBackend {
+		return true
+	}
+
+	if GetPathMatchType(rule) == gatewayv1beta1.PathMatchPrefix {
+		return rule.Path == "/"
+	}
+
+	return rule.Path == "/*" || rule.Path == "/.*"
+}
+
+// GetPathMatchType returns the path match type of the rule. gRPC rules match the service path by prefix if no match type is defined.
+func GetPathMatchType(rule gatewayv1beta1.Rule) gatewayv1beta1.PathMatchType {
+	if rule.PathMatchType != "" {
+		return rule.PathMatchType
+	}
+
+	if rule.RouteType == gatewayv1beta1.RouteTypeGRPC {
+		return gatewayv1beta1.PathMatchPrefix
+	}
+
+	return gatewayv1beta1.PathMatchRegex
+}
+
+// GetAccessRulePath returns the path of the rule as the regular expression that the URL of an access rule is matched with,
+// so oathkeeper matches the same requests as the route of the rule.
+func GetAccessRulePath(rule gatewayv1beta1.Rule) string {
+	switch {
+	case rule.DefaultBackend:
+		return "/.*"
+	case GetPathMatchType(rule) == gatewayv1beta1.PathMatchExact:
+		return regexp.QuoteMeta(rule.Path)
+	case GetPathMatchType(rule) == gatewayv1beta1.PathMatchPrefix:
+		return regexp.QuoteMeta(rule.Path) + ".*"
+	default:
+		return rule.Path
+	}
+}
+
+// GetStrippedPrefix returns the prefix that is removed from the request URI of the rule before the request is
+// forwarded, and false if no prefix is stripped. The trailing slash of the path is not part of the prefix, so the
+// forwarded path always starts with a slash.
+func GetStrippedPrefix(rule gatewayv1beta1.Rule) (string, bool) {
+	if !rule.StripPrefix || rule.DefaultBackend || GetPathMatchType(rule) != gatewayv1beta1.PathMatchPrefix {
+		return "", false
+	}
+
+	return strings.TrimSuffix(rule.Path, "/"), true
+}
+
+// GetDuplicatedMatches returns the match keys of the requests that are matched by more than one rule.
+func GetDuplicatedMatches(rules []gatewayv1beta1.Rule) map[string]bool {
+	matches := make(map[string]bool)
+	duplicatedMatches := make(map[string]bool)
+	for _, rule := range rules {
+		if matches[rule.GetMatchKey()] {
+			duplicatedMatches[rule.GetMatchKey()] = true
+		}
+		matches[rule.GetMatchKey()] = true
+	}
+
+	return duplicatedMatches
+}
+
+// GetHeaderMatches returns the header matches of the rule.
+func GetHeaderMatches(rule gatewayv1beta1.Rule) map[string]*v1beta1.StringMatch {
+	return toIstioStringMatches(rule.MatchHeaders)
+}
+
+// GetQueryParamMatches returns the query parameter matches of the rule.
+func GetQueryParamMatches(rule gatewayv1beta1.Rule) map[string]*v1beta1.StringMatch {
+	return toIstioStringMatches(rule.MatchQueryParams)
+}
+
+func toIstioStringMatches(matches map[string]gatewayv1beta1.StringMatch) map[string]*v1beta1.StringMatch {
+	if len(matches) == 0 {
+		return nil
+	}
+
+	result := make(map[string]*v1beta1.StringMatch, len(matches))
+	for name, match := range matches {
+		switch {
+		case match.Exact != "":
+			result[name] = &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Exact{Exact: match.Exact}}
+		case match.Prefix != "":
+			result[name] = &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Prefix{Prefix: match.Prefix}}
+		default:
+			result[name] = &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Regex{Regex: match.Regex}}
+		}
+	}
+
+	return result
+}
+
 func FilterAccessStrategies(accessStrategies []*gatewayv1beta1.Authenticator, includeAllow bool, includeOryOnly bool, includeJwt bool) []*gatewayv1beta1.Authenticator {
 	filterFunc := func(auth *gatewayv1beta1.Authenticator) bool {
 		return ((includeAllow && auth.Handler.Name == "allow") ||
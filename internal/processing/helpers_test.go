@@ -0,0 +1,245 @@
+package processing
+
+import (
+	"strings"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func ruleWithStrategies(mutators []*gatewayv1beta1.Mutator, strategies ...string) gatewayv1beta1.Rule {
+	var accessStrategies []*gatewayv1beta1.Authenticator
+	for _, strategy := range strategies {
+		accessStrategies = append(accessStrategies, &gatewayv1beta1.Authenticator{Handler: &gatewayv1beta1.Handler{Name: strategy}})
+	}
+
+	return gatewayv1beta1.Rule{Path: "/headers", AccessStrategies: accessStrategies, Mutators: mutators}
+}
+
+var _ = Describe("SortRulesBySpecificity", func() {
+	It("should move the catch-all rules behind the specific rules and keep the order of the specific rules", func() {
+		rules := []gatewayv1beta1.Rule{
+			{Path: "/*"},
+			{Path: "/b"},
+			{Path: "/.*"},
+			{Path: "/a"},
+			{Path: "/", PathMatchType: gatewayv1beta1.PathMatchPrefix},
+			{Path: "/c", PathMatchType: gatewayv1beta1.PathMatchPrefix},
+		}
+
+		sorted := SortRulesBySpecificity(rules)
+
+		var paths []string
+		for _, rule := range sorted {
+			paths = append(paths, rule.Path)
+		}
+		Expect(paths).To(Equal([]string{"/b", "/a", "/c", "/*", "/.*", "/"}))
+		Expect(rules[0].Path).To(Equal("/*"))
+	})
+
+	It("should not treat the exact path / as catch-all", func() {
+		rules := []gatewayv1beta1.Rule{
+			{Path: "/", PathMatchType: gatewayv1beta1.PathMatchExact},
+			{Path: "/a"},
+		}
+
+		sorted := SortRulesBySpecificity(rules)
+
+		Expect(sorted[0].Path).To(Equal("/"))
+		Expect(sorted[1].Path).To(Equal("/a"))
+	})
+})
+
+var _ = Describe("GetOwnerLabelValue", func() {
+	It("should be the value of the owner labels that are looked up", func() {
+		apiRule := &gatewayv1beta1.APIRule{ObjectMeta: metav1.ObjectMeta{Name: "test-apirule", Namespace: "test-namespace"}}
+
+		Expect(GetOwnerLabelValue(apiRule)).To(Equal("test-apirule.test-namespace"))
+		Expect(GetOwnerLabels(apiRule)).To(Equal(map[string]string{OwnerLabelv1alpha1: "test-apirule.test-namespace"}))
+	})
+})
+
+var _ = Describe("GetVirtualServiceGenerateName", func() {
+	apiRule := func(name string) *gatewayv1beta1.APIRule {
+		return &gatewayv1beta1.APIRule{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace"}}
+	}
+
+	DescribeTable("should add the prefix and suffix to the APIRule name",
+		func(prefix string, suffix string, expected string) {
+			Expect(GetVirtualServiceGenerateName(apiRule("test-apirule"), prefix, suffix)).To(Equal(expected))
+		},
+		Entry("neither prefix nor suffix", "", "", "test-apirule-"),
+		Entry("prefix", "dev-", "", "dev-test-apirule-"),
+		Entry("suffix", "", "-eu", "test-apirule-eu-"),
+		Entry("prefix and suffix", "dev-", "-eu", "dev-test-apirule-eu-"),
+	)
+
+	It("should truncate a too long name and keep the suffix", func() {
+		name := strings.Repeat("a", 60)
+
+		generateName := GetVirtualServiceGenerateName(apiRule(name), "dev-", "-eu")
+
+		Expect(generateName).To(HaveLen(maxGenerateNameLength))
+		Expect(generateName).To(HavePrefix("dev-aaaa"))
+		Expect(generateName).To(MatchRegexp(`-[0-9a-f]{8}-eu-$`))
+	})
+
+	It("should keep the truncated names of different APIRules distinct", func() {
+		first := GetVirtualServiceGenerateName(apiRule(strings.Repeat("a", 60)+"-first"), "", "")
+		second := GetVirtualServiceGenerateName(apiRule(strings.Repeat("a", 60)+"-second"), "", "")
+
+		Expect(first).To(HaveLen(maxGenerateNameLength))
+		Expect(second).To(HaveLen(maxGenerateNameLength))
+		Expect(first).ToNot(Equal(second))
+	})
+
+	It("should not keep a dot in front of the hash of a truncated name", func() {
+		name := strings.Repeat("a", 47) + "." + strings.Repeat("b", 20)
+
+		generateName := GetVirtualServiceGenerateName(apiRule(name), "", "")
+
+		Expect(generateName).To(MatchRegexp(`^a{47}-[0-9a-f]{8}-$`))
+	})
+})
+
+var _ = Describe("ValidateVirtualServiceNameAffixes", func() {
+	DescribeTable("should validate the prefix and suffix",
+		func(prefix string, suffix string, expectedError string) {
+			err := ValidateVirtualServiceNameAffixes(prefix, suffix)
+
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(expectedError)))
+			}
+		},
+		Entry("no prefix and suffix", "", "", ""),
+		Entry("valid prefix and suffix", "dev-", "-eu-1", ""),
+		Entry("uppercase prefix", "Dev-", "", "may only contain lowercase alphanumeric characters and dashes"),
+		Entry("suffix with a dot", "", ".eu", "may only contain lowercase alphanumeric characters and dashes"),
+		Entry("too long prefix and suffix", strings.Repeat("a", 20), strings.Repeat("b", 13), "must not be longer than 32 characters combined"),
+	)
+})
+
+var _ = Describe("GetRuleService", func() {
+	name := "rule-service"
+	specName := "spec-service"
+	var port uint32 = 8080
+	ruleService := &gatewayv1beta1.Service{Name: &name, Port: &port}
+	specService := &gatewayv1beta1.Service{Name: &specName, Port: &port}
+
+	DescribeTable("should return the service of the rule or the spec",
+		func(specService *gatewayv1beta1.Service, ruleService *gatewayv1beta1.Service, expectedService *gatewayv1beta1.Service, expectedError string) {
+			apiRule := &gatewayv1beta1.APIRule{Spec: gatewayv1beta1.APIRuleSpec{Service: specService}}
+
+			service, err := GetRuleService(apiRule, gatewayv1beta1.Rule{Service: ruleService})
+
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(service).To(Equal(expectedService))
+			} else {
+				Expect(err).To(MatchError(expectedError))
+				Expect(service).To(BeNil())
+			}
+		},
+		Entry("rule service only", nil, ruleService, ruleService, ""),
+		Entry("spec service only", specService, nil, specService, ""),
+		Entry("rule and spec service", specService, ruleService, ruleService, ""),
+		Entry("neither rule nor spec service", nil, nil, nil, "no service defined for the rule and no service defined on spec level"),
+		Entry("service without name", &gatewayv1beta1.Service{Port: &port}, nil, nil, "service doesn't define a name"),
+		Entry("service without port", &gatewayv1beta1.Service{Name: &specName}, nil, nil, "service spec-service doesn't define a port"),
+	)
+})
+
+var _ = Describe("GetRouteName", func() {
+	api := &gatewayv1beta1.APIRule{ObjectMeta: metav1.ObjectMeta{Name: "httpbin", Namespace: "default"}}
+
+	DescribeTable("should derive the route name from the APIRule name and namespace, the index and the path",
+		func(path string, index int, expectedName string) {
+			Expect(GetRouteName(api, gatewayv1beta1.Rule{Path: path}, index)).To(Equal(expectedName))
+		},
+		Entry("simple path", "/headers", 0, "httpbin.default.0-headers"),
+		Entry("path with regex", "/api/v1/.*", 1, "httpbin.default.1-api-v1"),
+		Entry("path with upper case characters", "/Status/{id}", 2, "httpbin.default.2-status-id"),
+		Entry("catch-all path", "/.*", 3, "httpbin.default.3"),
+	)
+
+	It("should derive different route names for APIRules with the same name in different namespaces", func() {
+		other := &gatewayv1beta1.APIRule{ObjectMeta: metav1.ObjectMeta{Name: "httpbin", Namespace: "other"}}
+		rule := gatewayv1beta1.Rule{Path: "/headers"}
+
+		Expect(GetRouteName(api, rule, 0)).ToNot(Equal(GetRouteName(other, rule, 0)))
+	})
+})
+
+var _ = Describe("ShouldRouteDirectlyToService", func() {
+	mutators := []*gatewayv1beta1.Mutator{{Handler: &gatewayv1beta1.Handler{Name: "header"}}}
+
+	DescribeTable("should route the requests of the access strategies to the expected target",
+		func(rule gatewayv1beta1.Rule, expectDirectRouting bool) {
+			Expect(ShouldRouteDirectlyToService(rule)).To(Equal(expectDirectRouting))
+		},
+		Entry("allow routes to the service", ruleWithStrategies(nil, "allow"), true),
+		Entry("allow with mutators routes to oathkeeper", ruleWithStrategies(mutators, "allow"), false),
+		Entry("jwt routes to the service", ruleWithStrategies(nil, "jwt"), true),
+		Entry("jwt with mutators routes to the service", ruleWithStrategies(mutators, "jwt"), true),
+		Entry("noop routes to oathkeeper", ruleWithStrategies(nil, "noop"), false),
+		Entry("oauth2_introspection routes to oathkeeper", ruleWithStrategies(nil, "oauth2_introspection"), false),
+		Entry("cookie_session routes to oathkeeper", ruleWithStrategies(nil, "cookie_session"), false),
+		Entry("noop and jwt routes to the service", ruleWithStrategies(nil, "noop", "jwt"), true),
+		Entry("allow and noop routes to oathkeeper", ruleWithStrategies(nil, "allow", "noop"), false),
+		Entry("no access strategy routes to the service", ruleWithStrategies(nil), true),
+	)
+})
+
+var _ = Describe("GetAccessRulePath", func() {
+	DescribeTable("should match the same requests as the route of the rule",
+		func(rule gatewayv1beta1.Rule, expectedPath string) {
+			Expect(GetAccessRulePath(rule)).To(Equal(expectedPath))
+		},
+		Entry("regex", gatewayv1beta1.Rule{Path: "/api/.*"}, "/api/.*"),
+		Entry("exact", gatewayv1beta1.Rule{Path: "/api/v1.0", PathMatchType: gatewayv1beta1.PathMatchExact}, `/api/v1\.0`),
+		Entry("prefix", gatewayv1beta1.Rule{Path: "/api", PathMatchType: gatewayv1beta1.PathMatchPrefix}, "/api.*"),
+		Entry("grpc prefix by default", gatewayv1beta1.Rule{Path: "/pkg.Service/", RouteType: gatewayv1beta1.RouteTypeGRPC}, `/pkg\.Service/.*`),
+		Entry("default backend", gatewayv1beta1.Rule{Path: "/", DefaultBackend: true}, "/.*"),
+	)
+})
+
+var _ = Describe("FilterDuplicatePaths", func() {
+	It("should keep the rules with the same path but a different path match type", func() {
+		rules := []gatewayv1beta1.Rule{
+			{Path: "/api", PathMatchType: gatewayv1beta1.PathMatchPrefix},
+			{Path: "/api", PathMatchType: gatewayv1beta1.PathMatchExact},
+			{Path: "/api"},
+		}
+
+		filteredRules := FilterDuplicatePaths(rules)
+
+		Expect(filteredRules).To(Equal(rules))
+	})
+
+	It("should keep the rules with the same path but a different route type or case sensitivity", func() {
+		rules := []gatewayv1beta1.Rule{
+			{Path: "/api", PathMatchType: gatewayv1beta1.PathMatchPrefix},
+			{Path: "/api", RouteType: gatewayv1beta1.RouteTypeGRPC},
+			{Path: "/api", PathMatchType: gatewayv1beta1.PathMatchPrefix, IgnorePathCase: true},
+		}
+
+		filteredRules := FilterDuplicatePaths(rules)
+
+		Expect(filteredRules).To(Equal(rules))
+	})
+
+	It("should filter the rules with the regex path match type as duplicates of the rules with the default path match type", func() {
+		rules := []gatewayv1beta1.Rule{
+			{Path: "/api", Methods: []string{"GET"}},
+			{Path: "/api", PathMatchType: gatewayv1beta1.PathMatchRegex, Methods: []string{"POST"}},
+		}
+
+		filteredRules := FilterDuplicatePaths(rules)
+
+		Expect(filteredRules).To(Equal(rules[:1]))
+	})
+})
@@ -0,0 +1,270 @@
+package processing
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/builders"
+	"github.com/kyma-project/api-gateway/internal/helpers"
+	"istio.io/api/networking/v1beta1"
+	"k8s.io/utils/strings/slices"
+)
+
+// HTTPRouteConfig is the configuration of the routes of the Virtual Services, which are created the same way by all
+// handlers apart from the routing target of the rules.
+type HTTPRouteConfig struct {
+	OathkeeperSvc     string
+	OathkeeperSvcPort uint32
+	CorsConfig        *CorsConfig
+	HTTPTimeout       *time.Duration
+	RetryConfig       *RetryConfig
+	CatchAllPathRegex string
+	MatchedRuleHeader string
+	// CatchAllPathPrefix matches the requests of rules with the path /* by the prefix / if no CatchAllPathRegex is set,
+	// otherwise the path is matched as a regex.
+	CatchAllPathPrefix bool
+	// RouteDirectlyToService returns true if the requests of the rule are routed to the service, and false if they are
+	// forwarded to oathkeeper.
+	RouteDirectlyToService func(rule gatewayv1beta1.Rule) bool
+}
+
+// NewHTTPRouteConfig returns the configuration of the routes from the reconciliation config. The handler specific
+// fields are not set.
+func NewHTTPRouteConfig(config ReconciliationConfig) HTTPRouteConfig {
+	return HTTPRouteConfig{
+		OathkeeperSvc:     config.OathkeeperSvc,
+		OathkeeperSvcPort: config.OathkeeperSvcPort,
+		CorsConfig:        config.CorsConfig,
+		HTTPTimeout:       config.HTTPTimeout,
+		RetryConfig:       config.RetryConfig,
+		CatchAllPathRegex: config.CatchAllPathRegex,
+		MatchedRuleHeader: config.MatchedRuleHeader,
+	}
+}
+
+// NewHTTPRoute returns the route of the rule with the given index for the resolved hosts of the Virtual Service. The
+// duplicated matches are the match keys of the rules that are merged into a single route. No route is returned if the
+// rule has no service to route to, the errors of all invalid fields of the rule are returned.
+func NewHTTPRoute(api *gatewayv1beta1.APIRule, rule gatewayv1beta1.Rule, index int, hosts []string, duplicatedMatches map[string]bool, config HTTPRouteConfig) (*v1beta1.HTTPRoute, FieldErrors) {
+	field := GetRuleField(api, rule)
+	routeName := GetRouteName(api, rule, index)
+	httpRouteBuilder := builders.HTTPRoute().Name(routeName)
+	serviceNamespace := helpers.FindServiceNamespace(api, &rule)
+	routeDirectlyToService := config.RouteDirectlyToService(rule)
+
+	// Redirects, direct responses and weighted destinations are only supported for rules with the allow access strategy, since the
+	// traffic of all other access strategies is either handled by oathkeeper or secured by an authorization policy
+	// for a single service.
+	// The maintenance response replaces the routing of all rules, independent of their access strategy.
+	maintenance := IsInMaintenance(api)
+	redirect := !maintenance && !IsSecured(rule) && rule.Redirect != nil
+	directResponse := !maintenance && !IsSecured(rule) && rule.DirectResponse != nil
+	forwarded := !maintenance && !redirect && !directResponse
+	if maintenance {
+		httpRouteBuilder.DirectResponse(builders.HTTPDirectResponse().
+			Status(GetMaintenanceStatus(api)).
+			Body(api.Spec.Maintenance.Body))
+	} else if redirect {
+		httpRouteBuilder.Redirect(builders.HTTPRedirect().
+			Uri(rule.Redirect.URI).
+			Scheme(rule.Redirect.Scheme).
+			Authority(rule.Redirect.Authority).
+			RedirectCode(rule.Redirect.Code))
+	} else if directResponse {
+		httpRouteBuilder.DirectResponse(builders.HTTPDirectResponse().
+			Status(rule.DirectResponse.Status).
+			Body(rule.DirectResponse.Body))
+	} else if !IsSecured(rule) && len(rule.Destinations) > 0 {
+		for _, destination := range rule.Destinations {
+			destinationHost := helpers.GetHostLocalDomain(*destination.Name, helpers.FindDestinationNamespace(api, &destination.Service))
+			httpRouteBuilder.Route(builders.RouteDestination().Host(destinationHost).Port(*destination.Port).Weight(destination.Weight))
+		}
+	} else {
+		var host, subset string
+		var port uint32
+
+		if routeDirectlyToService {
+			subset = rule.Subset
+			service, err := GetRuleService(api, rule)
+			if err != nil {
+				return nil, FieldErrors{NewRuleError(rule.Path, NewFieldError(field+".service", err))}
+			}
+			host = helpers.GetHostLocalDomain(*service.Name, serviceNamespace)
+			port = *service.Port
+		} else {
+			host, port = GetOathkeeperTarget(api, config.OathkeeperSvc, config.OathkeeperSvcPort)
+		}
+
+		httpRouteBuilder.Route(builders.RouteDestination().Host(host).Port(port).Subset(subset))
+	}
+
+	// Requests that are forwarded to oathkeeper must keep the original URI, since the access rules match on it.
+	strippedPrefix, stripPrefix := GetStrippedPrefix(rule)
+	stripPrefix = stripPrefix && routeDirectlyToService && forwarded
+	if routeDirectlyToService && (rule.Rewrite != nil || stripPrefix) && forwarded {
+		rewriteBuilder := builders.HTTPRewrite()
+		if rule.Rewrite != nil {
+			rewriteBuilder.Uri(rule.Rewrite.URI).Authority(rule.Rewrite.Authority)
+		}
+		// The matched prefix of the URI is replaced by the rewrite, so the prefix is replaced by the root path.
+		if stripPrefix {
+			rewriteBuilder.Uri("/")
+		}
+		httpRouteBuilder.Rewrite(rewriteBuilder)
+	}
+
+	if !IsSecured(rule) && rule.Mirror != nil && forwarded {
+		mirrorHost := helpers.GetHostLocalDomain(*rule.Mirror.Name, helpers.FindDestinationNamespace(api, &rule.Mirror.Service))
+		httpRouteBuilder.Mirror(mirrorHost, *rule.Mirror.Port)
+		if rule.Mirror.Percentage != nil {
+			httpRouteBuilder.MirrorPercentage(float64(*rule.Mirror.Percentage))
+		}
+	}
+
+	matchBuilder := builders.MatchRequest()
+	switch {
+	case rule.DefaultBackend:
+		matchBuilder.Uri().Prefix("/")
+	case GetPathMatchType(rule) == gatewayv1beta1.PathMatchExact:
+		matchBuilder.Uri().Exact(rule.Path)
+	case stripPrefix:
+		matchBuilder.Uri().Prefix(strippedPrefix + "/")
+	case GetPathMatchType(rule) == gatewayv1beta1.PathMatchPrefix:
+		matchBuilder.Uri().Prefix(rule.Path)
+	default:
+		if rule.Path == "/*" && config.CatchAllPathRegex != "" {
+			matchBuilder.Uri().Regex(config.CatchAllPathRegex)
+		} else if rule.Path == "/*" && config.CatchAllPathPrefix {
+			matchBuilder.Uri().Prefix("/")
+		} else {
+			matchBuilder.Uri().Regex(rule.Path)
+		}
+	}
+
+	matchBuilder.IgnoreUriCase(rule.IgnorePathCase)
+
+	if headers := GetHeaderMatches(rule); headers != nil {
+		matchBuilder.Headers(headers)
+	}
+	if queryParams := GetQueryParamMatches(rule); queryParams != nil {
+		matchBuilder.QueryParams(queryParams)
+	}
+
+	// The methods of the requests that are forwarded to oathkeeper are matched by the access rules. Rules with the same
+	// path, header and query parameter matches are merged into a single route by filtering the duplicates, therefore
+	// the methods can only be matched if the match is unique. gRPC requests are always sent with POST, so the methods
+	// are not matched for gRPC rules.
+	if routeDirectlyToService && len(rule.Methods) > 0 && !duplicatedMatches[rule.GetMatchKey()] && rule.RouteType != gatewayv1beta1.RouteTypeGRPC {
+		methods := rule.Methods
+		// The CORS preflight requests are sent with OPTIONS and must match the route to get the CORS policy applied.
+		if !IsCorsDisabled(api) && !slices.Contains(methods, http.MethodOptions) {
+			methods = append(slices.Clone(methods), http.MethodOptions)
+		}
+		if len(methods) == 1 {
+			matchBuilder.Method().Exact(methods[0])
+		} else {
+			matchBuilder.Method().Regex(strings.Join(methods, "|"))
+		}
+	}
+	httpRouteBuilder.Match(matchBuilder)
+	// The prefix is matched with a trailing slash, so it isn't stripped from longer path segments like /service-ab of
+	// the prefix /service-a. The prefix without the trailing slash is matched exactly instead.
+	if stripPrefix && strippedPrefix != "" {
+		httpRouteBuilder.Match(matchBuilder.Copy().Uri().Exact(strippedPrefix))
+	}
+	if !IsCorsDisabled(api) {
+		corsConfig := GetRuleCorsConfig(api, rule, config.CorsConfig)
+		httpRouteBuilder.CorsPolicy(builders.CorsPolicy().
+			AllowOrigins(corsConfig.AllowOrigins...).
+			AllowMethods(corsConfig.AllowMethods...).
+			AllowHeaders(corsConfig.AllowHeaders...).
+			ExposeHeaders(corsConfig.ExposeHeaders...).
+			AllowCredentials(corsConfig.AllowCredentials).
+			MaxAge(corsConfig.MaxAge))
+	}
+
+	var errs FieldErrors
+	// Timeout, retries and faults only apply to requests that are forwarded to a destination.
+	if forwarded {
+		timeout, err := GetRuleTimeout(api, rule, config.HTTPTimeout)
+		if err != nil {
+			errs = append(errs, NewRuleError(rule.Path, NewFieldError(field+".timeout", fmt.Errorf("invalid timeout: %w", err))))
+		}
+		if timeout != nil {
+			httpRouteBuilder.Timeout(*timeout)
+		}
+
+		retryConfig, err := GetRuleRetryConfig(rule, config.RetryConfig)
+		if err != nil {
+			errs = append(errs, NewRuleError(rule.Path, NewFieldError(field+".retries", fmt.Errorf("invalid retries: %w", err))))
+		}
+		if retryConfig != nil {
+			httpRouteBuilder.Retries(retryConfig.Attempts, retryConfig.PerTryTimeout, retryConfig.RetryOn)
+		}
+
+		if rule.Fault != nil {
+			faultBuilder := builders.HTTPFaultInjection()
+			if rule.Fault.Delay != nil {
+				fixedDelay, err := time.ParseDuration(rule.Fault.Delay.FixedDelay)
+				if err != nil {
+					errs = append(errs, NewRuleError(rule.Path, NewFieldError(field+".fault.delay.fixedDelay", fmt.Errorf("invalid fault delay: %w", err))))
+				}
+				faultBuilder.Delay(fixedDelay, float64(rule.Fault.Delay.Percentage))
+			}
+			if rule.Fault.Abort != nil {
+				faultBuilder.Abort(rule.Fault.Abort.HTTPStatus, float64(rule.Fault.Abort.Percentage))
+			}
+			httpRouteBuilder.Fault(faultBuilder)
+		}
+	}
+
+	headersBuilder := builders.NewHttpRouteHeadersBuilder()
+	// A route that serves multiple hosts or the subdomains of a wildcard host can't set a fixed forwarded host, so the
+	// authority of the request is forwarded.
+	if len(hosts) == 1 && !helpers.IsWildcardHost(hosts[0]) && !IsHostPreserved(api, rule) {
+		headersBuilder.SetHostHeader(hosts[0])
+	} else if len(hosts) > 0 && !IsHostPreserved(api, rule) {
+		headersBuilder.SetHostHeaderFromAuthority()
+	}
+	if HasForwardedHeaders(api) {
+		headersBuilder.SetForwardedHeaders()
+	}
+	// The header operations of the rule are applied before the mutators, so the mutators of JWT rules take precedence.
+	headersBuilder = ApplyRuleHeaders(headersBuilder, rule)
+	if headers := GetMaintenanceResponseHeaders(api); maintenance && len(headers) > 0 {
+		headersBuilder.SetResponseHeaders(headers)
+	}
+	if config.MatchedRuleHeader != "" {
+		headersBuilder.SetResponseHeaders(map[string]string{config.MatchedRuleHeader: routeName})
+	}
+
+	// We need to add mutators only for JWT secured rules that are routed directly to the service, since the rules handled
+	// by oathkeeper create access rules and therefore use ory mutators. The "allow" access strategy does not support
+	// mutators at all.
+	if routeDirectlyToService && IsJwtSecured(rule) {
+		cookieMutator, err := rule.GetCookieMutator()
+		if err != nil {
+			errs = append(errs, NewRuleError(rule.Path, NewFieldError(field+".mutators", fmt.Errorf("invalid cookie mutator: %w", err))))
+		}
+		if cookieMutator.HasCookies() {
+			headersBuilder.SetRequestCookies(cookieMutator.ToString())
+		}
+
+		headerMutator, err := rule.GetHeaderMutator()
+		if err != nil {
+			errs = append(errs, NewRuleError(rule.Path, NewFieldError(field+".mutators", fmt.Errorf("invalid header mutator: %w", err))))
+		}
+		if headerMutator.HasHeaders() {
+			headersBuilder.SetRequestHeaders(headerMutator.Headers)
+		}
+	}
+	// The preserved headers are forwarded unchanged, so they must not be touched by the header operations or mutators.
+	headersBuilder.PreserveRequestHeaders(rule.PreserveHeaders...)
+
+	httpRouteBuilder.Headers(headersBuilder.Get())
+
+	return httpRouteBuilder.Get(), errs
+}
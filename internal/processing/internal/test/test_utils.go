@@ -9,6 +9,7 @@ import (
 	. "github.com/onsi/gomega"
 	rulev1alpha1 "github.com/ory/oathkeeper-maester/api/v1alpha1"
 	"istio.io/api/networking/v1beta1"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
 	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 	securityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,7 +36,7 @@ const (
 	OathkeeperSvcPort uint32    = 1234
 	TestLabelKey                = "key"
 	TestLabelValue              = "value"
-	DefaultDomain               = "myDomain.com"
+	DefaultDomain               = "mydomain.com"
 	TestSelectorKey             = "app"
 )
 
@@ -45,7 +46,7 @@ var (
 	ServicePort             uint32 = 8080
 	ApiGateway                     = "some-gateway"
 	ServiceName                    = "example-service"
-	ServiceHostWithNoDomain        = "myService"
+	ServiceHostWithNoDomain        = "myservice"
 	ServiceHost                    = ServiceHostWithNoDomain + "." + DefaultDomain
 
 	TestAllowOrigin  = []*v1beta1.StringMatch{{MatchType: &v1beta1.StringMatch_Regex{Regex: ".*"}}}
@@ -74,10 +75,14 @@ func GetFakeClient(objs ...client.Object) client.Client {
 	scheme := runtime.NewScheme()
 	err := networkingv1beta1.AddToScheme(scheme)
 	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	err = networkingv1alpha3.AddToScheme(scheme)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
 	err = rulev1alpha1.AddToScheme(scheme)
 	gomega.Expect(err).NotTo(gomega.HaveOccurred())
 	err = securityv1beta1.AddToScheme(scheme)
 	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	err = apirulev1beta1.AddToScheme(scheme)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
 }
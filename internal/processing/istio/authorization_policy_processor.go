@@ -19,6 +19,8 @@ const (
 )
 
 var (
+	// The scopes are provided by the providers either as space-delimited string, e.g. in the scope claim, or as array,
+	// e.g. in the scp claim. Istio matches both formats, therefore a rule is generated for each of the claims.
 	defaultScopeKeys = []string{"request.auth.claims[scp]", "request.auth.claims[scope]", "request.auth.claims[scopes]"}
 )
 
@@ -36,12 +38,17 @@ type authorizationPolicyCreator struct {
 	additionalLabels map[string]string
 }
 
-// Create returns the JwtAuthorization Policy using the configuration of the APIRule.
+// Create returns the JwtAuthorization Policy and the policies restricting the source CIDRs using the configuration of the APIRule.
 func (r authorizationPolicyCreator) Create(api *gatewayv1beta1.APIRule) (hashbasedstate.Desired, error) {
 	state := hashbasedstate.NewDesired()
 	hasJwtRule := processing.HasJwtRule(api)
 	if hasJwtRule {
 		for _, rule := range api.Spec.Rules {
+			// Redirected requests, direct responses and rules without a service on rule or spec level don't reach a
+			// workload that could be selected by an authorization policy.
+			if rule.Redirect != nil || rule.DirectResponse != nil || (rule.Service == nil && api.Spec.Service == nil) {
+				continue
+			}
 			aps, err := generateAuthorizationPolicies(api, rule, r.additionalLabels)
 			if err != nil {
 				return state, err
@@ -57,6 +64,11 @@ func (r authorizationPolicyCreator) Create(api *gatewayv1beta1.APIRule) (hashbas
 			}
 		}
 	}
+
+	if err := processing.AddSourceAuthorizationPolicies(&state, api, r.additionalLabels); err != nil {
+		return state, err
+	}
+
 	return state, nil
 }
 
@@ -99,8 +111,8 @@ func generateAuthorizationPolicy(api *gatewayv1beta1.APIRule, rule gatewayv1beta
 		WithGenerateName(namePrefix).
 		WithNamespace(namespace).
 		WithSpec(builders.NewAuthorizationPolicySpecBuilder().FromAP(generateAuthorizationPolicySpec(api, rule, authorization)).Get()).
-		WithLabel(processing.OwnerLabel, fmt.Sprintf("%s.%s", api.ObjectMeta.Name, api.ObjectMeta.Namespace)).
-		WithLabel(processing.OwnerLabelv1alpha1, fmt.Sprintf("%s.%s", api.ObjectMeta.Name, api.ObjectMeta.Namespace))
+		WithLabel(processing.OwnerLabel, processing.GetOwnerLabelValue(api)).
+		WithLabel(processing.OwnerLabelv1alpha1, processing.GetOwnerLabelValue(api))
 
 	for k, v := range additionalLabels {
 		apBuilder.WithLabel(k, v)
@@ -149,19 +161,10 @@ func generateAuthorizationPolicySpec(api *gatewayv1beta1.APIRule, rule gatewayv1
 }
 
 func withTo(b *builders.RuleBuilder, rule gatewayv1beta1.Rule) *builders.RuleBuilder {
-	// APIRule and VirtualService supported a regex match. Since AuthorizationPolicy supports only prefix, suffix and wildcard
-	// and we have clusters with "/.*" in APIRule, we need special handling of this case.
-	if rule.Path == "/.*" {
-		return b.WithTo(
-			builders.NewToBuilder().
-				WithOperation(builders.NewOperationBuilder().
-					WithMethods(rule.Methods).WithPath("/*").Get()).
-				Get())
-	}
 	return b.WithTo(
 		builders.NewToBuilder().
 			WithOperation(builders.NewOperationBuilder().
-				WithMethods(rule.Methods).WithPath(rule.Path).Get()).
+				WithMethods(rule.Methods).WithPath(processing.GetAuthorizationPolicyPath(rule)).Get()).
 			Get())
 }
 
@@ -3,6 +3,8 @@ package istio_test
 import (
 	"context"
 	"fmt"
+	"strings"
+
 	"github.com/kyma-project/api-gateway/internal/processing/hashbasedstate"
 
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
@@ -194,6 +196,31 @@ var _ = Describe("JwtAuthorization Policy Processor", func() {
 		Expect(ap.Spec.Rules[0].To[0].Operation.Paths).To(ContainElement("/*"))
 	})
 
+	It("should set path with trailing wildcard when the Rule path match type is prefix", func() {
+		// given
+		jwt := createIstioJwtAccessStrategy()
+		service := &gatewayv1beta1.Service{
+			Name: &ServiceName,
+			Port: &ServicePort,
+		}
+
+		ruleJwt := GetRuleWithServiceFor("/api", ApiMethods, []*gatewayv1beta1.Mutator{}, []*gatewayv1beta1.Authenticator{jwt}, service)
+		ruleJwt.PathMatchType = gatewayv1beta1.PathMatchPrefix
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{ruleJwt})
+		client := GetFakeClient()
+		processor := istio.NewAuthorizationPolicyProcessor(GetTestConfig(), &testLogger)
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+
+		ap := result[0].Obj.(*securityv1beta1.AuthorizationPolicy)
+
+		Expect(ap.Spec.Rules[0].To[0].Operation.Paths).To(Equal([]string{"/api*"}))
+	})
+
 	It("should produce two APs for a rule with one issuer and two paths", func() {
 		// given
 		jwt := createIstioJwtAccessStrategy()
@@ -484,6 +511,77 @@ var _ = Describe("JwtAuthorization Policy Processor", func() {
 
 	})
 
+	// isAllowedForClaims evaluates the conditions of the AP like Istio for a token with the given claims. A string claim
+	// is matched as a space-delimited list, as it's done for the scope claim.
+	isAllowedForClaims := func(ap *securityv1beta1.AuthorizationPolicy, claims map[string]interface{}) bool {
+		claimContains := func(claim interface{}, value string) bool {
+			switch c := claim.(type) {
+			case string:
+				return slices.Contains(strings.Fields(c), value)
+			case []string:
+				return slices.Contains(c, value)
+			}
+			return false
+		}
+
+		for _, rule := range ap.Spec.Rules {
+			allowed := true
+			for _, condition := range rule.When {
+				claimName := strings.TrimSuffix(strings.TrimPrefix(condition.Key, "request.auth.claims["), "]")
+				matches := false
+				for _, value := range condition.Values {
+					if claimContains(claims[claimName], value) {
+						matches = true
+					}
+				}
+				allowed = allowed && matches
+			}
+			if allowed {
+				return true
+			}
+		}
+		return false
+	}
+
+	DescribeTable("should produce AP that requires the scopes and audiences of the authorization",
+		func(claims map[string]interface{}, expectedAllowed bool) {
+			// given
+			jwtConfigJSON := fmt.Sprintf(`{
+				"authentications": [{"issuer": "%s", "jwksUri": "%s"}],
+				"authorizations": [{"requiredScopes": ["%s", "%s"], "audiences": ["https://example.com"]}]
+				}`, JwtIssuer, JwksUri, RequiredScopeA, RequiredScopeB)
+			jwt := &gatewayv1beta1.Authenticator{
+				Handler: &gatewayv1beta1.Handler{
+					Name:   "jwt",
+					Config: &runtime.RawExtension{Raw: []byte(jwtConfigJSON)},
+				},
+			}
+			service := &gatewayv1beta1.Service{
+				Name: &ServiceName,
+				Port: &ServicePort,
+			}
+			ruleJwt := GetRuleWithServiceFor(HeadersApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, []*gatewayv1beta1.Authenticator{jwt}, service)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{ruleJwt})
+			processor := istio.NewAuthorizationPolicyProcessor(GetTestConfig(), &testLogger)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+			ap := result[0].Obj.(*securityv1beta1.AuthorizationPolicy)
+			Expect(isAllowedForClaims(ap, claims)).To(Equal(expectedAllowed))
+		},
+		Entry("token with space-delimited scope claim", map[string]interface{}{"scope": "openid scope-a scope-b", "aud": []string{"https://example.com"}}, true),
+		Entry("token with array scp claim", map[string]interface{}{"scp": []string{"scope-b", "scope-a"}, "aud": "https://example.com"}, true),
+		Entry("token with array scopes claim", map[string]interface{}{"scopes": []string{"scope-a", "scope-b"}, "aud": []string{"https://example.com", "other"}}, true),
+		Entry("token with missing scope", map[string]interface{}{"scope": "scope-a", "aud": []string{"https://example.com"}}, false),
+		Entry("token with scopes split across claims", map[string]interface{}{"scope": "scope-a", "scp": []string{"scope-b"}, "aud": "https://example.com"}, false),
+		Entry("token with audience mismatch", map[string]interface{}{"scope": "scope-a scope-b", "aud": []string{"https://other.example.com"}}, false),
+		Entry("token without audience", map[string]interface{}{"scp": []string{"scope-a", "scope-b"}}, false),
+	)
+
 	When("single handler only", func() {
 
 		It("should create AP with From in Rules Spec for jwt", func() {
@@ -1400,6 +1498,127 @@ var _ = Describe("JwtAuthorization Policy Processor", func() {
 			Expect(result).To(ContainElements(newMatcher, deletedMatcher))
 		})
 	}
+
+	When("source CIDRs are defined", func() {
+		allowStrategies := []*gatewayv1beta1.Authenticator{{Handler: &gatewayv1beta1.Handler{Name: "allow"}}}
+
+		It("should create AP that denies the requests from other sources for rule with allow handler", func() {
+			// given
+			rule := GetRuleFor("/internal", ApiMethods, []*gatewayv1beta1.Mutator{}, allowStrategies)
+			rule.SourceCIDRs = []string{"10.0.0.0/8", "192.168.1.1"}
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			processor := istio.NewAuthorizationPolicyProcessor(GetTestConfig(), &testLogger)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Action.String()).To(Equal("create"))
+
+			ap := result[0].Obj.(*securityv1beta1.AuthorizationPolicy)
+			Expect(ap.Namespace).To(Equal(ApiNamespace))
+			Expect(ap.Spec.Action).To(Equal(v1beta1.AuthorizationPolicy_DENY))
+			Expect(ap.Spec.Selector.MatchLabels).To(HaveKeyWithValue("app", ServiceName))
+			Expect(ap.Spec.Rules).To(HaveLen(1))
+			Expect(ap.Spec.Rules[0].From).To(HaveLen(1))
+			Expect(ap.Spec.Rules[0].From[0].Source.NotRemoteIpBlocks).To(Equal([]string{"10.0.0.0/8", "192.168.1.1"}))
+			Expect(ap.Spec.Rules[0].To[0].Operation.Paths).To(Equal([]string{"/internal"}))
+			Expect(ap.Spec.Rules[0].To[0].Operation.Methods).To(Equal(ApiMethods))
+		})
+
+		It("should create AP that denies the requests from other sources in addition to the AP for rule with jwt handler", func() {
+			// given
+			rule := getRuleForApTest([]string{"GET"}, "/internal", "test-service")
+			rule.SourceCIDRs = []string{"10.0.0.0/8"}
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			processor := istio.NewAuthorizationPolicyProcessor(GetTestConfig(), &testLogger)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(2))
+
+			var actions []v1beta1.AuthorizationPolicy_Action
+			for _, change := range result {
+				ap := change.Obj.(*securityv1beta1.AuthorizationPolicy)
+				Expect(ap.Spec.Rules[0].To[0].Operation.Paths).To(Equal([]string{"/internal"}))
+				actions = append(actions, ap.Spec.Action)
+			}
+			Expect(actions).To(ConsistOf(v1beta1.AuthorizationPolicy_ALLOW, v1beta1.AuthorizationPolicy_DENY))
+		})
+
+		It("should create AP for each destination of the rule", func() {
+			// given
+			otherService := "other-service"
+			rule := GetRuleFor("/internal", ApiMethods, []*gatewayv1beta1.Mutator{}, allowStrategies)
+			rule.Destinations = []gatewayv1beta1.WeightedService{
+				{Service: gatewayv1beta1.Service{Name: &ServiceName, Port: &ServicePort}, Weight: 50},
+				{Service: gatewayv1beta1.Service{Name: &otherService, Port: &ServicePort}, Weight: 50},
+			}
+			rule.SourceCIDRs = []string{"10.0.0.0/8"}
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			processor := istio.NewAuthorizationPolicyProcessor(GetTestConfig(), &testLogger)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(2))
+
+			var selectedServices []string
+			for _, change := range result {
+				ap := change.Obj.(*securityv1beta1.AuthorizationPolicy)
+				Expect(ap.Spec.Action).To(Equal(v1beta1.AuthorizationPolicy_DENY))
+				selectedServices = append(selectedServices, ap.Spec.Selector.MatchLabels["app"])
+			}
+			Expect(selectedServices).To(ConsistOf(ServiceName, otherService))
+		})
+
+		It("should not create AP for rule with a direct response", func() {
+			// given
+			rule := GetRuleFor("/internal", ApiMethods, []*gatewayv1beta1.Mutator{}, allowStrategies)
+			rule.DirectResponse = &gatewayv1beta1.DirectResponse{Status: 200}
+			rule.SourceCIDRs = []string{"10.0.0.0/8"}
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			processor := istio.NewAuthorizationPolicyProcessor(GetTestConfig(), &testLogger)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(BeEmpty())
+		})
+
+		It("should delete AP when the source CIDRs are removed from the rule", func() {
+			// given
+			rule := GetRuleFor("/internal", ApiMethods, []*gatewayv1beta1.Mutator{}, allowStrategies)
+			rule.SourceCIDRs = []string{"10.0.0.0/8"}
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			processor := istio.NewAuthorizationPolicyProcessor(GetTestConfig(), &testLogger)
+
+			created, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+			Expect(err).To(BeNil())
+			Expect(created).To(HaveLen(1))
+			existingAp := created[0].Obj.(*securityv1beta1.AuthorizationPolicy)
+			existingAp.Name = "existing-ap"
+
+			apiRule.Spec.Rules[0].SourceCIDRs = nil
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(existingAp), apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Action.String()).To(Equal("delete"))
+		})
+	})
 })
 
 func getRuleForApTest(methods []string, path string, serviceName string, namespace ...string) gatewayv1beta1.Rule {
@@ -0,0 +1,163 @@
+package istio
+
+import (
+	"fmt"
+	"time"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/builders"
+	"github.com/kyma-project/api-gateway/internal/helpers"
+	"github.com/kyma-project/api-gateway/internal/processing"
+	"github.com/kyma-project/api-gateway/internal/processing/processors"
+	"istio.io/api/networking/v1beta1"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+)
+
+// NewDestinationRuleProcessor returns a DestinationRuleProcessor with the desired state handling specific for the Istio handler.
+func NewDestinationRuleProcessor(config processing.ReconciliationConfig) processors.DestinationRuleProcessor {
+	return processors.DestinationRuleProcessor{
+		Creator: destinationRuleCreator{
+			additionalLabels: config.AdditionalLabels,
+			idleTimeout:      config.IdleTimeout,
+		},
+	}
+}
+
+type destinationRuleCreator struct {
+	additionalLabels map[string]string
+	idleTimeout      time.Duration
+}
+
+// Create returns the Destination Rules for the services of the APIRule with TLS or HTTP/2 enabled, a traffic policy,
+// subsets or an idle timeout defined. The Destination Rules are only created for services the Virtual Service routes
+// to directly.
+func (r destinationRuleCreator) Create(api *gatewayv1beta1.APIRule) map[string]*networkingv1beta1.DestinationRule {
+	drBuilders := make(map[string]*builders.DestinationRuleBuilder)
+	idleTimeouts := make(map[string]time.Duration)
+	addService := func(service *gatewayv1beta1.Service, namespace string, idleTimeout time.Duration) {
+		if service == nil || (!service.TLS && service.Protocol != gatewayv1beta1.BackendProtocolHTTP2 && service.TrafficPolicy == nil && len(service.Subsets) == 0 && idleTimeout == 0) {
+			return
+		}
+
+		host := helpers.GetHostLocalDomain(*service.Name, namespace)
+		key := fmt.Sprintf("%s:%s", host, namespace)
+		if _, exists := drBuilders[key]; !exists {
+			drBuilders[key] = r.newDestinationRuleBuilder(api, host, namespace)
+		}
+		// The idle timeout applies to all connections to the service, so the longest idle timeout of the rules is used.
+		if idleTimeout > idleTimeouts[key] {
+			idleTimeouts[key] = idleTimeout
+		}
+		if service.TLS {
+			drBuilders[key].WithTLSOrigination(*service.Port)
+		}
+		if service.Protocol == gatewayv1beta1.BackendProtocolHTTP2 {
+			drBuilders[key].WithHTTP2Upgrade(*service.Port)
+		}
+		if service.TrafficPolicy != nil {
+			withTrafficPolicy(drBuilders[key], service.TrafficPolicy)
+		}
+		for _, subset := range service.Subsets {
+			drBuilders[key].WithSubset(subset.Name, subset.Labels)
+		}
+	}
+
+	// Disabled rules have no route, so the traffic policy of their services isn't applied.
+	for _, rule := range processing.FilterDisabledRules(api.Spec.Rules) {
+		if !processing.ShouldRouteDirectlyToService(rule) {
+			continue
+		}
+
+		allowed := !processing.IsSecured(rule)
+		if allowed && (rule.Redirect != nil || rule.DirectResponse != nil) {
+			continue
+		}
+
+		// The idle timeout is validated by the pattern in the CRD, so an idle timeout that can't be parsed isn't applied.
+		idleTimeout, _ := processing.GetRuleIdleTimeout(rule, r.idleTimeout)
+
+		if allowed && len(rule.Destinations) > 0 {
+			for _, destination := range rule.Destinations {
+				addService(&destination.Service, helpers.FindDestinationNamespace(api, &destination.Service), idleTimeout)
+			}
+		} else if rule.Service != nil {
+			addService(rule.Service, helpers.FindServiceNamespace(api, &rule), idleTimeout)
+		} else {
+			addService(api.Spec.Service, helpers.FindServiceNamespace(api, &rule), idleTimeout)
+		}
+
+		// The responses of the mirrored requests are discarded, so the idle timeout of the rule doesn't apply to them.
+		if allowed && rule.Mirror != nil {
+			addService(&rule.Mirror.Service, helpers.FindDestinationNamespace(api, &rule.Mirror.Service), 0)
+		}
+	}
+
+	destinationRules := make(map[string]*networkingv1beta1.DestinationRule)
+	for key, drBuilder := range drBuilders {
+		if idleTimeout := idleTimeouts[key]; idleTimeout > 0 {
+			drBuilder.WithIdleTimeout(idleTimeout)
+		}
+		dr := drBuilder.Get()
+		destinationRules[processors.GetDestinationRuleKey(dr)] = dr
+	}
+
+	return destinationRules
+}
+
+func (r destinationRuleCreator) newDestinationRuleBuilder(api *gatewayv1beta1.APIRule, host, namespace string) *builders.DestinationRuleBuilder {
+	drBuilder := builders.NewDestinationRuleBuilder().
+		WithGenerateName(fmt.Sprintf("%s-", api.ObjectMeta.Name)).
+		WithNamespace(namespace).
+		WithHost(host).
+		WithLabel(processing.OwnerLabel, processing.GetOwnerLabelValue(api)).
+		WithLabel(processing.OwnerLabelv1alpha1, processing.GetOwnerLabelValue(api))
+
+	for k, v := range r.additionalLabels {
+		drBuilder.WithLabel(k, v)
+	}
+
+	return drBuilder
+}
+
+func withTrafficPolicy(drBuilder *builders.DestinationRuleBuilder, trafficPolicy *gatewayv1beta1.TrafficPolicy) {
+	if pool := trafficPolicy.ConnectionPool; pool != nil {
+		drBuilder.WithConnectionPool(pool.MaxConnections, pool.MaxPendingRequests, pool.MaxRequestsPerConnection)
+	}
+
+	if detection := trafficPolicy.OutlierDetection; detection != nil {
+		// The durations are validated by the pattern in the CRD, so a duration that can't be parsed falls back to the Istio default.
+		var interval, baseEjectionTime time.Duration
+		if detection.Interval != nil {
+			interval, _ = time.ParseDuration(*detection.Interval)
+		}
+		if detection.BaseEjectionTime != nil {
+			baseEjectionTime, _ = time.ParseDuration(*detection.BaseEjectionTime)
+		}
+		drBuilder.WithOutlierDetection(detection.Consecutive5xxErrors, interval, baseEjectionTime, detection.MaxEjectionPercent)
+	}
+
+	if loadBalancer := trafficPolicy.LoadBalancer; loadBalancer != nil {
+		withLoadBalancer(drBuilder, loadBalancer)
+	}
+}
+
+// withLoadBalancer configures the load balancing algorithm of the service. The source of the hash key is validated, so
+// a consistent hash without a source falls back to round robin.
+func withLoadBalancer(drBuilder *builders.DestinationRuleBuilder, loadBalancer *gatewayv1beta1.LoadBalancer) {
+	hash := loadBalancer.ConsistentHash
+	switch {
+	case loadBalancer.Algorithm == gatewayv1beta1.LoadBalancerConsistentHash && hash != nil && hash.HTTPHeaderName != "":
+		drBuilder.WithHeaderHashLoadBalancer(hash.HTTPHeaderName)
+	case loadBalancer.Algorithm == gatewayv1beta1.LoadBalancerConsistentHash && hash != nil && hash.HTTPCookie != nil:
+		// The lifetime is validated by the pattern in the CRD, so a lifetime that can't be parsed creates a session cookie.
+		ttl, _ := time.ParseDuration(hash.HTTPCookie.TTL)
+		drBuilder.WithCookieHashLoadBalancer(hash.HTTPCookie.Name, hash.HTTPCookie.Path, ttl)
+	case loadBalancer.Algorithm == gatewayv1beta1.LoadBalancerConsistentHash && hash != nil && hash.UseSourceIP:
+		drBuilder.WithSourceIPHashLoadBalancer()
+	case loadBalancer.Algorithm == gatewayv1beta1.LoadBalancerLeastConn:
+		// LEAST_CONN is deprecated in Istio in favour of LEAST_REQUEST, which selects the instance with fewer active requests.
+		drBuilder.WithSimpleLoadBalancer(v1beta1.LoadBalancerSettings_LEAST_REQUEST)
+	default:
+		drBuilder.WithSimpleLoadBalancer(v1beta1.LoadBalancerSettings_ROUND_ROBIN)
+	}
+}
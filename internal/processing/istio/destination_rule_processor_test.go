@@ -0,0 +1,425 @@
+package istio_test
+
+import (
+	"context"
+	"time"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/processing"
+	. "github.com/kyma-project/api-gateway/internal/processing/internal/test"
+	"github.com/kyma-project/api-gateway/internal/processing/istio"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"istio.io/api/networking/v1beta1"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+)
+
+var _ = Describe("Destination Rule Processor", func() {
+	strategies := []*gatewayv1beta1.Authenticator{
+		{
+			Handler: &gatewayv1beta1.Handler{
+				Name: "allow",
+			},
+		},
+	}
+
+	It("should create a destination rule that originates TLS for the numeric port of the service", func() {
+		// given
+		name := "tls-service"
+		var port uint32 = 8443
+		rule := GetRuleWithServiceFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies, &gatewayv1beta1.Service{
+			Name: &name,
+			Port: &port,
+			TLS:  true,
+		})
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+		processor := istio.NewDestinationRuleProcessor(GetTestConfig())
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Action.String()).To(Equal("create"))
+
+		dr := result[0].Obj.(*networkingv1beta1.DestinationRule)
+		Expect(dr.Namespace).To(Equal(ApiNamespace))
+		Expect(dr.Spec.Host).To(Equal(name + "." + ApiNamespace + ".svc.cluster.local"))
+		Expect(dr.Spec.TrafficPolicy.PortLevelSettings).To(HaveLen(1))
+		Expect(dr.Spec.TrafficPolicy.PortLevelSettings[0].Port.Number).To(Equal(port))
+		Expect(dr.Spec.TrafficPolicy.PortLevelSettings[0].Tls.Mode).To(Equal(v1beta1.ClientTLSSettings_SIMPLE))
+	})
+
+	DescribeTable("should upgrade the requests to HTTP/2 only for services with the HTTP/2 protocol",
+		func(protocol gatewayv1beta1.BackendProtocol, expectDestinationRule bool) {
+			// given
+			name := "h2-service"
+			var port uint32 = 8080
+			rule := GetRuleWithServiceFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies, &gatewayv1beta1.Service{
+				Name:     &name,
+				Port:     &port,
+				Protocol: protocol,
+			})
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			processor := istio.NewDestinationRuleProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			if !expectDestinationRule {
+				Expect(result).To(BeEmpty())
+				return
+			}
+
+			Expect(result).To(HaveLen(1))
+			dr := result[0].Obj.(*networkingv1beta1.DestinationRule)
+			Expect(dr.Spec.TrafficPolicy.PortLevelSettings).To(HaveLen(1))
+			Expect(dr.Spec.TrafficPolicy.PortLevelSettings[0].Port.Number).To(Equal(port))
+			Expect(dr.Spec.TrafficPolicy.PortLevelSettings[0].Tls).To(BeNil())
+			Expect(dr.Spec.TrafficPolicy.PortLevelSettings[0].ConnectionPool.Http.H2UpgradePolicy).To(Equal(v1beta1.ConnectionPoolSettings_HTTPSettings_UPGRADE))
+		},
+		Entry("HTTP/1.1 by default", gatewayv1beta1.BackendProtocol(""), false),
+		Entry("HTTP/1.1", gatewayv1beta1.BackendProtocolHTTP, false),
+		Entry("HTTP/2", gatewayv1beta1.BackendProtocolHTTP2, true),
+	)
+
+	It("should enable HTTP/2 only for the gRPC port of a rule with protocol ports", func() {
+		// given
+		rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+		rule.ProtocolPorts = []gatewayv1beta1.ProtocolPort{
+			{Protocol: gatewayv1beta1.RouteTypeHTTP, Port: 80},
+			{Protocol: gatewayv1beta1.RouteTypeGRPC, Port: 9090},
+		}
+		apiRule := processing.ExpandProtocolPorts(GetAPIRuleFor([]gatewayv1beta1.Rule{rule}))
+		processor := istio.NewDestinationRuleProcessor(GetTestConfig())
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+		dr := result[0].Obj.(*networkingv1beta1.DestinationRule)
+		Expect(dr.Spec.TrafficPolicy.PortLevelSettings).To(HaveLen(1))
+		Expect(dr.Spec.TrafficPolicy.PortLevelSettings[0].Port.Number).To(Equal(uint32(9090)))
+		Expect(dr.Spec.TrafficPolicy.PortLevelSettings[0].ConnectionPool.Http.H2UpgradePolicy).To(Equal(v1beta1.ConnectionPoolSettings_HTTPSettings_UPGRADE))
+	})
+
+	It("should not create a destination rule for services without TLS", func() {
+		// given
+		rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+		processor := istio.NewDestinationRuleProcessor(GetTestConfig())
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(BeEmpty())
+	})
+
+	It("should not create a destination rule for the services of disabled rules", func() {
+		// given
+		name := "tls-service"
+		var port uint32 = 8443
+		enabled := false
+		rule := GetRuleWithServiceFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies, &gatewayv1beta1.Service{
+			Name: &name,
+			Port: &port,
+			TLS:  true,
+		})
+		rule.Enabled = &enabled
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+		processor := istio.NewDestinationRuleProcessor(GetTestConfig())
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(BeEmpty())
+	})
+
+	It("should not create a destination rule for services behind oathkeeper", func() {
+		// given
+		name := "tls-service"
+		var port uint32 = 8443
+		noop := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "noop",
+				},
+			},
+		}
+		rule := GetRuleWithServiceFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, noop, &gatewayv1beta1.Service{
+			Name: &name,
+			Port: &port,
+			TLS:  true,
+		})
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+		processor := istio.NewDestinationRuleProcessor(GetTestConfig())
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(BeEmpty())
+	})
+
+	It("should delete the destination rule when TLS is no longer enabled and not update an unchanged one", func() {
+		// given
+		name := "tls-service"
+		var port uint32 = 8443
+		tlsRule := GetRuleWithServiceFor("/tls", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies, &gatewayv1beta1.Service{
+			Name: &name,
+			Port: &port,
+			TLS:  true,
+		})
+		otherName := "other-service"
+		otherRule := GetRuleWithServiceFor("/other", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies, &gatewayv1beta1.Service{
+			Name: &otherName,
+			Port: &port,
+			TLS:  true,
+		})
+		processor := istio.NewDestinationRuleProcessor(GetTestConfig())
+
+		existing, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), GetAPIRuleFor([]gatewayv1beta1.Rule{tlsRule, otherRule}))
+		Expect(err).To(BeNil())
+		Expect(existing).To(HaveLen(2))
+
+		existingDr := existing[0].Obj.(*networkingv1beta1.DestinationRule)
+		existingDr.Name = "dr-1"
+		otherDr := existing[1].Obj.(*networkingv1beta1.DestinationRule)
+		otherDr.Name = "dr-2"
+		client := GetFakeClient(existingDr, otherDr)
+
+		otherRule.Service.TLS = false
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{tlsRule, otherRule})
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Action.String()).To(Equal("delete"))
+		Expect(result[0].Obj.(*networkingv1beta1.DestinationRule).Spec.Host).To(Equal(otherName + "." + ApiNamespace + ".svc.cluster.local"))
+	})
+
+	It("should create a destination rule with the traffic policy of the service and update it only if the policy changes", func() {
+		// given
+		name := "limited-service"
+		var port uint32 = 8080
+		interval := "10s"
+		service := &gatewayv1beta1.Service{
+			Name: &name,
+			Port: &port,
+			TrafficPolicy: &gatewayv1beta1.TrafficPolicy{
+				ConnectionPool: &gatewayv1beta1.ConnectionPool{MaxConnections: 100, MaxPendingRequests: 10},
+				OutlierDetection: &gatewayv1beta1.OutlierDetection{
+					Consecutive5xxErrors: 5,
+					Interval:             &interval,
+					MaxEjectionPercent:   50,
+				},
+			},
+		}
+		rule := GetRuleWithServiceFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies, service)
+		processor := istio.NewDestinationRuleProcessor(GetTestConfig())
+
+		// when
+		created, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), GetAPIRuleFor([]gatewayv1beta1.Rule{rule}))
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(created).To(HaveLen(1))
+		Expect(created[0].Action.String()).To(Equal("create"))
+
+		dr := created[0].Obj.(*networkingv1beta1.DestinationRule)
+		Expect(dr.Spec.Host).To(Equal(name + "." + ApiNamespace + ".svc.cluster.local"))
+		Expect(dr.Spec.TrafficPolicy.PortLevelSettings).To(BeEmpty())
+		Expect(dr.Spec.TrafficPolicy.ConnectionPool.Tcp.MaxConnections).To(Equal(int32(100)))
+		Expect(dr.Spec.TrafficPolicy.ConnectionPool.Http.Http1MaxPendingRequests).To(Equal(int32(10)))
+		Expect(dr.Spec.TrafficPolicy.OutlierDetection.Consecutive_5XxErrors.GetValue()).To(Equal(uint32(5)))
+		Expect(dr.Spec.TrafficPolicy.OutlierDetection.Interval.AsDuration().String()).To(Equal(interval))
+		Expect(dr.Spec.TrafficPolicy.OutlierDetection.MaxEjectionPercent).To(Equal(int32(50)))
+
+		// given
+		dr.Name = "dr-1"
+		client := GetFakeClient(dr)
+
+		// when
+		unchanged, err := processor.EvaluateReconciliation(context.TODO(), client, GetAPIRuleFor([]gatewayv1beta1.Rule{rule}))
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(unchanged).To(BeEmpty())
+
+		// given
+		service.TrafficPolicy.ConnectionPool.MaxConnections = 200
+
+		// when
+		updated, err := processor.EvaluateReconciliation(context.TODO(), client, GetAPIRuleFor([]gatewayv1beta1.Rule{rule}))
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(updated).To(HaveLen(1))
+		Expect(updated[0].Action.String()).To(Equal("update"))
+		Expect(updated[0].Obj.(*networkingv1beta1.DestinationRule).Spec.TrafficPolicy.ConnectionPool.Tcp.MaxConnections).To(Equal(int32(200)))
+	})
+
+	DescribeTable("should create a destination rule with the load balancer of the service",
+		func(loadBalancer *gatewayv1beta1.LoadBalancer, assertLoadBalancer func(*v1beta1.LoadBalancerSettings)) {
+			// given
+			name := "sticky-service"
+			var port uint32 = 8080
+			service := &gatewayv1beta1.Service{
+				Name:          &name,
+				Port:          &port,
+				TrafficPolicy: &gatewayv1beta1.TrafficPolicy{LoadBalancer: loadBalancer},
+			}
+			rule := GetRuleWithServiceFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies, service)
+			processor := istio.NewDestinationRuleProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), GetAPIRuleFor([]gatewayv1beta1.Rule{rule}))
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			dr := result[0].Obj.(*networkingv1beta1.DestinationRule)
+			Expect(dr.Spec.Host).To(Equal(name + "." + ApiNamespace + ".svc.cluster.local"))
+			assertLoadBalancer(dr.Spec.TrafficPolicy.LoadBalancer)
+		},
+		Entry("round robin",
+			&gatewayv1beta1.LoadBalancer{Algorithm: gatewayv1beta1.LoadBalancerRoundRobin},
+			func(lb *v1beta1.LoadBalancerSettings) {
+				Expect(lb.GetSimple()).To(Equal(v1beta1.LoadBalancerSettings_ROUND_ROBIN))
+			}),
+		Entry("round robin without algorithm",
+			&gatewayv1beta1.LoadBalancer{},
+			func(lb *v1beta1.LoadBalancerSettings) {
+				Expect(lb.GetSimple()).To(Equal(v1beta1.LoadBalancerSettings_ROUND_ROBIN))
+			}),
+		Entry("least connections",
+			&gatewayv1beta1.LoadBalancer{Algorithm: gatewayv1beta1.LoadBalancerLeastConn},
+			func(lb *v1beta1.LoadBalancerSettings) {
+				Expect(lb.GetSimple()).To(Equal(v1beta1.LoadBalancerSettings_LEAST_REQUEST))
+			}),
+		Entry("consistent hash on a header",
+			&gatewayv1beta1.LoadBalancer{
+				Algorithm:      gatewayv1beta1.LoadBalancerConsistentHash,
+				ConsistentHash: &gatewayv1beta1.ConsistentHash{HTTPHeaderName: "x-user"},
+			},
+			func(lb *v1beta1.LoadBalancerSettings) {
+				Expect(lb.GetConsistentHash().GetHttpHeaderName()).To(Equal("x-user"))
+			}),
+		Entry("consistent hash on a cookie",
+			&gatewayv1beta1.LoadBalancer{
+				Algorithm:      gatewayv1beta1.LoadBalancerConsistentHash,
+				ConsistentHash: &gatewayv1beta1.ConsistentHash{HTTPCookie: &gatewayv1beta1.HTTPCookie{Name: "session", Path: "/", TTL: "30m"}},
+			},
+			func(lb *v1beta1.LoadBalancerSettings) {
+				cookie := lb.GetConsistentHash().GetHttpCookie()
+				Expect(cookie.Name).To(Equal("session"))
+				Expect(cookie.Path).To(Equal("/"))
+				Expect(cookie.Ttl.AsDuration()).To(Equal(30 * time.Minute))
+			}),
+		Entry("consistent hash on the source IP",
+			&gatewayv1beta1.LoadBalancer{
+				Algorithm:      gatewayv1beta1.LoadBalancerConsistentHash,
+				ConsistentHash: &gatewayv1beta1.ConsistentHash{UseSourceIP: true},
+			},
+			func(lb *v1beta1.LoadBalancerSettings) {
+				Expect(lb.GetConsistentHash().GetUseSourceIp()).To(BeTrue())
+			}),
+	)
+
+	It("should create a destination rule with the subsets of the service", func() {
+		// given
+		name := "versioned-service"
+		var port uint32 = 8080
+		rule := GetRuleWithServiceFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies, &gatewayv1beta1.Service{
+			Name: &name,
+			Port: &port,
+			Subsets: []gatewayv1beta1.Subset{
+				{Name: "v1", Labels: map[string]string{"version": "v1"}},
+				{Name: "v2", Labels: map[string]string{"version": "v2"}},
+			},
+		})
+		rule.Subset = "v2"
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+		processor := istio.NewDestinationRuleProcessor(GetTestConfig())
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Action.String()).To(Equal("create"))
+
+		dr := result[0].Obj.(*networkingv1beta1.DestinationRule)
+		Expect(dr.Spec.Host).To(Equal(name + "." + ApiNamespace + ".svc.cluster.local"))
+		Expect(dr.Spec.TrafficPolicy).To(BeNil())
+		Expect(dr.Spec.Subsets).To(HaveLen(2))
+		Expect(dr.Spec.Subsets[0].Name).To(Equal("v1"))
+		Expect(dr.Spec.Subsets[0].Labels).To(Equal(map[string]string{"version": "v1"}))
+		Expect(dr.Spec.Subsets[1].Name).To(Equal("v2"))
+		Expect(dr.Spec.Subsets[1].Labels).To(Equal(map[string]string{"version": "v2"}))
+	})
+
+	It("should create a destination rule with the idle timeout of the rules independent of the request timeout", func() {
+		// given
+		name := "long-polling-service"
+		var port uint32 = 8080
+		service := &gatewayv1beta1.Service{Name: &name, Port: &port}
+		timeout := "10m"
+		shortIdleTimeout := "1m"
+		longIdleTimeout := "5m"
+		pollRule := GetRuleWithServiceFor("/poll", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies, service)
+		pollRule.Timeout = &timeout
+		pollRule.IdleTimeout = &longIdleTimeout
+		statusRule := GetRuleWithServiceFor("/status", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies, service)
+		statusRule.IdleTimeout = &shortIdleTimeout
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{statusRule, pollRule})
+		processor := istio.NewDestinationRuleProcessor(GetTestConfig())
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Action.String()).To(Equal("create"))
+
+		dr := result[0].Obj.(*networkingv1beta1.DestinationRule)
+		Expect(dr.Spec.TrafficPolicy.ConnectionPool.Http.IdleTimeout.AsDuration()).To(Equal(5 * time.Minute))
+	})
+
+	It("should create a destination rule with the default idle timeout", func() {
+		// given
+		name := "idle-service"
+		var port uint32 = 8080
+		rule := GetRuleWithServiceFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies, &gatewayv1beta1.Service{Name: &name, Port: &port})
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+		config := GetTestConfig()
+		config.IdleTimeout = 2 * time.Minute
+		processor := istio.NewDestinationRuleProcessor(config)
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+
+		dr := result[0].Obj.(*networkingv1beta1.DestinationRule)
+		Expect(dr.Spec.TrafficPolicy.ConnectionPool.Http.IdleTimeout.AsDuration()).To(Equal(2 * time.Minute))
+	})
+})
@@ -0,0 +1,66 @@
+package istio
+
+import (
+	"fmt"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/builders"
+	"github.com/kyma-project/api-gateway/internal/processing"
+	"github.com/kyma-project/api-gateway/internal/processing/processors"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+)
+
+// NewEnvoyFilterProcessor returns an EnvoyFilterProcessor with the desired state handling specific for the Istio handler.
+func NewEnvoyFilterProcessor(config processing.ReconciliationConfig) processors.EnvoyFilterProcessor {
+	namespace := config.LuaFilterNamespace
+	if namespace == "" {
+		namespace = processing.DefaultLuaFilterNamespace
+	}
+
+	return processors.EnvoyFilterProcessor{
+		Creator: envoyFilterCreator{
+			enabled:          config.LuaFilters,
+			namespace:        namespace,
+			additionalLabels: config.AdditionalLabels,
+		},
+	}
+}
+
+type envoyFilterCreator struct {
+	enabled          bool
+	namespace        string
+	additionalLabels map[string]string
+}
+
+// Create returns an Envoy Filter for each route of a rule with a Lua script. No Envoy Filters are returned if the Lua
+// filters are disabled, so the processor removes the Envoy Filters that were created while they were enabled.
+func (r envoyFilterCreator) Create(api *gatewayv1beta1.APIRule) map[string]*networkingv1alpha3.EnvoyFilter {
+	envoyFilters := make(map[string]*networkingv1alpha3.EnvoyFilter)
+	if !r.enabled {
+		return envoyFilters
+	}
+
+	for i, rule := range processing.GetRoutedRules(api) {
+		if rule.Lua == nil {
+			continue
+		}
+
+		routeName := processing.GetRouteName(api, rule, i)
+		efBuilder := builders.NewEnvoyFilterBuilder().
+			WithGenerateName(fmt.Sprintf("%s-", api.ObjectMeta.Name)).
+			WithNamespace(r.namespace).
+			WithWorkloadSelector(processing.LuaFilterWorkloadSelector).
+			WithLuaRouteFilter(processing.GetLuaFilterName(api, routeName), routeName, rule.Lua.InlineCode).
+			WithLabel(processing.OwnerLabel, processing.GetOwnerLabelValue(api)).
+			WithLabel(processing.OwnerLabelv1alpha1, processing.GetOwnerLabelValue(api))
+
+		for k, v := range r.additionalLabels {
+			efBuilder.WithLabel(k, v)
+		}
+
+		ef := efBuilder.Get()
+		envoyFilters[processors.GetEnvoyFilterKey(ef)] = ef
+	}
+
+	return envoyFilters
+}
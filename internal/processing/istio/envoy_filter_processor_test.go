@@ -0,0 +1,187 @@
+package istio_test
+
+import (
+	"context"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/processing"
+	. "github.com/kyma-project/api-gateway/internal/processing/internal/test"
+	"github.com/kyma-project/api-gateway/internal/processing/istio"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"istio.io/api/networking/v1alpha3"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Envoy Filter Processor", func() {
+	strategies := []*gatewayv1beta1.Authenticator{
+		{
+			Handler: &gatewayv1beta1.Handler{
+				Name: "allow",
+			},
+		},
+	}
+
+	getLuaConfig := func() processing.ReconciliationConfig {
+		config := GetTestConfig()
+		config.LuaFilters = true
+		return config
+	}
+
+	getLuaRule := func(path string, code string) gatewayv1beta1.Rule {
+		rule := GetRuleFor(path, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+		rule.Lua = &gatewayv1beta1.Lua{InlineCode: code}
+		return rule
+	}
+
+	It("should create an envoy filter owned by the APIRule for the route of a rule with a Lua script", func() {
+		// given
+		code := "function envoy_on_request(handle) handle:headers():add(\"x-signed\", \"true\") end"
+		rules := []gatewayv1beta1.Rule{
+			GetRuleFor("/other", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies),
+			getLuaRule(ApiPath, code),
+		}
+		apiRule := GetAPIRuleFor(rules)
+		processor := istio.NewEnvoyFilterProcessor(getLuaConfig())
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Action.String()).To(Equal("create"))
+
+		ef := result[0].Obj.(*networkingv1alpha3.EnvoyFilter)
+		Expect(ef.Namespace).To(Equal(processing.DefaultLuaFilterNamespace))
+		Expect(ef.GenerateName).To(Equal(ApiName + "-"))
+		Expect(ef.Labels).To(HaveKeyWithValue(processing.OwnerLabel, processing.GetOwnerLabelValue(apiRule)))
+		Expect(ef.Labels).To(HaveKeyWithValue(processing.OwnerLabelv1alpha1, processing.GetOwnerLabelValue(apiRule)))
+		Expect(ef.Labels).To(HaveKeyWithValue(TestLabelKey, TestLabelValue))
+		Expect(ef.Spec.WorkloadSelector.Labels).To(Equal(processing.LuaFilterWorkloadSelector))
+
+		routeName := processing.GetRouteName(apiRule, rules[1], 1)
+		filterName := processing.GetLuaFilterName(apiRule, routeName)
+		Expect(ef.Spec.ConfigPatches).To(HaveLen(2))
+		Expect(ef.Spec.ConfigPatches[0].ApplyTo).To(Equal(v1alpha3.EnvoyFilter_HTTP_FILTER))
+		Expect(ef.Spec.ConfigPatches[0].Patch.Value.Fields["name"].GetStringValue()).To(Equal(filterName))
+		Expect(ef.Spec.ConfigPatches[1].ApplyTo).To(Equal(v1alpha3.EnvoyFilter_HTTP_ROUTE))
+		Expect(ef.Spec.ConfigPatches[1].Match.GetRouteConfiguration().GetVhost().GetRoute().GetName()).To(Equal(routeName))
+		perRoute := ef.Spec.ConfigPatches[1].Patch.Value.Fields["typed_per_filter_config"].GetStructValue().Fields[filterName].GetStructValue()
+		Expect(perRoute.Fields["source_code"].GetStructValue().Fields["inline_string"].GetStringValue()).To(Equal(code))
+	})
+
+	It("should create the envoy filters in the configured namespace", func() {
+		// given
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{getLuaRule(ApiPath, "function envoy_on_request(handle) end")})
+		config := getLuaConfig()
+		config.LuaFilterNamespace = "gateway-system"
+		processor := istio.NewEnvoyFilterProcessor(config)
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Obj.GetNamespace()).To(Equal("gateway-system"))
+	})
+
+	It("should match the routes of the APIRules with the same name in different namespaces by distinct names", func() {
+		// given
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{getLuaRule(ApiPath, "function envoy_on_request(handle) end")})
+		otherApiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{getLuaRule(ApiPath, "function envoy_on_response(handle) end")})
+		otherApiRule.Namespace = "other-namespace"
+		processor := istio.NewEnvoyFilterProcessor(getLuaConfig())
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+		Expect(err).To(BeNil())
+		otherResult, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), otherApiRule)
+		Expect(err).To(BeNil())
+
+		// then
+		Expect(result).To(HaveLen(1))
+		Expect(otherResult).To(HaveLen(1))
+		routeName := result[0].Obj.(*networkingv1alpha3.EnvoyFilter).Spec.ConfigPatches[1].Match.GetRouteConfiguration().GetVhost().GetRoute().GetName()
+		otherRouteName := otherResult[0].Obj.(*networkingv1alpha3.EnvoyFilter).Spec.ConfigPatches[1].Match.GetRouteConfiguration().GetVhost().GetRoute().GetName()
+		Expect(routeName).To(Equal(processing.GetRouteName(apiRule, apiRule.Spec.Rules[0], 0)))
+		Expect(otherRouteName).To(Equal(processing.GetRouteName(otherApiRule, otherApiRule.Spec.Rules[0], 0)))
+		Expect(routeName).ToNot(Equal(otherRouteName))
+	})
+
+	It("should update the envoy filter when the Lua script changes and delete it when the script is removed", func() {
+		// given
+		processor := istio.NewEnvoyFilterProcessor(getLuaConfig())
+		rules := []gatewayv1beta1.Rule{
+			getLuaRule(ImgApiPath, "function envoy_on_request(handle) end"),
+			getLuaRule(HeadersApiPath, "function envoy_on_response(handle) end"),
+		}
+		existing, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), GetAPIRuleFor(rules))
+		Expect(err).To(BeNil())
+		Expect(existing).To(HaveLen(2))
+
+		// The Envoy Filters are named after their routes to identify them in the changes.
+		var existingEfs []client.Object
+		for _, change := range existing {
+			ef := change.Obj.(*networkingv1alpha3.EnvoyFilter)
+			ef.Name = ef.Spec.ConfigPatches[1].Match.GetRouteConfiguration().GetVhost().GetRoute().GetName()
+			existingEfs = append(existingEfs, ef)
+		}
+		k8sClient := GetFakeClient(existingEfs...)
+
+		rules[0].Lua.InlineCode = "function envoy_on_request(handle) handle:logInfo(\"request\") end"
+		rules[1].Lua = nil
+		apiRule := GetAPIRuleFor(rules)
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), k8sClient, apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		actions := map[string]string{}
+		for _, change := range result {
+			actions[change.Obj.GetName()] = change.Action.String()
+		}
+		Expect(actions).To(Equal(map[string]string{
+			processing.GetRouteName(apiRule, rules[0], 0): "update",
+			processing.GetRouteName(apiRule, rules[1], 1): "delete",
+		}))
+	})
+
+	It("should delete the envoy filters when the Lua filters are disabled", func() {
+		// given
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{getLuaRule(ApiPath, "function envoy_on_request(handle) end")})
+		existing, err := istio.NewEnvoyFilterProcessor(getLuaConfig()).EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+		Expect(err).To(BeNil())
+		ef := existing[0].Obj.(*networkingv1alpha3.EnvoyFilter)
+		ef.Name = "ef-1"
+		processor := istio.NewEnvoyFilterProcessor(GetTestConfig())
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(ef), apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Action.String()).To(Equal("delete"))
+		Expect(result[0].Obj.GetName()).To(Equal("ef-1"))
+	})
+
+	It("should not create an envoy filter for disabled rules", func() {
+		// given
+		rule := getLuaRule(ApiPath, "function envoy_on_request(handle) end")
+		disabled := false
+		rule.Enabled = &disabled
+		rules := []gatewayv1beta1.Rule{rule, GetRuleFor(HeadersApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)}
+		processor := istio.NewEnvoyFilterProcessor(getLuaConfig())
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), GetAPIRuleFor(rules))
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(BeEmpty())
+	})
+})
@@ -8,6 +8,7 @@ import (
 
 	"github.com/kyma-project/api-gateway/api/v1beta1"
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/processing"
 	oryjwt "github.com/kyma-project/api-gateway/internal/types/ory"
 	"github.com/kyma-project/api-gateway/internal/validation"
 	apiv1beta1 "istio.io/api/type/v1beta1"
@@ -54,15 +55,20 @@ func (o *handlerValidator) Validate(attributePath string, handler *gatewayv1beta
 			attrPath := fmt.Sprintf("%s%s[%d]%s", attributePath, ".config.authentications", i, ".issuer")
 			failures = append(failures, validation.Failure{AttributePath: attrPath, Message: fmt.Sprintf("value is not a secured url err=%s", err)})
 		}
-		invalidJwksUri, err := validation.IsInvalidURL(authentication.JwksUri)
-		if invalidJwksUri {
-			attrPath := fmt.Sprintf("%s%s[%d]%s", attributePath, ".config.authentications", i, ".jwksUri")
-			failures = append(failures, validation.Failure{AttributePath: attrPath, Message: fmt.Sprintf("value is empty or not a valid url err=%s", err)})
-		}
-		unsecuredJwksUri, err := validation.IsUnsecuredURL(authentication.JwksUri)
-		if unsecuredJwksUri {
-			attrPath := fmt.Sprintf("%s%s[%d]%s", attributePath, ".config.authentications", i, ".jwksUri")
-			failures = append(failures, validation.Failure{AttributePath: attrPath, Message: fmt.Sprintf("value is not a secured url err=%s", err)})
+		// An issuer either provides the keys with the jwksUri or inline with the jwks.
+		if authentication.Jwks != "" {
+			failures = append(failures, validateInlineJwks(fmt.Sprintf("%s%s[%d]", attributePath, ".config.authentications", i), authentication)...)
+		} else {
+			invalidJwksUri, err := validation.IsInvalidURL(authentication.JwksUri)
+			if invalidJwksUri {
+				attrPath := fmt.Sprintf("%s%s[%d]%s", attributePath, ".config.authentications", i, ".jwksUri")
+				failures = append(failures, validation.Failure{AttributePath: attrPath, Message: fmt.Sprintf("value is empty or not a valid url err=%s", err)})
+			}
+			unsecuredJwksUri, err := validation.IsUnsecuredURL(authentication.JwksUri)
+			if unsecuredJwksUri {
+				attrPath := fmt.Sprintf("%s%s[%d]%s", attributePath, ".config.authentications", i, ".jwksUri")
+				failures = append(failures, validation.Failure{AttributePath: attrPath, Message: fmt.Sprintf("value is not a secured url err=%s", err)})
+			}
 		}
 		if len(authentication.FromHeaders) > 0 {
 			if hasFromParams {
@@ -78,6 +84,18 @@ func (o *handlerValidator) Validate(attributePath string, handler *gatewayv1beta
 			}
 			hasFromParams = true
 		}
+		for j, fromHeader := range authentication.FromHeaders {
+			if fromHeader.Name == "" {
+				attrPath := fmt.Sprintf("%s%s[%d]%s[%d]%s", attributePath, ".config.authentications", i, ".fromHeaders", j, ".name")
+				failures = append(failures, validation.Failure{AttributePath: attrPath, Message: "value is empty"})
+			}
+		}
+		for j, fromParam := range authentication.FromParams {
+			if fromParam == "" {
+				attrPath := fmt.Sprintf("%s%s[%d]%s[%d]", attributePath, ".config.authentications", i, ".fromParams", j)
+				failures = append(failures, validation.Failure{AttributePath: attrPath, Message: "value is empty"})
+			}
+		}
 		if len(authentication.FromHeaders) > 1 {
 			attrPath := fmt.Sprintf("%s%s[%d]%s", attributePath, ".config.authentications", i, ".fromHeaders")
 			failures = append(failures, validation.Failure{AttributePath: attrPath, Message: "multiple fromHeaders are not supported"})
@@ -94,6 +112,21 @@ func (o *handlerValidator) Validate(attributePath string, handler *gatewayv1beta
 	return failures
 }
 
+func validateInlineJwks(attributePath string, authentication *gatewayv1beta1.JwtAuthentication) []validation.Failure {
+	if authentication.JwksUri != "" {
+		return []validation.Failure{{AttributePath: attributePath + ".jwks", Message: "jwks and jwksUri can't be defined together"}}
+	}
+
+	var jwks struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal([]byte(authentication.Jwks), &jwks); err != nil || len(jwks.Keys) == 0 {
+		return []validation.Failure{{AttributePath: attributePath + ".jwks", Message: "value is not a JSON Web Key Set with keys"}}
+	}
+
+	return nil
+}
+
 func checkForOryConfig(attributePath string, handler *gatewayv1beta1.Handler) (problems []validation.Failure) {
 	var template oryjwt.JWTAccStrConfig
 	err := json.Unmarshal(handler.Config.Raw, &template)
@@ -212,7 +245,7 @@ type rulesValidator struct {
 }
 
 func (v *rulesValidator) Validate(attrPath string, rules []gatewayv1beta1.Rule) []validation.Failure {
-	var failures []validation.Failure
+	failures := validateRoutingTargets(attrPath, rules)
 	jwtAuths := map[string]*gatewayv1beta1.JwtAuthentication{}
 	for i, rule := range rules {
 		for j, accessStrategy := range rule.AccessStrategies {
@@ -241,7 +274,7 @@ func (v *rulesValidator) Validate(attrPath string, rules []gatewayv1beta1.Rule)
 }
 
 func isJwtAuthenticationsEqual(auth1 *gatewayv1beta1.JwtAuthentication, auth2 *gatewayv1beta1.JwtAuthentication) bool {
-	if auth1.Issuer != auth2.Issuer || auth1.JwksUri != auth2.JwksUri {
+	if auth1.Issuer != auth2.Issuer || auth1.JwksUri != auth2.JwksUri || auth1.Jwks != auth2.Jwks {
 		return false
 	}
 	if len(auth1.FromHeaders) != len(auth2.FromHeaders) {
@@ -262,3 +295,33 @@ func isJwtAuthenticationsEqual(auth1 *gatewayv1beta1.JwtAuthentication, auth2 *g
 	}
 	return true
 }
+
+// validateRoutingTargets validates that enabled rules with the same path don't mix the jwt access strategy, which is routed
+// directly to the service, with access strategies that are routed to oathkeeper. The rules share a single route, so the
+// requests of one of the rules would be routed to the wrong target.
+func validateRoutingTargets(attrPath string, rules []gatewayv1beta1.Rule) []validation.Failure {
+	jwtPaths := map[string]bool{}
+	oathkeeperPaths := map[string]bool{}
+	for _, rule := range processing.FilterDisabledRules(rules) {
+		if processing.IsJwtSecured(rule) {
+			jwtPaths[rule.GetMatchKey()] = true
+		} else if !processing.ShouldRouteDirectlyToService(rule) {
+			oathkeeperPaths[rule.GetMatchKey()] = true
+		}
+	}
+
+	var failures []validation.Failure
+	for i, rule := range rules {
+		if !rule.IsEnabled() || !jwtPaths[rule.GetMatchKey()] || !oathkeeperPaths[rule.GetMatchKey()] {
+			continue
+		}
+		if processing.IsJwtSecured(rule) || !processing.ShouldRouteDirectlyToService(rule) {
+			failures = append(failures, validation.Failure{
+				AttributePath: fmt.Sprintf("%s[%d].accessStrategies", attrPath, i),
+				Message:       fmt.Sprintf("Rules with path %s can't mix the jwt access strategy with access strategies handled by oathkeeper", rule.Path),
+			})
+		}
+	}
+
+	return failures
+}
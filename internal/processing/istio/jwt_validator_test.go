@@ -10,6 +10,7 @@ import (
 
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
 	"github.com/kyma-project/api-gateway/internal/types/ory"
+	"github.com/kyma-project/api-gateway/internal/validation"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -214,6 +215,43 @@ var _ = Describe("JWT Handler validation", func() {
 
 	Context("for authentications", func() {
 
+		DescribeTable("Should validate the keys of the issuers",
+			func(authentications []*gatewayv1beta1.JwtAuthentication, expectedPath string, expectedMessage string) {
+				//given
+				handler := &gatewayv1beta1.Handler{
+					Name:   "jwt",
+					Config: processingtest.GetRawConfig(gatewayv1beta1.JwtConfig{Authentications: authentications}),
+				}
+
+				//when
+				problems := (&handlerValidator{}).Validate("", handler)
+
+				//then
+				if expectedMessage == "" {
+					Expect(problems).To(BeEmpty())
+				} else {
+					Expect(problems).To(ContainElement(validation.Failure{AttributePath: expectedPath, Message: expectedMessage}))
+				}
+			},
+			Entry("one issuer with jwksUri", []*gatewayv1beta1.JwtAuthentication{
+				{Issuer: "https://issuer.test/", JwksUri: "https://issuer.test/.well-known/jwks.json"},
+			}, "", ""),
+			Entry("two issuers with jwksUri and inline jwks", []*gatewayv1beta1.JwtAuthentication{
+				{Issuer: "https://issuer.test/", JwksUri: "https://issuer.test/.well-known/jwks.json"},
+				{Issuer: "https://another.issuer.test/", Jwks: `{"keys": [{"kty": "RSA", "e": "AQAB", "n": "abc"}]}`},
+			}, "", ""),
+			Entry("issuer with jwksUri and inline jwks", []*gatewayv1beta1.JwtAuthentication{
+				{Issuer: "https://issuer.test/", JwksUri: "https://issuer.test/.well-known/jwks.json", Jwks: `{"keys": [{"kty": "RSA"}]}`},
+			}, ".config.authentications[0].jwks", "jwks and jwksUri can't be defined together"),
+			Entry("issuer with inline jwks without keys", []*gatewayv1beta1.JwtAuthentication{
+				{Issuer: "https://issuer.test/", Jwks: `{"keys": []}`},
+			}, ".config.authentications[0].jwks", "value is not a JSON Web Key Set with keys"),
+			Entry("second issuer without jwksUri and inline jwks", []*gatewayv1beta1.JwtAuthentication{
+				{Issuer: "https://issuer.test/", JwksUri: "https://issuer.test/.well-known/jwks.json"},
+				{Issuer: "https://another.issuer.test/"},
+			}, ".config.authentications[1].jwksUri", "value is empty or not a valid url err=value is empty"),
+		)
+
 		It("Should fail validation when authentication has more than one fromHeaders", func() {
 			//given
 			config := processingtest.GetRawConfig(
@@ -241,6 +279,60 @@ var _ = Describe("JWT Handler validation", func() {
 			Expect(problems[0].Message).To(Equal("multiple fromHeaders are not supported"))
 		})
 
+		It("Should fail validation when a fromHeaders has an empty name", func() {
+			//given
+			config := processingtest.GetRawConfig(
+				gatewayv1beta1.JwtConfig{
+					Authentications: []*gatewayv1beta1.JwtAuthentication{
+						{
+							Issuer:      "https://issuer.test/",
+							JwksUri:     "file://.well-known/jwks.json",
+							FromHeaders: []*gatewayv1beta1.JwtHeader{{Prefix: "Bearer "}},
+						},
+					},
+				})
+
+			handler := &gatewayv1beta1.Handler{
+				Name:   "jwt",
+				Config: config,
+			}
+
+			//when
+			problems := (&handlerValidator{}).Validate("", handler)
+
+			//then
+			Expect(problems).To(HaveLen(1))
+			Expect(problems[0].AttributePath).To(Equal(".config.authentications[0].fromHeaders[0].name"))
+			Expect(problems[0].Message).To(Equal("value is empty"))
+		})
+
+		It("Should fail validation when a fromParams is empty", func() {
+			//given
+			config := processingtest.GetRawConfig(
+				gatewayv1beta1.JwtConfig{
+					Authentications: []*gatewayv1beta1.JwtAuthentication{
+						{
+							Issuer:     "https://issuer.test/",
+							JwksUri:    "file://.well-known/jwks.json",
+							FromParams: []string{""},
+						},
+					},
+				})
+
+			handler := &gatewayv1beta1.Handler{
+				Name:   "jwt",
+				Config: config,
+			}
+
+			//when
+			problems := (&handlerValidator{}).Validate("", handler)
+
+			//then
+			Expect(problems).To(HaveLen(1))
+			Expect(problems[0].AttributePath).To(Equal(".config.authentications[0].fromParams[0]"))
+			Expect(problems[0].Message).To(Equal("value is empty"))
+		})
+
 		It("Should fail validation when authentication has more than one fromParams", func() {
 			//given
 			config := processingtest.GetRawConfig(
@@ -730,9 +822,53 @@ var _ = Describe("JWT Handler validation", func() {
 			//then
 			Expect(problems).To(HaveLen(0))
 		})
+
+		DescribeTable("Should validate the routing targets of rules with the same path",
+			func(rules []gatewayv1beta1.Rule, expectedPaths []string) {
+				//when
+				problems := (&rulesValidator{}).Validate(".spec.rules", rules)
+
+				//then
+				var paths []string
+				for _, problem := range problems {
+					paths = append(paths, problem.AttributePath)
+					Expect(problem.Message).To(Equal("Rules with path /headers can't mix the jwt access strategy with access strategies handled by oathkeeper"))
+				}
+				Expect(paths).To(Equal(expectedPaths))
+			},
+			Entry("jwt and noop for the same path",
+				[]gatewayv1beta1.Rule{routingRule("/headers", "GET", "jwt"), routingRule("/headers", "POST", "noop")},
+				[]string{".spec.rules[0].accessStrategies", ".spec.rules[1].accessStrategies"}),
+			Entry("jwt and oauth2_introspection for the same path with an allow rule",
+				[]gatewayv1beta1.Rule{routingRule("/headers", "GET", "allow"), routingRule("/headers", "POST", "oauth2_introspection"), routingRule("/headers", "PUT", "jwt")},
+				[]string{".spec.rules[1].accessStrategies", ".spec.rules[2].accessStrategies"}),
+			Entry("jwt and noop for different paths",
+				[]gatewayv1beta1.Rule{routingRule("/headers", "GET", "jwt"), routingRule("/ip", "GET", "noop")},
+				nil),
+			Entry("jwt and allow for the same path",
+				[]gatewayv1beta1.Rule{routingRule("/headers", "GET", "jwt"), routingRule("/headers", "POST", "allow")},
+				nil),
+			Entry("jwt and a disabled noop rule for the same path",
+				[]gatewayv1beta1.Rule{routingRule("/headers", "GET", "jwt"), disabled(routingRule("/headers", "POST", "noop"))},
+				nil),
+		)
 	})
 })
 
+func routingRule(path string, method string, strategy string) gatewayv1beta1.Rule {
+	return gatewayv1beta1.Rule{
+		Path:             path,
+		Methods:          []string{method},
+		AccessStrategies: []*gatewayv1beta1.Authenticator{{Handler: &gatewayv1beta1.Handler{Name: strategy}}},
+	}
+}
+
+func disabled(rule gatewayv1beta1.Rule) gatewayv1beta1.Rule {
+	enabled := false
+	rule.Enabled = &enabled
+	return rule
+}
+
 func emptyJWTIstioConfig() *runtime.RawExtension {
 	return processingtest.GetRawConfig(
 		&gatewayv1beta1.JwtConfig{})
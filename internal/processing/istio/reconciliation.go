@@ -21,9 +21,11 @@ func NewIstioReconciliation(config processing.ReconciliationConfig, log *logr.Lo
 	vsProcessor := NewVirtualServiceProcessor(config)
 	apProcessor := NewAuthorizationPolicyProcessor(config, log)
 	raProcessor := NewRequestAuthenticationProcessor(config)
+	drProcessor := NewDestinationRuleProcessor(config)
+	efProcessor := NewEnvoyFilterProcessor(config)
 
 	return Reconciliation{
-		processors: []processing.ReconciliationProcessor{vsProcessor, raProcessor, apProcessor, acProcessor},
+		processors: []processing.ReconciliationProcessor{vsProcessor, drProcessor, efProcessor, raProcessor, apProcessor, acProcessor},
 		config:     config,
 	}
 }
@@ -41,9 +43,16 @@ func (r Reconciliation) Validate(ctx context.Context, client client.Client, apiR
 		InjectionValidator:        &injectionValidator{ctx: ctx, client: client},
 		RulesValidator:            &rulesValidator{},
 		ServiceBlockList:          r.config.ServiceBlockList,
+		RestrictServiceNamespaces: r.config.RestrictServiceNamespaces,
+		ServiceNamespaceAllowList: r.config.ServiceNamespaceAllowList,
 		DomainAllowList:           r.config.DomainAllowList,
 		HostBlockList:             r.config.HostBlockList,
 		DefaultDomainName:         r.config.DefaultDomainName,
+		LuaFilters:                r.config.LuaFilters,
+	}
+	// The existence of the services is only validated if enabled, since the services might be created after the APIRule.
+	if r.config.ValidateServices {
+		validator.ServiceValidator = &validation.ServiceExistenceValidator{Ctx: ctx, Client: client}
 	}
 	return validator.Validate(apiRule, vsList), nil
 }
@@ -51,3 +60,7 @@ func (r Reconciliation) Validate(ctx context.Context, client client.Client, apiR
 func (r Reconciliation) GetProcessors() []processing.ReconciliationProcessor {
 	return r.processors
 }
+
+func (r Reconciliation) GetDuplicatePathsMode() processing.DuplicatePathsMode {
+	return r.config.DuplicatePathsMode
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/processing"
 	. "github.com/kyma-project/api-gateway/internal/processing/internal/test"
 	"github.com/kyma-project/api-gateway/internal/processing/istio"
 	. "github.com/onsi/ginkgo/v2"
@@ -17,6 +18,96 @@ import (
 )
 
 var _ = Describe("Reconciliation", func() {
+	It("should report a validation error and not create a Virtual Service for an APIRule without rules", func() {
+		// given
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{})
+		fakeClient := GetFakeClient()
+		reconciliation := istio.NewIstioReconciliation(GetTestConfig(), &testLogger)
+
+		// when
+		status := processing.Reconcile(context.TODO(), fakeClient, &testLogger, reconciliation, apiRule)
+
+		// then
+		Expect(status.ApiRuleStatus.Code).To(Equal(gatewayv1beta1.StatusError))
+		Expect(status.ApiRuleStatus.Description).To(ContainSubstring("No rules defined"))
+
+		var vsList networkingv1beta1.VirtualServiceList
+		Expect(fakeClient.List(context.TODO(), &vsList)).To(Succeed())
+		Expect(vsList.Items).To(BeEmpty())
+	})
+
+	It("should return the validation failure of an APIRule without rules in a dry run", func() {
+		// given
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{})
+		reconciliation := istio.NewIstioReconciliation(GetTestConfig(), &testLogger)
+
+		// when
+		failures, err := processing.DryRunReconcile(context.TODO(), GetFakeClient(), reconciliation, apiRule)
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(failures).To(HaveLen(1))
+		Expect(failures[0].Message).To(Equal("No rules defined"))
+	})
+
+	It("should not create any object for a valid APIRule in a dry run", func() {
+		// given
+		allow := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{GetRuleFor("/headers", ApiMethods, []*gatewayv1beta1.Mutator{}, allow)})
+		fakeClient := GetFakeClient()
+		reconciliation := istio.NewIstioReconciliation(GetTestConfig(), &testLogger)
+
+		// when
+		failures, err := processing.DryRunReconcile(context.TODO(), fakeClient, reconciliation, apiRule)
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(failures).To(BeEmpty())
+
+		var vsList networkingv1beta1.VirtualServiceList
+		Expect(fakeClient.List(context.TODO(), &vsList)).To(Succeed())
+		Expect(vsList.Items).To(BeEmpty())
+	})
+
+	It("should reconcile the APIRules that share the aggregated Virtual Service of their host repeatedly", func() {
+		// given
+		allow := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+		first := GetAPIRuleFor([]gatewayv1beta1.Rule{GetRuleFor("/img", ApiMethods, []*gatewayv1beta1.Mutator{}, allow)})
+		first.Name = "first"
+		second := GetAPIRuleFor([]gatewayv1beta1.Rule{GetRuleFor("/headers", ApiMethods, []*gatewayv1beta1.Mutator{}, allow)})
+		second.Name = "second"
+		fakeClient := GetFakeClient(first, second)
+		config := GetTestConfig()
+		config.AggregateByHost = true
+		reconciliation := istio.NewIstioReconciliation(config, &testLogger)
+
+		for _, apiRule := range []*gatewayv1beta1.APIRule{first, second, first} {
+			// when
+			status := processing.Reconcile(context.TODO(), fakeClient, &testLogger, reconciliation, apiRule)
+
+			// then
+			Expect(status.ApiRuleStatus.Code).To(Equal(gatewayv1beta1.StatusOK), status.ApiRuleStatus.Description)
+		}
+
+		var vsList networkingv1beta1.VirtualServiceList
+		Expect(fakeClient.List(context.TODO(), &vsList)).To(Succeed())
+		Expect(vsList.Items).To(HaveLen(1))
+		Expect(vsList.Items[0].Name).To(Equal(processing.GetAggregatedVirtualServiceName(first)))
+		Expect(vsList.Items[0].Spec.Http).To(HaveLen(2))
+	})
+
 	When("multiple handlers in addition to Istio JWT", func() {
 		jwtConfigJSON := fmt.Sprintf(`{"authentications": [{"issuer": "%s", "jwksUri": "%s"}]}`, JwtIssuer, JwksUri)
 		jwt := []*gatewayv1beta1.Authenticator{
@@ -137,8 +228,8 @@ var _ = Describe("Reconciliation", func() {
 					vsCreated = true
 					Expect(vs).NotTo(BeNil())
 					Expect(len(vs.Spec.Http)).To(Equal(2))
-					Expect(len(vs.Spec.Http[1].Route)).To(Equal(1))
-					Expect(vs.Spec.Http[1].Route[0].Destination.Host).To(Equal(ServiceName + "." + ApiNamespace + ".svc.cluster.local"))
+					Expect(len(vs.Spec.Http[0].Route)).To(Equal(1))
+					Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(ServiceName + "." + ApiNamespace + ".svc.cluster.local"))
 				} else if arOk {
 					oryRuleCreated = true
 					Expect(ar).NotTo(BeNil())
@@ -203,8 +294,8 @@ var _ = Describe("Reconciliation", func() {
 					vsCreated = true
 					Expect(vs).NotTo(BeNil())
 					Expect(len(vs.Spec.Http)).To(Equal(2))
-					Expect(len(vs.Spec.Http[1].Route)).To(Equal(1))
-					Expect(vs.Spec.Http[1].Route[0].Destination.Host).To(Equal(ServiceName + "." + ApiNamespace + ".svc.cluster.local"))
+					Expect(len(vs.Spec.Http[0].Route)).To(Equal(1))
+					Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(ServiceName + "." + ApiNamespace + ".svc.cluster.local"))
 				} else if arOk {
 					oryRuleCreated = true
 					Expect(ar).NotTo(BeNil())
@@ -45,8 +45,8 @@ func generateRequestAuthentication(api *gatewayv1beta1.APIRule, rule gatewayv1be
 		WithGenerateName(namePrefix).
 		WithNamespace(namespace).
 		WithSpec(builders.NewRequestAuthenticationSpecBuilder().WithFrom(generateRequestAuthenticationSpec(api, rule)).Get()).
-		WithLabel(processing.OwnerLabel, fmt.Sprintf("%s.%s", api.ObjectMeta.Name, api.ObjectMeta.Namespace)).
-		WithLabel(processing.OwnerLabelv1alpha1, fmt.Sprintf("%s.%s", api.ObjectMeta.Name, api.ObjectMeta.Namespace))
+		WithLabel(processing.OwnerLabel, processing.GetOwnerLabelValue(api)).
+		WithLabel(processing.OwnerLabelv1alpha1, processing.GetOwnerLabelValue(api))
 
 	for k, v := range additionalLabels {
 		raBuilder.WithLabel(k, v)
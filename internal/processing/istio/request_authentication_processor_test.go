@@ -115,6 +115,48 @@ var _ = Describe("Request Authentication Processor", func() {
 		Expect(ra.Spec.JwtRules[0].JwksUri).To(Equal(JwksUri))
 	})
 
+	DescribeTable("should produce RA with the configured token location",
+		func(location string, assertJwtRule func(*v1beta1.JWTRule)) {
+			// given
+			jwtConfigJSON := fmt.Sprintf(`{"authentications": [{"issuer": "%s", "jwksUri": "%s", %s}]}`, JwtIssuer, JwksUri, location)
+			jwt := &gatewayv1beta1.Authenticator{
+				Handler: &gatewayv1beta1.Handler{
+					Name:   "jwt",
+					Config: &runtime.RawExtension{Raw: []byte(jwtConfigJSON)},
+				},
+			}
+			service := &gatewayv1beta1.Service{
+				Name: &ServiceName,
+				Port: &ServicePort,
+			}
+
+			ruleJwt := GetRuleWithServiceFor(HeadersApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, []*gatewayv1beta1.Authenticator{jwt}, service)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{ruleJwt})
+			client := GetFakeClient()
+			processor := istio.NewRequestAuthenticationProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+			ra := result[0].Obj.(*securityv1beta1.RequestAuthentication)
+			Expect(ra.Spec.JwtRules).To(HaveLen(1))
+			assertJwtRule(ra.Spec.JwtRules[0])
+		},
+		Entry("from header", `"fromHeaders": [{"name": "x-jwt-token", "prefix": "Token "}]`, func(jwtRule *v1beta1.JWTRule) {
+			Expect(jwtRule.FromHeaders).To(HaveLen(1))
+			Expect(jwtRule.FromHeaders[0].Name).To(Equal("x-jwt-token"))
+			Expect(jwtRule.FromHeaders[0].Prefix).To(Equal("Token "))
+			Expect(jwtRule.FromParams).To(BeEmpty())
+		}),
+		Entry("from query parameter", `"fromParams": ["jwt_token"]`, func(jwtRule *v1beta1.JWTRule) {
+			Expect(jwtRule.FromParams).To(Equal([]string{"jwt_token"}))
+			Expect(jwtRule.FromHeaders).To(BeEmpty())
+		}),
+	)
+
 	It("should produce RA for a Rule without service, but service definition on ApiRule level", func() {
 		// given
 		jwt := createIstioJwtAccessStrategy()
@@ -197,6 +239,44 @@ var _ = Describe("Request Authentication Processor", func() {
 		Expect(ra.Labels[processing.OwnerLabel]).To(Equal(fmt.Sprintf("%s.%s", apiRule.Name, apiRule.Namespace)))
 	})
 
+	It("should produce RA from a rule with two issuers of which one has inline jwks", func() {
+		// given
+		jwks := `{\"keys\": [{\"kty\": \"RSA\", \"e\": \"AQAB\", \"n\": \"abc\"}]}`
+		jwtConfigJSON := fmt.Sprintf(`{
+			"authentications": [{"issuer": "%s", "jwksUri": "%s"}, {"issuer": "%s", "jwks": "%s"}]
+			}`, JwtIssuer, JwksUri, JwtIssuer2, jwks)
+		jwt := &gatewayv1beta1.Authenticator{
+			Handler: &gatewayv1beta1.Handler{
+				Name:   "jwt",
+				Config: &runtime.RawExtension{Raw: []byte(jwtConfigJSON)},
+			},
+		}
+		service := &gatewayv1beta1.Service{
+			Name: &ServiceName,
+			Port: &ServicePort,
+		}
+
+		ruleJwt := GetRuleWithServiceFor(HeadersApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, []*gatewayv1beta1.Authenticator{jwt}, service)
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{ruleJwt})
+		client := GetFakeClient()
+		processor := istio.NewRequestAuthenticationProcessor(GetTestConfig())
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+		ra := result[0].Obj.(*securityv1beta1.RequestAuthentication)
+		Expect(ra.Spec.JwtRules).To(HaveLen(2))
+		Expect(ra.Spec.JwtRules[0].Issuer).To(Equal(JwtIssuer))
+		Expect(ra.Spec.JwtRules[0].JwksUri).To(Equal(JwksUri))
+		Expect(ra.Spec.JwtRules[0].Jwks).To(BeEmpty())
+		Expect(ra.Spec.JwtRules[1].Issuer).To(Equal(JwtIssuer2))
+		Expect(ra.Spec.JwtRules[1].JwksUri).To(BeEmpty())
+		Expect(ra.Spec.JwtRules[1].Jwks).To(Equal(`{"keys": [{"kty": "RSA", "e": "AQAB", "n": "abc"}]}`))
+	})
+
 	It("should produce RA from a rule with two issuers and one path", func() {
 		jwtConfigJSON := fmt.Sprintf(`{
 			"authentications": [{"issuer": "%s", "jwksUri": "%s"}, {"issuer": "%s", "jwksUri": "%s"}]
@@ -1,8 +1,7 @@
 package istio
 
 import (
-	"fmt"
-	"time"
+	"errors"
 
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
 	"github.com/kyma-project/api-gateway/internal/builders"
@@ -12,118 +11,106 @@ import (
 	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 )
 
+func init() {
+	processors.RegisterVirtualServiceCreator(helpers.JWT_HANDLER_ISTIO, newVirtualServiceCreator)
+}
+
 // NewVirtualServiceProcessor returns a VirtualServiceProcessor with the desired state handling specific for the Istio handler.
 func NewVirtualServiceProcessor(config processing.ReconciliationConfig) processors.VirtualServiceProcessor {
-	return processors.VirtualServiceProcessor{
-		Creator: virtualServiceCreator{
-			oathkeeperSvc:       config.OathkeeperSvc,
-			oathkeeperSvcPort:   config.OathkeeperSvcPort,
-			corsConfig:          config.CorsConfig,
-			additionalLabels:    config.AdditionalLabels,
-			defaultDomainName:   config.DefaultDomainName,
-			httpTimeoutDuration: config.HTTPTimeoutDuration,
-		},
+	return processors.NewVirtualServiceProcessor(config, newVirtualServiceCreator(config))
+}
+
+func newVirtualServiceCreator(config processing.ReconciliationConfig) processors.VirtualServiceCreator {
+	routeConfig := processing.NewHTTPRouteConfig(config)
+	routeConfig.CatchAllPathPrefix = true
+	routeConfig.RouteDirectlyToService = processing.ShouldRouteDirectlyToService
+
+	return virtualServiceCreator{
+		routeConfig:       routeConfig,
+		additionalLabels:  config.AdditionalLabels,
+		defaultDomainName: config.DefaultDomainName,
+		strictHostDomain:  config.StrictHostDomain,
+		fixedName:         config.VirtualServiceFixedName,
+		namePrefix:        config.VirtualServiceNamePrefix,
+		nameSuffix:        config.VirtualServiceNameSuffix,
+		exportTo:          config.ExportTo,
 	}
 }
 
 type virtualServiceCreator struct {
-	oathkeeperSvc       string
-	oathkeeperSvcPort   uint32
-	corsConfig          *processing.CorsConfig
-	defaultDomainName   string
-	additionalLabels    map[string]string
-	httpTimeoutDuration int
+	routeConfig       processing.HTTPRouteConfig
+	defaultDomainName string
+	additionalLabels  map[string]string
+	strictHostDomain  bool
+	fixedName         bool
+	namePrefix        string
+	nameSuffix        string
+	exportTo          []string
 }
 
 // Create returns the Virtual Service using the configuration of the APIRule.
 func (r virtualServiceCreator) Create(api *gatewayv1beta1.APIRule) (*networkingv1beta1.VirtualService, error) {
-	virtualServiceNamePrefix := fmt.Sprintf("%s-", api.ObjectMeta.Name)
+	// Istio rejects Virtual Services without routes, the validation of the APIRule reports this case with a clear message.
+	enabledRules := processing.FilterDisabledRules(api.Spec.Rules)
+	if len(enabledRules) == 0 {
+		return nil, processing.NewFieldError(".spec.rules", errors.New("no rules defined"))
+	}
 
 	vsSpecBuilder := builders.VirtualServiceSpec()
-	vsSpecBuilder.Host(helpers.GetHostWithDomain(*api.Spec.Host, r.defaultDomainName))
-	vsSpecBuilder.Gateway(*api.Spec.Gateway)
-	filteredRules := processing.FilterDuplicatePaths(api.Spec.Rules)
-
-	for _, rule := range filteredRules {
-		httpRouteBuilder := builders.HTTPRoute()
-		serviceNamespace := helpers.FindServiceNamespace(api, &rule)
-		routeDirectlyToService := false
-		if !processing.IsSecured(rule) {
-			routeDirectlyToService = true
-		} else if processing.IsJwtSecured(rule) {
-			routeDirectlyToService = true
-		}
-
-		var host string
-		var port uint32
-
-		if routeDirectlyToService {
-			// Use rule level service if it exists
-			if rule.Service != nil {
-				host = helpers.GetHostLocalDomain(*rule.Service.Name, serviceNamespace)
-				port = *rule.Service.Port
-			} else {
-				// Otherwise use service defined on APIRule spec level
-				host = helpers.GetHostLocalDomain(*api.Spec.Service.Name, serviceNamespace)
-				port = *api.Spec.Service.Port
-			}
-		} else {
-			host = r.oathkeeperSvc
-			port = r.oathkeeperSvcPort
-		}
-
-		httpRouteBuilder.Route(builders.RouteDestination().Host(host).Port(port))
-
-		if rule.Path == "/*" {
-			httpRouteBuilder.Match(builders.MatchRequest().Uri().Prefix("/"))
-		} else {
-			httpRouteBuilder.Match(builders.MatchRequest().Uri().Regex(rule.Path))
+	var hosts []string
+	for _, host := range helpers.GetHosts(api) {
+		hostWithDomain, err := helpers.ResolveHostWithDomain(host, r.defaultDomainName, r.strictHostDomain)
+		if err != nil {
+			return nil, err
 		}
-		httpRouteBuilder.CorsPolicy(builders.CorsPolicy().
-			AllowOrigins(r.corsConfig.AllowOrigins...).
-			AllowMethods(r.corsConfig.AllowMethods...).
-			AllowHeaders(r.corsConfig.AllowHeaders...))
-		httpRouteBuilder.Timeout(time.Second * time.Duration(r.httpTimeoutDuration))
-
-		headersBuilder := builders.NewHttpRouteHeadersBuilder().
-			SetHostHeader(helpers.GetHostWithDomain(*api.Spec.Host, r.defaultDomainName))
-
-		// We need to add mutators only for JWT secured rules, since "noop" and "oauth2_introspection" access strategies
-		// create access rules and therefore use ory mutators. The "allow" access strategy does not support mutators at all.
-		if processing.IsJwtSecured(rule) {
-			cookieMutator, err := rule.GetCookieMutator()
-			if err != nil {
-				return nil, err
-			}
-			if cookieMutator.HasCookies() {
-				headersBuilder.SetRequestCookies(cookieMutator.ToString())
-			}
-
-			headerMutator, err := rule.GetHeaderMutator()
-			if err != nil {
-				return nil, err
-			}
-			if headerMutator.HasHeaders() {
-				headersBuilder.SetRequestHeaders(headerMutator.Headers)
-			}
+		hosts = append(hosts, hostWithDomain)
+		vsSpecBuilder.Host(hostWithDomain)
+	}
+	for _, gateway := range helpers.GetGateways(api) {
+		vsSpecBuilder.Gateway(gateway)
+	}
+	vsSpecBuilder.ExportTo(processing.GetExportTo(api, r.exportTo)...)
+	filteredRules := processing.GetRoutedRules(api)
+	duplicatedMatches := processing.GetDuplicatedMatches(enabledRules)
+
+	// The errors of all rules are collected, so all invalid fields are reported at once.
+	var errs processing.FieldErrors
+	for i, rule := range filteredRules {
+		httpRoute, err := processing.NewHTTPRoute(api, rule, i, hosts, duplicatedMatches, r.routeConfig)
+		errs = append(errs, err...)
+		if httpRoute != nil {
+			vsSpecBuilder.HTTP(builders.HTTPRoute().From(httpRoute))
 		}
+	}
 
-		httpRouteBuilder.Headers(headersBuilder.Get())
-
-		vsSpecBuilder.HTTP(httpRouteBuilder)
-
+	if len(errs) > 0 {
+		return nil, errs
 	}
 
 	vsBuilder := builders.VirtualService().
-		GenerateName(virtualServiceNamePrefix).
 		Namespace(api.ObjectMeta.Namespace).
-		Label(processing.OwnerLabel, fmt.Sprintf("%s.%s", api.ObjectMeta.Name, api.ObjectMeta.Namespace)).
-		Label(processing.OwnerLabelv1alpha1, fmt.Sprintf("%s.%s", api.ObjectMeta.Name, api.ObjectMeta.Namespace))
+		Label(processing.OwnerLabel, processing.GetOwnerLabelValue(api)).
+		Label(processing.OwnerLabelv1alpha1, processing.GetOwnerLabelValue(api))
+
+	// The existing VirtualService is looked up by the owner labels, so the naming mode doesn't affect finding it.
+	if r.fixedName {
+		vsBuilder.Name(processing.GetVirtualServiceFixedName(api))
+	} else {
+		vsBuilder.GenerateName(processing.GetVirtualServiceGenerateName(api, r.namePrefix, r.nameSuffix))
+	}
 
 	for k, v := range r.additionalLabels {
 		vsBuilder.Label(k, v)
 	}
 
+	if accessLogDisabledPaths := processing.GetAccessLogDisabledPathsAnnotation(api); accessLogDisabledPaths != "" {
+		vsBuilder.Annotation(processing.AccessLogDisabledPathsAnnotation, accessLogDisabledPaths)
+	}
+
+	if maxRequestHeadersBytes := processing.GetMaxRequestHeadersBytesAnnotation(api); maxRequestHeadersBytes != "" {
+		vsBuilder.Annotation(processing.MaxRequestHeadersBytesAnnotation, maxRequestHeadersBytes)
+	}
+
 	vsBuilder.Spec(vsSpecBuilder)
 
 	return vsBuilder.Get(), nil
@@ -2,8 +2,10 @@ package istio_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/helpers"
 	"github.com/kyma-project/api-gateway/internal/processing"
 	. "github.com/kyma-project/api-gateway/internal/processing/internal/test"
 	processingtest "github.com/kyma-project/api-gateway/internal/processing/internal/test"
@@ -11,10 +13,13 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	rulev1alpha1 "github.com/ory/oathkeeper-maester/api/v1alpha1"
+	"istio.io/api/networking/v1beta1"
 	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"time"
 )
 
 var _ = Describe("Virtual Service Processor", func() {
@@ -185,29 +190,143 @@ var _ = Describe("Virtual Service Processor", func() {
 		})
 	})
 
-	When("handler is noop", func() {
-		It("should not override Oathkeeper service destination host with spec level service", func() {
+	When("the host domain is resolved", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		DescribeTable("should set the host",
+			func(host string, defaultDomain string, strict bool, expectedHost string) {
+				// given
+				rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+				apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+				apiRule.Spec.Host = &host
+				client := GetFakeClient()
+				config := GetTestConfig()
+				config.DefaultDomainName = defaultDomain
+				config.StrictHostDomain = strict
+				processor := istio.NewVirtualServiceProcessor(config)
+
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+				// then
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+
+				vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+				Expect(vs.Spec.Hosts).To(Equal([]string{expectedHost}))
+				Expect(vs.Spec.Http[0].Headers.Request.Set).To(HaveKeyWithValue("x-forwarded-host", expectedHost))
+			},
+			Entry("fully qualified host is used verbatim", "myservice.example.com", DefaultDomain, false, "myservice.example.com"),
+			Entry("fully qualified host is used verbatim in strict mode without default domain", "myservice.example.com", "", true, "myservice.example.com"),
+			Entry("short host is completed with the default domain", "myservice", DefaultDomain, false, "myservice."+DefaultDomain),
+			Entry("short host is completed with the default domain in strict mode", "myservice", DefaultDomain, true, "myservice."+DefaultDomain),
+		)
+
+		DescribeTable("should set the wildcard host",
+			func(host string, expectedHost string) {
+				// given
+				rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+				apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+				apiRule.Spec.Host = &host
+				client := GetFakeClient()
+				config := GetTestConfig()
+				config.DefaultDomainName = DefaultDomain
+				processor := istio.NewVirtualServiceProcessor(config)
+
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+				// then
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+
+				vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+				Expect(vs.Spec.Hosts).To(Equal([]string{expectedHost}))
+				Expect(vs.Spec.Http[0].Headers.Request.Set).To(HaveKeyWithValue("x-forwarded-host", "%REQ(:AUTHORITY)%"))
+			},
+			Entry("fully qualified wildcard host is used verbatim", "*.apps.example.com", "*.apps.example.com"),
+			Entry("short wildcard host is completed with the default domain", "*.apps", "*.apps."+DefaultDomain),
+		)
+
+		It("should return an error for a short host without default domain in strict mode", func() {
+			// given
+			host := "myservice"
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			apiRule.Spec.Host = &host
+			client := GetFakeClient()
+			config := GetTestConfig()
+			config.DefaultDomainName = ""
+			config.StrictHostDomain = true
+			processor := istio.NewVirtualServiceProcessor(config)
+
+			// when
+			_, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("host myservice is not fully qualified and no default domain is configured"))
+		})
+	})
+
+	When("multiple hosts are defined", func() {
+		It("should add all hosts to the VS and forward the authority of the request as forwarded host", func() {
 			// given
 			strategies := []*gatewayv1beta1.Authenticator{
 				{
 					Handler: &gatewayv1beta1.Handler{
-						Name: "noop",
+						Name: "allow",
 					},
 				},
 			}
 
-			overrideServiceName := "testName"
-			overrideServicePort := uint32(8080)
+			allowRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rules := []gatewayv1beta1.Rule{allowRule}
 
-			service := &gatewayv1beta1.Service{
-				Name: &overrideServiceName,
-				Port: &overrideServicePort,
+			apiRule := GetAPIRuleFor(rules)
+			apiRule.Spec.Hosts = []string{ServiceHostWithNoDomain + "-internal", "app.internal.com"}
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Hosts).To(Equal([]string{ServiceHost, ServiceHostWithNoDomain + "-internal." + DefaultDomain, "app.internal.com"}))
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Headers.Request.Set).To(HaveKeyWithValue("x-forwarded-host", "%REQ(:AUTHORITY)%"))
+		})
+
+		It("should not set the forwarded host header when the host is preserved", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
 			}
 
-			allowRule := GetRuleWithServiceFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies, service)
+			preserveHost := true
+			allowRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			allowRule.PreserveHost = &preserveHost
 			rules := []gatewayv1beta1.Rule{allowRule}
 
 			apiRule := GetAPIRuleFor(rules)
+			apiRule.Spec.Hosts = []string{"app.internal.com"}
 			client := GetFakeClient()
 			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
 
@@ -219,57 +338,180 @@ var _ = Describe("Virtual Service Processor", func() {
 			Expect(result).To(HaveLen(1))
 
 			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+			Expect(vs.Spec.Http[0].Headers.Request.Set).ToNot(HaveKey("x-forwarded-host"))
+		})
 
-			Expect(len(vs.Spec.Http[0].Route)).To(Equal(1))
-			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(OathkeeperSvc))
+		It("should normalize the hosts and pass valid hosts through unchanged", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)})
+			upperCaseHost := "MyService.MyDomain.com."
+			apiRule.Spec.Host = &upperCaseHost
+			apiRule.Spec.Hosts = []string{"App.Internal.com.", "app.example.com"}
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+			Expect(vs.Spec.Hosts).To(Equal([]string{"myservice.mydomain.com", "app.internal.com", "app.example.com"}))
 		})
 
-		When("existing virtual service has owner v1alpha1 owner label", func() {
-			It("should get and update", func() {
+		DescribeTable("should not set the forwarded host header if the host is preserved",
+			func(specPreserveHost *bool, rulePreserveHost *bool, expectHostHeader bool) {
 				// given
-				noop := []*gatewayv1beta1.Authenticator{
+				strategies := []*gatewayv1beta1.Authenticator{
 					{
 						Handler: &gatewayv1beta1.Handler{
-							Name: "noop",
+							Name: "allow",
 						},
 					},
 				}
 
-				noopRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, noop)
-				rules := []gatewayv1beta1.Rule{noopRule}
+				allowRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+				allowRule.PreserveHost = rulePreserveHost
+				rules := []gatewayv1beta1.Rule{allowRule}
 
 				apiRule := GetAPIRuleFor(rules)
+				apiRule.Spec.PreserveHost = specPreserveHost
+				client := GetFakeClient()
+				processor := istio.NewVirtualServiceProcessor(GetTestConfig())
 
-				rule := rulev1alpha1.Rule{
-					ObjectMeta: metav1.ObjectMeta{
-						Labels: map[string]string{
-							processing.OwnerLabelv1alpha1: fmt.Sprintf("%s.%s", apiRule.ObjectMeta.Name, apiRule.ObjectMeta.Namespace),
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+				// then
+				Expect(err).To(BeNil())
+				Expect(result).To(HaveLen(1))
+
+				vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+				Expect(vs.Spec.Http).To(HaveLen(1))
+				if expectHostHeader {
+					Expect(vs.Spec.Http[0].Headers.Request.Set).To(HaveKeyWithValue("x-forwarded-host", ServiceHost))
+				} else {
+					Expect(vs.Spec.Http[0].Headers.Request.Set).ToNot(HaveKey("x-forwarded-host"))
+				}
+			},
+			Entry("not configured", nil, nil, true),
+			Entry("preserved for the APIRule", pointer.Bool(true), nil, false),
+			Entry("preserved for the rule", nil, pointer.Bool(true), false),
+			Entry("preserved for the APIRule but not for the rule", pointer.Bool(true), pointer.Bool(false), true),
+		)
+
+		DescribeTable("should set the forwarded headers if enabled",
+			func(forwardedHeaders *bool, expectForwardedHeaders bool) {
+				// given
+				strategies := []*gatewayv1beta1.Authenticator{
+					{
+						Handler: &gatewayv1beta1.Handler{
+							Name: "allow",
 						},
 					},
-					Spec: rulev1alpha1.RuleSpec{
-						Match: &rulev1alpha1.Match{
-							URL: "some url",
+				}
+
+				allowRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+				rules := []gatewayv1beta1.Rule{allowRule}
+
+				apiRule := GetAPIRuleFor(rules)
+				apiRule.Spec.ForwardedHeaders = forwardedHeaders
+				client := GetFakeClient()
+				processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+				// then
+				Expect(err).To(BeNil())
+				Expect(result).To(HaveLen(1))
+
+				vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+				Expect(vs.Spec.Http).To(HaveLen(1))
+				if expectForwardedHeaders {
+					Expect(vs.Spec.Http[0].Headers.Request.Add).To(HaveKeyWithValue("x-forwarded-for", "%DOWNSTREAM_REMOTE_ADDRESS_WITHOUT_PORT%"))
+					Expect(vs.Spec.Http[0].Headers.Request.Set).To(HaveKeyWithValue("x-forwarded-proto", "%REQ(:SCHEME)%"))
+					Expect(vs.Spec.Http[0].Headers.Request.Set).To(HaveKeyWithValue("x-forwarded-host", ServiceHost))
+				} else {
+					Expect(vs.Spec.Http[0].Headers.Request.Add).ToNot(HaveKey("x-forwarded-for"))
+					Expect(vs.Spec.Http[0].Headers.Request.Set).ToNot(HaveKey("x-forwarded-proto"))
+				}
+			},
+			Entry("not configured", nil, false),
+			Entry("disabled", pointer.Bool(false), false),
+			Entry("enabled", pointer.Bool(true), true),
+		)
+	})
+
+	When("additional gateways are defined", func() {
+		DescribeTable("should reference all gateways in the VS",
+			func(gateway string, gateways []string, expectedGateways []string) {
+				// given
+				strategies := []*gatewayv1beta1.Authenticator{
+					{
+						Handler: &gatewayv1beta1.Handler{
+							Name: "allow",
 						},
 					},
 				}
 
-				vs := networkingv1beta1.VirtualService{
-					ObjectMeta: metav1.ObjectMeta{
-						Labels: map[string]string{
-							processing.OwnerLabelv1alpha1: fmt.Sprintf("%s.%s", apiRule.ObjectMeta.Name, apiRule.ObjectMeta.Namespace),
+				allowRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+				apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{allowRule})
+				apiRule.Spec.Gateway = &gateway
+				apiRule.Spec.Gateways = gateways
+				client := GetFakeClient()
+				processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+				// then
+				Expect(err).To(BeNil())
+				Expect(result).To(HaveLen(1))
+
+				vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+				Expect(vs.Spec.Gateways).To(Equal(expectedGateways))
+			},
+			Entry("single gateway", "some-gateway", nil, []string{"some-gateway"}),
+			Entry("namespaced gateway", "kyma-system/kyma-gateway", nil, []string{"kyma-system/kyma-gateway"}),
+			Entry("multiple gateways", "kyma-system/kyma-gateway", []string{"some-namespace/internal-gateway", "mesh"},
+				[]string{"kyma-system/kyma-gateway", "some-namespace/internal-gateway", "mesh"}),
+			Entry("duplicate gateways", "kyma-system/kyma-gateway", []string{"kyma-system/kyma-gateway"}, []string{"kyma-system/kyma-gateway"}),
+			Entry("mesh gateway only", "mesh", nil, []string{"mesh"}),
+			Entry("ingress and mesh gateway", "kyma-system/kyma-gateway", []string{"mesh"}, []string{"kyma-system/kyma-gateway", "mesh"}),
+		)
+	})
+
+	When("no gateway is defined", func() {
+		emptyGateway := ""
+
+		DescribeTable("should apply the VS only to the mesh",
+			func(gateway *string, gateways []string) {
+				// given
+				strategies := []*gatewayv1beta1.Authenticator{
+					{
+						Handler: &gatewayv1beta1.Handler{
+							Name: "allow",
 						},
 					},
 				}
 
-				scheme := runtime.NewScheme()
-				err := rulev1alpha1.AddToScheme(scheme)
-				Expect(err).NotTo(HaveOccurred())
-				err = networkingv1beta1.AddToScheme(scheme)
-				Expect(err).NotTo(HaveOccurred())
-				err = gatewayv1beta1.AddToScheme(scheme)
-				Expect(err).NotTo(HaveOccurred())
-
-				client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&rule, &vs).Build()
+				allowRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+				apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{allowRule})
+				apiRule.Spec.Gateway = gateway
+				apiRule.Spec.Gateways = gateways
+				client := GetFakeClient()
 				processor := istio.NewVirtualServiceProcessor(GetTestConfig())
 
 				// when
@@ -278,78 +520,2619 @@ var _ = Describe("Virtual Service Processor", func() {
 				// then
 				Expect(err).To(BeNil())
 				Expect(result).To(HaveLen(1))
-				Expect(result[0].Action.String()).To(Equal("update"))
 
-				resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+				vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+				Expect(vs.Spec.Gateways).To(Equal([]string{"mesh"}))
+				Expect(vs.Spec.Http).To(HaveLen(1))
+			},
+			Entry("nil gateway", nil, nil),
+			Entry("empty gateway", &emptyGateway, nil),
+			Entry("nil gateway with additional mesh gateway", nil, []string{"mesh"}),
+		)
+	})
 
-				Expect(resultVs).NotTo(BeNil())
-				Expect(resultVs).NotTo(BeNil())
-				Expect(len(resultVs.Spec.Gateways)).To(Equal(1))
-				Expect(len(resultVs.Spec.Hosts)).To(Equal(1))
-				Expect(resultVs.Spec.Hosts[0]).To(Equal(ServiceHost))
-				Expect(len(resultVs.Spec.Http)).To(Equal(1))
+	When("export is configured", func() {
+		DescribeTable("should export the VS to the configured namespaces",
+			func(defaultExportTo []string, exportTo []string, expectedExportTo []string) {
+				// given
+				strategies := []*gatewayv1beta1.Authenticator{
+					{
+						Handler: &gatewayv1beta1.Handler{
+							Name: "allow",
+						},
+					},
+				}
 
-				Expect(len(resultVs.Spec.Http[0].Route)).To(Equal(1))
-				Expect(resultVs.Spec.Http[0].Route[0].Destination.Host).To(Equal(OathkeeperSvc))
-				Expect(resultVs.Spec.Http[0].Route[0].Destination.Port.Number).To(Equal(OathkeeperSvcPort))
+				allowRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+				apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{allowRule})
+				apiRule.Spec.ExportTo = exportTo
+				client := GetFakeClient()
+				config := GetTestConfig()
+				config.ExportTo = defaultExportTo
+				processor := istio.NewVirtualServiceProcessor(config)
 
-				Expect(len(resultVs.Spec.Http[0].Match)).To(Equal(1))
-				Expect(resultVs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[0].Path))
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
 
-				Expect(resultVs.Spec.Http[0].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
-				Expect(resultVs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
-				Expect(resultVs.Spec.Http[0].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
-			})
-		})
+				// then
+				Expect(err).To(BeNil())
+				Expect(result).To(HaveLen(1))
+
+				vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+				Expect(vs.Spec.ExportTo).To(Equal(expectedExportTo))
+			},
+			Entry("to all namespaces if nothing is configured", nil, nil, nil),
+			Entry("to the default namespaces", []string{"."}, nil, []string{"."}),
+			Entry("to the namespaces of the APIRule", nil, []string{".", "other-namespace"}, []string{".", "other-namespace"}),
+			Entry("to the namespaces of the APIRule instead of the default namespaces", []string{"."}, []string{"*"}, []string{"*"}),
+		)
 	})
 
-	When("multiple handler", func() {
-		It("should return service for given paths", func() {
+	DescribeTable("matched rule header",
+		func(matchedRuleHeader string, expectedValues []string) {
 			// given
-			noop := []*gatewayv1beta1.Authenticator{
+			strategies := []*gatewayv1beta1.Authenticator{
 				{
 					Handler: &gatewayv1beta1.Handler{
-						Name: "noop",
+						Name: "allow",
+					},
+				},
+			}
+
+			rules := []gatewayv1beta1.Rule{
+				GetRuleFor("/headers", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies),
+				GetRuleFor("/status", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies),
+			}
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			config := GetTestConfig()
+			config.MatchedRuleHeader = matchedRuleHeader
+			processor := istio.NewVirtualServiceProcessor(config)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+			Expect(vs.Spec.Http).To(HaveLen(2))
+			for i, route := range vs.Spec.Http {
+				if expectedValues == nil {
+					Expect(route.Headers.Response).To(BeNil())
+				} else {
+					Expect(route.Headers.Response.Set).To(HaveKeyWithValue(matchedRuleHeader, expectedValues[i]))
+				}
+			}
+		},
+		Entry("is not set if disabled", "", nil),
+		Entry("is set to the route name of the rule if enabled", "x-matched-rule", []string{"test-apirule.some-namespace.0-headers", "test-apirule.some-namespace.1-status"}),
+	)
+
+	When("rule defines a CORS policy", func() {
+		It("should use the rule CORS policy and fall back to the default for unset fields", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
 					},
 				},
 			}
 
-			jwtConfigJSON := fmt.Sprintf(`
-						{
-							"trusted_issuers": ["%s"],
-							"jwks": [],
-							"required_scope": [%s]
-					}`, JwtIssuer, ToCSVList(ApiScopes))
+			overrideRule := GetRuleFor("/override", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			overrideRule.CorsPolicy = &gatewayv1beta1.CorsPolicy{
+				AllowOrigins: []string{"https://example.com"},
+				AllowMethods: []string{"PATCH"},
+			}
+			defaultRule := GetRuleFor("/default", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rules := []gatewayv1beta1.Rule{overrideRule, defaultRule}
+
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(2))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins[0].GetExact()).To(Equal("https://example.com"))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal([]string{"PATCH"}))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+
+			Expect(vs.Spec.Http[1].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
+			Expect(vs.Spec.Http[1].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
+			Expect(vs.Spec.Http[1].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+		})
+
+		It("should use the rule CORS policy over the spec CORS policy and the spec CORS policy over the default", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			overrideRule := GetRuleFor("/override", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			overrideRule.CorsPolicy = &gatewayv1beta1.CorsPolicy{
+				AllowMethods: []string{"PATCH"},
+			}
+			specRule := GetRuleFor("/spec", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rules := []gatewayv1beta1.Rule{overrideRule, specRule}
+
+			apiRule := GetAPIRuleFor(rules)
+			apiRule.Spec.CorsPolicy = &gatewayv1beta1.CorsPolicy{
+				AllowOrigins: []string{"https://example.com"},
+				AllowMethods: []string{"GET"},
+			}
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(2))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins[0].GetExact()).To(Equal("https://example.com"))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal([]string{"PATCH"}))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+
+			Expect(vs.Spec.Http[1].CorsPolicy.AllowOrigins).To(HaveLen(1))
+			Expect(vs.Spec.Http[1].CorsPolicy.AllowOrigins[0].GetExact()).To(Equal("https://example.com"))
+			Expect(vs.Spec.Http[1].CorsPolicy.AllowMethods).To(Equal([]string{"GET"}))
+			Expect(vs.Spec.Http[1].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+		})
+
+		It("should use the max age of the rule CORS policy over the default max age", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			maxAge := "10m"
+			disabledMaxAge := "0s"
+			overrideRule := GetRuleFor("/override", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			overrideRule.CorsPolicy = &gatewayv1beta1.CorsPolicy{MaxAge: &maxAge}
+			disabledRule := GetRuleFor("/disabled", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			disabledRule.CorsPolicy = &gatewayv1beta1.CorsPolicy{MaxAge: &disabledMaxAge}
+			defaultRule := GetRuleFor("/default", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rules := []gatewayv1beta1.Rule{overrideRule, disabledRule, defaultRule}
+
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			config := GetTestConfig()
+			corsConfig := *TestCors
+			corsConfig.MaxAge = time.Hour
+			config.CorsConfig = &corsConfig
+			processor := istio.NewVirtualServiceProcessor(config)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(3))
+			Expect(vs.Spec.Http[0].CorsPolicy.MaxAge.AsDuration()).To(Equal(10 * time.Minute))
+			Expect(vs.Spec.Http[1].CorsPolicy.MaxAge).To(BeNil())
+			Expect(vs.Spec.Http[2].CorsPolicy.MaxAge.AsDuration()).To(Equal(time.Hour))
+		})
+
+		DescribeTable("should translate the wildcard of the rule CORS policy",
+			func(allowHeaders []string, allowMethods []string, expectedHeaders []string, expectedMethods []string) {
+				// given
+				strategies := []*gatewayv1beta1.Authenticator{
+					{
+						Handler: &gatewayv1beta1.Handler{
+							Name: "allow",
+						},
+					},
+				}
+
+				rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+				rule.CorsPolicy = &gatewayv1beta1.CorsPolicy{
+					AllowHeaders: allowHeaders,
+					AllowMethods: allowMethods,
+				}
+
+				apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+				client := GetFakeClient()
+				processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+				// then
+				Expect(err).To(BeNil())
+				Expect(result).To(HaveLen(1))
+
+				vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+				Expect(vs.Spec.Http).To(HaveLen(1))
+				Expect(vs.Spec.Http[0].CorsPolicy.AllowHeaders).To(Equal(expectedHeaders))
+				Expect(vs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal(expectedMethods))
+			},
+			Entry("without wildcard", []string{"x-custom"}, []string{"GET", "POST"}, []string{"x-custom"}, []string{"GET", "POST"}),
+			Entry("wildcard headers", []string{"x-custom", "*"}, []string{"GET"}, []string{"*"}, []string{"GET"}),
+			Entry("wildcard methods", []string{"x-custom"}, []string{"*", "GET"}, []string{"x-custom"}, []string{"*"}),
+		)
+
+		It("should set exact and regex origins of the rule CORS policy", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.CorsPolicy = &gatewayv1beta1.CorsPolicy{
+				AllowOrigins:      []string{"https://example.com"},
+				AllowOriginsRegex: []string{`https://.*\.example\.com`},
+			}
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins).To(HaveLen(2))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins[0].GetExact()).To(Equal("https://example.com"))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins[1].GetRegex()).To(Equal(`https://.*\.example\.com`))
+		})
+	})
+
+	When("rules define header matches", func() {
+		It("should route the same path to different services based on the headers", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			v2ServiceName, betaServiceName := "v2-service", "beta-service"
+			var port uint32 = 8080
+
+			v2Rule := GetRuleWithServiceFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies, &gatewayv1beta1.Service{Name: &v2ServiceName, Port: &port})
+			v2Rule.MatchHeaders = map[string]gatewayv1beta1.StringMatch{"X-Api-Version": {Exact: "v2"}}
+			betaRule := GetRuleWithServiceFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies, &gatewayv1beta1.Service{Name: &betaServiceName, Port: &port})
+			betaRule.MatchHeaders = map[string]gatewayv1beta1.StringMatch{"X-Api-Version": {Prefix: "beta"}}
+			defaultRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{v2Rule, betaRule, defaultRule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(3))
+
+			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(ApiPath))
+			Expect(vs.Spec.Http[0].Match[0].Headers).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Match[0].Headers["X-Api-Version"].GetExact()).To(Equal("v2"))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(v2ServiceName + "." + ApiNamespace + ".svc.cluster.local"))
+
+			Expect(vs.Spec.Http[1].Match[0].Uri.GetRegex()).To(Equal(ApiPath))
+			Expect(vs.Spec.Http[1].Match[0].Headers).To(HaveLen(1))
+			Expect(vs.Spec.Http[1].Match[0].Headers["X-Api-Version"].GetPrefix()).To(Equal("beta"))
+			Expect(vs.Spec.Http[1].Route[0].Destination.Host).To(Equal(betaServiceName + "." + ApiNamespace + ".svc.cluster.local"))
+
+			Expect(vs.Spec.Http[2].Match[0].Uri.GetRegex()).To(Equal(ApiPath))
+			Expect(vs.Spec.Http[2].Match[0].Headers).To(BeNil())
+			Expect(vs.Spec.Http[2].Route[0].Destination.Host).To(Equal(ServiceName + "." + ApiNamespace + ".svc.cluster.local"))
+		})
+	})
+
+	When("rule defines query parameter matches", func() {
+		It("should set the query parameter matches", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.MatchQueryParams = map[string]gatewayv1beta1.StringMatch{
+				"version": {Exact: "2"},
+				"channel": {Regex: "beta|preview"},
+			}
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Match[0].QueryParams).To(HaveLen(2))
+			Expect(vs.Spec.Http[0].Match[0].QueryParams["version"].GetExact()).To(Equal("2"))
+			Expect(vs.Spec.Http[0].Match[0].QueryParams["channel"].GetRegex()).To(Equal("beta|preview"))
+		})
+	})
+
+	When("the APIRule has no rules", func() {
+		It("should return an error and no virtual service", func() {
+			// given
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{})
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("no rules defined"))
+			Expect(result).To(BeEmpty())
+		})
+	})
+
+	When("rules are disabled", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		It("should not create routes for the disabled rules", func() {
+			// given
+			disabledRule := GetRuleFor("/disabled", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			disabledRule.Enabled = pointer.Bool(false)
+			enabledRule := GetRuleFor("/enabled", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			explicitlyEnabledRule := GetRuleFor("/explicitly-enabled", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			explicitlyEnabledRule.Enabled = pointer.Bool(true)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{disabledRule, enabledRule, explicitlyEnabledRule})
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+			Expect(vs.Spec.Http).To(HaveLen(2))
+			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal("/enabled"))
+			Expect(vs.Spec.Http[1].Match[0].Uri.GetRegex()).To(Equal("/explicitly-enabled"))
+		})
+
+		It("should return an error and no virtual service if all rules are disabled", func() {
+			// given
+			disabledRule := GetRuleFor("/disabled", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			disabledRule.Enabled = pointer.Bool(false)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{disabledRule})
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("no rules defined"))
+			Expect(result).To(BeEmpty())
+		})
+	})
+
+	When("the desired state is requested", func() {
+		It("should return the virtual service for the APIRule", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			vs, err := processor.GetDesiredState(apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(vs.Spec.Hosts).To(Equal([]string{ServiceHost}))
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(ApiPath))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(ServiceName + "." + ApiNamespace + ".svc.cluster.local"))
+		})
+	})
+
+	When("rule defines a mirror", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		It("should mirror the traffic to the service with the percentage", func() {
+			// given
+			mirrorName := "mirror-service"
+			var mirrorPort, mirrorPercentage uint32 = 8080, 20
+
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.Mirror = &gatewayv1beta1.Mirror{
+				Service:    gatewayv1beta1.Service{Name: &mirrorName, Port: &mirrorPort},
+				Percentage: &mirrorPercentage,
+			}
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Route).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Mirror.Host).To(Equal(mirrorName + "." + ApiNamespace + ".svc.cluster.local"))
+			Expect(vs.Spec.Http[0].Mirror.Port.Number).To(Equal(mirrorPort))
+			Expect(vs.Spec.Http[0].MirrorPercentage.Value).To(Equal(float64(20)))
+		})
+
+		It("should not mirror the traffic by default", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Mirror).To(BeNil())
+			Expect(vs.Spec.Http[0].MirrorPercentage).To(BeNil())
+		})
+	})
+
+	When("rule defines a rewrite", func() {
+		It("should rewrite the requests of the configured rule only", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			rewrittenRule := GetRuleFor("/public/foo", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rewrittenRule.Rewrite = &gatewayv1beta1.Rewrite{URI: "/foo", Authority: "backend.example.com"}
+			rule := GetRuleFor("/bar", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rewrittenRule, rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(2))
+			Expect(vs.Spec.Http[0].Rewrite.Uri).To(Equal("/foo"))
+			Expect(vs.Spec.Http[0].Rewrite.Authority).To(Equal("backend.example.com"))
+			Expect(vs.Spec.Http[1].Rewrite).To(BeNil())
+		})
+
+		It("should not rewrite the requests that are forwarded to oathkeeper", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "noop",
+					},
+				},
+			}
+
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.Rewrite = &gatewayv1beta1.Rewrite{URI: "/foo"}
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(OathkeeperSvc))
+			Expect(vs.Spec.Http[0].Rewrite).To(BeNil())
+		})
+	})
+
+	When("rule defines a redirect", func() {
+		It("should redirect the requests instead of routing them to a destination", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			rule := gatewayv1beta1.Rule{
+				Path:             "/old",
+				Methods:          ApiMethods,
+				AccessStrategies: strategies,
+				Redirect: &gatewayv1beta1.Redirect{
+					URI:       "/new",
+					Scheme:    "https",
+					Authority: "new.example.com",
+					Code:      302,
+				},
+			}
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			apiRule.Spec.Service = nil
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Route).To(BeEmpty())
+			Expect(vs.Spec.Http[0].Timeout).To(BeNil())
+			Expect(vs.Spec.Http[0].Redirect.Uri).To(Equal("/new"))
+			Expect(vs.Spec.Http[0].Redirect.Scheme).To(Equal("https"))
+			Expect(vs.Spec.Http[0].Redirect.Authority).To(Equal("new.example.com"))
+			Expect(vs.Spec.Http[0].Redirect.RedirectCode).To(Equal(uint32(302)))
+		})
+	})
+
+	When("rule defines a direct response", func() {
+		It("should return the direct response instead of routing the requests to a destination", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			rule := gatewayv1beta1.Rule{
+				Path:             "/healthz",
+				Methods:          []string{"GET"},
+				AccessStrategies: strategies,
+				DirectResponse: &gatewayv1beta1.DirectResponse{
+					Status: 200,
+					Body:   "ok",
+				},
+			}
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			apiRule.Spec.Service = nil
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Route).To(BeEmpty())
+			Expect(vs.Spec.Http[0].Timeout).To(BeNil())
+			Expect(vs.Spec.Http[0].DirectResponse.Status).To(Equal(uint32(200)))
+			Expect(vs.Spec.Http[0].DirectResponse.Body.GetString_()).To(Equal("ok"))
+		})
+	})
+
+	When("the APIRule is in maintenance", func() {
+		allow := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+		noop := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "noop",
+				},
+			},
+		}
+
+		It("should return the maintenance response for all routes", func() {
+			// given
+			redirectRule := GetRuleFor("/old", ApiMethods, []*gatewayv1beta1.Mutator{}, allow)
+			redirectRule.Redirect = &gatewayv1beta1.Redirect{URI: "/new"}
+			rules := []gatewayv1beta1.Rule{
+				GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, allow),
+				GetRuleFor(HeadersApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, noop),
+				redirectRule,
+			}
+
+			apiRule := GetAPIRuleFor(rules)
+			retryAfter := "120"
+			apiRule.Spec.Maintenance = &gatewayv1beta1.Maintenance{Enabled: true, RetryAfter: &retryAfter, Body: "Under maintenance"}
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(3))
+			for _, route := range vs.Spec.Http {
+				Expect(route.Route).To(BeEmpty())
+				Expect(route.Redirect).To(BeNil())
+				Expect(route.Timeout).To(BeNil())
+				Expect(route.Retries).To(BeNil())
+				Expect(route.DirectResponse.Status).To(Equal(processing.DefaultMaintenanceStatus))
+				Expect(route.DirectResponse.Body.GetString_()).To(Equal("Under maintenance"))
+				Expect(route.Headers.Response.Set).To(HaveKeyWithValue("retry-after", "120"))
+			}
+		})
+
+		It("should return the configured status without Retry-After header", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, allow)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			status := uint32(500)
+			apiRule.Spec.Maintenance = &gatewayv1beta1.Maintenance{Enabled: true, Status: &status}
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].DirectResponse.Status).To(Equal(uint32(500)))
+			Expect(vs.Spec.Http[0].Headers.Response).To(BeNil())
+		})
+
+		It("should route the requests to the service if the maintenance is disabled", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, allow)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			apiRule.Spec.Maintenance = &gatewayv1beta1.Maintenance{Enabled: false}
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].DirectResponse).To(BeNil())
+			Expect(vs.Spec.Http[0].Route).To(HaveLen(1))
+		})
+	})
+
+	When("rule defines weighted destinations", func() {
+		It("should split the traffic across the destinations", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			stableName, canaryName, canaryNamespace := "stable-service", "canary-service", "canary-namespace"
+			var stablePort, canaryPort uint32 = 8080, 9090
+
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.Destinations = []gatewayv1beta1.WeightedService{
+				{
+					Service: gatewayv1beta1.Service{Name: &stableName, Port: &stablePort},
+					Weight:  90,
+				},
+				{
+					Service: gatewayv1beta1.Service{Name: &canaryName, Namespace: &canaryNamespace, Port: &canaryPort},
+					Weight:  10,
+				},
+			}
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Route).To(HaveLen(2))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(stableName + "." + ApiNamespace + ".svc.cluster.local"))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Port.Number).To(Equal(stablePort))
+			Expect(vs.Spec.Http[0].Route[0].Weight).To(Equal(int32(90)))
+			Expect(vs.Spec.Http[0].Route[1].Destination.Host).To(Equal(canaryName + "." + canaryNamespace + ".svc.cluster.local"))
+			Expect(vs.Spec.Http[0].Route[1].Destination.Port.Number).To(Equal(canaryPort))
+			Expect(vs.Spec.Http[0].Route[1].Weight).To(Equal(int32(10)))
+		})
+	})
+
+	DescribeTable("default timeout",
+		func(defaultTimeout *time.Duration, expectedTimeout *time.Duration) {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			config := GetTestConfig()
+			config.HTTPTimeout = defaultTimeout
+			processor := istio.NewVirtualServiceProcessor(config)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			if expectedTimeout == nil {
+				Expect(vs.Spec.Http[0].Timeout).To(BeNil())
+			} else {
+				Expect(vs.Spec.Http[0].Timeout).NotTo(BeNil())
+				Expect(vs.Spec.Http[0].Timeout.AsDuration()).To(Equal(*expectedTimeout))
+			}
+		},
+		Entry("should not set a timeout if the default timeout is unset", nil, nil),
+		Entry("should set a timeout of 0s if the default timeout is zero", pointer.Duration(0), pointer.Duration(0)),
+		Entry("should set the default timeout if it is positive", pointer.Duration(3*time.Minute), pointer.Duration(3*time.Minute)),
+	)
+
+	When("rule defines a timeout", func() {
+		It("should use the rule timeout and the default timeout for rules without timeout", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			timeout := "2m30s"
+			slowRule := GetRuleFor("/slow", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			slowRule.Timeout = &timeout
+			defaultRule := GetRuleFor("/default", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rules := []gatewayv1beta1.Rule{slowRule, defaultRule}
+
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			config := GetTestConfig()
+			config.HTTPTimeout = pointer.Duration(10 * time.Second)
+			processor := istio.NewVirtualServiceProcessor(config)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(2))
+			Expect(vs.Spec.Http[0].Timeout.AsDuration()).To(Equal(150 * time.Second))
+			Expect(vs.Spec.Http[1].Timeout.AsDuration()).To(Equal(10 * time.Second))
+		})
+
+		It("should use the timeout of the port the rule is routed to", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			timeout := "30s"
+			portTimeouts := []gatewayv1beta1.PortTimeout{{Port: 8080, Timeout: "2m"}, {Port: 9090, Timeout: "5m"}}
+			serviceRule := GetRuleFor("/service", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			serviceRule.Timeout = &timeout
+			serviceRule.PortTimeouts = portTimeouts
+
+			grpcPort := uint32(9090)
+			grpcRule := GetRuleFor("/grpc", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			grpcRule.Timeout = &timeout
+			grpcRule.PortTimeouts = portTimeouts
+			grpcRule.Service = &gatewayv1beta1.Service{Name: &ServiceName, Port: &grpcPort}
+
+			otherPort := uint32(8081)
+			otherRule := GetRuleFor("/other", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			otherRule.Timeout = &timeout
+			otherRule.PortTimeouts = portTimeouts
+			otherRule.Service = &gatewayv1beta1.Service{Name: &ServiceName, Port: &otherPort}
+
+			rules := []gatewayv1beta1.Rule{serviceRule, grpcRule, otherRule}
+
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(3))
+			Expect(vs.Spec.Http[0].Timeout.AsDuration()).To(Equal(2 * time.Minute))
+			Expect(vs.Spec.Http[1].Timeout.AsDuration()).To(Equal(5 * time.Minute))
+			Expect(vs.Spec.Http[2].Timeout.AsDuration()).To(Equal(30 * time.Second))
+		})
+
+		It("should use the longest timeout of the ports of the destinations", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			firstPort := uint32(8081)
+			secondPort := uint32(8082)
+			firstName := "first-service"
+			secondName := "second-service"
+			rule := GetRuleFor("/weighted", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.Destinations = []gatewayv1beta1.WeightedService{
+				{Service: gatewayv1beta1.Service{Name: &firstName, Port: &firstPort}, Weight: 50},
+				{Service: gatewayv1beta1.Service{Name: &secondName, Port: &secondPort}, Weight: 50},
+			}
+			rule.PortTimeouts = []gatewayv1beta1.PortTimeout{{Port: 8081, Timeout: "1m"}, {Port: 8082, Timeout: "3m"}}
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Timeout.AsDuration()).To(Equal(3 * time.Minute))
+		})
+
+		It("should not change the rule timeout for a rule with an idle timeout", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			timeout := "10m"
+			idleTimeout := "1m"
+			pollRule := GetRuleFor("/poll", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			pollRule.Timeout = &timeout
+			pollRule.IdleTimeout = &idleTimeout
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{pollRule})
+			client := GetFakeClient()
+			config := GetTestConfig()
+			config.IdleTimeout = 30 * time.Second
+			processor := istio.NewVirtualServiceProcessor(config)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Timeout.AsDuration()).To(Equal(10 * time.Minute))
+		})
+	})
+
+	When("rule is used for WebSocket connections", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		It("should not set the default timeout", func() {
+			// given
+			webSocketRule := GetRuleFor("/ws", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			webSocketRule.WebSocket = true
+			defaultRule := GetRuleFor("/default", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{webSocketRule, defaultRule})
+			client := GetFakeClient()
+			config := GetTestConfig()
+			config.HTTPTimeout = pointer.Duration(10 * time.Second)
+			processor := istio.NewVirtualServiceProcessor(config)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(2))
+			Expect(vs.Spec.Http[0].Timeout).To(BeNil())
+			Expect(vs.Spec.Http[1].Timeout.AsDuration()).To(Equal(10 * time.Second))
+		})
+
+		It("should set the timeout defined on the rule", func() {
+			// given
+			timeout := "1h"
+			webSocketRule := GetRuleFor("/ws", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			webSocketRule.WebSocket = true
+			webSocketRule.Timeout = &timeout
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{webSocketRule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Timeout.AsDuration()).To(Equal(time.Hour))
+		})
+	})
+
+	When("retries are configured", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		It("should not set retries when neither default nor rule retries are configured", func() {
+			// given
+			rules := []gatewayv1beta1.Rule{GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)}
+
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+			Expect(vs.Spec.Http[0].Retries).To(BeNil())
+		})
+
+		It("should set the default retries and overwrite them with rule retries", func() {
+			// given
+			perTryTimeout := "500ms"
+			retryRule := GetRuleFor("/retry", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			retryRule.Retries = &gatewayv1beta1.Retries{
+				Attempts:      5,
+				PerTryTimeout: &perTryTimeout,
+				RetryOn:       "connect-failure",
+			}
+			defaultRule := GetRuleFor("/default", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rules := []gatewayv1beta1.Rule{retryRule, defaultRule}
+
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			config := GetTestConfig()
+			config.RetryConfig = &processing.RetryConfig{
+				Attempts:      2,
+				PerTryTimeout: time.Second,
+				RetryOn:       "5xx",
+			}
+			processor := istio.NewVirtualServiceProcessor(config)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+			Expect(vs.Spec.Http).To(HaveLen(2))
+
+			Expect(vs.Spec.Http[0].Retries.Attempts).To(Equal(int32(5)))
+			Expect(vs.Spec.Http[0].Retries.PerTryTimeout.AsDuration()).To(Equal(500 * time.Millisecond))
+			Expect(vs.Spec.Http[0].Retries.RetryOn).To(Equal("connect-failure"))
+
+			Expect(vs.Spec.Http[1].Retries.Attempts).To(Equal(int32(2)))
+			Expect(vs.Spec.Http[1].Retries.PerTryTimeout.AsDuration()).To(Equal(time.Second))
+			Expect(vs.Spec.Http[1].Retries.RetryOn).To(Equal("5xx"))
+		})
+	})
+
+	When("handler is noop", func() {
+		DescribeTable("should route to the oathkeeper target of the APIRule",
+			func(target *gatewayv1beta1.OathkeeperTarget, expectedHost string, expectedPort uint32) {
+				// given
+				strategies := []*gatewayv1beta1.Authenticator{
+					{
+						Handler: &gatewayv1beta1.Handler{
+							Name: "noop",
+						},
+					},
+				}
+
+				apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)})
+				apiRule.Spec.Oathkeeper = target
+				processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+				// then
+				Expect(err).To(BeNil())
+				Expect(result).To(HaveLen(1))
+
+				vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+				Expect(vs.Spec.Http[0].Route).To(HaveLen(1))
+				Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(expectedHost))
+				Expect(vs.Spec.Http[0].Route[0].Destination.Port.Number).To(Equal(expectedPort))
+			},
+			Entry("configured default target", nil, OathkeeperSvc, OathkeeperSvcPort),
+			Entry("overridden target", &gatewayv1beta1.OathkeeperTarget{Host: "oathkeeper-internal.kyma-system.svc.cluster.local", Port: 4455},
+				"oathkeeper-internal.kyma-system.svc.cluster.local", uint32(4455)),
+		)
+
+		It("should not override Oathkeeper service destination host with spec level service", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "noop",
+					},
+				},
+			}
+
+			overrideServiceName := "testName"
+			overrideServicePort := uint32(8080)
+
+			service := &gatewayv1beta1.Service{
+				Name: &overrideServiceName,
+				Port: &overrideServicePort,
+			}
+
+			allowRule := GetRuleWithServiceFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies, service)
+			rules := []gatewayv1beta1.Rule{allowRule}
+
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(len(vs.Spec.Http[0].Route)).To(Equal(1))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(OathkeeperSvc))
+		})
+
+		When("existing virtual service has owner v1alpha1 owner label", func() {
+			It("should get and update", func() {
+				// given
+				noop := []*gatewayv1beta1.Authenticator{
+					{
+						Handler: &gatewayv1beta1.Handler{
+							Name: "noop",
+						},
+					},
+				}
+
+				noopRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, noop)
+				rules := []gatewayv1beta1.Rule{noopRule}
+
+				apiRule := GetAPIRuleFor(rules)
+
+				rule := rulev1alpha1.Rule{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							processing.OwnerLabelv1alpha1: fmt.Sprintf("%s.%s", apiRule.ObjectMeta.Name, apiRule.ObjectMeta.Namespace),
+						},
+					},
+					Spec: rulev1alpha1.RuleSpec{
+						Match: &rulev1alpha1.Match{
+							URL: "some url",
+						},
+					},
+				}
+
+				vs := networkingv1beta1.VirtualService{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							processing.OwnerLabelv1alpha1: fmt.Sprintf("%s.%s", apiRule.ObjectMeta.Name, apiRule.ObjectMeta.Namespace),
+						},
+					},
+				}
+
+				scheme := runtime.NewScheme()
+				err := rulev1alpha1.AddToScheme(scheme)
+				Expect(err).NotTo(HaveOccurred())
+				err = networkingv1beta1.AddToScheme(scheme)
+				Expect(err).NotTo(HaveOccurred())
+				err = gatewayv1beta1.AddToScheme(scheme)
+				Expect(err).NotTo(HaveOccurred())
+
+				client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&rule, &vs).Build()
+				processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+				// then
+				Expect(err).To(BeNil())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Action.String()).To(Equal("update"))
+
+				resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+				Expect(resultVs).NotTo(BeNil())
+				Expect(resultVs).NotTo(BeNil())
+				Expect(len(resultVs.Spec.Gateways)).To(Equal(1))
+				Expect(len(resultVs.Spec.Hosts)).To(Equal(1))
+				Expect(resultVs.Spec.Hosts[0]).To(Equal(ServiceHost))
+				Expect(len(resultVs.Spec.Http)).To(Equal(1))
+
+				Expect(len(resultVs.Spec.Http[0].Route)).To(Equal(1))
+				Expect(resultVs.Spec.Http[0].Route[0].Destination.Host).To(Equal(OathkeeperSvc))
+				Expect(resultVs.Spec.Http[0].Route[0].Destination.Port.Number).To(Equal(OathkeeperSvcPort))
+
+				Expect(len(resultVs.Spec.Http[0].Match)).To(Equal(1))
+				Expect(resultVs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[0].Path))
+
+				Expect(resultVs.Spec.Http[0].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
+				Expect(resultVs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
+				Expect(resultVs.Spec.Http[0].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+			})
+		})
+	})
+
+	When("multiple handler", func() {
+		It("should return service for given paths", func() {
+			// given
+			noop := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "noop",
+					},
+				},
+			}
+
+			jwtConfigJSON := fmt.Sprintf(`
+						{
+							"trusted_issuers": ["%s"],
+							"jwks": [],
+							"required_scope": [%s]
+					}`, JwtIssuer, ToCSVList(ApiScopes))
+
+			jwt := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "jwt",
+						Config: &runtime.RawExtension{
+							Raw: []byte(jwtConfigJSON),
+						},
+					},
+				},
+			}
+
+			testMutators := []*gatewayv1beta1.Mutator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "noop",
+					},
+				},
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "idtoken",
+					},
+				},
+			}
+
+			noopRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, noop)
+			jwtRule := GetRuleFor(HeadersApiPath, ApiMethods, testMutators, jwt)
+			rules := []gatewayv1beta1.Rule{noopRule, jwtRule}
+
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs).NotTo(BeNil())
+			Expect(len(vs.Spec.Gateways)).To(Equal(1))
+			Expect(len(vs.Spec.Hosts)).To(Equal(1))
+			Expect(vs.Spec.Hosts[0]).To(Equal(ServiceHost))
+			Expect(len(vs.Spec.Http)).To(Equal(2))
+
+			Expect(len(vs.Spec.Http[0].Route)).To(Equal(1))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(ServiceName + "." + ApiNamespace + ".svc.cluster.local"))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Port.Number).To(Equal(ServicePort))
+			Expect(len(vs.Spec.Http[0].Match)).To(Equal(1))
+			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[1].Path))
+
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+
+			Expect(len(vs.Spec.Http[1].Route)).To(Equal(1))
+			Expect(vs.Spec.Http[1].Route[0].Destination.Host).To(Equal(OathkeeperSvc))
+			Expect(vs.Spec.Http[1].Route[0].Destination.Port.Number).To(Equal(OathkeeperSvcPort))
+			Expect(len(vs.Spec.Http[1].Match)).To(Equal(1))
+			Expect(vs.Spec.Http[1].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[0].Path))
+
+			Expect(vs.Spec.Http[1].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
+			Expect(vs.Spec.Http[1].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
+			Expect(vs.Spec.Http[1].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+
+			Expect(vs.ObjectMeta.Name).To(BeEmpty())
+			Expect(vs.ObjectMeta.GenerateName).To(Equal(ApiName + "-"))
+			Expect(vs.ObjectMeta.Namespace).To(Equal(ApiNamespace))
+			Expect(vs.ObjectMeta.Labels[TestLabelKey]).To(Equal(TestLabelValue))
+		})
+
+		It("should return service for two same paths and different methods", func() {
+			// given
+			noop := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "noop",
+					},
+				},
+			}
+
+			jwtConfigJSON := fmt.Sprintf(`
+						{
+							"trusted_issuers": ["%s"],
+							"jwks": [],
+							"required_scope": [%s]
+					}`, JwtIssuer, ToCSVList(ApiScopes))
+
+			jwt := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "jwt",
+						Config: &runtime.RawExtension{
+							Raw: []byte(jwtConfigJSON),
+						},
+					},
+				},
+			}
+
+			testMutators := []*gatewayv1beta1.Mutator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "noop",
+					},
+				},
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "idtoken",
+					},
+				},
+			}
+			getMethod := []string{"GET"}
+			postMethod := []string{"POST"}
+			noopRule := GetRuleFor(ApiPath, getMethod, []*gatewayv1beta1.Mutator{}, noop)
+			jwtRule := GetRuleFor(ApiPath, postMethod, testMutators, jwt)
+			rules := []gatewayv1beta1.Rule{noopRule, jwtRule}
+
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs).NotTo(BeNil())
+			Expect(len(vs.Spec.Gateways)).To(Equal(1))
+			Expect(len(vs.Spec.Hosts)).To(Equal(1))
+			Expect(vs.Spec.Hosts[0]).To(Equal(ServiceHost))
+			Expect(len(vs.Spec.Http)).To(Equal(1))
+
+			Expect(len(vs.Spec.Http[0].Route)).To(Equal(1))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(OathkeeperSvc))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Port.Number).To(Equal(OathkeeperSvcPort))
+			Expect(len(vs.Spec.Http[0].Match)).To(Equal(1))
+			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[0].Path))
+
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+
+			Expect(vs.ObjectMeta.Name).To(BeEmpty())
+			Expect(vs.ObjectMeta.GenerateName).To(Equal(ApiName + "-"))
+			Expect(vs.ObjectMeta.Namespace).To(Equal(ApiNamespace))
+			Expect(vs.ObjectMeta.Labels[TestLabelKey]).To(Equal(TestLabelValue))
+		})
+
+		It("should return service for two same paths and one different", func() {
+			// given
+			noop := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "noop",
+					},
+				},
+			}
+
+			jwtConfigJSON := fmt.Sprintf(`
+						{
+							"trusted_issuers": ["%s"],
+							"jwks": [],
+							"required_scope": [%s]
+					}`, JwtIssuer, ToCSVList(ApiScopes))
+
+			jwt := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "jwt",
+						Config: &runtime.RawExtension{
+							Raw: []byte(jwtConfigJSON),
+						},
+					},
+				},
+			}
+
+			testMutators := []*gatewayv1beta1.Mutator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "noop",
+					},
+				},
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "idtoken",
+					},
+				},
+			}
+			getMethod := []string{"GET"}
+			postMethod := []string{"POST"}
+			noopGetRule := GetRuleFor(ApiPath, getMethod, []*gatewayv1beta1.Mutator{}, noop)
+			noopPostRule := GetRuleFor(ApiPath, postMethod, []*gatewayv1beta1.Mutator{}, noop)
+			jwtRule := GetRuleFor(HeadersApiPath, ApiMethods, testMutators, jwt)
+			rules := []gatewayv1beta1.Rule{noopGetRule, noopPostRule, jwtRule}
+
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs).NotTo(BeNil())
+			Expect(len(vs.Spec.Gateways)).To(Equal(1))
+			Expect(len(vs.Spec.Hosts)).To(Equal(1))
+			Expect(vs.Spec.Hosts[0]).To(Equal(ServiceHost))
+			Expect(len(vs.Spec.Http)).To(Equal(2))
+
+			Expect(len(vs.Spec.Http[0].Route)).To(Equal(1))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(ServiceName + "." + ApiNamespace + ".svc.cluster.local"))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Port.Number).To(Equal(ServicePort))
+			Expect(len(vs.Spec.Http[0].Match)).To(Equal(1))
+			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[2].Path))
+
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+
+			Expect(len(vs.Spec.Http[1].Route)).To(Equal(1))
+			Expect(vs.Spec.Http[1].Route[0].Destination.Host).To(Equal(OathkeeperSvc))
+			Expect(vs.Spec.Http[1].Route[0].Destination.Port.Number).To(Equal(OathkeeperSvcPort))
+			Expect(len(vs.Spec.Http[1].Match)).To(Equal(1))
+			Expect(vs.Spec.Http[1].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[0].Path))
+
+			Expect(vs.Spec.Http[1].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
+			Expect(vs.Spec.Http[1].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
+			Expect(vs.Spec.Http[1].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+
+			Expect(vs.ObjectMeta.Name).To(BeEmpty())
+			Expect(vs.ObjectMeta.GenerateName).To(Equal(ApiName + "-"))
+			Expect(vs.ObjectMeta.Namespace).To(Equal(ApiNamespace))
+			Expect(vs.ObjectMeta.Labels[TestLabelKey]).To(Equal(TestLabelValue))
+		})
+
+		It("should return service for jwt & oauth authenticators for given path", func() {
+			// given
+			oauthConfigJSON := fmt.Sprintf(`{"required_scope": [%s]}`, ToCSVList(ApiScopes))
+
+			jwtConfigJSON := fmt.Sprintf(`
+						{
+							"trusted_issuers": ["%s"],
+							"jwks": [],
+							"required_scope": [%s]
+					}`, JwtIssuer, ToCSVList(ApiScopes))
+
+			jwt := &gatewayv1beta1.Authenticator{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "jwt",
+					Config: &runtime.RawExtension{
+						Raw: []byte(jwtConfigJSON),
+					},
+				},
+			}
+			oauth := &gatewayv1beta1.Authenticator{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "oauth2_introspection",
+					Config: &runtime.RawExtension{
+						Raw: []byte(oauthConfigJSON),
+					},
+				},
+			}
+
+			strategies := []*gatewayv1beta1.Authenticator{jwt, oauth}
+
+			allowRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rules := []gatewayv1beta1.Rule{allowRule}
+
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs).NotTo(BeNil())
+			Expect(len(vs.Spec.Gateways)).To(Equal(1))
+			Expect(len(vs.Spec.Hosts)).To(Equal(1))
+			Expect(vs.Spec.Hosts[0]).To(Equal(ServiceHost))
+			Expect(len(vs.Spec.Http)).To(Equal(1))
+
+			Expect(len(vs.Spec.Http[0].Route)).To(Equal(1))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(ServiceName + "." + ApiNamespace + ".svc.cluster.local"))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Port.Number).To(Equal(ServicePort))
+
+			Expect(len(vs.Spec.Http[0].Match)).To(Equal(1))
+			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[0].Path))
+
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
+			Expect(vs.Spec.Http[0].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+
+			Expect(vs.ObjectMeta.Name).To(BeEmpty())
+			Expect(vs.ObjectMeta.GenerateName).To(Equal(ApiName + "-"))
+			Expect(vs.ObjectMeta.Namespace).To(Equal(ApiNamespace))
+			Expect(vs.ObjectMeta.Labels[TestLabelKey]).To(Equal(TestLabelValue))
+		})
+	})
+
+	When("the path is `/*`", func() {
+		It("should set the match to prefix `/`", func() {
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			allowRule := GetRuleFor("/*", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rules := []gatewayv1beta1.Rule{allowRule}
+
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+
+			resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(resultVs.Spec.Http).To(HaveLen(1))
+			Expect(resultVs.Spec.Http[0].Match).To(HaveLen(1))
+			Expect(resultVs.Spec.Http[0].Match[0].Uri.GetPrefix()).To(Equal("/"))
+		})
+
+		It("should set the match to the configured catch-all regex", func() {
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			allowRule := GetRuleFor("/*", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			otherRule := GetRuleFor("/img", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rules := []gatewayv1beta1.Rule{allowRule, otherRule}
+
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			config := GetTestConfig()
+			config.CatchAllPathRegex = "/.*"
+			processor := istio.NewVirtualServiceProcessor(config)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+
+			resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(resultVs.Spec.Http).To(HaveLen(2))
+			Expect(resultVs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal("/img"))
+			Expect(resultVs.Spec.Http[1].Match[0].Uri.GetRegex()).To(Equal("/.*"))
+			Expect(resultVs.Spec.Http[1].Match[0].Uri.GetPrefix()).To(BeEmpty())
+		})
+
+		It("should emit the catch-all route after the more specific routes", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			rules := []gatewayv1beta1.Rule{
+				GetRuleFor("/*", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies),
+				GetRuleFor("/img", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies),
+				GetRuleFor("/headers", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies),
+			}
+
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+
+			resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(resultVs.Spec.Http).To(HaveLen(3))
+			Expect(resultVs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal("/img"))
+			Expect(resultVs.Spec.Http[1].Match[0].Uri.GetRegex()).To(Equal("/headers"))
+			Expect(resultVs.Spec.Http[2].Match[0].Uri.GetPrefix()).To(Equal("/"))
+		})
+
+		It("should emit the default backend route last with a prefix match", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			defaultRule := GetRuleFor("/.*", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			defaultRule.DefaultBackend = true
+			rules := []gatewayv1beta1.Rule{
+				defaultRule,
+				GetRuleFor("/img", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies),
+				GetRuleFor("/*", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies),
+			}
+
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+
+			resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(resultVs.Spec.Http).To(HaveLen(3))
+			Expect(resultVs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal("/img"))
+			Expect(resultVs.Spec.Http[1].Match[0].Uri.GetPrefix()).To(Equal("/"))
+			Expect(resultVs.Spec.Http[2].Match[0].Uri.GetPrefix()).To(Equal("/"))
+			Expect(resultVs.Spec.Http[2].Match[0].Uri.GetRegex()).To(BeEmpty())
+		})
+	})
+	When("the path match type is defined", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		DescribeTable("should set the match for the path match type",
+			func(path string, matchType gatewayv1beta1.PathMatchType, expectedMatch *v1beta1.StringMatch) {
+				// given
+				rule := GetRuleFor(path, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+				rule.PathMatchType = matchType
+
+				apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+				client := GetFakeClient()
+				processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+				// then
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+
+				resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+				Expect(resultVs.Spec.Http).To(HaveLen(1))
+				Expect(resultVs.Spec.Http[0].Match).To(HaveLen(1))
+				Expect(resultVs.Spec.Http[0].Match[0].Uri).To(Equal(expectedMatch))
+			},
+			Entry("regex when not defined", "/api/v1", gatewayv1beta1.PathMatchType(""), &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Regex{Regex: "/api/v1"}}),
+			Entry("regex", "/api/.*", gatewayv1beta1.PathMatchRegex, &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Regex{Regex: "/api/.*"}}),
+			Entry("exact", "/api/v1", gatewayv1beta1.PathMatchExact, &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Exact{Exact: "/api/v1"}}),
+			Entry("exact with regex special characters", "/api/v1.0/(id)?", gatewayv1beta1.PathMatchExact, &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Exact{Exact: "/api/v1.0/(id)?"}}),
+			Entry("prefix", "/api", gatewayv1beta1.PathMatchPrefix, &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Prefix{Prefix: "/api"}}),
+			Entry("prefix with regex special characters", "/api/v1.*", gatewayv1beta1.PathMatchPrefix, &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Prefix{Prefix: "/api/v1.*"}}),
+		)
+
+		DescribeTable("should ignore the case of the path match",
+			func(path string, matchType gatewayv1beta1.PathMatchType, ignorePathCase bool) {
+				// given
+				rule := GetRuleFor(path, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+				rule.PathMatchType = matchType
+				rule.IgnorePathCase = ignorePathCase
+
+				apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+				client := GetFakeClient()
+				processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+				// then
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+
+				resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+				Expect(resultVs.Spec.Http).To(HaveLen(1))
+				Expect(resultVs.Spec.Http[0].Match).To(HaveLen(1))
+				Expect(resultVs.Spec.Http[0].Match[0].IgnoreUriCase).To(Equal(ignorePathCase))
+			},
+			Entry("prefix ignoring the case", "/api", gatewayv1beta1.PathMatchPrefix, true),
+			Entry("regex ignoring the case", "/api/.*", gatewayv1beta1.PathMatchRegex, true),
+			Entry("prefix case-sensitive by default", "/api", gatewayv1beta1.PathMatchPrefix, false),
+			Entry("regex case-sensitive by default", "/api/.*", gatewayv1beta1.PathMatchRegex, false),
+		)
+
+		DescribeTable("should strip the matched prefix",
+			func(path string, rewrite *gatewayv1beta1.Rewrite, expectedMatches []*v1beta1.StringMatch, expectedRewrite *v1beta1.HTTPRewrite) {
+				// given
+				rule := GetRuleFor(path, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+				rule.PathMatchType = gatewayv1beta1.PathMatchPrefix
+				rule.StripPrefix = true
+				rule.Rewrite = rewrite
+
+				apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+				client := GetFakeClient()
+				processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+				// then
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+
+				resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+				Expect(resultVs.Spec.Http).To(HaveLen(1))
+				Expect(resultVs.Spec.Http[0].Match).To(HaveLen(len(expectedMatches)))
+				for i, expectedMatch := range expectedMatches {
+					Expect(resultVs.Spec.Http[0].Match[i].Uri).To(Equal(expectedMatch))
+					Expect(resultVs.Spec.Http[0].Match[i].Method).To(Equal(resultVs.Spec.Http[0].Match[0].Method))
+				}
+				Expect(resultVs.Spec.Http[0].Rewrite).To(Equal(expectedRewrite))
+			},
+			Entry("prefix", "/service-a", nil,
+				[]*v1beta1.StringMatch{
+					{MatchType: &v1beta1.StringMatch_Prefix{Prefix: "/service-a/"}},
+					{MatchType: &v1beta1.StringMatch_Exact{Exact: "/service-a"}},
+				},
+				&v1beta1.HTTPRewrite{Uri: "/"}),
+			Entry("prefix with a trailing slash", "/service-a/", nil,
+				[]*v1beta1.StringMatch{
+					{MatchType: &v1beta1.StringMatch_Prefix{Prefix: "/service-a/"}},
+					{MatchType: &v1beta1.StringMatch_Exact{Exact: "/service-a"}},
+				},
+				&v1beta1.HTTPRewrite{Uri: "/"}),
+			Entry("root prefix", "/", nil,
+				[]*v1beta1.StringMatch{
+					{MatchType: &v1beta1.StringMatch_Prefix{Prefix: "/"}},
+				},
+				&v1beta1.HTTPRewrite{Uri: "/"}),
+			Entry("prefix with a rewrite of the authority", "/service-a", &gatewayv1beta1.Rewrite{Authority: "example.com"},
+				[]*v1beta1.StringMatch{
+					{MatchType: &v1beta1.StringMatch_Prefix{Prefix: "/service-a/"}},
+					{MatchType: &v1beta1.StringMatch_Exact{Exact: "/service-a"}},
+				},
+				&v1beta1.HTTPRewrite{Uri: "/", Authority: "example.com"}),
+		)
+	})
+
+	When("CORS credentials, expose headers and max age are configured", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		It("should set them in the CORS policy", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			config := GetTestConfig()
+			config.CorsConfig = &processing.CorsConfig{
+				AllowOrigins:     TestCors.AllowOrigins,
+				AllowMethods:     TestCors.AllowMethods,
+				AllowHeaders:     TestCors.AllowHeaders,
+				ExposeHeaders:    []string{"X-Request-Id"},
+				AllowCredentials: true,
+				MaxAge:           time.Hour,
+			}
+			processor := istio.NewVirtualServiceProcessor(config)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+
+			resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(resultVs.Spec.Http).To(HaveLen(1))
+			Expect(resultVs.Spec.Http[0].CorsPolicy.ExposeHeaders).To(Equal([]string{"X-Request-Id"}))
+			Expect(resultVs.Spec.Http[0].CorsPolicy.AllowCredentials.GetValue()).To(BeTrue())
+			Expect(resultVs.Spec.Http[0].CorsPolicy.MaxAge.AsDuration()).To(Equal(time.Hour))
+		})
+
+		It("should not set them by default", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+
+			resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(resultVs.Spec.Http).To(HaveLen(1))
+			Expect(resultVs.Spec.Http[0].CorsPolicy.ExposeHeaders).To(BeNil())
+			Expect(resultVs.Spec.Http[0].CorsPolicy.AllowCredentials).To(BeNil())
+			Expect(resultVs.Spec.Http[0].CorsPolicy.MaxAge).To(BeNil())
+		})
+	})
+
+	When("CORS is disabled for the APIRule", func() {
+		It("should not set the CORS policy", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.CorsPolicy = &gatewayv1beta1.CorsPolicy{AllowOrigins: []string{"https://example.com"}}
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			disableCors := true
+			apiRule.Spec.DisableCors = &disableCors
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+
+			resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(resultVs.Spec.Http).To(HaveLen(1))
+			Expect(resultVs.Spec.Http[0].CorsPolicy).To(BeNil())
+		})
+	})
+
+	When("the rule has route type grpc", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		It("should match the gRPC service path by prefix and keep timeout and CORS", func() {
+			// given
+			rule := GetRuleFor("/my.package.Service/", []string{"POST"}, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.RouteType = gatewayv1beta1.RouteTypeGRPC
+			timeout := "30s"
+			rule.Timeout = &timeout
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+
+			resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(resultVs.Spec.Http).To(HaveLen(1))
+			Expect(resultVs.Spec.Http[0].Match).To(HaveLen(1))
+			Expect(resultVs.Spec.Http[0].Match[0].Uri.GetPrefix()).To(Equal("/my.package.Service/"))
+			Expect(resultVs.Spec.Http[0].Match[0].Method).To(BeNil())
+			Expect(resultVs.Spec.Http[0].Timeout.AsDuration()).To(Equal(time.Second * 30))
+			Expect(resultVs.Spec.Http[0].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
+			Expect(resultVs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
+			Expect(resultVs.Spec.Http[0].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+		})
+
+		It("should use the defined path match type", func() {
+			// given
+			rule := GetRuleFor("/my.package.Service/Method", []string{"POST"}, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.RouteType = gatewayv1beta1.RouteTypeGRPC
+			rule.PathMatchType = gatewayv1beta1.PathMatchExact
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+
+			resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(resultVs.Spec.Http).To(HaveLen(1))
+			Expect(resultVs.Spec.Http[0].Match[0].Uri.GetExact()).To(Equal("/my.package.Service/Method"))
+		})
+	})
+
+	When("the rule has methods defined", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		DescribeTable("should set the method match",
+			func(methods []string, expectedMatch *v1beta1.StringMatch) {
+				// given
+				rule := GetRuleFor(ApiPath, methods, []*gatewayv1beta1.Mutator{}, strategies)
+
+				apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+				// The OPTIONS method of the CORS preflight requests is added to the method match if CORS is enabled.
+				disableCors := true
+				apiRule.Spec.DisableCors = &disableCors
+				client := GetFakeClient()
+				processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+				// then
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+
+				resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+				Expect(resultVs.Spec.Http).To(HaveLen(1))
+				Expect(resultVs.Spec.Http[0].Match).To(HaveLen(1))
+				Expect(resultVs.Spec.Http[0].Match[0].Method).To(Equal(expectedMatch))
+			},
+			Entry("exact for a single method", []string{"GET"}, &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Exact{Exact: "GET"}}),
+			Entry("regex for multiple methods", []string{"GET", "POST"}, &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Regex{Regex: "GET|POST"}}),
+			Entry("no match for empty methods", []string{}, nil),
+		)
+
+		It("should match the OPTIONS method of the CORS preflight requests if CORS is enabled", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+			rule := GetRuleFor(ApiPath, []string{"GET"}, []*gatewayv1beta1.Mutator{}, strategies)
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].CorsPolicy).NotTo(BeNil())
+			Expect(vs.Spec.Http[0].Match[0].Method.GetRegex()).To(Equal("GET|OPTIONS"))
+		})
+
+		It("should not set the method match when the access strategy is handled by oathkeeper", func() {
+			// given
+			oauthStrategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "oauth2_introspection",
+					},
+				},
+			}
+			rule := GetRuleFor(ApiPath, []string{"GET"}, []*gatewayv1beta1.Mutator{}, oauthStrategies)
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+
+			resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(resultVs.Spec.Http).To(HaveLen(1))
+			Expect(resultVs.Spec.Http[0].Match[0].Method).To(BeNil())
+		})
+
+		It("should not set the method match when the path is defined in multiple rules", func() {
+			// given
+			getRule := GetRuleFor(ApiPath, []string{"GET"}, []*gatewayv1beta1.Mutator{}, strategies)
+			postRule := GetRuleFor(ApiPath, []string{"POST"}, []*gatewayv1beta1.Mutator{}, strategies)
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{getRule, postRule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+
+			resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(resultVs.Spec.Http).To(HaveLen(1))
+			Expect(resultVs.Spec.Http[0].Match[0].Method).To(BeNil())
+		})
+	})
+
+	When("rules define invalid fields", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		getFieldErrors := func(err error) map[string]string {
+			var fieldErrs processing.FieldErrors
+			Expect(errors.As(err, &fieldErrs)).To(BeTrue())
+
+			fields := make(map[string]string)
+			for _, e := range fieldErrs {
+				var fieldErr *processing.FieldError
+				Expect(errors.As(e, &fieldErr)).To(BeTrue())
+				fields[fieldErr.Field] = e.Error()
+			}
+			return fields
+		}
+
+		It("should return an error with the field paths of all invalid fields", func() {
+			// given
+			invalidTimeout := "10 seconds"
+			invalidPerTryTimeout := "abc"
+			validRule := GetRuleFor("/valid", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			timeoutRule := GetRuleFor("/timeout", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			timeoutRule.Timeout = &invalidTimeout
+			retriesRule := GetRuleFor("/retries", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			retriesRule.Retries = &gatewayv1beta1.Retries{PerTryTimeout: &invalidPerTryTimeout}
+			faultRule := GetRuleFor("/fault", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			faultRule.Fault = &gatewayv1beta1.Fault{Delay: &gatewayv1beta1.FaultDelay{FixedDelay: "soon"}}
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{validRule, timeoutRule, retriesRule, faultRule})
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(result).To(BeEmpty())
+			fields := getFieldErrors(err)
+			Expect(fields).To(HaveLen(3))
+			Expect(fields).To(HaveKeyWithValue(".spec.rules[1].timeout", ContainSubstring("rule with path /timeout: invalid timeout")))
+			Expect(fields).To(HaveKeyWithValue(".spec.rules[2].retries", ContainSubstring("rule with path /retries: invalid retries")))
+			Expect(fields).To(HaveKeyWithValue(".spec.rules[3].fault.delay.fixedDelay", ContainSubstring("rule with path /fault: invalid fault delay")))
+		})
+
+		It("should return an error with the field path of the rule service", func() {
+			// given
+			serviceName := "other-service"
+			validRule := GetRuleFor("/valid", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			validRule.Service = &gatewayv1beta1.Service{Name: &serviceName, Port: &ServicePort}
+			missingServiceRule := GetRuleFor("/missing", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{validRule, missingServiceRule})
+			apiRule.Spec.Service = nil
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			_, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(getFieldErrors(err)).To(Equal(map[string]string{
+				".spec.rules[1].service": "rule with path /missing: no service defined for the rule and no service defined on spec level",
+			}))
+		})
+
+		It("should return an error with the field path of the malformed mutators of a JWT rule", func() {
+			// given
+			jwtConfigJSON := fmt.Sprintf(`{"authentications": [{"issuer": "%s", "jwksUri": "%s"}]}`, JwtIssuer, JwksUri)
+			jwtStrategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "jwt",
+						Config: &runtime.RawExtension{
+							Raw: []byte(jwtConfigJSON),
+						},
+					},
+				},
+			}
+			mutators := []*gatewayv1beta1.Mutator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: gatewayv1beta1.HeaderMutator,
+						Config: &runtime.RawExtension{
+							Raw: []byte(`{"headers": ["not-a-map"]}`),
+						},
+					},
+				},
+			}
+			validRule := GetRuleFor("/valid", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			disabled := false
+			disabledRule := GetRuleFor("/jwt", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			disabledRule.Enabled = &disabled
+			jwtRule := GetRuleFor("/jwt", ApiMethods, mutators, jwtStrategies)
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{validRule, disabledRule, jwtRule})
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			_, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			fields := getFieldErrors(err)
+			Expect(fields).To(HaveLen(1))
+			Expect(fields).To(HaveKeyWithValue(".spec.rules[2].mutators", ContainSubstring("rule with path /jwt: invalid header mutator")))
+		})
+
+		It("should return an error with the field path of the rules if no rule is enabled", func() {
+			// given
+			disabled := false
+			disabledRule := GetRuleFor("/disabled", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			disabledRule.Enabled = &disabled
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{disabledRule})
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			_, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			var fieldErr *processing.FieldError
+			Expect(errors.As(err, &fieldErr)).To(BeTrue())
+			Expect(fieldErr.Field).To(Equal(".spec.rules"))
+		})
+	})
+
+	When("the cookie mutator of a JWT rule is malformed", func() {
+		It("should return an error with the path of the rule", func() {
+			// given
+			jwtConfigJSON := fmt.Sprintf(`{"trusted_issuers": ["%s"],"jwks": [],}`, JwtIssuer)
+
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "jwt",
+						Config: &runtime.RawExtension{
+							Raw: []byte(jwtConfigJSON),
+						},
+					},
+				},
+			}
+
+			mutators := []*gatewayv1beta1.Mutator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: gatewayv1beta1.CookieMutator,
+						Config: &runtime.RawExtension{
+							Raw: []byte(`{"cookies": "not-a-map"}`),
+						},
+					},
+				},
+			}
+
+			rule := GetRuleFor(ApiPath, ApiMethods, mutators, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			_, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid cookie mutator"))
+
+			var ruleErr *processing.RuleError
+			Expect(errors.As(err, &ruleErr)).To(BeTrue())
+			Expect(ruleErr.Path).To(Equal(ApiPath))
+		})
+	})
+
+	When("the access log is disabled for a rule", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		It("should annotate the VirtualService with the path of the rule", func() {
+			// given
+			healthRule := GetRuleFor("/healthz", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			healthRule.DisableAccessLog = true
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{healthRule, rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Annotations).To(HaveKeyWithValue(processing.AccessLogDisabledPathsAnnotation, `["/healthz"]`))
+		})
+
+		It("should not annotate the VirtualService by default", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Annotations).ToNot(HaveKey(processing.AccessLogDisabledPathsAnnotation))
+		})
+	})
+
+	When("the request headers size is limited for a rule", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		It("should annotate the VirtualService with the path and the limit of the rule", func() {
+			// given
+			maxRequestHeadersBytes := uint32(8192)
+			uploadRule := GetRuleFor("/upload", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			uploadRule.MaxRequestHeadersBytes = &maxRequestHeadersBytes
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{uploadRule, rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Annotations).To(HaveKeyWithValue(processing.MaxRequestHeadersBytesAnnotation, `{"/upload":8192}`))
+		})
+
+		It("should not annotate the VirtualService by default", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Annotations).ToNot(HaveKey(processing.MaxRequestHeadersBytesAnnotation))
+		})
+	})
+
+	When("the VirtualService fixed name is configured", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		It("should create the VirtualService with the fixed name", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			config := GetTestConfig()
+			config.VirtualServiceFixedName = true
+			processor := istio.NewVirtualServiceProcessor(config)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Action.String()).To(Equal("create"))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.ObjectMeta.Name).To(Equal(ApiName + "-vs"))
+			Expect(vs.ObjectMeta.GenerateName).To(BeEmpty())
+		})
+
+		It("should not change the existing VirtualService with the fixed name on the next reconciliation", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			config := GetTestConfig()
+			config.VirtualServiceFixedName = true
+			processor := istio.NewVirtualServiceProcessor(config)
+
+			desiredVs, err := processor.GetDesiredState(apiRule)
+			Expect(err).To(BeNil())
+			client := GetFakeClient(desiredVs)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(BeEmpty())
+		})
+
+		It("should create the VirtualService with a generated name by default", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.ObjectMeta.Name).To(BeEmpty())
+			Expect(vs.ObjectMeta.GenerateName).To(Equal(ApiName + "-"))
+		})
+
+		It("should add the configured prefix and suffix to the generated name", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			config := GetTestConfig()
+			config.VirtualServiceNamePrefix = "dev-"
+			config.VirtualServiceNameSuffix = "-eu"
+			processor := istio.NewVirtualServiceProcessor(config)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.ObjectMeta.Name).To(BeEmpty())
+			Expect(vs.ObjectMeta.GenerateName).To(Equal("dev-" + ApiName + "-eu-"))
+		})
+
+		It("should not add the configured prefix and suffix to the fixed name", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			config := GetTestConfig()
+			config.VirtualServiceFixedName = true
+			config.VirtualServiceNamePrefix = "dev-"
+			config.VirtualServiceNameSuffix = "-eu"
+			processor := istio.NewVirtualServiceProcessor(config)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
 
-			jwt := []*gatewayv1beta1.Authenticator{
-				{
-					Handler: &gatewayv1beta1.Handler{
-						Name: "jwt",
-						Config: &runtime.RawExtension{
-							Raw: []byte(jwtConfigJSON),
-						},
-					},
-				},
-			}
+			Expect(vs.ObjectMeta.Name).To(Equal(ApiName + "-vs"))
+			Expect(vs.ObjectMeta.GenerateName).To(BeEmpty())
+		})
+	})
 
-			testMutators := []*gatewayv1beta1.Mutator{
-				{
-					Handler: &gatewayv1beta1.Handler{
-						Name: "noop",
-					},
-				},
-				{
-					Handler: &gatewayv1beta1.Handler{
-						Name: "idtoken",
-					},
+	When("rule defines a fault", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
 				},
-			}
+			},
+		}
 
-			noopRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, noop)
-			jwtRule := GetRuleFor(HeadersApiPath, ApiMethods, testMutators, jwt)
-			rules := []gatewayv1beta1.Rule{noopRule, jwtRule}
-
-			apiRule := GetAPIRuleFor(rules)
+		It("should inject the delay and abort faults", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.Fault = &gatewayv1beta1.Fault{
+				Delay: &gatewayv1beta1.FaultDelay{FixedDelay: "5s", Percentage: 10},
+				Abort: &gatewayv1beta1.FaultAbort{HTTPStatus: 503, Percentage: 20},
+			}
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
 			client := GetFakeClient()
 			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
 
@@ -362,85 +3145,122 @@ var _ = Describe("Virtual Service Processor", func() {
 
 			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
 
-			Expect(vs).NotTo(BeNil())
-			Expect(len(vs.Spec.Gateways)).To(Equal(1))
-			Expect(len(vs.Spec.Hosts)).To(Equal(1))
-			Expect(vs.Spec.Hosts[0]).To(Equal(ServiceHost))
-			Expect(len(vs.Spec.Http)).To(Equal(2))
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Fault.Delay.GetFixedDelay().AsDuration()).To(Equal(5 * time.Second))
+			Expect(vs.Spec.Http[0].Fault.Delay.Percentage.Value).To(Equal(float64(10)))
+			Expect(vs.Spec.Http[0].Fault.Abort.GetHttpStatus()).To(Equal(int32(503)))
+			Expect(vs.Spec.Http[0].Fault.Abort.Percentage.Value).To(Equal(float64(20)))
+		})
 
-			Expect(len(vs.Spec.Http[0].Route)).To(Equal(1))
-			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(OathkeeperSvc))
-			Expect(vs.Spec.Http[0].Route[0].Destination.Port.Number).To(Equal(OathkeeperSvcPort))
-			Expect(len(vs.Spec.Http[0].Match)).To(Equal(1))
-			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[0].Path))
+		It("should not inject faults by default", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
 
-			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
-			Expect(vs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
-			Expect(vs.Spec.Http[0].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
 
-			Expect(len(vs.Spec.Http[1].Route)).To(Equal(1))
-			Expect(vs.Spec.Http[1].Route[0].Destination.Host).To(Equal(ServiceName + "." + ApiNamespace + ".svc.cluster.local"))
-			Expect(vs.Spec.Http[1].Route[0].Destination.Port.Number).To(Equal(ServicePort))
-			Expect(len(vs.Spec.Http[1].Match)).To(Equal(1))
-			Expect(vs.Spec.Http[1].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[1].Path))
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
 
-			Expect(vs.Spec.Http[1].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
-			Expect(vs.Spec.Http[1].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
-			Expect(vs.Spec.Http[1].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
 
-			Expect(vs.ObjectMeta.Name).To(BeEmpty())
-			Expect(vs.ObjectMeta.GenerateName).To(Equal(ApiName + "-"))
-			Expect(vs.ObjectMeta.Namespace).To(Equal(ApiNamespace))
-			Expect(vs.ObjectMeta.Labels[TestLabelKey]).To(Equal(TestLabelValue))
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Fault).To(BeNil())
 		})
+	})
 
-		It("should return service for two same paths and different methods", func() {
+	It("should set a deterministic and unique name on each route", func() {
+		// given
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{
+			GetRuleFor("/.*", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies),
+			GetRuleFor("/api/v1", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies),
+			GetRuleFor("/api.v1", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies),
+		})
+		processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+		// when
+		first, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+		Expect(err).To(BeNil())
+		second, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+		Expect(err).To(BeNil())
+
+		// then
+		firstVs := first[0].Obj.(*networkingv1beta1.VirtualService)
+		secondVs := second[0].Obj.(*networkingv1beta1.VirtualService)
+
+		var names []string
+		for i, route := range firstVs.Spec.Http {
+			names = append(names, route.Name)
+			Expect(secondVs.Spec.Http[i].Name).To(Equal(route.Name))
+		}
+		Expect(names).To(Equal([]string{processing.GetOwnerLabelValue(apiRule) + ".0-api-v1", processing.GetOwnerLabelValue(apiRule) + ".1-api-v1", processing.GetOwnerLabelValue(apiRule) + ".2"}))
+	})
+
+	When("rule defines a subset", func() {
+		It("should route to the subset of the service", func() {
 			// given
-			noop := []*gatewayv1beta1.Authenticator{
+			serviceName := "versioned-service"
+			var port uint32 = 8080
+			rule := GetRuleWithServiceFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, []*gatewayv1beta1.Authenticator{
 				{
 					Handler: &gatewayv1beta1.Handler{
-						Name: "noop",
+						Name: "allow",
 					},
 				},
-			}
+			}, &gatewayv1beta1.Service{
+				Name:    &serviceName,
+				Port:    &port,
+				Subsets: []gatewayv1beta1.Subset{{Name: "v2", Labels: map[string]string{"version": "v2"}}},
+			})
+			rule.Subset = "v2"
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
 
-			jwtConfigJSON := fmt.Sprintf(`
-						{
-							"trusted_issuers": ["%s"],
-							"jwks": [],
-							"required_scope": [%s]
-					}`, JwtIssuer, ToCSVList(ApiScopes))
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
 
-			jwt := []*gatewayv1beta1.Authenticator{
-				{
-					Handler: &gatewayv1beta1.Handler{
-						Name: "jwt",
-						Config: &runtime.RawExtension{
-							Raw: []byte(jwtConfigJSON),
-						},
-					},
-				},
-			}
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
 
-			testMutators := []*gatewayv1beta1.Mutator{
-				{
-					Handler: &gatewayv1beta1.Handler{
-						Name: "noop",
-					},
-				},
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Route).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(serviceName + "." + ApiNamespace + ".svc.cluster.local"))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Subset).To(Equal("v2"))
+		})
+	})
+
+	When("rule defines protocol ports", func() {
+		It("should route the gRPC requests to the gRPC port and all other requests to the HTTP port", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
 				{
 					Handler: &gatewayv1beta1.Handler{
-						Name: "idtoken",
+						Name: "allow",
 					},
 				},
 			}
-			getMethod := []string{"GET"}
-			postMethod := []string{"POST"}
-			noopRule := GetRuleFor(ApiPath, getMethod, []*gatewayv1beta1.Mutator{}, noop)
-			jwtRule := GetRuleFor(ApiPath, postMethod, testMutators, jwt)
-			rules := []gatewayv1beta1.Rule{noopRule, jwtRule}
 
-			apiRule := GetAPIRuleFor(rules)
+			rule := GetRuleFor(ApiPath, []string{"GET", "POST"}, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.ProtocolPorts = []gatewayv1beta1.ProtocolPort{
+				{Protocol: gatewayv1beta1.RouteTypeHTTP, Port: 80},
+				{Protocol: gatewayv1beta1.RouteTypeGRPC, Port: 9090},
+			}
+			apiRule := processing.ExpandProtocolPorts(GetAPIRuleFor([]gatewayv1beta1.Rule{rule}))
 			client := GetFakeClient()
 			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
 
@@ -453,46 +3273,76 @@ var _ = Describe("Virtual Service Processor", func() {
 
 			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
 
-			Expect(vs).NotTo(BeNil())
-			Expect(len(vs.Spec.Gateways)).To(Equal(1))
-			Expect(len(vs.Spec.Hosts)).To(Equal(1))
-			Expect(vs.Spec.Hosts[0]).To(Equal(ServiceHost))
-			Expect(len(vs.Spec.Http)).To(Equal(1))
-
-			Expect(len(vs.Spec.Http[0].Route)).To(Equal(1))
-			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(OathkeeperSvc))
-			Expect(vs.Spec.Http[0].Route[0].Destination.Port.Number).To(Equal(OathkeeperSvcPort))
-			Expect(len(vs.Spec.Http[0].Match)).To(Equal(1))
-			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[0].Path))
+			Expect(vs.Spec.Http).To(HaveLen(2))
 
-			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
-			Expect(vs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
-			Expect(vs.Spec.Http[0].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+			Expect(vs.Spec.Http[0].Match).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(ApiPath))
+			Expect(vs.Spec.Http[0].Match[0].Headers).To(HaveKey("content-type"))
+			Expect(vs.Spec.Http[0].Match[0].Headers["content-type"].GetPrefix()).To(Equal("application/grpc"))
+			Expect(vs.Spec.Http[0].Match[0].Method).To(BeNil())
+			Expect(vs.Spec.Http[0].Route).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(ServiceName + "." + ApiNamespace + ".svc.cluster.local"))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Port.Number).To(Equal(uint32(9090)))
 
-			Expect(vs.ObjectMeta.Name).To(BeEmpty())
-			Expect(vs.ObjectMeta.GenerateName).To(Equal(ApiName + "-"))
-			Expect(vs.ObjectMeta.Namespace).To(Equal(ApiNamespace))
-			Expect(vs.ObjectMeta.Labels[TestLabelKey]).To(Equal(TestLabelValue))
+			Expect(vs.Spec.Http[1].Match).To(HaveLen(1))
+			Expect(vs.Spec.Http[1].Match[0].Uri.GetRegex()).To(Equal(ApiPath))
+			Expect(vs.Spec.Http[1].Match[0].Headers).To(BeEmpty())
+			Expect(vs.Spec.Http[1].Match[0].Method.GetRegex()).To(Equal("GET|POST|OPTIONS"))
+			Expect(vs.Spec.Http[1].Route).To(HaveLen(1))
+			Expect(vs.Spec.Http[1].Route[0].Destination.Host).To(Equal(ServiceName + "." + ApiNamespace + ".svc.cluster.local"))
+			Expect(vs.Spec.Http[1].Route[0].Destination.Port.Number).To(Equal(uint32(80)))
 		})
+	})
 
-		It("should return service for two same paths and one different", func() {
+	When("rule defines header operations", func() {
+		headers := &gatewayv1beta1.Headers{
+			Request: &gatewayv1beta1.HeaderOperations{
+				Add:    map[string]string{"x-custom-header": "value"},
+				Remove: []string{"x-internal-header"},
+			},
+			Response: &gatewayv1beta1.HeaderOperations{
+				Add:    map[string]string{"x-frame-options": "DENY"},
+				Remove: []string{"server"},
+			},
+		}
+
+		It("should add the header operations to the VS for allow rules", func() {
 			// given
-			noop := []*gatewayv1beta1.Authenticator{
+			strategies := []*gatewayv1beta1.Authenticator{
 				{
 					Handler: &gatewayv1beta1.Handler{
-						Name: "noop",
+						Name: "allow",
 					},
 				},
 			}
 
-			jwtConfigJSON := fmt.Sprintf(`
-						{
-							"trusted_issuers": ["%s"],
-							"jwks": [],
-							"required_scope": [%s]
-					}`, JwtIssuer, ToCSVList(ApiScopes))
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.Headers = headers
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
 
-			jwt := []*gatewayv1beta1.Authenticator{
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Headers.Request.Set).To(HaveKey("x-forwarded-host"))
+			Expect(vs.Spec.Http[0].Headers.Request.Add).To(Equal(map[string]string{"x-custom-header": "value"}))
+			Expect(vs.Spec.Http[0].Headers.Request.Remove).To(Equal([]string{"x-internal-header"}))
+			Expect(vs.Spec.Http[0].Headers.Response.Add).To(Equal(map[string]string{"x-frame-options": "DENY"}))
+			Expect(vs.Spec.Http[0].Headers.Response.Remove).To(Equal([]string{"server"}))
+		})
+
+		It("should add the header operations together with the mutator headers to the VS for JWT rules", func() {
+			// given
+			jwtConfigJSON := fmt.Sprintf(`{"trusted_issuers": ["%s"],"jwks": [],}`, JwtIssuer)
+			strategies := []*gatewayv1beta1.Authenticator{
 				{
 					Handler: &gatewayv1beta1.Handler{
 						Name: "jwt",
@@ -502,27 +3352,20 @@ var _ = Describe("Virtual Service Processor", func() {
 					},
 				},
 			}
-
-			testMutators := []*gatewayv1beta1.Mutator{
-				{
-					Handler: &gatewayv1beta1.Handler{
-						Name: "noop",
-					},
-				},
+			mutators := []*gatewayv1beta1.Mutator{
 				{
 					Handler: &gatewayv1beta1.Handler{
-						Name: "idtoken",
+						Name: "header",
+						Config: &runtime.RawExtension{
+							Raw: []byte(`{"headers": {"x-mutator-header": "mutator-value"}}`),
+						},
 					},
 				},
 			}
-			getMethod := []string{"GET"}
-			postMethod := []string{"POST"}
-			noopGetRule := GetRuleFor(ApiPath, getMethod, []*gatewayv1beta1.Mutator{}, noop)
-			noopPostRule := GetRuleFor(ApiPath, postMethod, []*gatewayv1beta1.Mutator{}, noop)
-			jwtRule := GetRuleFor(HeadersApiPath, ApiMethods, testMutators, jwt)
-			rules := []gatewayv1beta1.Rule{noopGetRule, noopPostRule, jwtRule}
 
-			apiRule := GetAPIRuleFor(rules)
+			rule := GetRuleFor(ApiPath, ApiMethods, mutators, strategies)
+			rule.Headers = headers
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
 			client := GetFakeClient()
 			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
 
@@ -535,72 +3378,83 @@ var _ = Describe("Virtual Service Processor", func() {
 
 			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
 
-			Expect(vs).NotTo(BeNil())
-			Expect(len(vs.Spec.Gateways)).To(Equal(1))
-			Expect(len(vs.Spec.Hosts)).To(Equal(1))
-			Expect(vs.Spec.Hosts[0]).To(Equal(ServiceHost))
-			Expect(len(vs.Spec.Http)).To(Equal(2))
-
-			Expect(len(vs.Spec.Http[0].Route)).To(Equal(1))
-			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(OathkeeperSvc))
-			Expect(vs.Spec.Http[0].Route[0].Destination.Port.Number).To(Equal(OathkeeperSvcPort))
-			Expect(len(vs.Spec.Http[0].Match)).To(Equal(1))
-			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[0].Path))
-
-			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
-			Expect(vs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
-			Expect(vs.Spec.Http[0].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
-
-			Expect(len(vs.Spec.Http[1].Route)).To(Equal(1))
-			Expect(vs.Spec.Http[1].Route[0].Destination.Host).To(Equal(ServiceName + "." + ApiNamespace + ".svc.cluster.local"))
-			Expect(vs.Spec.Http[1].Route[0].Destination.Port.Number).To(Equal(ServicePort))
-			Expect(len(vs.Spec.Http[1].Match)).To(Equal(1))
-			Expect(vs.Spec.Http[1].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[2].Path))
-
-			Expect(vs.Spec.Http[1].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
-			Expect(vs.Spec.Http[1].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
-			Expect(vs.Spec.Http[1].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
-
-			Expect(vs.ObjectMeta.Name).To(BeEmpty())
-			Expect(vs.ObjectMeta.GenerateName).To(Equal(ApiName + "-"))
-			Expect(vs.ObjectMeta.Namespace).To(Equal(ApiNamespace))
-			Expect(vs.ObjectMeta.Labels[TestLabelKey]).To(Equal(TestLabelValue))
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Headers.Request.Set).To(HaveKeyWithValue("x-mutator-header", "mutator-value"))
+			Expect(vs.Spec.Http[0].Headers.Request.Add).To(Equal(map[string]string{"x-custom-header": "value"}))
+			Expect(vs.Spec.Http[0].Headers.Request.Remove).To(Equal([]string{"x-internal-header"}))
+			Expect(vs.Spec.Http[0].Headers.Response.Add).To(Equal(map[string]string{"x-frame-options": "DENY"}))
+			Expect(vs.Spec.Http[0].Headers.Response.Remove).To(Equal([]string{"server"}))
 		})
 
-		It("should return service for jwt & oauth authenticators for given path", func() {
+		It("should not apply the header operations and mutators to the preserved headers", func() {
 			// given
-			oauthConfigJSON := fmt.Sprintf(`{"required_scope": [%s]}`, ToCSVList(ApiScopes))
-
-			jwtConfigJSON := fmt.Sprintf(`
-						{
-							"trusted_issuers": ["%s"],
-							"jwks": [],
-							"required_scope": [%s]
-					}`, JwtIssuer, ToCSVList(ApiScopes))
-
-			jwt := &gatewayv1beta1.Authenticator{
-				Handler: &gatewayv1beta1.Handler{
-					Name: "jwt",
-					Config: &runtime.RawExtension{
-						Raw: []byte(jwtConfigJSON),
+			jwtConfigJSON := fmt.Sprintf(`{"trusted_issuers": ["%s"],"jwks": [],}`, JwtIssuer)
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "jwt",
+						Config: &runtime.RawExtension{
+							Raw: []byte(jwtConfigJSON),
+						},
+					},
+				},
+			}
+			mutators := []*gatewayv1beta1.Mutator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "header",
+						Config: &runtime.RawExtension{
+							Raw: []byte(`{"headers": {"x-mutator-header": "mutator-value", "b3": "mutator-value"}}`),
+						},
 					},
 				},
 			}
-			oauth := &gatewayv1beta1.Authenticator{
-				Handler: &gatewayv1beta1.Handler{
-					Name: "oauth2_introspection",
-					Config: &runtime.RawExtension{
-						Raw: []byte(oauthConfigJSON),
-					},
+
+			rule := GetRuleFor(ApiPath, ApiMethods, mutators, strategies)
+			rule.Headers = &gatewayv1beta1.Headers{
+				Request: &gatewayv1beta1.HeaderOperations{
+					Add:    map[string]string{"x-custom-header": "value"},
+					Remove: []string{"x-internal-header", "Traceparent"},
 				},
 			}
+			rule.PreserveHeaders = []string{"traceparent", "b3"}
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
 
-			strategies := []*gatewayv1beta1.Authenticator{jwt, oauth}
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
 
-			allowRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
-			rules := []gatewayv1beta1.Rule{allowRule}
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
 
-			apiRule := GetAPIRuleFor(rules)
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Headers.Request.Set).To(HaveKeyWithValue("x-mutator-header", "mutator-value"))
+			Expect(vs.Spec.Http[0].Headers.Request.Set).ToNot(HaveKey("b3"))
+			Expect(vs.Spec.Http[0].Headers.Request.Add).To(Equal(map[string]string{"x-custom-header": "value"}))
+			Expect(vs.Spec.Http[0].Headers.Request.Remove).To(Equal([]string{"x-internal-header"}))
+		})
+	})
+
+	When("rule is deprecated", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		It("should add the deprecation and sunset response headers", func() {
+			// given
+			since := "2024-01-01T00:00:00Z"
+			sunset := "2024-06-30T12:00:00+02:00"
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.Deprecation = &gatewayv1beta1.Deprecation{Since: &since, Sunset: &sunset}
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
 			client := GetFakeClient()
 			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
 
@@ -613,60 +3467,60 @@ var _ = Describe("Virtual Service Processor", func() {
 
 			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
 
-			Expect(vs).NotTo(BeNil())
-			Expect(len(vs.Spec.Gateways)).To(Equal(1))
-			Expect(len(vs.Spec.Hosts)).To(Equal(1))
-			Expect(vs.Spec.Hosts[0]).To(Equal(ServiceHost))
-			Expect(len(vs.Spec.Http)).To(Equal(1))
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Headers.Response.Set).To(Equal(map[string]string{
+				"deprecation": "@1704067200",
+				"sunset":      "Sun, 30 Jun 2024 10:00:00 GMT",
+			}))
+		})
 
-			Expect(len(vs.Spec.Http[0].Route)).To(Equal(1))
-			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(ServiceName + "." + ApiNamespace + ".svc.cluster.local"))
-			Expect(vs.Spec.Http[0].Route[0].Destination.Port.Number).To(Equal(ServicePort))
+		It("should set the deprecation header to true if no dates are defined", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.Deprecation = &gatewayv1beta1.Deprecation{}
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
 
-			Expect(len(vs.Spec.Http[0].Match)).To(Equal(1))
-			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[0].Path))
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
 
-			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
-			Expect(vs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
-			Expect(vs.Spec.Http[0].CorsPolicy.AllowHeaders).To(Equal(TestCors.AllowHeaders))
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
 
-			Expect(vs.ObjectMeta.Name).To(BeEmpty())
-			Expect(vs.ObjectMeta.GenerateName).To(Equal(ApiName + "-"))
-			Expect(vs.ObjectMeta.Namespace).To(Equal(ApiNamespace))
-			Expect(vs.ObjectMeta.Labels[TestLabelKey]).To(Equal(TestLabelValue))
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Headers.Response.Set).To(Equal(map[string]string{"deprecation": "true"}))
 		})
-	})
 
-	When("the path is `/*`", func() {
-		It("should set the match to prefix `/`", func() {
-			strategies := []*gatewayv1beta1.Authenticator{
-				{
-					Handler: &gatewayv1beta1.Handler{
-						Name: "allow",
-					},
+		It("should let the response header operations of the rule overwrite the deprecation headers", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.Deprecation = &gatewayv1beta1.Deprecation{}
+			rule.Headers = &gatewayv1beta1.Headers{
+				Response: &gatewayv1beta1.HeaderOperations{
+					Set: map[string]string{"deprecation": "@1704067200"},
 				},
 			}
-
-			allowRule := GetRuleFor("/*", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
-			rules := []gatewayv1beta1.Rule{allowRule}
-
-			apiRule := GetAPIRuleFor(rules)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
 			client := GetFakeClient()
 			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
 
 			// when
 			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
 
-			Expect(err).NotTo(HaveOccurred())
+			// then
+			Expect(err).To(BeNil())
 			Expect(result).To(HaveLen(1))
 
-			resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
 
-			Expect(resultVs.Spec.Http).To(HaveLen(1))
-			Expect(resultVs.Spec.Http[0].Match).To(HaveLen(1))
-			Expect(resultVs.Spec.Http[0].Match[0].Uri.GetPrefix()).To(Equal("/"))
+			Expect(vs.Spec.Http[0].Headers.Response.Set).To(Equal(map[string]string{"deprecation": "@1704067200"}))
 		})
 	})
+
 	Context("mutators are defined", func() {
 		When("access strategy is JWT", func() {
 			It("should return VS cookie and header configuration set", func() {
@@ -789,7 +3643,7 @@ var _ = Describe("Virtual Service Processor", func() {
 				//verify VS
 				Expect(vs).NotTo(BeNil())
 				Expect(vs.Spec.Http).To(HaveLen(1))
-				Expect(vs.Spec.Http[0].Headers.Request.Set).To(HaveKeyWithValue("x-forwarded-host", "myService.myDomain.com"))
+				Expect(vs.Spec.Http[0].Headers.Request.Set).To(HaveKeyWithValue("x-forwarded-host", "myservice.mydomain.com"))
 				Expect(vs.Spec.Http[0].Headers.Request.Set).To(HaveKeyWithValue("x-test-header-1", "header-value1"))
 			})
 
@@ -942,4 +3796,343 @@ var _ = Describe("Virtual Service Processor", func() {
 			Expect(vs.Spec.Http[0].Headers.Request.Set).ToNot(HaveKeyWithValue("x-test-header-1", "header-value1"))
 		})
 	})
+
+	When("neither the rule nor the APIRule defines a service", func() {
+		It("should return an error instead of the virtual service", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)})
+			apiRule.Spec.Service = nil
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			_, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(err).To(MatchError(fmt.Sprintf("rule with path %s: no service defined for the rule and no service defined on spec level", ApiPath)))
+		})
+	})
+
+	When("the virtual service is created", func() {
+		It("should set the owner labels that are used to look up the actual virtual service", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)})
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			created, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(created).To(HaveLen(1))
+			Expect(created[0].Action.String()).To(Equal("create"))
+
+			vs := created[0].Obj.(*networkingv1beta1.VirtualService)
+			for key, value := range processing.GetOwnerLabels(apiRule) {
+				Expect(vs.Labels).To(HaveKeyWithValue(key, value))
+			}
+			Expect(vs.Labels).To(HaveKeyWithValue(processing.OwnerLabel, processing.GetOwnerLabelValue(apiRule)))
+
+			// when
+			vs.Name = "created-vs"
+			unchanged, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(vs), apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(unchanged).To(BeEmpty())
+		})
+	})
+
+	When("virtual services are aggregated by host", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		getNamedAPIRule := func(name string, paths ...string) *gatewayv1beta1.APIRule {
+			var rules []gatewayv1beta1.Rule
+			for _, path := range paths {
+				rules = append(rules, GetRuleFor(path, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies))
+			}
+			apiRule := GetAPIRuleFor(rules)
+			apiRule.Name = name
+			return apiRule
+		}
+
+		getAggregatingConfig := func() processing.ReconciliationConfig {
+			config := GetTestConfig()
+			config.AggregateByHost = true
+			return config
+		}
+
+		It("should merge the routes of two APIRules with the same host into one VS", func() {
+			// given
+			first := getNamedAPIRule("first", "/img", "/*")
+			second := getNamedAPIRule("second", "/headers")
+			client := GetFakeClient(second)
+			processor := istio.NewVirtualServiceProcessor(getAggregatingConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, first)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Action.String()).To(Equal("create"))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+			Expect(vs.Name).To(Equal(processing.GetAggregatedVirtualServiceName(first)))
+			Expect(vs.GenerateName).To(BeEmpty())
+			Expect(vs.Spec.Hosts).To(Equal([]string{ServiceHost}))
+			Expect(vs.Spec.Gateways).To(Equal([]string{ApiGateway}))
+			Expect(vs.Labels).ToNot(HaveKey(processing.OwnerLabel))
+			Expect(vs.Labels).ToNot(HaveKey(processing.OwnerLabelv1alpha1))
+			Expect(vs.Labels).To(HaveKeyWithValue(helpers.AggregatedLabel, "true"))
+			Expect(vs.Annotations).To(HaveKeyWithValue(helpers.OwnersAnnotation,
+				`{"first.some-namespace":["first.some-namespace.0-img","first.some-namespace.1"],"second.some-namespace":["second.some-namespace.0-headers"]}`))
+
+			// The catch-all route of the first APIRule must not shadow the routes of the second APIRule.
+			Expect(vs.Spec.Http).To(HaveLen(3))
+			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal("/img"))
+			Expect(vs.Spec.Http[1].Match[0].Uri.GetRegex()).To(Equal("/headers"))
+			Expect(vs.Spec.Http[2].Match[0].Uri.GetPrefix()).To(Equal("/"))
+		})
+
+		It("should produce the same VS when the other APIRule is reconciled", func() {
+			// given
+			first := getNamedAPIRule("first", "/img")
+			second := getNamedAPIRule("second", "/headers")
+			processor := istio.NewVirtualServiceProcessor(getAggregatingConfig())
+
+			// when
+			firstResult, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(second), first)
+			Expect(err).To(BeNil())
+			secondResult, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(first), second)
+			Expect(err).To(BeNil())
+
+			// then
+			Expect(firstResult).To(HaveLen(1))
+			Expect(secondResult).To(HaveLen(1))
+			firstVs := firstResult[0].Obj.(*networkingv1beta1.VirtualService)
+			secondVs := secondResult[0].Obj.(*networkingv1beta1.VirtualService)
+			Expect(secondVs.Name).To(Equal(firstVs.Name))
+			Expect(secondVs.Annotations).To(Equal(firstVs.Annotations))
+			Expect(helpers.GetOwners(secondVs.Annotations)).To(HaveLen(2))
+			Expect(secondVs.Spec.Http).To(HaveLen(2))
+			for i := range firstVs.Spec.Http {
+				Expect(secondVs.Spec.Http[i].Name).To(Equal(firstVs.Spec.Http[i].Name))
+			}
+		})
+
+		It("should not merge the routes of APIRules with a different host", func() {
+			// given
+			first := getNamedAPIRule("first", "/img")
+			other := getNamedAPIRule("other", "/headers")
+			otherHost := "other." + DefaultDomain
+			other.Spec.Host = &otherHost
+			processor := istio.NewVirtualServiceProcessor(getAggregatingConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(other), first)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Name).To(Equal("first.some-namespace.0-img"))
+			Expect(helpers.GetOwners(vs.Annotations)).To(HaveKey("first.some-namespace"))
+			Expect(helpers.GetOwners(vs.Annotations)).ToNot(HaveKey("other.some-namespace"))
+		})
+
+		It("should update the routes of the APIRule in the existing VS and replace the VS owned by the APIRule alone", func() {
+			// given
+			first := getNamedAPIRule("first", "/img")
+			second := getNamedAPIRule("second", "/headers")
+			processor := istio.NewVirtualServiceProcessor(getAggregatingConfig())
+			created, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(second), first)
+			Expect(err).To(BeNil())
+			aggregated := created[0].Obj.(*networkingv1beta1.VirtualService)
+
+			ownedVs := networkingv1beta1.VirtualService{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "first-vs",
+					Namespace: ApiNamespace,
+					Labels:    map[string]string{processing.OwnerLabelv1alpha1: processing.GetOwnerLabelValue(first)},
+				},
+			}
+			client := GetFakeClient(second, aggregated, &ownedVs)
+			first.Spec.Rules = append(first.Spec.Rules, GetRuleFor("/status", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies))
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, first)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(2))
+			Expect(result[0].Action.String()).To(Equal("update"))
+			Expect(result[1].Action.String()).To(Equal("delete"))
+			Expect(result[1].Obj.GetName()).To(Equal("first-vs"))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+			Expect(vs.Name).To(Equal(aggregated.Name))
+			Expect(vs.Spec.Http).To(HaveLen(3))
+			Expect(helpers.GetOwners(vs.Annotations)["first.some-namespace"]).To(Equal([]string{"first.some-namespace.0-img", "first.some-namespace.1-status"}))
+			Expect(helpers.GetOwners(vs.Annotations)["second.some-namespace"]).To(Equal([]string{"second.some-namespace.0-headers"}))
+		})
+
+		It("should keep the actual routes of another APIRule whose VS can't be created", func() {
+			// given
+			first := getNamedAPIRule("first", "/img")
+			second := getNamedAPIRule("second", "/headers")
+			processor := istio.NewVirtualServiceProcessor(getAggregatingConfig())
+			created, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(second), first)
+			Expect(err).To(BeNil())
+			aggregated := created[0].Obj.(*networkingv1beta1.VirtualService)
+
+			second.Spec.Rules = nil
+			client := GetFakeClient(second, aggregated)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, first)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(BeEmpty())
+		})
+
+		It("should remove the routes of a paused APIRule from the aggregated VS", func() {
+			// given
+			first := getNamedAPIRule("first", "/img")
+			second := getNamedAPIRule("second", "/headers")
+			processor := istio.NewVirtualServiceProcessor(getAggregatingConfig())
+			created, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(second), first)
+			Expect(err).To(BeNil())
+			aggregated := created[0].Obj.(*networkingv1beta1.VirtualService)
+
+			first.Annotations = map[string]string{processing.PausedAnnotation: "true"}
+			client := GetFakeClient(first, aggregated)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, first)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Action.String()).To(Equal("update"))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Name).To(Equal("second.some-namespace.0-headers"))
+			Expect(helpers.GetOwners(vs.Annotations)).ToNot(HaveKey("first.some-namespace"))
+		})
+
+		It("should not merge the routes of a paused APIRule with the same host", func() {
+			// given
+			first := getNamedAPIRule("first", "/img")
+			second := getNamedAPIRule("second", "/headers")
+			second.Annotations = map[string]string{processing.PausedAnnotation: "true"}
+			processor := istio.NewVirtualServiceProcessor(getAggregatingConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(second), first)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Name).To(Equal("first.some-namespace.0-img"))
+			Expect(helpers.GetOwners(vs.Annotations)).ToNot(HaveKey("second.some-namespace"))
+		})
+	})
+
+	When("the APIRule is paused", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		getOwnedVirtualService := func(apiRule *gatewayv1beta1.APIRule) *networkingv1beta1.VirtualService {
+			return &networkingv1beta1.VirtualService{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "owned-vs",
+					Namespace: ApiNamespace,
+					Labels:    map[string]string{processing.OwnerLabelv1alpha1: processing.GetOwnerLabelValue(apiRule)},
+				},
+			}
+		}
+
+		It("should delete the existing VS", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			apiRule.Annotations = map[string]string{processing.PausedAnnotation: "true"}
+			client := GetFakeClient(getOwnedVirtualService(apiRule))
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Action.String()).To(Equal("delete"))
+			Expect(result[0].Obj.GetName()).To(Equal("owned-vs"))
+		})
+
+		It("should not create a VS if none exists", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			apiRule.Annotations = map[string]string{processing.PausedAnnotation: "true"}
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(BeEmpty())
+		})
+
+		It("should create the VS again when the APIRule is unpaused", func() {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			apiRule.Annotations = map[string]string{processing.PausedAnnotation: "false"}
+			processor := istio.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Action.String()).To(Equal("create"))
+		})
+	})
 })
@@ -0,0 +1,44 @@
+package processing
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// ManagedLabelsAnnotation is set on the generated resources and lists the keys of the labels set by the controller, so
+// labels that are no longer configured can be removed without touching the labels added by others. The value is a JSON
+// array of the label keys, e.g. ["apirule.gateway.kyma-project.io/v1beta1"].
+const ManagedLabelsAnnotation = "gateway.kyma-project.io/managed-labels"
+
+// GetManagedLabelsAnnotation returns the value of the ManagedLabelsAnnotation for the given labels. An empty value means
+// that no label is managed and the annotation should not be set.
+func GetManagedLabelsAnnotation(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	value, _ := json.Marshal(keys)
+	return string(value)
+}
+
+// GetManagedLabels returns the keys of the labels that are listed in the ManagedLabelsAnnotation. Resources that were
+// created before the annotation was introduced don't have it, so no label is known to be managed for them.
+func GetManagedLabels(annotations map[string]string) []string {
+	value, ok := annotations[ManagedLabelsAnnotation]
+	if !ok {
+		return nil
+	}
+
+	var keys []string
+	if err := json.Unmarshal([]byte(value), &keys); err != nil {
+		return nil
+	}
+
+	return keys
+}
@@ -0,0 +1,20 @@
+package processing
+
+import (
+	"fmt"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+)
+
+// DefaultLuaFilterNamespace is the namespace of the ingress gateway, in which the EnvoyFilters with the Lua scripts of
+// the rules are created if no other namespace is configured.
+const DefaultLuaFilterNamespace = "istio-system"
+
+// LuaFilterWorkloadSelector selects the ingress gateway that the EnvoyFilters with the Lua scripts are applied to.
+var LuaFilterWorkloadSelector = map[string]string{"istio": "ingressgateway"}
+
+// GetLuaFilterName returns the name of the Lua filter of a route. The Lua filters of all routes are added to the filter
+// chain of the gateway, so the name contains the namespace of the APIRule to be unique.
+func GetLuaFilterName(api *gatewayv1beta1.APIRule, routeName string) string {
+	return fmt.Sprintf("envoy.filters.http.lua.%s.%s", api.Namespace, routeName)
+}
@@ -0,0 +1,32 @@
+package processing
+
+import (
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+)
+
+// DefaultMaintenanceStatus is the status code of the maintenance response if the APIRule doesn't define one.
+const DefaultMaintenanceStatus uint32 = 503
+
+// IsInMaintenance returns true if the maintenance mode of the APIRule is enabled, so the requests of all rules are
+// answered with the maintenance response.
+func IsInMaintenance(api *gatewayv1beta1.APIRule) bool {
+	return api.Spec.Maintenance != nil && api.Spec.Maintenance.Enabled
+}
+
+// GetMaintenanceStatus returns the status code of the maintenance response of the APIRule.
+func GetMaintenanceStatus(api *gatewayv1beta1.APIRule) uint32 {
+	if api.Spec.Maintenance == nil || api.Spec.Maintenance.Status == nil {
+		return DefaultMaintenanceStatus
+	}
+
+	return *api.Spec.Maintenance.Status
+}
+
+// GetMaintenanceResponseHeaders returns the response headers of the maintenance response of the APIRule.
+func GetMaintenanceResponseHeaders(api *gatewayv1beta1.APIRule) map[string]string {
+	if api.Spec.Maintenance == nil || api.Spec.Maintenance.RetryAfter == nil {
+		return nil
+	}
+
+	return map[string]string{"retry-after": *api.Spec.Maintenance.RetryAfter}
+}
@@ -0,0 +1,51 @@
+package processing
+
+import (
+	"strings"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"k8s.io/utils/strings/slices"
+)
+
+// NormalizeMethods returns the methods in upper case without duplicates, in the order they are first defined. Envoy
+// matches the method of the requests case-sensitively, and HTTP methods are defined in upper case.
+func NormalizeMethods(methods []string) []string {
+	if methods == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(methods))
+	for _, method := range methods {
+		method = strings.ToUpper(strings.TrimSpace(method))
+		if !slices.Contains(normalized, method) {
+			normalized = append(normalized, method)
+		}
+	}
+
+	return normalized
+}
+
+// NormalizeRuleMethods returns the APIRule with the methods of all rules normalized by NormalizeMethods. The given
+// APIRule is not changed, if the methods of all rules are already normalized it is returned as is.
+func NormalizeRuleMethods(api *gatewayv1beta1.APIRule) *gatewayv1beta1.APIRule {
+	if !hasUnnormalizedMethods(api) {
+		return api
+	}
+
+	normalized := api.DeepCopy()
+	for i := range normalized.Spec.Rules {
+		normalized.Spec.Rules[i].Methods = NormalizeMethods(normalized.Spec.Rules[i].Methods)
+	}
+
+	return normalized
+}
+
+func hasUnnormalizedMethods(api *gatewayv1beta1.APIRule) bool {
+	for _, rule := range api.Spec.Rules {
+		if !slices.Equal(rule.Methods, NormalizeMethods(rule.Methods)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,58 @@
+package processing_test
+
+import (
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/processing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NormalizeMethods", func() {
+	DescribeTable("should return the methods in upper case without duplicates",
+		func(methods []string, expected []string) {
+			Expect(processing.NormalizeMethods(methods)).To(Equal(expected))
+		},
+		Entry("nil methods", nil, nil),
+		Entry("upper case methods", []string{"GET", "POST"}, []string{"GET", "POST"}),
+		Entry("lower case methods", []string{"get", "Post"}, []string{"GET", "POST"}),
+		Entry("methods with surrounding spaces", []string{" GET "}, []string{"GET"}),
+		Entry("duplicated methods", []string{"GET", "get", "POST", "GET"}, []string{"GET", "POST"}),
+	)
+})
+
+var _ = Describe("NormalizeRuleMethods", func() {
+	It("should return the APIRule unchanged when the methods are normalized", func() {
+		// given
+		api := &gatewayv1beta1.APIRule{
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Rules: []gatewayv1beta1.Rule{{Path: "/headers", Methods: []string{"GET", "POST"}}},
+			},
+		}
+
+		// when
+		normalized := processing.NormalizeRuleMethods(api)
+
+		// then
+		Expect(normalized).To(BeIdenticalTo(api))
+	})
+
+	It("should normalize the methods of all rules without changing the given APIRule", func() {
+		// given
+		api := &gatewayv1beta1.APIRule{
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Rules: []gatewayv1beta1.Rule{
+					{Path: "/headers", Methods: []string{"GET"}},
+					{Path: "/img", Methods: []string{"get", "put", "GET"}},
+				},
+			},
+		}
+
+		// when
+		normalized := processing.NormalizeRuleMethods(api)
+
+		// then
+		Expect(normalized.Spec.Rules[0].Methods).To(Equal([]string{"GET"}))
+		Expect(normalized.Spec.Rules[1].Methods).To(Equal([]string{"GET", "PUT"}))
+		Expect(api.Spec.Rules[1].Methods).To(Equal([]string{"get", "put", "GET"}))
+	})
+})
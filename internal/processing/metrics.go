@@ -0,0 +1,51 @@
+package processing
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ReconciliationMetrics records the outcomes of the evaluation of the reconciliation of APIRules.
+type ReconciliationMetrics interface {
+	// ObserveVirtualServiceChange records the action of a change that is required for a Virtual Service of the APIRule.
+	// It is called once per change, including the deletion of duplicates and the changes of aggregated Virtual Services,
+	// and once with the action "none" if no Virtual Service changes.
+	ObserveVirtualServiceChange(action string)
+	// ObserveVirtualServiceCreateError records that the desired Virtual Service couldn't be created from the APIRule.
+	ObserveVirtualServiceCreateError()
+}
+
+type prometheusReconciliationMetrics struct {
+	virtualServiceChanges      *prometheus.CounterVec
+	virtualServiceCreateErrors prometheus.Counter
+}
+
+// NewReconciliationMetrics returns ReconciliationMetrics that are exported as Prometheus metrics. The metrics are
+// registered with the given registerer, e.g. the metrics registry of controller-runtime.
+func NewReconciliationMetrics(registerer prometheus.Registerer) (ReconciliationMetrics, error) {
+	m := prometheusReconciliationMetrics{
+		virtualServiceChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "api_gateway_virtual_service_changes_total",
+			Help: "Number of evaluated Virtual Service changes by action.",
+		}, []string{"action"}),
+		virtualServiceCreateErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "api_gateway_virtual_service_create_errors_total",
+			Help: "Number of APIRules for which the desired Virtual Service couldn't be created.",
+		}),
+	}
+
+	for _, collector := range []prometheus.Collector{m.virtualServiceChanges, m.virtualServiceCreateErrors} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+func (m prometheusReconciliationMetrics) ObserveVirtualServiceChange(action string) {
+	m.virtualServiceChanges.WithLabelValues(action).Inc()
+}
+
+func (m prometheusReconciliationMetrics) ObserveVirtualServiceCreateError() {
+	m.virtualServiceCreateErrors.Inc()
+}
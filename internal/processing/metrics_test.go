@@ -0,0 +1,67 @@
+package processing_test
+
+import (
+	"github.com/kyma-project/api-gateway/internal/processing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var _ = Describe("NewReconciliationMetrics", func() {
+	It("should register the counters and increment them per action", func() {
+		// given
+		registry := prometheus.NewRegistry()
+		metrics, err := processing.NewReconciliationMetrics(registry)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		// when
+		metrics.ObserveVirtualServiceChange("create")
+		metrics.ObserveVirtualServiceChange("update")
+		metrics.ObserveVirtualServiceChange("update")
+		metrics.ObserveVirtualServiceCreateError()
+
+		// then
+		families, err := registry.Gather()
+		Expect(err).ShouldNot(HaveOccurred())
+		counters := map[string]float64{}
+		for _, family := range families {
+			for _, m := range family.Metric {
+				counters[family.GetName()+labelsOf(m)] = m.GetCounter().GetValue()
+			}
+		}
+		Expect(counters).To(Equal(map[string]float64{
+			"api_gateway_virtual_service_changes_total{action=create}": 1,
+			"api_gateway_virtual_service_changes_total{action=update}": 2,
+			"api_gateway_virtual_service_create_errors_total":          1,
+		}))
+	})
+
+	It("should return an error when the metrics are already registered", func() {
+		// given
+		registry := prometheus.NewRegistry()
+		_, err := processing.NewReconciliationMetrics(registry)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		// when
+		_, err = processing.NewReconciliationMetrics(registry)
+
+		// then
+		Expect(err).Should(HaveOccurred())
+	})
+})
+
+func labelsOf(m *dto.Metric) string {
+	if len(m.Label) == 0 {
+		return ""
+	}
+
+	labels := "{"
+	for i, label := range m.Label {
+		if i > 0 {
+			labels += ","
+		}
+		labels += label.GetName() + "=" + label.GetValue()
+	}
+	return labels + "}"
+}
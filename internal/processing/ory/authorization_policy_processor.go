@@ -22,7 +22,10 @@ type authorizationPolicyCreator struct {
 	additionalLabels map[string]string
 }
 
-// Create returns empty JwtAuthorization Policy
-func (r authorizationPolicyCreator) Create(_ *gatewayv1beta1.APIRule) (hashbasedstate.Desired, error) {
-	return hashbasedstate.NewDesired(), nil
+// Create returns the Authorization Policies that restrict the requests of the rules to their source CIDRs, since the
+// access strategies of the Ory handler are not enforced by Authorization Policies.
+func (r authorizationPolicyCreator) Create(api *gatewayv1beta1.APIRule) (hashbasedstate.Desired, error) {
+	state := hashbasedstate.NewDesired()
+	err := processing.AddSourceAuthorizationPolicies(&state, api, r.additionalLabels)
+	return state, err
 }
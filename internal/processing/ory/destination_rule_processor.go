@@ -0,0 +1,24 @@
+package ory
+
+import (
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/processing"
+	"github.com/kyma-project/api-gateway/internal/processing/processors"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+)
+
+// NewDestinationRuleProcessor returns a DestinationRuleProcessor with the desired state handling specific for the Ory handler.
+func NewDestinationRuleProcessor(_ processing.ReconciliationConfig) processors.DestinationRuleProcessor {
+	return processors.DestinationRuleProcessor{
+		Creator: destinationRuleCreator{},
+	}
+}
+
+type destinationRuleCreator struct{}
+
+// Create returns no Destination Rules, since the connections to the services are only configured by the Istio handler,
+// and the fields of the services configured in Destination Rules are rejected by the validation of the Ory handler.
+// The processor still removes the Destination Rules that were created by the Istio handler.
+func (r destinationRuleCreator) Create(_ *gatewayv1beta1.APIRule) map[string]*networkingv1beta1.DestinationRule {
+	return make(map[string]*networkingv1beta1.DestinationRule)
+}
@@ -0,0 +1,23 @@
+package ory
+
+import (
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/processing"
+	"github.com/kyma-project/api-gateway/internal/processing/processors"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+)
+
+// NewEnvoyFilterProcessor returns an EnvoyFilterProcessor with the desired state handling specific for the Ory handler.
+func NewEnvoyFilterProcessor(_ processing.ReconciliationConfig) processors.EnvoyFilterProcessor {
+	return processors.EnvoyFilterProcessor{
+		Creator: envoyFilterCreator{},
+	}
+}
+
+type envoyFilterCreator struct{}
+
+// Create returns no Envoy Filters, since the Lua scripts of the rules are only supported by the Istio handler. The
+// processor still removes the Envoy Filters that were created by the Istio handler.
+func (r envoyFilterCreator) Create(_ *gatewayv1beta1.APIRule) map[string]*networkingv1alpha3.EnvoyFilter {
+	return make(map[string]*networkingv1alpha3.EnvoyFilter)
+}
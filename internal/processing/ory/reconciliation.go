@@ -2,6 +2,8 @@ package ory
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
 	"github.com/go-logr/logr"
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
@@ -21,9 +23,11 @@ func NewOryReconciliation(config processing.ReconciliationConfig, log *logr.Logg
 	vsProcessor := NewVirtualServiceProcessor(config)
 	apProcessor := NewAuthorizationPolicyProcessor(config, log)
 	raProcessor := NewRequestAuthenticationProcessor(config)
+	drProcessor := NewDestinationRuleProcessor(config)
+	efProcessor := NewEnvoyFilterProcessor(config)
 
 	return Reconciliation{
-		processors: []processing.ReconciliationProcessor{vsProcessor, raProcessor, apProcessor, acProcessor},
+		processors: []processing.ReconciliationProcessor{vsProcessor, drProcessor, efProcessor, raProcessor, apProcessor, acProcessor},
 		config:     config,
 	}
 }
@@ -38,13 +42,109 @@ func (r Reconciliation) Validate(ctx context.Context, client client.Client, apiR
 		HandlerValidator:          &handlerValidator{},
 		AccessStrategiesValidator: &asValidator{},
 		ServiceBlockList:          r.config.ServiceBlockList,
+		RestrictServiceNamespaces: r.config.RestrictServiceNamespaces,
+		ServiceNamespaceAllowList: r.config.ServiceNamespaceAllowList,
 		DomainAllowList:           r.config.DomainAllowList,
 		HostBlockList:             r.config.HostBlockList,
 		DefaultDomainName:         r.config.DefaultDomainName,
 	}
-	return validator.Validate(apiRule, vsList), nil
+	// The existence of the services is only validated if enabled, since the services might be created after the APIRule.
+	if r.config.ValidateServices {
+		validator.ServiceValidator = &validation.ServiceExistenceValidator{Ctx: ctx, Client: client}
+	}
+	return append(validator.Validate(apiRule, vsList), validateUnsupportedFields(apiRule)...), nil
+}
+
+// validateUnsupportedFields returns the failures for the fields of the APIRule that are only supported by the Istio
+// handler, so they are rejected instead of being ignored silently.
+func validateUnsupportedFields(apiRule *gatewayv1beta1.APIRule) []validation.Failure {
+	var failures []validation.Failure
+	// The connections to the services are configured in Destination Rules, which are not created by the Ory handler.
+	for attributePath, service := range getServices(apiRule) {
+		if service.TLS {
+			failures = append(failures, validation.Failure{AttributePath: attributePath + ".tls", Message: "TLS origination is not supported with the Ory handler"})
+		}
+		if policy := service.TrafficPolicy; policy != nil {
+			if policy.ConnectionPool != nil {
+				failures = append(failures, validation.Failure{AttributePath: attributePath + ".trafficPolicy.connectionPool", Message: "Connection pool is not supported with the Ory handler"})
+			}
+			if policy.OutlierDetection != nil {
+				failures = append(failures, validation.Failure{AttributePath: attributePath + ".trafficPolicy.outlierDetection", Message: "Outlier detection is not supported with the Ory handler"})
+			}
+			if policy.LoadBalancer != nil {
+				failures = append(failures, validation.Failure{AttributePath: attributePath + ".trafficPolicy.loadBalancer", Message: "Load balancer is not supported with the Ory handler"})
+			}
+		}
+		if service.Protocol == gatewayv1beta1.BackendProtocolHTTP2 {
+			failures = append(failures, validation.Failure{AttributePath: attributePath + ".protocol", Message: "HTTP/2 to the service is not supported with the Ory handler"})
+		}
+		if len(service.Subsets) > 0 {
+			failures = append(failures, validation.Failure{AttributePath: attributePath + ".subsets", Message: "Subsets are not supported with the Ory handler"})
+		}
+	}
+
+	for i, rule := range apiRule.Spec.Rules {
+		attributePath := fmt.Sprintf(".spec.rules[%d]", i)
+
+		// The subsets and the idle timeout are configured in the Destination Rules of the services.
+		if rule.Subset != "" {
+			failures = append(failures, validation.Failure{AttributePath: attributePath + ".subset", Message: "Subsets are not supported with the Ory handler"})
+		}
+		if rule.IdleTimeout != nil {
+			failures = append(failures, validation.Failure{AttributePath: attributePath + ".idleTimeout", Message: "Idle timeout is not supported with the Ory handler"})
+		}
+
+		// The requests that are forwarded to oathkeeper keep the original URI, since the access rules match on it.
+		if processing.IsSecured(rule) && rule.StripPrefix {
+			failures = append(failures, validation.Failure{AttributePath: attributePath + ".stripPrefix", Message: "Strip prefix is only supported for rules with the allow access strategy with the Ory handler"})
+		}
+
+		// Oathkeeper proxies HTTP/1.1 requests only, so gRPC requests can only be routed to the service directly.
+		if processing.IsSecured(rule) && rule.RouteType == gatewayv1beta1.RouteTypeGRPC {
+			failures = append(failures, validation.Failure{AttributePath: attributePath + ".routeType", Message: "gRPC is only supported for rules with the allow access strategy with the Ory handler"})
+		}
+		// The gRPC requests of the protocol ports are sent to the service with HTTP/2, which is configured in a Destination Rule.
+		for j, protocolPort := range rule.ProtocolPorts {
+			if protocolPort.Protocol == gatewayv1beta1.RouteTypeGRPC {
+				failures = append(failures, validation.Failure{AttributePath: fmt.Sprintf("%s.protocolPorts[%d].protocol", attributePath, j), Message: "gRPC protocol ports are not supported with the Ory handler"})
+			}
+		}
+	}
+
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].AttributePath < failures[j].AttributePath
+	})
+
+	return failures
+}
+
+// getServices returns the services of the APIRule by their attribute path.
+func getServices(apiRule *gatewayv1beta1.APIRule) map[string]*gatewayv1beta1.Service {
+	services := make(map[string]*gatewayv1beta1.Service)
+	if apiRule.Spec.Service != nil {
+		services[".spec.service"] = apiRule.Spec.Service
+	}
+	for i := range apiRule.Spec.Rules {
+		rule := &apiRule.Spec.Rules[i]
+		attributePath := fmt.Sprintf(".spec.rules[%d]", i)
+		if rule.Service != nil {
+			services[attributePath+".service"] = rule.Service
+		}
+		for j := range rule.Destinations {
+			services[fmt.Sprintf("%s.destinations[%d]", attributePath, j)] = &rule.Destinations[j].Service
+		}
+		if rule.Mirror != nil {
+			services[attributePath+".mirror"] = &rule.Mirror.Service
+		}
+	}
+
+	return services
 }
 
 func (r Reconciliation) GetProcessors() []processing.ReconciliationProcessor {
 	return r.processors
 }
+
+func (r Reconciliation) GetDuplicatePathsMode() processing.DuplicatePathsMode {
+	return r.config.DuplicatePathsMode
+}
@@ -7,6 +7,7 @@ import (
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
 	. "github.com/kyma-project/api-gateway/internal/processing/internal/test"
 	"github.com/kyma-project/api-gateway/internal/processing/ory"
+	"github.com/kyma-project/api-gateway/internal/validation"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	rulev1alpha1 "github.com/ory/oathkeeper-maester/api/v1alpha1"
@@ -157,3 +158,96 @@ var _ = Describe("Reconciliation", func() {
 		})
 	})
 })
+
+var _ = Describe("Validate", func() {
+	noop := []*gatewayv1beta1.Authenticator{{Handler: &gatewayv1beta1.Handler{Name: "noop"}}}
+	allow := []*gatewayv1beta1.Authenticator{{Handler: &gatewayv1beta1.Handler{Name: "allow"}}}
+	jwt := []*gatewayv1beta1.Authenticator{{Handler: &gatewayv1beta1.Handler{
+		Name:   "jwt",
+		Config: &runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"trusted_issuers": ["%s"]}`, JwtIssuer))},
+	}}}
+
+	DescribeTable("should reject the fields that are only supported by the Istio handler",
+		func(strategies []*gatewayv1beta1.Authenticator, update func(api *gatewayv1beta1.APIRule), expectedFailures ...validation.Failure) {
+			// given
+			rule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			update(apiRule)
+			reconciliation := ory.NewOryReconciliation(GetTestConfig(), &testLogger)
+
+			// when
+			failures, err := reconciliation.Validate(context.TODO(), GetFakeClient(), apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			if len(expectedFailures) == 0 {
+				Expect(failures).To(BeEmpty())
+			} else {
+				Expect(failures).To(ConsistOf(expectedFailures))
+			}
+		},
+		Entry("gRPC rule with the allow access strategy", allow, func(api *gatewayv1beta1.APIRule) {
+			api.Spec.Rules[0].RouteType = gatewayv1beta1.RouteTypeGRPC
+		}),
+		Entry("gRPC rule handled by oathkeeper", noop, func(api *gatewayv1beta1.APIRule) {
+			api.Spec.Rules[0].RouteType = gatewayv1beta1.RouteTypeGRPC
+		}, validation.Failure{AttributePath: ".spec.rules[0].routeType", Message: "gRPC is only supported for rules with the allow access strategy with the Ory handler"}),
+		Entry("gRPC protocol port", allow, func(api *gatewayv1beta1.APIRule) {
+			api.Spec.Rules[0].ProtocolPorts = []gatewayv1beta1.ProtocolPort{{Protocol: gatewayv1beta1.RouteTypeGRPC, Port: 9090}, {Protocol: gatewayv1beta1.RouteTypeHTTP, Port: 8080}}
+		}, validation.Failure{AttributePath: ".spec.rules[0].protocolPorts[0].protocol", Message: "gRPC protocol ports are not supported with the Ory handler"}),
+		Entry("idle timeout of the rule", allow, func(api *gatewayv1beta1.APIRule) {
+			idleTimeout := "5m"
+			api.Spec.Rules[0].IdleTimeout = &idleTimeout
+		}, validation.Failure{AttributePath: ".spec.rules[0].idleTimeout", Message: "Idle timeout is not supported with the Ory handler"}),
+		Entry("HTTP/2 to the spec service", allow, func(api *gatewayv1beta1.APIRule) {
+			api.Spec.Service.Protocol = gatewayv1beta1.BackendProtocolHTTP2
+		}, validation.Failure{AttributePath: ".spec.service.protocol", Message: "HTTP/2 to the service is not supported with the Ory handler"}),
+		Entry("strip prefix of a rule with the allow access strategy", allow, func(api *gatewayv1beta1.APIRule) {
+			api.Spec.Rules[0].PathMatchType = gatewayv1beta1.PathMatchPrefix
+			api.Spec.Rules[0].StripPrefix = true
+		}),
+		Entry("strip prefix of a rule handled by oathkeeper", jwt, func(api *gatewayv1beta1.APIRule) {
+			api.Spec.Rules[0].PathMatchType = gatewayv1beta1.PathMatchPrefix
+			api.Spec.Rules[0].StripPrefix = true
+		}, validation.Failure{AttributePath: ".spec.rules[0].stripPrefix", Message: "Strip prefix is only supported for rules with the allow access strategy with the Ory handler"}),
+		Entry("TLS of the spec service", allow, func(api *gatewayv1beta1.APIRule) {
+			api.Spec.Service.TLS = true
+		}, validation.Failure{AttributePath: ".spec.service.tls", Message: "TLS origination is not supported with the Ory handler"}),
+		Entry("TLS of the rule service", allow, func(api *gatewayv1beta1.APIRule) {
+			api.Spec.Rules[0].Service = &gatewayv1beta1.Service{Name: api.Spec.Service.Name, Port: api.Spec.Service.Port, TLS: true}
+		}, validation.Failure{AttributePath: ".spec.rules[0].service.tls", Message: "TLS origination is not supported with the Ory handler"}),
+		Entry("TLS of a weighted destination", allow, func(api *gatewayv1beta1.APIRule) {
+			api.Spec.Rules[0].Destinations = []gatewayv1beta1.WeightedService{
+				{Service: gatewayv1beta1.Service{Name: api.Spec.Service.Name, Port: api.Spec.Service.Port}, Weight: 50},
+				{Service: gatewayv1beta1.Service{Name: api.Spec.Service.Name, Port: api.Spec.Service.Port, TLS: true}, Weight: 50},
+			}
+		}, validation.Failure{AttributePath: ".spec.rules[0].destinations[1].tls", Message: "TLS origination is not supported with the Ory handler"}),
+		Entry("connection pool of the spec service", allow, func(api *gatewayv1beta1.APIRule) {
+			api.Spec.Service.TrafficPolicy = &gatewayv1beta1.TrafficPolicy{ConnectionPool: &gatewayv1beta1.ConnectionPool{MaxConnections: 10}}
+		}, validation.Failure{AttributePath: ".spec.service.trafficPolicy.connectionPool", Message: "Connection pool is not supported with the Ory handler"}),
+		Entry("outlier detection of the mirror service", allow, func(api *gatewayv1beta1.APIRule) {
+			api.Spec.Rules[0].Mirror = &gatewayv1beta1.Mirror{Service: gatewayv1beta1.Service{
+				Name:          api.Spec.Service.Name,
+				Port:          api.Spec.Service.Port,
+				TrafficPolicy: &gatewayv1beta1.TrafficPolicy{OutlierDetection: &gatewayv1beta1.OutlierDetection{}},
+			}}
+		}, validation.Failure{AttributePath: ".spec.rules[0].mirror.trafficPolicy.outlierDetection", Message: "Outlier detection is not supported with the Ory handler"}),
+		Entry("load balancer of the rule service", allow, func(api *gatewayv1beta1.APIRule) {
+			api.Spec.Rules[0].Service = &gatewayv1beta1.Service{
+				Name:          api.Spec.Service.Name,
+				Port:          api.Spec.Service.Port,
+				TrafficPolicy: &gatewayv1beta1.TrafficPolicy{LoadBalancer: &gatewayv1beta1.LoadBalancer{Algorithm: gatewayv1beta1.LoadBalancerLeastConn}},
+			}
+		}, validation.Failure{AttributePath: ".spec.rules[0].service.trafficPolicy.loadBalancer", Message: "Load balancer is not supported with the Ory handler"}),
+		Entry("subsets of the spec service", allow, func(api *gatewayv1beta1.APIRule) {
+			api.Spec.Service.Subsets = []gatewayv1beta1.Subset{{Name: "v1", Labels: map[string]string{"version": "v1"}}}
+		}, validation.Failure{AttributePath: ".spec.service.subsets", Message: "Subsets are not supported with the Ory handler"}),
+		Entry("subset of the rule", allow, func(api *gatewayv1beta1.APIRule) {
+			api.Spec.Service.Subsets = []gatewayv1beta1.Subset{{Name: "v1", Labels: map[string]string{"version": "v1"}}}
+			api.Spec.Rules[0].Subset = "v1"
+		},
+			validation.Failure{AttributePath: ".spec.rules[0].subset", Message: "Subsets are not supported with the Ory handler"},
+			validation.Failure{AttributePath: ".spec.service.subsets", Message: "Subsets are not supported with the Ory handler"},
+		),
+	)
+})
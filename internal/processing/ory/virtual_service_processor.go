@@ -1,88 +1,118 @@
 package ory
 
 import (
-	"fmt"
+	"errors"
+
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
 	"github.com/kyma-project/api-gateway/internal/builders"
 	"github.com/kyma-project/api-gateway/internal/helpers"
 	"github.com/kyma-project/api-gateway/internal/processing"
 	"github.com/kyma-project/api-gateway/internal/processing/processors"
 	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
-	"time"
 )
 
+func init() {
+	processors.RegisterVirtualServiceCreator(helpers.JWT_HANDLER_ORY, newVirtualServiceCreator)
+}
+
 // NewVirtualServiceProcessor returns a VirtualServiceProcessor with the desired state handling specific for the Ory handler.
 func NewVirtualServiceProcessor(config processing.ReconciliationConfig) processors.VirtualServiceProcessor {
-	return processors.VirtualServiceProcessor{
-		Creator: virtualServiceCreator{
-			oathkeeperSvc:       config.OathkeeperSvc,
-			oathkeeperSvcPort:   config.OathkeeperSvcPort,
-			corsConfig:          config.CorsConfig,
-			additionalLabels:    config.AdditionalLabels,
-			defaultDomainName:   config.DefaultDomainName,
-			httpTimeoutDuration: config.HTTPTimeoutDuration,
-		},
+	return processors.NewVirtualServiceProcessor(config, newVirtualServiceCreator(config))
+}
+
+func newVirtualServiceCreator(config processing.ReconciliationConfig) processors.VirtualServiceCreator {
+	routeConfig := processing.NewHTTPRouteConfig(config)
+	// All secured rules are handled by oathkeeper, so only the rules with the allow access strategy are routed directly.
+	routeConfig.RouteDirectlyToService = func(rule gatewayv1beta1.Rule) bool {
+		return !processing.IsSecured(rule)
+	}
+
+	return virtualServiceCreator{
+		routeConfig:       routeConfig,
+		additionalLabels:  config.AdditionalLabels,
+		defaultDomainName: config.DefaultDomainName,
+		strictHostDomain:  config.StrictHostDomain,
+		fixedName:         config.VirtualServiceFixedName,
+		namePrefix:        config.VirtualServiceNamePrefix,
+		nameSuffix:        config.VirtualServiceNameSuffix,
+		exportTo:          config.ExportTo,
 	}
 }
 
 type virtualServiceCreator struct {
-	oathkeeperSvc       string
-	oathkeeperSvcPort   uint32
-	corsConfig          *processing.CorsConfig
-	defaultDomainName   string
-	additionalLabels    map[string]string
-	httpTimeoutDuration int
+	routeConfig       processing.HTTPRouteConfig
+	defaultDomainName string
+	additionalLabels  map[string]string
+	strictHostDomain  bool
+	fixedName         bool
+	namePrefix        string
+	nameSuffix        string
+	exportTo          []string
 }
 
 // Create returns the Virtual Service using the configuration of the APIRule.
 func (r virtualServiceCreator) Create(api *gatewayv1beta1.APIRule) (*networkingv1beta1.VirtualService, error) {
-	virtualServiceNamePrefix := fmt.Sprintf("%s-", api.ObjectMeta.Name)
+	// Istio rejects Virtual Services without routes, the validation of the APIRule reports this case with a clear message.
+	enabledRules := processing.FilterDisabledRules(api.Spec.Rules)
+	if len(enabledRules) == 0 {
+		return nil, processing.NewFieldError(".spec.rules", errors.New("no rules defined"))
+	}
 
 	vsSpecBuilder := builders.VirtualServiceSpec()
-	vsSpecBuilder.Host(helpers.GetHostWithDomain(*api.Spec.Host, r.defaultDomainName))
-	vsSpecBuilder.Gateway(*api.Spec.Gateway)
-	filteredRules := processing.FilterDuplicatePaths(api.Spec.Rules)
-
-	for _, rule := range filteredRules {
-		httpRouteBuilder := builders.HTTPRoute()
-		host, port := r.oathkeeperSvc, r.oathkeeperSvcPort
-		serviceNamespace := helpers.FindServiceNamespace(api, &rule)
-
-		if !processing.IsSecured(rule) {
-			// Use rule level service if it exists
-			if rule.Service != nil {
-				host = fmt.Sprintf("%s.%s.svc.cluster.local", *rule.Service.Name, serviceNamespace)
-				port = *rule.Service.Port
-			} else {
-				// Otherwise use service defined on APIRule spec level
-				host = fmt.Sprintf("%s.%s.svc.cluster.local", *api.Spec.Service.Name, serviceNamespace)
-				port = *api.Spec.Service.Port
-			}
+	var hosts []string
+	for _, host := range helpers.GetHosts(api) {
+		hostWithDomain, err := helpers.ResolveHostWithDomain(host, r.defaultDomainName, r.strictHostDomain)
+		if err != nil {
+			return nil, err
 		}
+		hosts = append(hosts, hostWithDomain)
+		vsSpecBuilder.Host(hostWithDomain)
+	}
+	for _, gateway := range helpers.GetGateways(api) {
+		vsSpecBuilder.Gateway(gateway)
+	}
+	vsSpecBuilder.ExportTo(processing.GetExportTo(api, r.exportTo)...)
+	filteredRules := processing.GetRoutedRules(api)
+	duplicatedMatches := processing.GetDuplicatedMatches(enabledRules)
 
-		httpRouteBuilder.Route(builders.RouteDestination().Host(host).Port(port))
-		httpRouteBuilder.Match(builders.MatchRequest().Uri().Regex(rule.Path))
-		httpRouteBuilder.CorsPolicy(builders.CorsPolicy().
-			AllowOrigins(r.corsConfig.AllowOrigins...).
-			AllowMethods(r.corsConfig.AllowMethods...).
-			AllowHeaders(r.corsConfig.AllowHeaders...))
-		httpRouteBuilder.Headers(builders.NewHttpRouteHeadersBuilder().
-			SetHostHeader(helpers.GetHostWithDomain(*api.Spec.Host, r.defaultDomainName)).Get())
-		httpRouteBuilder.Timeout(time.Second * time.Duration(r.httpTimeoutDuration))
-		vsSpecBuilder.HTTP(httpRouteBuilder)
+	// The errors of all rules are collected, so all invalid fields are reported at once.
+	var errs processing.FieldErrors
+	for i, rule := range filteredRules {
+		httpRoute, err := processing.NewHTTPRoute(api, rule, i, hosts, duplicatedMatches, r.routeConfig)
+		errs = append(errs, err...)
+		if httpRoute != nil {
+			vsSpecBuilder.HTTP(builders.HTTPRoute().From(httpRoute))
+		}
+	}
 
+	if len(errs) > 0 {
+		return nil, errs
 	}
 
 	vsBuilder := builders.VirtualService().
-		GenerateName(virtualServiceNamePrefix).
 		Namespace(api.ObjectMeta.Namespace).
-		Label(processing.OwnerLabel, fmt.Sprintf("%s.%s", api.ObjectMeta.Name, api.ObjectMeta.Namespace)).
-		Label(processing.OwnerLabelv1alpha1, fmt.Sprintf("%s.%s", api.ObjectMeta.Name, api.ObjectMeta.Namespace))
+		Label(processing.OwnerLabel, processing.GetOwnerLabelValue(api)).
+		Label(processing.OwnerLabelv1alpha1, processing.GetOwnerLabelValue(api))
+
+	// The existing VirtualService is looked up by the owner labels, so the naming mode doesn't affect finding it.
+	if r.fixedName {
+		vsBuilder.Name(processing.GetVirtualServiceFixedName(api))
+	} else {
+		vsBuilder.GenerateName(processing.GetVirtualServiceGenerateName(api, r.namePrefix, r.nameSuffix))
+	}
 
 	for k, v := range r.additionalLabels {
 		vsBuilder.Label(k, v)
 	}
 
+	if accessLogDisabledPaths := processing.GetAccessLogDisabledPathsAnnotation(api); accessLogDisabledPaths != "" {
+		vsBuilder.Annotation(processing.AccessLogDisabledPathsAnnotation, accessLogDisabledPaths)
+	}
+
+	if maxRequestHeadersBytes := processing.GetMaxRequestHeadersBytesAnnotation(api); maxRequestHeadersBytes != "" {
+		vsBuilder.Annotation(processing.MaxRequestHeadersBytesAnnotation, maxRequestHeadersBytes)
+	}
+
 	vsBuilder.Spec(vsSpecBuilder)
 
 	return vsBuilder.Get(), nil
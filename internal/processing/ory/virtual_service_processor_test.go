@@ -11,6 +11,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	rulev1alpha1 "github.com/ory/oathkeeper-maester/api/v1alpha1"
+	"istio.io/api/networking/v1beta1"
 	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -184,6 +185,303 @@ var _ = Describe("Virtual Service Processor", func() {
 		})
 	})
 
+	When("the path is `/*`", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		DescribeTable("should translate the path to the configured catch-all regex",
+			func(catchAllPathRegex string, expectedRegex string) {
+				// given
+				rule := GetRuleFor("/*", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+				apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+				client := GetFakeClient()
+				config := GetTestConfig()
+				config.CatchAllPathRegex = catchAllPathRegex
+				processor := ory.NewVirtualServiceProcessor(config)
+
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+				// then
+				Expect(err).To(BeNil())
+				Expect(result).To(HaveLen(1))
+
+				vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+				Expect(vs.Spec.Http).To(HaveLen(1))
+				Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(expectedRegex))
+			},
+			Entry("not configured", "", "/*"),
+			Entry("configured", "/[a-z].*", "/[a-z].*"),
+		)
+	})
+
+	When("the rule defines a path match type", func() {
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "noop",
+				},
+			},
+		}
+
+		DescribeTable("should set the match for the path match type",
+			func(path string, matchType gatewayv1beta1.PathMatchType, expectedMatch *v1beta1.StringMatch) {
+				// given
+				rule := GetRuleFor(path, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+				rule.PathMatchType = matchType
+
+				apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+				client := GetFakeClient()
+				processor := ory.NewVirtualServiceProcessor(GetTestConfig())
+
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+				// then
+				Expect(err).To(BeNil())
+				Expect(result).To(HaveLen(1))
+
+				vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+				Expect(vs.Spec.Http).To(HaveLen(1))
+				Expect(vs.Spec.Http[0].Match).To(HaveLen(1))
+				Expect(vs.Spec.Http[0].Match[0].Uri).To(Equal(expectedMatch))
+			},
+			Entry("regex when not defined", "/api/v1", gatewayv1beta1.PathMatchType(""), &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Regex{Regex: "/api/v1"}}),
+			Entry("regex", "/api/.*", gatewayv1beta1.PathMatchRegex, &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Regex{Regex: "/api/.*"}}),
+			Entry("exact", "/api/v1", gatewayv1beta1.PathMatchExact, &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Exact{Exact: "/api/v1"}}),
+			Entry("prefix", "/api", gatewayv1beta1.PathMatchPrefix, &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Prefix{Prefix: "/api"}}),
+		)
+	})
+
+	When("the rule has methods defined", func() {
+		DescribeTable("should set the method match",
+			func(handler string, methods []string, expectedMatch *v1beta1.StringMatch) {
+				// given
+				strategies := []*gatewayv1beta1.Authenticator{
+					{
+						Handler: &gatewayv1beta1.Handler{
+							Name: handler,
+						},
+					},
+				}
+				rule := GetRuleFor(ApiPath, methods, []*gatewayv1beta1.Mutator{}, strategies)
+
+				apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+				// The OPTIONS method of the CORS preflight requests is added to the method match if CORS is enabled.
+				disableCors := true
+				apiRule.Spec.DisableCors = &disableCors
+				client := GetFakeClient()
+				processor := ory.NewVirtualServiceProcessor(GetTestConfig())
+
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+				// then
+				Expect(err).To(BeNil())
+				Expect(result).To(HaveLen(1))
+
+				vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+				Expect(vs.Spec.Http).To(HaveLen(1))
+				Expect(vs.Spec.Http[0].Match).To(HaveLen(1))
+				Expect(vs.Spec.Http[0].Match[0].Method).To(Equal(expectedMatch))
+			},
+			Entry("exact for a single method", "allow", []string{"GET"}, &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Exact{Exact: "GET"}}),
+			Entry("regex for multiple methods", "allow", []string{"GET", "POST"}, &v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Regex{Regex: "GET|POST"}}),
+			Entry("no match for empty methods", "allow", []string{}, nil),
+			Entry("no match if the methods are matched by the access rule", "noop", []string{"GET"}, nil),
+			Entry("no match if the methods are matched by the jwt access rule", "jwt", []string{"GET"}, nil),
+		)
+
+		It("should match the OPTIONS method of the CORS preflight requests if CORS is enabled", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+			rule := GetRuleFor(ApiPath, []string{"GET"}, []*gatewayv1beta1.Mutator{}, strategies)
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := ory.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].CorsPolicy).NotTo(BeNil())
+			Expect(vs.Spec.Http[0].Match[0].Method.GetRegex()).To(Equal("GET|OPTIONS"))
+		})
+
+		It("should not set the method match for rules with the same path", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+			getRule := GetRuleFor(ApiPath, []string{"GET"}, []*gatewayv1beta1.Mutator{}, strategies)
+			postRule := GetRuleFor(ApiPath, []string{"POST"}, []*gatewayv1beta1.Mutator{}, strategies)
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{getRule, postRule})
+			client := GetFakeClient()
+			processor := ory.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Match[0].Method).To(BeNil())
+		})
+	})
+
+	When("the rule has the grpc route type", func() {
+		It("should match the service path by prefix without the methods", func() {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+			rule := GetRuleFor("/my.package.Service/", []string{"POST"}, []*gatewayv1beta1.Mutator{}, strategies)
+			rule.RouteType = gatewayv1beta1.RouteTypeGRPC
+
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			client := GetFakeClient()
+			processor := ory.NewVirtualServiceProcessor(GetTestConfig())
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Match[0].Uri.GetPrefix()).To(Equal("/my.package.Service/"))
+			Expect(vs.Spec.Http[0].Match[0].Method).To(BeNil())
+		})
+	})
+
+	When("the rule strips the matched prefix", func() {
+		DescribeTable("should rewrite the requests routed to the service",
+			func(handler string, expectedMatches []*v1beta1.StringMatch, expectedRewrite *v1beta1.HTTPRewrite) {
+				// given
+				strategies := []*gatewayv1beta1.Authenticator{
+					{
+						Handler: &gatewayv1beta1.Handler{
+							Name: handler,
+						},
+					},
+				}
+				rule := GetRuleFor("/service-a", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+				rule.PathMatchType = gatewayv1beta1.PathMatchPrefix
+				rule.StripPrefix = true
+
+				apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+				client := GetFakeClient()
+				processor := ory.NewVirtualServiceProcessor(GetTestConfig())
+
+				// when
+				result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+				// then
+				Expect(err).To(BeNil())
+				Expect(result).To(HaveLen(1))
+
+				vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+
+				Expect(vs.Spec.Http).To(HaveLen(1))
+				Expect(vs.Spec.Http[0].Match).To(HaveLen(len(expectedMatches)))
+				for i, expectedMatch := range expectedMatches {
+					Expect(vs.Spec.Http[0].Match[i].Uri).To(Equal(expectedMatch))
+				}
+				Expect(vs.Spec.Http[0].Rewrite).To(Equal(expectedRewrite))
+			},
+			Entry("allow access strategy", "allow",
+				[]*v1beta1.StringMatch{
+					{MatchType: &v1beta1.StringMatch_Prefix{Prefix: "/service-a/"}},
+					{MatchType: &v1beta1.StringMatch_Exact{Exact: "/service-a"}},
+				},
+				&v1beta1.HTTPRewrite{Uri: "/"}),
+			Entry("access strategy handled by oathkeeper", "noop",
+				[]*v1beta1.StringMatch{
+					{MatchType: &v1beta1.StringMatch_Prefix{Prefix: "/service-a"}},
+				},
+				nil),
+		)
+	})
+
+	DescribeTable("matched rule header",
+		func(matchedRuleHeader string, expectedValues []string) {
+			// given
+			strategies := []*gatewayv1beta1.Authenticator{
+				{
+					Handler: &gatewayv1beta1.Handler{
+						Name: "allow",
+					},
+				},
+			}
+
+			rules := []gatewayv1beta1.Rule{
+				GetRuleFor("/headers", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies),
+				GetRuleFor("/status", ApiMethods, []*gatewayv1beta1.Mutator{}, strategies),
+			}
+			apiRule := GetAPIRuleFor(rules)
+			client := GetFakeClient()
+			config := GetTestConfig()
+			config.MatchedRuleHeader = matchedRuleHeader
+			processor := ory.NewVirtualServiceProcessor(config)
+
+			// when
+			result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(1))
+
+			vs := result[0].Obj.(*networkingv1beta1.VirtualService)
+			Expect(vs.Spec.Http).To(HaveLen(2))
+			for i, route := range vs.Spec.Http {
+				if expectedValues == nil {
+					Expect(route.Headers.Response).To(BeNil())
+				} else {
+					Expect(route.Headers.Response.Set).To(HaveKeyWithValue(matchedRuleHeader, expectedValues[i]))
+				}
+			}
+		},
+		Entry("is not set if disabled", "", nil),
+		Entry("is set to the route name of the rule if enabled", "x-matched-rule", []string{"test-apirule.some-namespace.0-headers", "test-apirule.some-namespace.1-status"}),
+	)
+
 	When("handler is noop", func() {
 		It("should not override Oathkeeper service destination host with spec level service", func() {
 			// given
@@ -370,7 +668,7 @@ var _ = Describe("Virtual Service Processor", func() {
 			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(OathkeeperSvc))
 			Expect(vs.Spec.Http[0].Route[0].Destination.Port.Number).To(Equal(OathkeeperSvcPort))
 			Expect(len(vs.Spec.Http[0].Match)).To(Equal(1))
-			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[0].Path))
+			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[1].Path))
 
 			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
 			Expect(vs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
@@ -380,7 +678,7 @@ var _ = Describe("Virtual Service Processor", func() {
 			Expect(vs.Spec.Http[1].Route[0].Destination.Host).To(Equal(OathkeeperSvc))
 			Expect(vs.Spec.Http[1].Route[0].Destination.Port.Number).To(Equal(OathkeeperSvcPort))
 			Expect(len(vs.Spec.Http[1].Match)).To(Equal(1))
-			Expect(vs.Spec.Http[1].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[1].Path))
+			Expect(vs.Spec.Http[1].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[0].Path))
 
 			Expect(vs.Spec.Http[1].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
 			Expect(vs.Spec.Http[1].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
@@ -543,7 +841,7 @@ var _ = Describe("Virtual Service Processor", func() {
 			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(OathkeeperSvc))
 			Expect(vs.Spec.Http[0].Route[0].Destination.Port.Number).To(Equal(OathkeeperSvcPort))
 			Expect(len(vs.Spec.Http[0].Match)).To(Equal(1))
-			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[0].Path))
+			Expect(vs.Spec.Http[0].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[2].Path))
 
 			Expect(vs.Spec.Http[0].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
 			Expect(vs.Spec.Http[0].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
@@ -553,7 +851,7 @@ var _ = Describe("Virtual Service Processor", func() {
 			Expect(vs.Spec.Http[1].Route[0].Destination.Host).To(Equal(OathkeeperSvc))
 			Expect(vs.Spec.Http[1].Route[0].Destination.Port.Number).To(Equal(OathkeeperSvcPort))
 			Expect(len(vs.Spec.Http[1].Match)).To(Equal(1))
-			Expect(vs.Spec.Http[1].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[2].Path))
+			Expect(vs.Spec.Http[1].Match[0].Uri.GetRegex()).To(Equal(apiRule.Spec.Rules[0].Path))
 
 			Expect(vs.Spec.Http[1].CorsPolicy.AllowOrigins).To(Equal(TestCors.AllowOrigins))
 			Expect(vs.Spec.Http[1].CorsPolicy.AllowMethods).To(Equal(TestCors.AllowMethods))
@@ -0,0 +1,74 @@
+package processing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+)
+
+// PathOverlap is a pair of rule paths that match some of the same requests.
+type PathOverlap struct {
+	Path            string
+	OverlappingPath string
+}
+
+// DetectOverlappingPaths returns the pairs of rule paths where one path matches the other. Rules with the same path are
+// not considered overlapping, since they are merged into a single route. The detection is a best-effort check, so
+// two regular expressions that only match a common subset of requests are not detected.
+func DetectOverlappingPaths(rules []gatewayv1beta1.Rule) []PathOverlap {
+	var overlaps []PathOverlap
+	detected := make(map[PathOverlap]bool)
+
+	for i := range rules {
+		for j := i + 1; j < len(rules); j++ {
+			// The default backend is intended to handle the requests that are not matched by the other rules.
+			if rules[i].Path == rules[j].Path || rules[i].DefaultBackend || rules[j].DefaultBackend {
+				continue
+			}
+
+			if !matchesPath(rules[i], rules[j].Path) && !matchesPath(rules[j], rules[i].Path) {
+				continue
+			}
+
+			overlap := PathOverlap{Path: rules[i].Path, OverlappingPath: rules[j].Path}
+			if !detected[overlap] {
+				detected[overlap] = true
+				overlaps = append(overlaps, overlap)
+			}
+		}
+	}
+
+	return overlaps
+}
+
+// matchesPath returns true if the path of the rule matches the given path in the same way as the generated route does.
+func matchesPath(rule gatewayv1beta1.Rule, path string) bool {
+	if IsCatchAllPath(rule) {
+		return true
+	}
+
+	switch GetPathMatchType(rule) {
+	case gatewayv1beta1.PathMatchExact:
+		return rule.Path == path
+	case gatewayv1beta1.PathMatchPrefix:
+		return strings.HasPrefix(path, rule.Path)
+	default:
+		// Invalid regular expressions are reported by the validation.
+		re, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", rule.Path))
+		if err != nil {
+			return false
+		}
+		return re.MatchString(path)
+	}
+}
+
+func generateOverlappingPathsWarning(overlaps []PathOverlap) string {
+	description := "Warning: The paths of the following rules overlap, so requests might not be handled by the expected rule:"
+	for _, overlap := range overlaps {
+		description += fmt.Sprintf("\n%s and %s", overlap.Path, overlap.OverlappingPath)
+	}
+
+	return description
+}
@@ -0,0 +1,51 @@
+package processing
+
+import (
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DetectOverlappingPaths", func() {
+	It("should detect overlapping paths", func() {
+		rules := []gatewayv1beta1.Rule{
+			{Path: "/api/.*"},
+			{Path: "/api/v1"},
+			{Path: "/img", PathMatchType: gatewayv1beta1.PathMatchPrefix},
+			{Path: "/img/icons", PathMatchType: gatewayv1beta1.PathMatchExact},
+		}
+
+		overlaps := DetectOverlappingPaths(rules)
+
+		Expect(overlaps).To(Equal([]PathOverlap{
+			{Path: "/api/.*", OverlappingPath: "/api/v1"},
+			{Path: "/img", OverlappingPath: "/img/icons"},
+		}))
+	})
+
+	It("should detect the overlap of a catch-all path with every other path", func() {
+		rules := []gatewayv1beta1.Rule{
+			{Path: "/headers"},
+			{Path: "/*"},
+		}
+
+		overlaps := DetectOverlappingPaths(rules)
+
+		Expect(overlaps).To(Equal([]PathOverlap{{Path: "/headers", OverlappingPath: "/*"}}))
+	})
+
+	It("should not detect disjoint paths", func() {
+		rules := []gatewayv1beta1.Rule{
+			{Path: "/api/v1/.*"},
+			{Path: "/api/v2"},
+			{Path: "/img", PathMatchType: gatewayv1beta1.PathMatchExact},
+			{Path: "/img/icons", PathMatchType: gatewayv1beta1.PathMatchPrefix},
+			{Path: "/headers", Methods: []string{"GET"}},
+			{Path: "/headers", Methods: []string{"POST"}},
+		}
+
+		overlaps := DetectOverlappingPaths(rules)
+
+		Expect(overlaps).To(BeEmpty())
+	})
+})
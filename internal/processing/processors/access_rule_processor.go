@@ -3,6 +3,7 @@ package processors
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
 	"github.com/kyma-project/api-gateway/internal/builders"
@@ -115,8 +116,8 @@ func GenerateAccessRule(api *gatewayv1beta1.APIRule, rule gatewayv1beta1.Rule, a
 		GenerateName(namePrefix).
 		Namespace(namespace).
 		Spec(builders.AccessRuleSpec().From(GenerateAccessRuleSpec(api, rule, accessStrategies, defaultDomainName))).
-		Label(processing.OwnerLabel, fmt.Sprintf("%s.%s", api.ObjectMeta.Name, api.ObjectMeta.Namespace)).
-		Label(processing.OwnerLabelv1alpha1, fmt.Sprintf("%s.%s", api.ObjectMeta.Name, api.ObjectMeta.Namespace))
+		Label(processing.OwnerLabel, processing.GetOwnerLabelValue(api)).
+		Label(processing.OwnerLabelv1alpha1, processing.GetOwnerLabelValue(api))
 
 	for k, v := range additionalLabels {
 		arBuilder.Label(k, v)
@@ -128,7 +129,7 @@ func GenerateAccessRule(api *gatewayv1beta1.APIRule, rule gatewayv1beta1.Rule, a
 func GenerateAccessRuleSpec(api *gatewayv1beta1.APIRule, rule gatewayv1beta1.Rule, accessStrategies []*gatewayv1beta1.Authenticator, defaultDomainName string) *rulev1alpha1.RuleSpec {
 	accessRuleSpec := builders.AccessRuleSpec().
 		Match(builders.Match().
-			URL(fmt.Sprintf("<http|https>://%s<%s>", helpers.GetHostWithDomain(*api.Spec.Host, defaultDomainName), rule.Path)).
+			URL(fmt.Sprintf("<http|https>://%s<%s>", getAccessRuleHost(helpers.GetHostWithDomain(helpers.NormalizeHost(*api.Spec.Host), defaultDomainName)), processing.GetAccessRulePath(rule))).
 			Methods(rule.Methods)).
 		Authorizer(builders.Authorizer().Handler(builders.Handler().
 			Name("allow"))).
@@ -145,3 +146,13 @@ func GenerateAccessRuleSpec(api *gatewayv1beta1.APIRule, rule gatewayv1beta1.Rul
 			URL(fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", *api.Spec.Service.Name, serviceNamespace, int(*api.Spec.Service.Port)))).Get()
 	}
 }
+
+// getAccessRuleHost returns the host in the URL of the access rule. The wildcard label of wildcard hosts is replaced by a
+// regular expression that matches the subdomains like the wildcard host of the Virtual Service.
+func getAccessRuleHost(host string) string {
+	if helpers.IsWildcardHost(host) {
+		return "<[^/]+>." + strings.TrimPrefix(host, helpers.WildcardHostPrefix)
+	}
+
+	return host
+}
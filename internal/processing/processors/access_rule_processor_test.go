@@ -28,9 +28,9 @@ var _ = Describe("Access Rule Processor", func() {
 			Creator: mockCreator{
 				createMock: func() map[string]*rulev1alpha1.Rule {
 					return map[string]*rulev1alpha1.Rule{
-						"<http|https>://myService.myDomain.com<path>": builders.AccessRule().Spec(
+						"<http|https>://myservice.mydomain.com<path>": builders.AccessRule().Spec(
 							builders.AccessRuleSpec().Match(
-								builders.Match().URL("<http|https>://myService.myDomain.com<path>"))).Get(),
+								builders.Match().URL("<http|https>://myservice.mydomain.com<path>"))).Get(),
 					}
 				},
 			},
@@ -94,9 +94,9 @@ var _ = Describe("Access Rule Processor", func() {
 			Creator: mockCreator{
 				createMock: func() map[string]*rulev1alpha1.Rule {
 					return map[string]*rulev1alpha1.Rule{
-						"<http|https>://myService.myDomain.com<path>": builders.AccessRule().Spec(
+						"<http|https>://myservice.mydomain.com<path>": builders.AccessRule().Spec(
 							builders.AccessRuleSpec().Match(
-								builders.Match().URL("<http|https>://myService.myDomain.com<path>"))).Get(),
+								builders.Match().URL("<http|https>://myservice.mydomain.com<path>"))).Get(),
 					}
 				},
 			},
@@ -223,9 +223,9 @@ var _ = Describe("Access Rule Processor", func() {
 				Creator: mockCreator{
 					createMock: func() map[string]*rulev1alpha1.Rule {
 						return map[string]*rulev1alpha1.Rule{
-							"<http|https>://myService.myDomain.com<newPath>": builders.AccessRule().Spec(
+							"<http|https>://myservice.mydomain.com<newPath>": builders.AccessRule().Spec(
 								builders.AccessRuleSpec().Match(
-									builders.Match().URL("<http|https>://myService.myDomain.com<newPath>"))).Get(),
+									builders.Match().URL("<http|https>://myservice.mydomain.com<newPath>"))).Get(),
 						}
 					},
 				},
@@ -243,7 +243,7 @@ var _ = Describe("Access Rule Processor", func() {
 				"Obj": PointTo(MatchFields(IgnoreExtras, Fields{
 					"Spec": MatchFields(IgnoreExtras, Fields{
 						"Match": PointTo(MatchFields(IgnoreExtras, Fields{
-							"URL": Equal("<http|https>://myService.myDomain.com<newPath>"),
+							"URL": Equal("<http|https>://myservice.mydomain.com<newPath>"),
 						})),
 					}),
 				})),
@@ -254,7 +254,7 @@ var _ = Describe("Access Rule Processor", func() {
 				"Obj": PointTo(MatchFields(IgnoreExtras, Fields{
 					"Spec": MatchFields(IgnoreExtras, Fields{
 						"Match": PointTo(MatchFields(IgnoreExtras, Fields{
-							"URL": Equal("<http|https>://myService.myDomain.com<oldPath>"),
+							"URL": Equal("<http|https>://myservice.mydomain.com<oldPath>"),
 						})),
 					}),
 				})),
@@ -265,6 +265,27 @@ var _ = Describe("Access Rule Processor", func() {
 	})
 })
 
+var _ = Describe("GenerateAccessRuleSpec", func() {
+	DescribeTable("should match the URL of the host",
+		func(host string, expectedURL string) {
+			// given
+			noop := []*gatewayv1beta1.Authenticator{{Handler: &gatewayv1beta1.Handler{Name: "noop"}}}
+			rule := GetRuleFor("/headers", ApiMethods, []*gatewayv1beta1.Mutator{}, noop)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+			apiRule.Spec.Host = &host
+
+			// when
+			spec := processors.GenerateAccessRuleSpec(apiRule, rule, noop, "example.com")
+
+			// then
+			Expect(spec.Match.URL).To(Equal(expectedURL))
+		},
+		Entry("fully qualified host", "myservice.apps.example.com", "<http|https>://myservice.apps.example.com</headers>"),
+		Entry("fully qualified wildcard host", "*.apps.example.com", "<http|https>://<[^/]+>.apps.example.com</headers>"),
+		Entry("short wildcard host completed with the default domain", "*.apps", "<http|https>://<[^/]+>.apps.example.com</headers>"),
+	)
+})
+
 type mockCreator struct {
 	createMock func() map[string]*rulev1alpha1.Rule
 }
@@ -0,0 +1,91 @@
+package processors
+
+import (
+	"context"
+	"fmt"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/processing"
+	"google.golang.org/protobuf/proto"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DestinationRuleProcessor is the generic processor that handles the Istio Destination Rules in the reconciliation of API Rule.
+type DestinationRuleProcessor struct {
+	Creator DestinationRuleCreator
+}
+
+// DestinationRuleCreator provides the creation of DestinationRules using the configuration in the given APIRule.
+// The key of the map is expected to be the key returned by GetDestinationRuleKey.
+type DestinationRuleCreator interface {
+	Create(api *gatewayv1beta1.APIRule) map[string]*networkingv1beta1.DestinationRule
+}
+
+func (r DestinationRuleProcessor) EvaluateReconciliation(ctx context.Context, client ctrlclient.Client, apiRule *gatewayv1beta1.APIRule) ([]*processing.ObjectChange, error) {
+	desired := r.getDesiredState(apiRule)
+	actual, err := r.getActualState(ctx, client, apiRule)
+	if err != nil {
+		return make([]*processing.ObjectChange, 0), err
+	}
+
+	changes := r.getObjectChanges(desired, actual)
+
+	return changes, nil
+}
+
+func (r DestinationRuleProcessor) getDesiredState(api *gatewayv1beta1.APIRule) map[string]*networkingv1beta1.DestinationRule {
+	return r.Creator.Create(api)
+}
+
+func (r DestinationRuleProcessor) getActualState(ctx context.Context, client ctrlclient.Client, api *gatewayv1beta1.APIRule) (map[string]*networkingv1beta1.DestinationRule, error) {
+	labels := processing.GetOwnerLabels(api)
+
+	var drList networkingv1beta1.DestinationRuleList
+	if err := client.List(ctx, &drList, ctrlclient.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+
+	destinationRules := make(map[string]*networkingv1beta1.DestinationRule)
+	for _, dr := range drList.Items {
+		destinationRules[GetDestinationRuleKey(dr)] = dr
+	}
+
+	return destinationRules, nil
+}
+
+func (r DestinationRuleProcessor) getObjectChanges(desiredDrs map[string]*networkingv1beta1.DestinationRule, actualDrs map[string]*networkingv1beta1.DestinationRule) []*processing.ObjectChange {
+	var changes []*processing.ObjectChange
+
+	for key, desired := range desiredDrs {
+		actual, exists := actualDrs[key]
+		if !exists {
+			changes = append(changes, processing.NewObjectCreateAction(desired))
+			continue
+		}
+
+		// An update is only necessary if the Destination Rule has changed, to avoid writing the object in every reconciliation.
+		if !proto.Equal(&actual.Spec, &desired.Spec) {
+			actual.Spec = *desired.Spec.DeepCopy()
+			changes = append(changes, processing.NewObjectUpdateAction(actual))
+		}
+	}
+
+	for key, actual := range actualDrs {
+		if _, exists := desiredDrs[key]; !exists {
+			changes = append(changes, processing.NewObjectDeleteAction(actual))
+		}
+	}
+
+	return changes
+}
+
+// GetDestinationRuleKey returns the key of the Destination Rule, which is unique for the host in a namespace.
+func GetDestinationRuleKey(dr *networkingv1beta1.DestinationRule) string {
+	namespace := dr.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	return fmt.Sprintf("%s:%s", dr.Spec.Host, namespace)
+}
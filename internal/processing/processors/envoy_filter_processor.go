@@ -0,0 +1,94 @@
+package processors
+
+import (
+	"context"
+	"fmt"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/processing"
+	"google.golang.org/protobuf/proto"
+	"istio.io/api/networking/v1alpha3"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// EnvoyFilterProcessor is the generic processor that handles the Istio Envoy Filters in the reconciliation of API Rule.
+type EnvoyFilterProcessor struct {
+	Creator EnvoyFilterCreator
+}
+
+// EnvoyFilterCreator provides the creation of EnvoyFilters using the configuration in the given APIRule.
+// The key of the map is expected to be the key returned by GetEnvoyFilterKey.
+type EnvoyFilterCreator interface {
+	Create(api *gatewayv1beta1.APIRule) map[string]*networkingv1alpha3.EnvoyFilter
+}
+
+func (r EnvoyFilterProcessor) EvaluateReconciliation(ctx context.Context, client ctrlclient.Client, apiRule *gatewayv1beta1.APIRule) ([]*processing.ObjectChange, error) {
+	desired := r.getDesiredState(apiRule)
+	actual, err := r.getActualState(ctx, client, apiRule)
+	if err != nil {
+		return make([]*processing.ObjectChange, 0), err
+	}
+
+	changes := r.getObjectChanges(desired, actual)
+
+	return changes, nil
+}
+
+func (r EnvoyFilterProcessor) getDesiredState(api *gatewayv1beta1.APIRule) map[string]*networkingv1alpha3.EnvoyFilter {
+	return r.Creator.Create(api)
+}
+
+func (r EnvoyFilterProcessor) getActualState(ctx context.Context, client ctrlclient.Client, api *gatewayv1beta1.APIRule) (map[string]*networkingv1alpha3.EnvoyFilter, error) {
+	labels := processing.GetOwnerLabels(api)
+
+	var efList networkingv1alpha3.EnvoyFilterList
+	if err := client.List(ctx, &efList, ctrlclient.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+
+	envoyFilters := make(map[string]*networkingv1alpha3.EnvoyFilter)
+	for _, ef := range efList.Items {
+		envoyFilters[GetEnvoyFilterKey(ef)] = ef
+	}
+
+	return envoyFilters, nil
+}
+
+func (r EnvoyFilterProcessor) getObjectChanges(desiredEfs map[string]*networkingv1alpha3.EnvoyFilter, actualEfs map[string]*networkingv1alpha3.EnvoyFilter) []*processing.ObjectChange {
+	var changes []*processing.ObjectChange
+
+	for key, desired := range desiredEfs {
+		actual, exists := actualEfs[key]
+		if !exists {
+			changes = append(changes, processing.NewObjectCreateAction(desired))
+			continue
+		}
+
+		// An update is only necessary if the Envoy Filter has changed, to avoid writing the object in every reconciliation.
+		if !proto.Equal(&actual.Spec, &desired.Spec) {
+			actual.Spec = *desired.Spec.DeepCopy()
+			changes = append(changes, processing.NewObjectUpdateAction(actual))
+		}
+	}
+
+	for key, actual := range actualEfs {
+		if _, exists := desiredEfs[key]; !exists {
+			changes = append(changes, processing.NewObjectDeleteAction(actual))
+		}
+	}
+
+	return changes
+}
+
+// GetEnvoyFilterKey returns the key of the Envoy Filter, which is unique for the route it configures in a namespace.
+func GetEnvoyFilterKey(ef *networkingv1alpha3.EnvoyFilter) string {
+	var route string
+	for _, patch := range ef.Spec.ConfigPatches {
+		if patch.ApplyTo == v1alpha3.EnvoyFilter_HTTP_ROUTE {
+			route = patch.Match.GetRouteConfiguration().GetVhost().GetRoute().GetName()
+		}
+	}
+
+	return fmt.Sprintf("%s:%s", route, ef.Namespace)
+}
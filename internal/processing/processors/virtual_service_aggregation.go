@@ -0,0 +1,209 @@
+package processors
+
+import (
+	"context"
+	"sort"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/helpers"
+	"github.com/kyma-project/api-gateway/internal/processing"
+	"istio.io/api/networking/v1beta1"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// getAggregatedState returns the Virtual Service that aggregates the routes of all API Rules sharing the hosts and
+// gateways of the API Rule and the actual aggregated Virtual Service. The returned changes clean up the Virtual Services
+// that were owned by the API Rule alone before the aggregation was enabled, and remove the routes of the API Rule from
+// the aggregated Virtual Services of its previous hosts and gateways.
+func (r VirtualServiceProcessor) getAggregatedState(ctx context.Context, client ctrlclient.Client, api *gatewayv1beta1.APIRule, desired *networkingv1beta1.VirtualService) (*networkingv1beta1.VirtualService, *networkingv1beta1.VirtualService, []*processing.ObjectChange, error) {
+	var cleanupChanges []*processing.ObjectChange
+	owned, duplicates, err := r.getActualState(ctx, client, api)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if owned != nil {
+		cleanupChanges = append(cleanupChanges, processing.NewObjectDeleteAction(owned))
+	}
+	for _, duplicate := range duplicates {
+		cleanupChanges = append(cleanupChanges, processing.NewObjectDeleteAction(duplicate))
+	}
+
+	name := processing.GetAggregatedVirtualServiceName(api)
+	actual, removeChanges, err := r.removeFromAggregatedVirtualServices(ctx, client, api, name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cleanupChanges = append(cleanupChanges, removeChanges...)
+
+	var apiRules gatewayv1beta1.APIRuleList
+	if err := client.List(ctx, &apiRules, ctrlclient.InNamespace(api.Namespace)); err != nil {
+		return nil, nil, nil, err
+	}
+
+	contributions := map[string]*networkingv1beta1.VirtualService{processing.GetOwnerLabelValue(api): desired}
+	for i := range apiRules.Items {
+		other := &apiRules.Items[i]
+		// The routes of paused API Rules are not served, so they are left out of the aggregated Virtual Service.
+		if other.Name == api.Name || other.DeletionTimestamp != nil || processing.IsPaused(other) || !processing.SharesVirtualService(api, other) {
+			continue
+		}
+
+		vs, err := r.getSharedDesiredState(ctx, client, other)
+		if err != nil {
+			// The actual routes of an API Rule that can't be created are kept, its own reconciliation reports the error.
+			vs = getActualContribution(actual, processing.GetOwnerLabelValue(other))
+		}
+		if vs != nil {
+			contributions[processing.GetOwnerLabelValue(other)] = vs
+		}
+	}
+
+	return newAggregatedVirtualService(name, desired, contributions), actual, cleanupChanges, nil
+}
+
+// removeFromAggregatedVirtualServices returns the aggregated Virtual Service with the given name and the changes that
+// remove the routes of the API Rule from all other aggregated Virtual Services in its namespace. Aggregated Virtual
+// Services without other owners are deleted.
+func (r VirtualServiceProcessor) removeFromAggregatedVirtualServices(ctx context.Context, client ctrlclient.Client, api *gatewayv1beta1.APIRule, name string) (*networkingv1beta1.VirtualService, []*processing.ObjectChange, error) {
+	owner := processing.GetOwnerLabelValue(api)
+	var aggregatedList networkingv1beta1.VirtualServiceList
+	if err := client.List(ctx, &aggregatedList, ctrlclient.InNamespace(api.Namespace), ctrlclient.MatchingLabels{helpers.AggregatedLabel: "true"}); err != nil {
+		return nil, nil, err
+	}
+
+	var actual *networkingv1beta1.VirtualService
+	var changes []*processing.ObjectChange
+	for _, vs := range aggregatedList.Items {
+		if name != "" && vs.Name == name {
+			actual = vs
+			continue
+		}
+		if _, ok := helpers.GetOwners(vs.Annotations)[owner]; !ok {
+			continue
+		}
+		if processing.RemoveOwner(vs, owner) {
+			changes = append(changes, processing.NewObjectUpdateAction(vs))
+		} else {
+			changes = append(changes, processing.NewObjectDeleteAction(vs))
+		}
+	}
+
+	return actual, changes, nil
+}
+
+// getSharedDesiredState returns the Virtual Service that would be created for another API Rule sharing the aggregated
+// Virtual Service, which is resolved the same way as in its own reconciliation.
+func (r VirtualServiceProcessor) getSharedDesiredState(ctx context.Context, client ctrlclient.Client, api *gatewayv1beta1.APIRule) (*networkingv1beta1.VirtualService, error) {
+	resolved, err := processing.ResolveServicePortNames(ctx, client, api)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err = processing.ResolveCorsOrigins(ctx, client, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getDesiredState(processing.ExpandProtocolPorts(processing.NormalizeRuleMethods(resolved)))
+}
+
+// getActualContribution returns a Virtual Service with the routes of the owner in the actual aggregated Virtual Service,
+// or nil if the owner has no routes in it.
+func getActualContribution(actual *networkingv1beta1.VirtualService, owner string) *networkingv1beta1.VirtualService {
+	if actual == nil {
+		return nil
+	}
+	names, ok := helpers.GetOwners(actual.Annotations)[owner]
+	if !ok {
+		return nil
+	}
+
+	vs := &networkingv1beta1.VirtualService{}
+	vs.Spec.Http = selectRoutesByName(actual.Spec.Http, names)
+	return vs
+}
+
+// newAggregatedVirtualService returns the Virtual Service with the routes of all contributing Virtual Services by owner.
+// The routes are ordered by owner, and catch-all routes are moved to the end, so they don't shadow the routes of the
+// owners that follow.
+func newAggregatedVirtualService(name string, desired *networkingv1beta1.VirtualService, contributions map[string]*networkingv1beta1.VirtualService) *networkingv1beta1.VirtualService {
+	owners := make([]string, 0, len(contributions))
+	for owner := range contributions {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	var routes []*v1beta1.HTTPRoute
+	routeNames := make(map[string][]string, len(owners))
+	var accessLogDisabledPaths, maxRequestHeadersBytes []string
+	for _, owner := range owners {
+		vs := contributions[owner]
+		routeNames[owner] = []string{}
+		for _, route := range vs.Spec.Http {
+			routes = append(routes, route.DeepCopy())
+			routeNames[owner] = append(routeNames[owner], route.Name)
+		}
+		accessLogDisabledPaths = append(accessLogDisabledPaths, vs.Annotations[processing.AccessLogDisabledPathsAnnotation])
+		maxRequestHeadersBytes = append(maxRequestHeadersBytes, vs.Annotations[processing.MaxRequestHeadersBytesAnnotation])
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		return !isCatchAllRoute(routes[i]) && isCatchAllRoute(routes[j])
+	})
+
+	aggregated := &networkingv1beta1.VirtualService{}
+	aggregated.Name = name
+	aggregated.Namespace = desired.Namespace
+	aggregated.Spec.Hosts = desired.Spec.Hosts
+	aggregated.Spec.Gateways = desired.Spec.Gateways
+	aggregated.Spec.ExportTo = desired.Spec.ExportTo
+	aggregated.Spec.Http = routes
+
+	aggregated.Labels = make(map[string]string, len(desired.Labels))
+	for k, v := range desired.Labels {
+		if k != processing.OwnerLabel && k != processing.OwnerLabelv1alpha1 {
+			aggregated.Labels[k] = v
+		}
+	}
+	aggregated.Labels[helpers.AggregatedLabel] = "true"
+
+	aggregated.Annotations = map[string]string{
+		helpers.OwnersAnnotation:           processing.GetOwnersAnnotation(routeNames),
+		processing.ManagedLabelsAnnotation: processing.GetManagedLabelsAnnotation(aggregated.Labels),
+	}
+	if value := processing.MergeAccessLogDisabledPathsAnnotations(accessLogDisabledPaths...); value != "" {
+		aggregated.Annotations[processing.AccessLogDisabledPathsAnnotation] = value
+	}
+	if value := processing.MergeMaxRequestHeadersBytesAnnotations(maxRequestHeadersBytes...); value != "" {
+		aggregated.Annotations[processing.MaxRequestHeadersBytesAnnotation] = value
+	}
+
+	return aggregated
+}
+
+// isCatchAllRoute returns true if the route matches the requests of all paths.
+func isCatchAllRoute(route *v1beta1.HTTPRoute) bool {
+	for _, match := range route.Match {
+		if match.Uri == nil || match.Uri.GetPrefix() == "/" || match.Uri.GetRegex() == "/*" || match.Uri.GetRegex() == "/.*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// selectRoutesByName returns the routes whose name is contained in the names.
+func selectRoutesByName(routes []*v1beta1.HTTPRoute, names []string) []*v1beta1.HTTPRoute {
+	selected := make(map[string]bool, len(names))
+	for _, name := range names {
+		selected[name] = true
+	}
+
+	var filtered []*v1beta1.HTTPRoute
+	for _, route := range routes {
+		if selected[route.Name] {
+			filtered = append(filtered, route)
+		}
+	}
+
+	return filtered
+}
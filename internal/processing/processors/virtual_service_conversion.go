@@ -0,0 +1,183 @@
+package processors
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"istio.io/api/networking/v1beta1"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+)
+
+const clusterLocalDomainSuffix = ".svc.cluster.local"
+
+// APIRuleSpecFromVirtualService reconstructs the hosts, gateways, exported namespaces, paths, services, timeouts and CORS
+// policies of the APIRule spec that a Virtual Service created by the controller represents. The other fields of the
+// APIRule, like the access strategies, can't be derived from the Virtual Service and are left empty. Routes that can't be
+// mapped to a rule are skipped, and the returned warnings describe the skipped routes and fields.
+func APIRuleSpecFromVirtualService(vs *networkingv1beta1.VirtualService) (gatewayv1beta1.APIRuleSpec, []string) {
+	var spec gatewayv1beta1.APIRuleSpec
+	var warnings []string
+
+	if len(vs.Spec.Hosts) > 0 {
+		host := vs.Spec.Hosts[0]
+		spec.Host = &host
+		spec.Hosts = append(spec.Hosts, vs.Spec.Hosts[1:]...)
+	}
+	if len(vs.Spec.Gateways) > 0 {
+		gateway := vs.Spec.Gateways[0]
+		spec.Gateway = &gateway
+		spec.Gateways = append(spec.Gateways, vs.Spec.Gateways[1:]...)
+	}
+	spec.ExportTo = append(spec.ExportTo, vs.Spec.ExportTo...)
+
+	for _, route := range vs.Spec.Http {
+		rule, ruleWarnings := ruleFromHTTPRoute(route)
+		for _, warning := range ruleWarnings {
+			warnings = append(warnings, fmt.Sprintf("http[%s]: %s", routeKey(route), warning))
+		}
+		if rule != nil {
+			spec.Rules = append(spec.Rules, *rule)
+		}
+	}
+
+	// The service is defined on spec level if all rules are routed to it, as it is the case for most APIRules.
+	if len(spec.Rules) > 0 && spec.Rules[0].Service != nil {
+		service := spec.Rules[0].Service
+		for _, rule := range spec.Rules {
+			if !reflect.DeepEqual(rule.Service, service) {
+				return spec, warnings
+			}
+		}
+		spec.Service = service
+		for i := range spec.Rules {
+			spec.Rules[i].Service = nil
+		}
+	}
+
+	return spec, warnings
+}
+
+// ruleFromHTTPRoute returns the rule that the route represents, or nil if the route can't be mapped to a rule.
+func ruleFromHTTPRoute(route *v1beta1.HTTPRoute) (*gatewayv1beta1.Rule, []string) {
+	switch {
+	case len(route.Match) != 1 || route.Match[0].Uri == nil:
+		return nil, []string{"route doesn't match exactly one URI"}
+	case route.Redirect != nil || route.DirectResponse != nil:
+		return nil, []string{"routes with a redirect or a direct response are not supported"}
+	case len(route.Route) == 0:
+		return nil, []string{"route doesn't define a destination"}
+	}
+
+	var warnings []string
+	rule := &gatewayv1beta1.Rule{}
+
+	match := route.Match[0]
+	switch {
+	case match.Uri.GetExact() != "":
+		rule.Path = match.Uri.GetExact()
+		rule.PathMatchType = gatewayv1beta1.PathMatchExact
+	case match.Uri.GetPrefix() == "/":
+		rule.Path = "/*"
+	case match.Uri.GetPrefix() != "":
+		rule.Path = match.Uri.GetPrefix()
+		rule.PathMatchType = gatewayv1beta1.PathMatchPrefix
+	default:
+		rule.Path = match.Uri.GetRegex()
+	}
+	switch {
+	case match.Method.GetExact() != "":
+		rule.Methods = []string{match.Method.GetExact()}
+	case match.Method.GetRegex() != "":
+		rule.Methods = strings.Split(match.Method.GetRegex(), "|")
+	}
+	// The OPTIONS method is added to the method match of the routes with a CORS policy for the preflight requests.
+	if route.CorsPolicy != nil && len(rule.Methods) > 1 {
+		var methods []string
+		for _, method := range rule.Methods {
+			if method != http.MethodOptions {
+				methods = append(methods, method)
+			}
+		}
+		rule.Methods = methods
+	}
+
+	services := make([]gatewayv1beta1.Service, 0, len(route.Route))
+	for _, destination := range route.Route {
+		service, err := serviceFromDestination(destination.Destination)
+		if err != nil {
+			return nil, []string{err.Error()}
+		}
+		services = append(services, service)
+	}
+	if len(route.Route) == 1 {
+		rule.Service = &services[0]
+		rule.Subset = route.Route[0].Destination.Subset
+	} else {
+		for i, destination := range route.Route {
+			rule.Destinations = append(rule.Destinations, gatewayv1beta1.WeightedService{Service: services[i], Weight: destination.Weight})
+		}
+	}
+
+	if route.Timeout != nil {
+		timeout := route.Timeout.AsDuration().String()
+		rule.Timeout = &timeout
+	}
+
+	if route.CorsPolicy != nil {
+		policy, corsWarnings := corsPolicyFromVirtualService(route.CorsPolicy)
+		rule.CorsPolicy = policy
+		warnings = append(warnings, corsWarnings...)
+	}
+
+	return rule, warnings
+}
+
+// serviceFromDestination returns the service of a destination in the cluster local domain.
+func serviceFromDestination(destination *v1beta1.Destination) (gatewayv1beta1.Service, error) {
+	if destination == nil || !strings.HasSuffix(destination.Host, clusterLocalDomainSuffix) {
+		return gatewayv1beta1.Service{}, fmt.Errorf("destination host %s is not a service of the cluster", destination.GetHost())
+	}
+
+	nameAndNamespace := strings.Split(strings.TrimSuffix(destination.Host, clusterLocalDomainSuffix), ".")
+	if len(nameAndNamespace) != 2 {
+		return gatewayv1beta1.Service{}, fmt.Errorf("destination host %s is not a service of the cluster", destination.Host)
+	}
+
+	service := gatewayv1beta1.Service{Name: &nameAndNamespace[0], Namespace: &nameAndNamespace[1]}
+	if destination.Port != nil {
+		port := destination.Port.Number
+		service.Port = &port
+	}
+
+	return service, nil
+}
+
+// corsPolicyFromVirtualService returns the allowed origins, methods, headers and the max age of the CORS policy. The
+// other fields of the CORS policy can't be configured in the APIRule.
+func corsPolicyFromVirtualService(policy *v1beta1.CorsPolicy) (*gatewayv1beta1.CorsPolicy, []string) {
+	var warnings []string
+	corsPolicy := &gatewayv1beta1.CorsPolicy{
+		AllowMethods: policy.AllowMethods,
+		AllowHeaders: policy.AllowHeaders,
+	}
+	if policy.MaxAge != nil {
+		maxAge := policy.MaxAge.AsDuration().String()
+		corsPolicy.MaxAge = &maxAge
+	}
+
+	for _, origin := range policy.AllowOrigins {
+		switch {
+		case origin.GetExact() != "":
+			corsPolicy.AllowOrigins = append(corsPolicy.AllowOrigins, origin.GetExact())
+		case origin.GetRegex() != "":
+			corsPolicy.AllowOriginsRegex = append(corsPolicy.AllowOriginsRegex, origin.GetRegex())
+		default:
+			warnings = append(warnings, fmt.Sprintf("allowed origin %s is not supported, only exact and regex origins are supported", stringMatchKey(origin)))
+		}
+	}
+
+	return corsPolicy, warnings
+}
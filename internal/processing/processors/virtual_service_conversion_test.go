@@ -0,0 +1,171 @@
+package processors_test
+
+import (
+	"context"
+	"time"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/builders"
+	. "github.com/kyma-project/api-gateway/internal/processing/internal/test"
+	"github.com/kyma-project/api-gateway/internal/processing/istio"
+	"github.com/kyma-project/api-gateway/internal/processing/processors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"istio.io/api/networking/v1beta1"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("APIRuleSpecFromVirtualService", func() {
+	allow := []*gatewayv1beta1.Authenticator{{Handler: &gatewayv1beta1.Handler{Name: "allow"}}}
+
+	createVirtualService := func(apiRule *gatewayv1beta1.APIRule) *networkingv1beta1.VirtualService {
+		config := GetTestConfig()
+		config.HTTPTimeout = pointer.Duration(180 * time.Second)
+		result, err := istio.NewVirtualServiceProcessor(config).EvaluateReconciliation(context.TODO(), GetFakeClient(), apiRule)
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+
+		return result[0].Obj.(*networkingv1beta1.VirtualService)
+	}
+
+	findRule := func(rules []gatewayv1beta1.Rule, path string) gatewayv1beta1.Rule {
+		for _, rule := range rules {
+			if rule.Path == path {
+				return rule
+			}
+		}
+		Fail("no rule found for path " + path)
+		return gatewayv1beta1.Rule{}
+	}
+
+	It("should reconstruct the host, gateway, service, paths, timeouts and CORS policies of the APIRule", func() {
+		// given
+		timeout := "2m"
+		imgRule := GetRuleFor("/img", ApiMethods, []*gatewayv1beta1.Mutator{}, allow)
+		headersRule := GetRuleFor("/headers", []string{"GET", "POST"}, []*gatewayv1beta1.Mutator{}, allow)
+		headersRule.PathMatchType = gatewayv1beta1.PathMatchExact
+		headersRule.Timeout = &timeout
+		headersRule.CorsPolicy = &gatewayv1beta1.CorsPolicy{
+			AllowOrigins:      []string{"https://example.com"},
+			AllowOriginsRegex: []string{"https://.*\\.example\\.com"},
+			AllowMethods:      []string{"GET"},
+		}
+		catchAllRule := GetRuleFor("/*", ApiMethods, []*gatewayv1beta1.Mutator{}, allow)
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{imgRule, headersRule, catchAllRule})
+
+		// when
+		spec, warnings := processors.APIRuleSpecFromVirtualService(createVirtualService(apiRule))
+
+		// then
+		Expect(warnings).To(BeEmpty())
+		Expect(*spec.Host).To(Equal(ServiceHost))
+		Expect(spec.Hosts).To(BeEmpty())
+		Expect(*spec.Gateway).To(Equal(ApiGateway))
+		Expect(*spec.Service.Name).To(Equal(ServiceName))
+		Expect(*spec.Service.Namespace).To(Equal(ApiNamespace))
+		Expect(*spec.Service.Port).To(Equal(ServicePort))
+		Expect(spec.Rules).To(HaveLen(3))
+
+		img := findRule(spec.Rules, "/img")
+		Expect(img.PathMatchType).To(BeEmpty())
+		Expect(img.Methods).To(Equal(ApiMethods))
+		Expect(img.Service).To(BeNil())
+		Expect(*img.Timeout).To(Equal("3m0s"))
+		Expect(img.CorsPolicy.AllowOriginsRegex).To(Equal([]string{".*"}))
+		Expect(img.CorsPolicy.AllowMethods).To(Equal(TestAllowMethods))
+		Expect(img.CorsPolicy.AllowHeaders).To(Equal(TestAllowHeaders))
+
+		headers := findRule(spec.Rules, "/headers")
+		Expect(headers.PathMatchType).To(Equal(gatewayv1beta1.PathMatchExact))
+		Expect(headers.Methods).To(Equal([]string{"GET", "POST"}))
+		Expect(*headers.Timeout).To(Equal("2m0s"))
+		Expect(headers.CorsPolicy.AllowOrigins).To(Equal([]string{"https://example.com"}))
+		Expect(headers.CorsPolicy.AllowOriginsRegex).To(Equal([]string{"https://.*\\.example\\.com"}))
+		Expect(headers.CorsPolicy.AllowMethods).To(Equal([]string{"GET"}))
+
+		catchAll := findRule(spec.Rules, "/*")
+		Expect(catchAll.PathMatchType).To(BeEmpty())
+		Expect(catchAll.Methods).To(Equal(ApiMethods))
+	})
+
+	It("should reconstruct the services of the rules if the rules are routed to different services", func() {
+		// given
+		otherServiceName := "other-service"
+		otherServiceNamespace := "other-namespace"
+		otherServicePort := uint32(9090)
+		otherServiceRule := GetRuleWithServiceFor("/other", ApiMethods, []*gatewayv1beta1.Mutator{}, allow,
+			&gatewayv1beta1.Service{Name: &otherServiceName, Namespace: &otherServiceNamespace, Port: &otherServicePort})
+		weightedRule := GetRuleFor("/weighted", ApiMethods, []*gatewayv1beta1.Mutator{}, allow)
+		weightedRule.Destinations = []gatewayv1beta1.WeightedService{
+			{Service: gatewayv1beta1.Service{Name: &ServiceName, Port: &ServicePort}, Weight: 80},
+			{Service: gatewayv1beta1.Service{Name: &otherServiceName, Namespace: &otherServiceNamespace, Port: &otherServicePort}, Weight: 20},
+		}
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{GetRuleFor("/img", ApiMethods, []*gatewayv1beta1.Mutator{}, allow), otherServiceRule, weightedRule})
+
+		// when
+		spec, warnings := processors.APIRuleSpecFromVirtualService(createVirtualService(apiRule))
+
+		// then
+		Expect(warnings).To(BeEmpty())
+		Expect(spec.Service).To(BeNil())
+		Expect(spec.Rules).To(HaveLen(3))
+
+		img := findRule(spec.Rules, "/img")
+		Expect(*img.Service.Name).To(Equal(ServiceName))
+		Expect(*img.Service.Namespace).To(Equal(ApiNamespace))
+
+		other := findRule(spec.Rules, "/other")
+		Expect(*other.Service.Name).To(Equal(otherServiceName))
+		Expect(*other.Service.Namespace).To(Equal(otherServiceNamespace))
+		Expect(*other.Service.Port).To(Equal(otherServicePort))
+
+		weighted := findRule(spec.Rules, "/weighted")
+		Expect(weighted.Service).To(BeNil())
+		Expect(weighted.Destinations).To(HaveLen(2))
+		Expect(*weighted.Destinations[0].Name).To(Equal(ServiceName))
+		Expect(*weighted.Destinations[0].Namespace).To(Equal(ApiNamespace))
+		Expect(weighted.Destinations[0].Weight).To(Equal(int32(80)))
+		Expect(*weighted.Destinations[1].Name).To(Equal(otherServiceName))
+		Expect(weighted.Destinations[1].Weight).To(Equal(int32(20)))
+	})
+
+	It("should return the rules of the routes that can be mapped and warnings for the other routes", func() {
+		// given
+		vs := builders.VirtualService().Name("test-vs").Spec(builders.VirtualServiceSpec().
+			Host("example.com").
+			Host("other.example.com").
+			Gateway("kyma-system/kyma-gateway").
+			HTTP(builders.HTTPRoute().
+				Match(builders.MatchRequest().Uri().Prefix("/api")).
+				Route(builders.RouteDestination().Host("example-service.some-namespace.svc.cluster.local").Port(8080)).
+				CorsPolicy(builders.CorsPolicy().AllowOrigins(&v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Prefix{Prefix: "https://"}})).
+				Timeout(time.Second * 30)).
+			HTTP(builders.HTTPRoute().
+				Match(builders.MatchRequest().Uri().Exact("/old")).
+				Redirect(builders.HTTPRedirect().Uri("/new"))).
+			HTTP(builders.HTTPRoute().
+				Match(builders.MatchRequest().Uri().Regex("/external")).
+				Route(builders.RouteDestination().Host("httpbin.org").Port(443)))).
+			Get()
+
+		// when
+		spec, warnings := processors.APIRuleSpecFromVirtualService(vs)
+
+		// then
+		Expect(*spec.Host).To(Equal("example.com"))
+		Expect(spec.Hosts).To(Equal([]string{"other.example.com"}))
+		Expect(*spec.Gateway).To(Equal("kyma-system/kyma-gateway"))
+		Expect(*spec.Service.Name).To(Equal("example-service"))
+		Expect(spec.Rules).To(HaveLen(1))
+		Expect(spec.Rules[0].Path).To(Equal("/api"))
+		Expect(spec.Rules[0].PathMatchType).To(Equal(gatewayv1beta1.PathMatchPrefix))
+		Expect(*spec.Rules[0].Timeout).To(Equal("30s"))
+		Expect(spec.Rules[0].CorsPolicy.AllowOrigins).To(BeEmpty())
+		Expect(warnings).To(Equal([]string{
+			"http[prefix:/api]: allowed origin prefix:https:// is not supported, only exact and regex origins are supported",
+			"http[exact:/old]: routes with a redirect or a direct response are not supported",
+			"http[regex:/external]: destination host httpbin.org is not a service of the cluster",
+		}))
+	})
+})
@@ -0,0 +1,78 @@
+package processors
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kyma-project/api-gateway/internal/processing"
+)
+
+// VirtualServiceCreatorFactory returns the VirtualServiceCreator of a handler for the given configuration.
+type VirtualServiceCreatorFactory func(config processing.ReconciliationConfig) VirtualServiceCreator
+
+var (
+	virtualServiceCreatorsMu sync.RWMutex
+	virtualServiceCreators   = make(map[string]VirtualServiceCreatorFactory)
+)
+
+// RegisterVirtualServiceCreator registers the factory of the VirtualServiceCreator for the handler, a factory that was
+// registered before for the handler is replaced. The handler packages register their creators when they are
+// initialized, so this package doesn't depend on them.
+func RegisterVirtualServiceCreator(handler string, factory VirtualServiceCreatorFactory) {
+	virtualServiceCreatorsMu.Lock()
+	defer virtualServiceCreatorsMu.Unlock()
+
+	virtualServiceCreators[handler] = factory
+}
+
+// NewVirtualServiceCreator returns the VirtualServiceCreator that is registered for the handler, or an error if no
+// creator is registered for the handler.
+func NewVirtualServiceCreator(handler string, config processing.ReconciliationConfig) (VirtualServiceCreator, error) {
+	virtualServiceCreatorsMu.RLock()
+	factory, ok := virtualServiceCreators[handler]
+	virtualServiceCreatorsMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("no VirtualServiceCreator is registered for handler %s, registered handlers are %v", handler, RegisteredVirtualServiceCreators())
+	}
+
+	return factory(config), nil
+}
+
+// RegisteredVirtualServiceCreators returns the sorted names of the handlers with a registered VirtualServiceCreator.
+func RegisteredVirtualServiceCreators() []string {
+	virtualServiceCreatorsMu.RLock()
+	defer virtualServiceCreatorsMu.RUnlock()
+
+	handlers := make([]string, 0, len(virtualServiceCreators))
+	for handler := range virtualServiceCreators {
+		handlers = append(handlers, handler)
+	}
+	sort.Strings(handlers)
+
+	return handlers
+}
+
+// NewVirtualServiceProcessor returns a VirtualServiceProcessor with the given creator and the options of the
+// configuration.
+func NewVirtualServiceProcessor(config processing.ReconciliationConfig, creator VirtualServiceCreator) VirtualServiceProcessor {
+	return VirtualServiceProcessor{
+		Creator:         creator,
+		Metrics:         config.Metrics,
+		Recorder:        config.Recorder,
+		UseOwnerIndex:   config.UseOwnerIndex,
+		AggregateByHost: config.AggregateByHost,
+	}
+}
+
+// NewVirtualServiceProcessorForHandler returns a VirtualServiceProcessor with the VirtualServiceCreator that is
+// registered for the handler.
+func NewVirtualServiceProcessorForHandler(handler string, config processing.ReconciliationConfig) (VirtualServiceProcessor, error) {
+	creator, err := NewVirtualServiceCreator(handler, config)
+	if err != nil {
+		return VirtualServiceProcessor{}, err
+	}
+
+	return NewVirtualServiceProcessor(config, creator), nil
+}
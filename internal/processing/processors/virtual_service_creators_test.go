@@ -0,0 +1,96 @@
+package processors_test
+
+import (
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/helpers"
+	"github.com/kyma-project/api-gateway/internal/processing"
+	. "github.com/kyma-project/api-gateway/internal/processing/internal/test"
+	_ "github.com/kyma-project/api-gateway/internal/processing/istio"
+	_ "github.com/kyma-project/api-gateway/internal/processing/ory"
+	"github.com/kyma-project/api-gateway/internal/processing/processors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+)
+
+var _ = Describe("VirtualServiceCreator registry", func() {
+	jwt := []*gatewayv1beta1.Authenticator{{Handler: &gatewayv1beta1.Handler{Name: "jwt"}}}
+
+	It("should register the creators of the Istio and Ory handlers", func() {
+		Expect(processors.RegisteredVirtualServiceCreators()).To(ContainElements(helpers.JWT_HANDLER_ISTIO, helpers.JWT_HANDLER_ORY))
+	})
+
+	DescribeTable("should return the creator of the handler",
+		func(handler string, expectedDestinationHost string) {
+			// given
+			rule := GetRuleFor(HeadersApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, jwt)
+			apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{rule})
+
+			// when
+			creator, err := processors.NewVirtualServiceCreator(handler, GetTestConfig())
+
+			// then
+			Expect(err).NotTo(HaveOccurred())
+
+			vs, err := creator.Create(apiRule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vs.Spec.Http).To(HaveLen(1))
+			Expect(vs.Spec.Http[0].Route[0].Destination.Host).To(Equal(expectedDestinationHost))
+		},
+		// The Istio handler routes JWT secured requests to the service, the Ory handler routes them to Oathkeeper.
+		Entry("Istio handler", helpers.JWT_HANDLER_ISTIO, ServiceName+"."+ApiNamespace+".svc.cluster.local"),
+		Entry("Ory handler", helpers.JWT_HANDLER_ORY, OathkeeperSvc),
+	)
+
+	It("should return an error for an unknown handler", func() {
+		// when
+		creator, err := processors.NewVirtualServiceCreator("unknown", GetTestConfig())
+
+		// then
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("no VirtualServiceCreator is registered for handler unknown"))
+		Expect(creator).To(BeNil())
+	})
+
+	It("should return a processor with the registered creator and the options of the configuration", func() {
+		// given
+		config := GetTestConfig()
+		config.AggregateByHost = true
+		config.UseOwnerIndex = true
+
+		// when
+		processor, err := processors.NewVirtualServiceProcessorForHandler(helpers.JWT_HANDLER_ISTIO, config)
+
+		// then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(processor.Creator).NotTo(BeNil())
+		Expect(processor.AggregateByHost).To(BeTrue())
+		Expect(processor.UseOwnerIndex).To(BeTrue())
+	})
+
+	It("should replace the creator of a handler that is registered again", func() {
+		// given
+		vs := &networkingv1beta1.VirtualService{}
+		processors.RegisterVirtualServiceCreator("test", func(_ processing.ReconciliationConfig) processors.VirtualServiceCreator {
+			return nil
+		})
+		processors.RegisterVirtualServiceCreator("test", func(_ processing.ReconciliationConfig) processors.VirtualServiceCreator {
+			return staticVirtualServiceCreator{vs: vs}
+		})
+
+		// when
+		creator, err := processors.NewVirtualServiceCreator("test", GetTestConfig())
+
+		// then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creator.Create(&gatewayv1beta1.APIRule{})).To(BeIdenticalTo(vs))
+	})
+})
+
+type staticVirtualServiceCreator struct {
+	vs *networkingv1beta1.VirtualService
+}
+
+func (c staticVirtualServiceCreator) Create(_ *gatewayv1beta1.APIRule) (*networkingv1beta1.VirtualService, error) {
+	return c.vs, nil
+}
@@ -0,0 +1,192 @@
+package processors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"istio.io/api/networking/v1beta1"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	"k8s.io/utils/strings/slices"
+)
+
+// VirtualServiceDiff is the field level difference between the actual and the desired spec of a Virtual Service.
+// HTTP routes are identified by their matches, so a route with changed matches is reported as removed and added.
+type VirtualServiceDiff struct {
+	AddedRoutes   []string
+	RemovedRoutes []string
+	ChangedFields []FieldChange
+}
+
+// FieldChange is a field of the Virtual Service spec whose actual value differs from the desired value.
+type FieldChange struct {
+	Path    string
+	Actual  interface{}
+	Desired interface{}
+}
+
+type routeField struct {
+	name  string
+	equal func(actual *v1beta1.HTTPRoute, desired *v1beta1.HTTPRoute) bool
+	get   func(route *v1beta1.HTTPRoute) interface{}
+}
+
+var routeFields = []routeField{
+	{"route", func(a, d *v1beta1.HTTPRoute) bool { return equalMessages(a.Route, d.Route) }, func(r *v1beta1.HTTPRoute) interface{} { return r.Route }},
+	{"redirect", func(a, d *v1beta1.HTTPRoute) bool { return proto.Equal(a.Redirect, d.Redirect) }, func(r *v1beta1.HTTPRoute) interface{} { return r.Redirect }},
+	{"directResponse", func(a, d *v1beta1.HTTPRoute) bool { return proto.Equal(a.DirectResponse, d.DirectResponse) }, func(r *v1beta1.HTTPRoute) interface{} { return r.DirectResponse }},
+	{"rewrite", func(a, d *v1beta1.HTTPRoute) bool { return proto.Equal(a.Rewrite, d.Rewrite) }, func(r *v1beta1.HTTPRoute) interface{} { return r.Rewrite }},
+	{"timeout", func(a, d *v1beta1.HTTPRoute) bool { return proto.Equal(a.Timeout, d.Timeout) }, func(r *v1beta1.HTTPRoute) interface{} { return r.Timeout.AsDuration() }},
+	{"retries", func(a, d *v1beta1.HTTPRoute) bool { return proto.Equal(a.Retries, d.Retries) }, func(r *v1beta1.HTTPRoute) interface{} { return r.Retries }},
+	{"fault", func(a, d *v1beta1.HTTPRoute) bool { return proto.Equal(a.Fault, d.Fault) }, func(r *v1beta1.HTTPRoute) interface{} { return r.Fault }},
+	{"mirror", func(a, d *v1beta1.HTTPRoute) bool { return proto.Equal(a.Mirror, d.Mirror) }, func(r *v1beta1.HTTPRoute) interface{} { return r.Mirror }},
+	{"mirrorPercentage", func(a, d *v1beta1.HTTPRoute) bool { return proto.Equal(a.MirrorPercentage, d.MirrorPercentage) }, func(r *v1beta1.HTTPRoute) interface{} { return r.MirrorPercentage }},
+	{"corsPolicy", func(a, d *v1beta1.HTTPRoute) bool { return proto.Equal(a.CorsPolicy, d.CorsPolicy) }, func(r *v1beta1.HTTPRoute) interface{} { return r.CorsPolicy }},
+	{"headers", func(a, d *v1beta1.HTTPRoute) bool { return proto.Equal(a.Headers, d.Headers) }, func(r *v1beta1.HTTPRoute) interface{} { return r.Headers }},
+}
+
+// DiffVirtualServices returns the difference between the actual and the desired spec of a Virtual Service. If the
+// actual Virtual Service is nil, all desired routes are reported as added.
+func DiffVirtualServices(desired *networkingv1beta1.VirtualService, actual *networkingv1beta1.VirtualService) VirtualServiceDiff {
+	actualSpec := &v1beta1.VirtualService{}
+	if actual != nil {
+		actualSpec = &actual.Spec
+	}
+
+	var diff VirtualServiceDiff
+	if !slices.Equal(actualSpec.Hosts, desired.Spec.Hosts) {
+		diff.ChangedFields = append(diff.ChangedFields, FieldChange{Path: "hosts", Actual: actualSpec.Hosts, Desired: desired.Spec.Hosts})
+	}
+	if !slices.Equal(actualSpec.Gateways, desired.Spec.Gateways) {
+		diff.ChangedFields = append(diff.ChangedFields, FieldChange{Path: "gateways", Actual: actualSpec.Gateways, Desired: desired.Spec.Gateways})
+	}
+	if !slices.Equal(actualSpec.ExportTo, desired.Spec.ExportTo) {
+		diff.ChangedFields = append(diff.ChangedFields, FieldChange{Path: "exportTo", Actual: actualSpec.ExportTo, Desired: desired.Spec.ExportTo})
+	}
+
+	actualRoutes := make(map[string]*v1beta1.HTTPRoute, len(actualSpec.Http))
+	var actualOrder []string
+	for _, route := range actualSpec.Http {
+		key := routeKey(route)
+		actualRoutes[key] = route
+		actualOrder = append(actualOrder, key)
+	}
+
+	desiredRoutes := make(map[string]bool, len(desired.Spec.Http))
+	var desiredOrder []string
+	for _, route := range desired.Spec.Http {
+		key := routeKey(route)
+		desiredRoutes[key] = true
+
+		actualRoute, ok := actualRoutes[key]
+		if !ok {
+			diff.AddedRoutes = append(diff.AddedRoutes, key)
+			continue
+		}
+		desiredOrder = append(desiredOrder, key)
+
+		for _, field := range routeFields {
+			if !field.equal(actualRoute, route) {
+				diff.ChangedFields = append(diff.ChangedFields, FieldChange{
+					Path:    fmt.Sprintf("http[%s].%s", key, field.name),
+					Actual:  field.get(actualRoute),
+					Desired: field.get(route),
+				})
+			}
+		}
+	}
+
+	var retainedOrder []string
+	for _, key := range actualOrder {
+		if desiredRoutes[key] {
+			retainedOrder = append(retainedOrder, key)
+		} else {
+			diff.RemovedRoutes = append(diff.RemovedRoutes, key)
+		}
+	}
+
+	// Istio evaluates the routes in order, so moving a route changes the routing even if the route itself is unchanged.
+	if !slices.Equal(retainedOrder, desiredOrder) {
+		diff.ChangedFields = append(diff.ChangedFields, FieldChange{Path: "http", Actual: retainedOrder, Desired: desiredOrder})
+	}
+
+	return diff
+}
+
+// IsEmpty returns true if the actual and the desired spec of the Virtual Service don't differ.
+func (d VirtualServiceDiff) IsEmpty() bool {
+	return len(d.AddedRoutes) == 0 && len(d.RemovedRoutes) == 0 && len(d.ChangedFields) == 0
+}
+
+// String returns the diff with one line per added route, removed route and changed field.
+func (d VirtualServiceDiff) String() string {
+	var lines []string
+	for _, key := range d.AddedRoutes {
+		lines = append(lines, fmt.Sprintf("+ http[%s]", key))
+	}
+	for _, key := range d.RemovedRoutes {
+		lines = append(lines, fmt.Sprintf("- http[%s]", key))
+	}
+	for _, change := range d.ChangedFields {
+		lines = append(lines, fmt.Sprintf("~ %s: %v -> %v", change.Path, change.Actual, change.Desired))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// routeKey returns an identifier of the route that is built from the URI, method, header and query parameter matches.
+func routeKey(route *v1beta1.HTTPRoute) string {
+	var matches []string
+	for _, match := range route.Match {
+		parts := []string{stringMatchKey(match.Uri)}
+		if match.Method != nil {
+			parts = append(parts, "method="+stringMatchKey(match.Method))
+		}
+		for _, name := range sortedKeys(match.Headers) {
+			parts = append(parts, fmt.Sprintf("header:%s=%s", name, stringMatchKey(match.Headers[name])))
+		}
+		for _, name := range sortedKeys(match.QueryParams) {
+			parts = append(parts, fmt.Sprintf("query:%s=%s", name, stringMatchKey(match.QueryParams[name])))
+		}
+		matches = append(matches, strings.Join(parts, ","))
+	}
+
+	return strings.Join(matches, "|")
+}
+
+func stringMatchKey(match *v1beta1.StringMatch) string {
+	switch {
+	case match == nil:
+		return "*"
+	case match.GetExact() != "":
+		return "exact:" + match.GetExact()
+	case match.GetPrefix() != "":
+		return "prefix:" + match.GetPrefix()
+	default:
+		return "regex:" + match.GetRegex()
+	}
+}
+
+func sortedKeys(m map[string]*v1beta1.StringMatch) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func equalMessages[T proto.Message](actual []T, desired []T) bool {
+	if len(actual) != len(desired) {
+		return false
+	}
+	for i := range actual {
+		if !proto.Equal(actual[i], desired[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,105 @@
+package processors_test
+
+import (
+	"time"
+
+	"github.com/kyma-project/api-gateway/internal/builders"
+	"github.com/kyma-project/api-gateway/internal/processing/processors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"istio.io/api/networking/v1beta1"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+)
+
+var _ = Describe("DiffVirtualServices", func() {
+	virtualService := func(timeout time.Duration, allowOrigin string, paths ...string) *networkingv1beta1.VirtualService {
+		spec := builders.VirtualServiceSpec().Host("example.com").Gateway("kyma-system/kyma-gateway")
+		for _, path := range paths {
+			spec.HTTP(builders.HTTPRoute().
+				Match(builders.MatchRequest().Uri().Regex(path)).
+				Route(builders.RouteDestination().Host("example-service.some-namespace.svc.cluster.local").Port(8080)).
+				CorsPolicy(builders.CorsPolicy().AllowOrigins(&v1beta1.StringMatch{MatchType: &v1beta1.StringMatch_Exact{Exact: allowOrigin}})).
+				Timeout(timeout))
+		}
+		return builders.VirtualService().Name("test-vs").Spec(spec).Get()
+	}
+
+	It("should return an empty diff for equal specs", func() {
+		// given
+		desired := virtualService(time.Second*30, "https://example.com", "/img", "/headers")
+		actual := virtualService(time.Second*30, "https://example.com", "/img", "/headers")
+
+		// when
+		diff := processors.DiffVirtualServices(desired, actual)
+
+		// then
+		Expect(diff.IsEmpty()).To(BeTrue())
+		Expect(diff.String()).To(BeEmpty())
+	})
+
+	It("should report all routes as added if there is no actual Virtual Service", func() {
+		// given
+		desired := virtualService(time.Second*30, "https://example.com", "/img", "/headers")
+
+		// when
+		diff := processors.DiffVirtualServices(desired, nil)
+
+		// then
+		Expect(diff.AddedRoutes).To(Equal([]string{"regex:/img", "regex:/headers"}))
+		Expect(diff.RemovedRoutes).To(BeEmpty())
+		Expect(diff.ChangedFields).To(HaveLen(2))
+		Expect(diff.ChangedFields[0].Path).To(Equal("hosts"))
+		Expect(diff.ChangedFields[1].Path).To(Equal("gateways"))
+	})
+
+	It("should report the added and removed routes", func() {
+		// given
+		desired := virtualService(time.Second*30, "https://example.com", "/img", "/headers")
+		actual := virtualService(time.Second*30, "https://example.com", "/img", "/old")
+
+		// when
+		diff := processors.DiffVirtualServices(desired, actual)
+
+		// then
+		Expect(diff.AddedRoutes).To(Equal([]string{"regex:/headers"}))
+		Expect(diff.RemovedRoutes).To(Equal([]string{"regex:/old"}))
+		Expect(diff.ChangedFields).To(BeEmpty())
+		Expect(diff.String()).To(Equal("+ http[regex:/headers]\n- http[regex:/old]"))
+	})
+
+	It("should report the changed timeout and CORS policy of a route", func() {
+		// given
+		desired := virtualService(time.Second*60, "https://desired.com", "/img")
+		actual := virtualService(time.Second*30, "https://actual.com", "/img")
+
+		// when
+		diff := processors.DiffVirtualServices(desired, actual)
+
+		// then
+		Expect(diff.AddedRoutes).To(BeEmpty())
+		Expect(diff.RemovedRoutes).To(BeEmpty())
+		Expect(diff.ChangedFields).To(HaveLen(2))
+		Expect(diff.ChangedFields[0].Path).To(Equal("http[regex:/img].timeout"))
+		Expect(diff.ChangedFields[0].Actual).To(Equal(time.Second * 30))
+		Expect(diff.ChangedFields[0].Desired).To(Equal(time.Second * 60))
+		Expect(diff.ChangedFields[1].Path).To(Equal("http[regex:/img].corsPolicy"))
+		Expect(diff.ChangedFields[1].Actual.(*v1beta1.CorsPolicy).AllowOrigins[0].GetExact()).To(Equal("https://actual.com"))
+		Expect(diff.ChangedFields[1].Desired.(*v1beta1.CorsPolicy).AllowOrigins[0].GetExact()).To(Equal("https://desired.com"))
+		Expect(diff.String()).To(HavePrefix("~ http[regex:/img].timeout: 30s -> 1m0s\n~ http[regex:/img].corsPolicy: "))
+	})
+
+	It("should report the changed order of the routes", func() {
+		// given
+		desired := virtualService(time.Second*30, "https://example.com", "/img", "/headers")
+		actual := virtualService(time.Second*30, "https://example.com", "/headers", "/img")
+
+		// when
+		diff := processors.DiffVirtualServices(desired, actual)
+
+		// then
+		Expect(diff.ChangedFields).To(HaveLen(1))
+		Expect(diff.ChangedFields[0].Path).To(Equal("http"))
+		Expect(diff.ChangedFields[0].Actual).To(Equal([]string{"regex:/headers", "regex:/img"}))
+		Expect(diff.ChangedFields[0].Desired).To(Equal([]string{"regex:/img", "regex:/headers"}))
+	})
+})
@@ -2,16 +2,37 @@ package processors
 
 import (
 	"context"
+	"reflect"
 
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/helpers"
 	"github.com/kyma-project/api-gateway/internal/processing"
+	"google.golang.org/protobuf/proto"
 	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // VirtualServiceProcessor is the generic processor that handles the Virtual Service in the reconciliation of API Rule.
 type VirtualServiceProcessor struct {
 	Creator VirtualServiceCreator
+	// Metrics are optional, no metrics are recorded if not set.
+	Metrics processing.ReconciliationMetrics
+	// Recorder is optional, no events are recorded if not set.
+	Recorder record.EventRecorder
+	// UseOwnerIndex looks up the actual Virtual Services by the processing.OwnerIndex instead of the owner labels,
+	// the index must be registered with the field indexer of the client.
+	UseOwnerIndex bool
+	// AggregateByHost merges the routes of all API Rules in the namespace that share the hosts and gateways into a
+	// single Virtual Service, which is owned by all of them.
+	AggregateByHost bool
+}
+
+var virtualServiceEventReasons = map[string]string{
+	"create": "VirtualServiceCreated",
+	"update": "VirtualServiceUpdated",
+	"delete": "VirtualServiceDeleted",
 }
 
 // VirtualServiceCreator provides the creation of a Virtual Service using the configuration in the given APIRule.
@@ -20,45 +41,211 @@ type VirtualServiceCreator interface {
 }
 
 func (r VirtualServiceProcessor) EvaluateReconciliation(ctx context.Context, client ctrlclient.Client, apiRule *gatewayv1beta1.APIRule) ([]*processing.ObjectChange, error) {
+	// The Virtual Service of a paused API Rule is removed, so its requests are no longer served until it is unpaused.
+	if processing.IsPaused(apiRule) {
+		changes, err := r.getPausedChanges(ctx, client, apiRule)
+		if err != nil {
+			return make([]*processing.ObjectChange, 0), err
+		}
+		r.observeChanges(changes)
+		r.recordChanges(apiRule, changes)
+
+		return changes, nil
+	}
+
 	desired, err := r.getDesiredState(apiRule)
 	if err != nil {
+		if r.Metrics != nil {
+			r.Metrics.ObserveVirtualServiceCreateError()
+		}
+		if r.Recorder != nil {
+			r.Recorder.Eventf(apiRule, corev1.EventTypeWarning, "VirtualServiceCreateFailed", "Failed to create the Virtual Service: %s", err)
+		}
 		return make([]*processing.ObjectChange, 0), err
 	}
 
-	actual, err := r.getActualState(ctx, client, apiRule)
+	var actual *networkingv1beta1.VirtualService
+	var cleanupChanges []*processing.ObjectChange
+	if r.AggregateByHost {
+		desired, actual, cleanupChanges, err = r.getAggregatedState(ctx, client, apiRule, desired)
+	} else {
+		var duplicates []*networkingv1beta1.VirtualService
+		actual, duplicates, err = r.getActualState(ctx, client, apiRule)
+		// Only one Virtual Service is expected per API Rule, therefore all other owned Virtual Services are deleted
+		for _, duplicate := range duplicates {
+			cleanupChanges = append(cleanupChanges, processing.NewObjectDeleteAction(duplicate))
+		}
+	}
 	if err != nil {
 		return make([]*processing.ObjectChange, 0), err
 	}
 
-	changes := r.getObjectChanges(desired, actual)
+	var changes []*processing.ObjectChange
+	change := r.getObjectChanges(desired, actual)
+	if change != nil {
+		changes = append(changes, change)
+	}
+	changes = append(changes, cleanupChanges...)
+	r.observeChanges(changes)
+	r.recordChanges(apiRule, changes)
 
-	return []*processing.ObjectChange{changes}, nil
+	return changes, nil
+}
+
+// getPausedChanges returns the changes that remove the Virtual Services of a paused API Rule. The routes of the API Rule
+// are removed from the aggregated Virtual Services, which keep serving the routes of the other owners.
+func (r VirtualServiceProcessor) getPausedChanges(ctx context.Context, client ctrlclient.Client, apiRule *gatewayv1beta1.APIRule) ([]*processing.ObjectChange, error) {
+	var changes []*processing.ObjectChange
+	owned, duplicates, err := r.getActualState(ctx, client, apiRule)
+	if err != nil {
+		return nil, err
+	}
+	if owned != nil {
+		changes = append(changes, processing.NewObjectDeleteAction(owned))
+	}
+	for _, duplicate := range duplicates {
+		changes = append(changes, processing.NewObjectDeleteAction(duplicate))
+	}
+
+	if r.AggregateByHost {
+		_, aggregatedChanges, err := r.removeFromAggregatedVirtualServices(ctx, client, apiRule, "")
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, aggregatedChanges...)
+	}
+
+	return changes, nil
+}
+
+// observeChanges records the action of each change of a Virtual Service in the metrics, or "none" if no Virtual Service
+// changes.
+func (r VirtualServiceProcessor) observeChanges(changes []*processing.ObjectChange) {
+	if r.Metrics == nil {
+		return
+	}
+	if len(changes) == 0 {
+		r.Metrics.ObserveVirtualServiceChange("none")
+		return
+	}
+	for _, objectChange := range changes {
+		r.Metrics.ObserveVirtualServiceChange(objectChange.Action.String())
+	}
+}
+
+// recordChanges records an event on the API Rule for each change of a Virtual Service.
+func (r VirtualServiceProcessor) recordChanges(apiRule *gatewayv1beta1.APIRule, changes []*processing.ObjectChange) {
+	if r.Recorder == nil {
+		return
+	}
+	for _, objectChange := range changes {
+		r.Recorder.Eventf(apiRule, corev1.EventTypeNormal, virtualServiceEventReasons[objectChange.Action.String()], "Virtual Service %s: %s", getEventObjectName(objectChange.Obj), objectChange.Action)
+	}
+}
+
+// getEventObjectName returns the name of the object, or the prefix of the generated name if the object isn't created yet.
+func getEventObjectName(obj ctrlclient.Object) string {
+	if obj.GetName() != "" {
+		return obj.GetName()
+	}
+
+	return obj.GetGenerateName()
+}
+
+// GetDesiredState returns the Virtual Service that would be created for the APIRule without accessing the cluster.
+func (r VirtualServiceProcessor) GetDesiredState(api *gatewayv1beta1.APIRule) (*networkingv1beta1.VirtualService, error) {
+	return r.getDesiredState(api)
 }
 
 func (r VirtualServiceProcessor) getDesiredState(api *gatewayv1beta1.APIRule) (*networkingv1beta1.VirtualService, error) {
-	return r.Creator.Create(api)
+	vs, err := r.Creator.Create(api)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateVirtualService(vs); err != nil {
+		return nil, err
+	}
+
+	// The labels set by the creator are recorded, so they can be removed from the Virtual Service once they are no longer desired.
+	if managedLabels := processing.GetManagedLabelsAnnotation(vs.Labels); managedLabels != "" {
+		if vs.Annotations == nil {
+			vs.Annotations = make(map[string]string)
+		}
+		vs.Annotations[processing.ManagedLabelsAnnotation] = managedLabels
+	}
+
+	return vs, nil
 }
 
-func (r VirtualServiceProcessor) getActualState(ctx context.Context, client ctrlclient.Client, api *gatewayv1beta1.APIRule) (*networkingv1beta1.VirtualService, error) {
-	labels := processing.GetOwnerLabels(api)
+// getActualState returns the Virtual Service owned by the API Rule and all additional Virtual Services that are owned by
+// the same API Rule.
+func (r VirtualServiceProcessor) getActualState(ctx context.Context, client ctrlclient.Client, api *gatewayv1beta1.APIRule) (*networkingv1beta1.VirtualService, []*networkingv1beta1.VirtualService, error) {
+	var listOption ctrlclient.ListOption = ctrlclient.MatchingLabels(processing.GetOwnerLabels(api))
+	if r.UseOwnerIndex {
+		listOption = ctrlclient.MatchingFields{processing.OwnerIndex: processing.GetOwnerLabelValue(api)}
+	}
 
 	var vsList networkingv1beta1.VirtualServiceList
-	if err := client.List(ctx, &vsList, ctrlclient.MatchingLabels(labels)); err != nil {
-		return nil, err
+	if err := client.List(ctx, &vsList, listOption); err != nil {
+		return nil, nil, err
 	}
 
 	if len(vsList.Items) >= 1 {
-		return vsList.Items[0], nil
+		return vsList.Items[0], vsList.Items[1:], nil
 	} else {
-		return nil, nil
+		return nil, nil, nil
 	}
 }
 
 func (r VirtualServiceProcessor) getObjectChanges(desiredVs *networkingv1beta1.VirtualService, actualVs *networkingv1beta1.VirtualService) *processing.ObjectChange {
 	if actualVs != nil {
+		// Labels and annotations that were added by other controllers must survive the update, therefore only the
+		// managed ones are set on the actual Virtual Service. Labels that were managed before but are no longer desired
+		// are removed.
+		labels := mergeManagedMetadata(actualVs.Labels, desiredVs.Labels, processing.GetManagedLabels(actualVs.Annotations)...)
+		annotations := mergeManagedMetadata(actualVs.Annotations, desiredVs.Annotations, processing.AccessLogDisabledPathsAnnotation, processing.MaxRequestHeadersBytesAnnotation, processing.ManagedLabelsAnnotation, helpers.OwnersAnnotation)
+
+		// An update is only necessary if the Virtual Service has changed, to avoid writing the object in every reconciliation.
+		if proto.Equal(&actualVs.Spec, &desiredVs.Spec) && reflect.DeepEqual(labels, actualVs.Labels) && reflect.DeepEqual(annotations, actualVs.Annotations) {
+			return nil
+		}
+
 		actualVs.Spec = *desiredVs.Spec.DeepCopy()
+		actualVs.Labels = labels
+		actualVs.Annotations = annotations
 		return processing.NewObjectUpdateAction(actualVs)
 	} else {
 		return processing.NewObjectCreateAction(desiredVs)
 	}
 }
+
+// mergeManagedMetadata returns the actual metadata updated with the desired metadata. The optional managed keys are
+// removed if they are no longer desired.
+func mergeManagedMetadata(actual map[string]string, desired map[string]string, managedKeys ...string) map[string]string {
+	if len(desired) == 0 && !containsAnyKey(actual, managedKeys) {
+		return actual
+	}
+
+	merged := make(map[string]string, len(actual)+len(desired))
+	for k, v := range actual {
+		merged[k] = v
+	}
+	for _, k := range managedKeys {
+		delete(merged, k)
+	}
+	for k, v := range desired {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+func containsAnyKey(m map[string]string, keys []string) bool {
+	for _, k := range keys {
+		if _, ok := m[k]; ok {
+			return true
+		}
+	}
+
+	return false
+}
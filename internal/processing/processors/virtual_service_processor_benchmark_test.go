@@ -0,0 +1,104 @@
+package processors_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/kyma-project/api-gateway/internal/processing"
+	. "github.com/kyma-project/api-gateway/internal/processing/internal/test"
+	"github.com/kyma-project/api-gateway/internal/processing/processors"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const benchmarkVirtualServices = 5000
+
+func BenchmarkVirtualServiceLookupByOwnerLabels(b *testing.B) {
+	benchmarkVirtualServiceLookup(b, false)
+}
+
+func BenchmarkVirtualServiceLookupByOwnerIndex(b *testing.B) {
+	benchmarkVirtualServiceLookup(b, true)
+}
+
+func benchmarkVirtualServiceLookup(b *testing.B, useOwnerIndex bool) {
+	apiRule := GetAPIRuleFor(nil)
+	apiRule.ObjectMeta.Name = fmt.Sprintf("apirule-%d", benchmarkVirtualServices/2)
+	client := newCacheReader(b, benchmarkVirtualServices, apiRule.ObjectMeta.Namespace)
+	processor := processors.VirtualServiceProcessor{
+		Creator:       mockVirtualServiceCreator{},
+		UseOwnerIndex: useOwnerIndex,
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(result) != 1 || result[0].Obj.GetName() != apiRule.ObjectMeta.Name {
+			b.Fatalf("unexpected result of the lookup: %v", result)
+		}
+	}
+}
+
+// cacheReader mimics the cache of the manager, which returns copies of the cached objects and resolves a label selector
+// by filtering all cached objects and a field selector by a lookup in the registered index.
+type cacheReader struct {
+	ctrlclient.Client
+	indexer cache.Indexer
+}
+
+func newCacheReader(b *testing.B, count int, namespace string) cacheReader {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+		processing.OwnerIndex: func(obj interface{}) ([]string, error) {
+			return processing.IndexByOwner(obj.(ctrlclient.Object)), nil
+		},
+	})
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("apirule-%d", i)
+		err := indexer.Add(&networkingv1beta1.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{processing.OwnerLabelv1alpha1: fmt.Sprintf("%s.%s", name, namespace)},
+			},
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	return cacheReader{indexer: indexer}
+}
+
+func (c cacheReader) List(_ context.Context, list ctrlclient.ObjectList, opts ...ctrlclient.ListOption) error {
+	listOptions := (&ctrlclient.ListOptions{}).ApplyOptions(opts)
+
+	var objs []interface{}
+	if listOptions.FieldSelector != nil {
+		requirements := listOptions.FieldSelector.Requirements()
+		indexed, err := c.indexer.ByIndex(requirements[0].Field, requirements[0].Value)
+		if err != nil {
+			return err
+		}
+		objs = indexed
+	} else {
+		for _, obj := range c.indexer.List() {
+			if listOptions.LabelSelector.Matches(labels.Set(obj.(ctrlclient.Object).GetLabels())) {
+				objs = append(objs, obj)
+			}
+		}
+	}
+
+	vsList := list.(*networkingv1beta1.VirtualServiceList)
+	for _, obj := range objs {
+		vsList.Items = append(vsList.Items, obj.(*networkingv1beta1.VirtualService).DeepCopy())
+	}
+
+	return nil
+}
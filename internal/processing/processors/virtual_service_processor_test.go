@@ -14,6 +14,7 @@ import (
 	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -35,6 +36,22 @@ var _ = Describe("Virtual Service Processor", func() {
 		Expect(result[0].Action.String()).To(Equal("create"))
 	})
 
+	It("should return the desired virtual service without accessing the cluster", func() {
+		// given
+		apiRule := &gatewayv1beta1.APIRule{}
+
+		processor := processors.VirtualServiceProcessor{
+			Creator: mockVirtualServiceCreator{},
+		}
+
+		// when
+		vs, err := processor.GetDesiredState(apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(vs.Spec.Hosts).To(Equal([]string{"example.com"}))
+	})
+
 	It("should update virtual service when virtual service exists", func() {
 		// given
 		strategies := []*gatewayv1beta1.Authenticator{
@@ -76,11 +93,578 @@ var _ = Describe("Virtual Service Processor", func() {
 		Expect(result).To(HaveLen(1))
 		Expect(result[0].Action.String()).To(Equal("update"))
 	})
+
+	It("should not update virtual service when it has not changed", func() {
+		// given
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		allowRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+		rules := []gatewayv1beta1.Rule{allowRule}
+
+		apiRule := GetAPIRuleFor(rules)
+
+		creator := mockVirtualServiceCreator{}
+		vs, err := creator.Create(apiRule)
+		Expect(err).NotTo(HaveOccurred())
+		vs.Name = "vs"
+		vs.Labels = map[string]string{
+			processing.OwnerLabelv1alpha1: fmt.Sprintf("%s.%s", apiRule.ObjectMeta.Name, apiRule.ObjectMeta.Namespace),
+		}
+
+		scheme := runtime.NewScheme()
+		err = networkingv1beta1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vs).Build()
+
+		processor := processors.VirtualServiceProcessor{
+			Creator: creator,
+		}
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(BeEmpty())
+	})
+
+	It("should delete additional virtual services owned by the same API Rule", func() {
+		// given
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		allowRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+		rules := []gatewayv1beta1.Rule{allowRule}
+
+		apiRule := GetAPIRuleFor(rules)
+		ownerLabels := map[string]string{
+			processing.OwnerLabelv1alpha1: fmt.Sprintf("%s.%s", apiRule.ObjectMeta.Name, apiRule.ObjectMeta.Namespace),
+		}
+
+		vs := networkingv1beta1.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "vs",
+				Labels: ownerLabels,
+			},
+		}
+		duplicateVs := networkingv1beta1.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "duplicate-vs",
+				Labels: ownerLabels,
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		err := networkingv1beta1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&vs, &duplicateVs).Build()
+
+		processor := processors.VirtualServiceProcessor{
+			Creator: mockVirtualServiceCreator{},
+		}
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Action.String()).To(Equal("update"))
+		Expect(result[1].Action.String()).To(Equal("delete"))
+		Expect(result[1].Obj.GetName()).NotTo(Equal(result[0].Obj.GetName()))
+	})
+
+	It("should preserve foreign labels and annotations when virtual service is updated", func() {
+		// given
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		allowRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+		rules := []gatewayv1beta1.Rule{allowRule}
+
+		apiRule := GetAPIRuleFor(rules)
+		ownerLabelValue := fmt.Sprintf("%s.%s", apiRule.ObjectMeta.Name, apiRule.ObjectMeta.Namespace)
+
+		vs := networkingv1beta1.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					processing.OwnerLabelv1alpha1: ownerLabelValue,
+					"third-party-label":           "foreign",
+					"managed-label":               "outdated",
+				},
+				Annotations: map[string]string{
+					"third-party.io/annotation": "foreign",
+				},
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		err := networkingv1beta1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&vs).Build()
+
+		processor := processors.VirtualServiceProcessor{
+			Creator: mockLabeledVirtualServiceCreator{
+				labels: map[string]string{
+					processing.OwnerLabel:         ownerLabelValue,
+					processing.OwnerLabelv1alpha1: ownerLabelValue,
+					"managed-label":               "current",
+				},
+			},
+		}
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Action.String()).To(Equal("update"))
+
+		resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+		Expect(resultVs.Annotations).To(HaveKeyWithValue("third-party.io/annotation", "foreign"))
+		Expect(resultVs.Labels).To(HaveKeyWithValue("third-party-label", "foreign"))
+		Expect(resultVs.Labels).To(HaveKeyWithValue("managed-label", "current"))
+		Expect(resultVs.Labels).To(HaveKeyWithValue(processing.OwnerLabel, ownerLabelValue))
+		Expect(resultVs.Labels).To(HaveKeyWithValue(processing.OwnerLabelv1alpha1, ownerLabelValue))
+	})
+
+	It("should remove the managed labels that are no longer desired when virtual service is updated", func() {
+		// given
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		allowRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+		rules := []gatewayv1beta1.Rule{allowRule}
+
+		apiRule := GetAPIRuleFor(rules)
+		ownerLabelValue := fmt.Sprintf("%s.%s", apiRule.ObjectMeta.Name, apiRule.ObjectMeta.Namespace)
+
+		vs := networkingv1beta1.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					processing.OwnerLabelv1alpha1: ownerLabelValue,
+					"third-party-label":           "foreign",
+					"removed-label":               "value",
+				},
+				Annotations: map[string]string{
+					processing.ManagedLabelsAnnotation: fmt.Sprintf(`["%s","removed-label"]`, processing.OwnerLabelv1alpha1),
+				},
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		err := networkingv1beta1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&vs).Build()
+
+		processor := processors.VirtualServiceProcessor{
+			Creator: mockLabeledVirtualServiceCreator{
+				labels: map[string]string{
+					processing.OwnerLabelv1alpha1: ownerLabelValue,
+					"added-label":                 "value",
+				},
+			},
+		}
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Action.String()).To(Equal("update"))
+
+		resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+		Expect(resultVs.Labels).To(Equal(map[string]string{
+			processing.OwnerLabelv1alpha1: ownerLabelValue,
+			"third-party-label":           "foreign",
+			"added-label":                 "value",
+		}))
+		Expect(resultVs.Annotations).To(HaveKeyWithValue(processing.ManagedLabelsAnnotation, fmt.Sprintf(`["added-label","%s"]`, processing.OwnerLabelv1alpha1)))
+	})
+
+	It("should remove the access log and request headers size annotations when they are no longer desired", func() {
+		// given
+		strategies := []*gatewayv1beta1.Authenticator{
+			{
+				Handler: &gatewayv1beta1.Handler{
+					Name: "allow",
+				},
+			},
+		}
+
+		allowRule := GetRuleFor(ApiPath, ApiMethods, []*gatewayv1beta1.Mutator{}, strategies)
+		rules := []gatewayv1beta1.Rule{allowRule}
+
+		apiRule := GetAPIRuleFor(rules)
+		ownerLabelValue := fmt.Sprintf("%s.%s", apiRule.ObjectMeta.Name, apiRule.ObjectMeta.Namespace)
+
+		vs := networkingv1beta1.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					processing.OwnerLabelv1alpha1: ownerLabelValue,
+				},
+				Annotations: map[string]string{
+					"third-party.io/annotation":                 "foreign",
+					processing.AccessLogDisabledPathsAnnotation: `["/healthz"]`,
+					processing.MaxRequestHeadersBytesAnnotation: `{"/upload":8192}`,
+				},
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		err := networkingv1beta1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&vs).Build()
+
+		processor := processors.VirtualServiceProcessor{
+			Creator: mockLabeledVirtualServiceCreator{
+				labels: map[string]string{
+					processing.OwnerLabelv1alpha1: ownerLabelValue,
+				},
+			},
+		}
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Action.String()).To(Equal("update"))
+
+		resultVs := result[0].Obj.(*networkingv1beta1.VirtualService)
+		Expect(resultVs.Annotations).To(HaveKeyWithValue("third-party.io/annotation", "foreign"))
+		Expect(resultVs.Annotations).ToNot(HaveKey(processing.AccessLogDisabledPathsAnnotation))
+		Expect(resultVs.Annotations).ToNot(HaveKey(processing.MaxRequestHeadersBytesAnnotation))
+	})
+})
+
+var _ = Describe("Virtual Service Processor metrics", func() {
+	It("should record the create action when no virtual service exists", func() {
+		// given
+		metrics := &fakeReconciliationMetrics{changes: map[string]int{}}
+		processor := processors.VirtualServiceProcessor{
+			Creator: mockVirtualServiceCreator{},
+			Metrics: metrics,
+		}
+
+		// when
+		_, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), &gatewayv1beta1.APIRule{})
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(metrics.changes).To(Equal(map[string]int{"create": 1}))
+		Expect(metrics.createErrors).To(Equal(0))
+	})
+
+	It("should record the update and none actions for an existing virtual service", func() {
+		// given
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{})
+		ownerLabelValue := fmt.Sprintf("%s.%s", apiRule.ObjectMeta.Name, apiRule.ObjectMeta.Namespace)
+		vs := builders.VirtualService().Name("vs").Label(processing.OwnerLabelv1alpha1, ownerLabelValue).
+			Spec(builders.VirtualServiceSpec().Host("example.com").Gateway("kyma-system/kyma-gateway")).Get()
+
+		scheme := runtime.NewScheme()
+		err := networkingv1beta1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vs).Build()
+
+		metrics := &fakeReconciliationMetrics{changes: map[string]int{}}
+		changedProcessor := processors.VirtualServiceProcessor{
+			Creator: mockLabeledVirtualServiceCreator{labels: map[string]string{"foo": "bar"}},
+			Metrics: metrics,
+		}
+		unchangedProcessor := processors.VirtualServiceProcessor{
+			Creator: mockLabeledVirtualServiceCreator{},
+			Metrics: metrics,
+		}
+
+		// when
+		_, err = changedProcessor.EvaluateReconciliation(context.TODO(), client, apiRule)
+		Expect(err).To(BeNil())
+		_, err = unchangedProcessor.EvaluateReconciliation(context.TODO(), client, apiRule)
+		Expect(err).To(BeNil())
+
+		// then
+		Expect(metrics.changes).To(Equal(map[string]int{"update": 1, "none": 1}))
+	})
+
+	It("should record the actions of the deleted duplicates of the virtual service", func() {
+		// given
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{})
+		ownerLabelValue := fmt.Sprintf("%s.%s", apiRule.ObjectMeta.Name, apiRule.ObjectMeta.Namespace)
+		vs := builders.VirtualService().Name("vs").Label(processing.OwnerLabelv1alpha1, ownerLabelValue).
+			Spec(builders.VirtualServiceSpec().Host("example.com").Gateway("kyma-system/kyma-gateway")).Get()
+		duplicate := builders.VirtualService().Name("duplicate-vs").Label(processing.OwnerLabelv1alpha1, ownerLabelValue).
+			Spec(builders.VirtualServiceSpec().Host("example.com").Gateway("kyma-system/kyma-gateway")).Get()
+
+		scheme := runtime.NewScheme()
+		err := networkingv1beta1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vs, duplicate).Build()
+
+		metrics := &fakeReconciliationMetrics{changes: map[string]int{}}
+		processor := processors.VirtualServiceProcessor{
+			Creator: mockLabeledVirtualServiceCreator{labels: map[string]string{"foo": "bar"}},
+			Metrics: metrics,
+		}
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(2))
+		Expect(metrics.changes).To(Equal(map[string]int{"update": 1, "delete": 1}))
+	})
+
+	It("should record the error when the desired virtual service can't be created", func() {
+		// given
+		metrics := &fakeReconciliationMetrics{changes: map[string]int{}}
+		processor := processors.VirtualServiceProcessor{
+			Creator: failingVirtualServiceCreator{},
+			Metrics: metrics,
+		}
+
+		// when
+		_, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), &gatewayv1beta1.APIRule{})
+
+		// then
+		Expect(err).To(HaveOccurred())
+		Expect(metrics.changes).To(BeEmpty())
+		Expect(metrics.createErrors).To(Equal(1))
+	})
+
+	It("should record the error when the desired virtual service is invalid", func() {
+		// given
+		metrics := &fakeReconciliationMetrics{changes: map[string]int{}}
+		processor := processors.VirtualServiceProcessor{
+			Creator: invalidVirtualServiceCreator{},
+			Metrics: metrics,
+		}
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), &gatewayv1beta1.APIRule{})
+
+		// then
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("the Virtual Service is invalid: no gateways defined"))
+		Expect(result).To(BeEmpty())
+		Expect(metrics.changes).To(BeEmpty())
+		Expect(metrics.createErrors).To(Equal(1))
+	})
+})
+
+var _ = Describe("Virtual Service Processor events", func() {
+	It("should record a normal event with the generated name for the created virtual service", func() {
+		// given
+		recorder := record.NewFakeRecorder(10)
+		processor := processors.VirtualServiceProcessor{
+			Creator:  mockGeneratedNameVirtualServiceCreator{},
+			Recorder: recorder,
+		}
+
+		// when
+		_, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), &gatewayv1beta1.APIRule{})
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).To(HaveLen(1))
+		Expect(<-recorder.Events).To(Equal("Normal VirtualServiceCreated Virtual Service test-vs-: create"))
+	})
+
+	It("should record normal events for the updated and the deleted duplicate virtual services", func() {
+		// given
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{})
+		ownerLabelValue := fmt.Sprintf("%s.%s", apiRule.ObjectMeta.Name, apiRule.ObjectMeta.Namespace)
+		vs := builders.VirtualService().Name("vs-1").Label(processing.OwnerLabelv1alpha1, ownerLabelValue).Get()
+		duplicate := builders.VirtualService().Name("vs-2").Label(processing.OwnerLabelv1alpha1, ownerLabelValue).Get()
+
+		scheme := runtime.NewScheme()
+		err := networkingv1beta1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vs, duplicate).Build()
+
+		recorder := record.NewFakeRecorder(10)
+		processor := processors.VirtualServiceProcessor{
+			Creator:  mockVirtualServiceCreator{},
+			Recorder: recorder,
+		}
+
+		// when
+		_, err = processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).To(HaveLen(2))
+		Expect(<-recorder.Events).To(Equal("Normal VirtualServiceUpdated Virtual Service vs-1: update"))
+		Expect(<-recorder.Events).To(Equal("Normal VirtualServiceDeleted Virtual Service vs-2: delete"))
+	})
+
+	It("should not record an event when the virtual service is unchanged", func() {
+		// given
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{})
+		ownerLabelValue := fmt.Sprintf("%s.%s", apiRule.ObjectMeta.Name, apiRule.ObjectMeta.Namespace)
+		vs := builders.VirtualService().Name("vs").Label(processing.OwnerLabelv1alpha1, ownerLabelValue).
+			Spec(builders.VirtualServiceSpec().Host("example.com").Gateway("kyma-system/kyma-gateway")).Get()
+
+		scheme := runtime.NewScheme()
+		err := networkingv1beta1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vs).Build()
+
+		recorder := record.NewFakeRecorder(10)
+		processor := processors.VirtualServiceProcessor{
+			Creator:  mockLabeledVirtualServiceCreator{},
+			Recorder: recorder,
+		}
+
+		// when
+		_, err = processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should look up the virtual service of the API Rule by the owner index", func() {
+		// given
+		apiRule := GetAPIRuleFor([]gatewayv1beta1.Rule{})
+		ownerLabelValue := fmt.Sprintf("%s.%s", apiRule.ObjectMeta.Name, apiRule.ObjectMeta.Namespace)
+
+		owned := networkingv1beta1.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "owned-vs",
+				Labels: map[string]string{processing.OwnerLabelv1alpha1: ownerLabelValue},
+			},
+		}
+		other := networkingv1beta1.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "other-vs",
+				Labels: map[string]string{processing.OwnerLabelv1alpha1: "other-apirule." + apiRule.ObjectMeta.Namespace},
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		err := networkingv1beta1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+
+		client := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(&other, &owned).
+			WithIndex(&networkingv1beta1.VirtualService{}, processing.OwnerIndex, processing.IndexByOwner).
+			Build()
+
+		processor := processors.VirtualServiceProcessor{
+			Creator:       mockVirtualServiceCreator{},
+			UseOwnerIndex: true,
+		}
+
+		// when
+		result, err := processor.EvaluateReconciliation(context.TODO(), client, apiRule)
+
+		// then
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Action.String()).To(Equal("update"))
+		Expect(result[0].Obj.GetName()).To(Equal("owned-vs"))
+	})
+
+	It("should record a warning event when the desired virtual service can't be created", func() {
+		// given
+		recorder := record.NewFakeRecorder(10)
+		processor := processors.VirtualServiceProcessor{
+			Creator:  failingVirtualServiceCreator{},
+			Recorder: recorder,
+		}
+
+		// when
+		_, err := processor.EvaluateReconciliation(context.TODO(), GetFakeClient(), &gatewayv1beta1.APIRule{})
+
+		// then
+		Expect(err).To(HaveOccurred())
+		Expect(recorder.Events).To(HaveLen(1))
+		Expect(<-recorder.Events).To(Equal("Warning VirtualServiceCreateFailed Failed to create the Virtual Service: no rules defined"))
+	})
 })
 
+type fakeReconciliationMetrics struct {
+	changes      map[string]int
+	createErrors int
+}
+
+func (m *fakeReconciliationMetrics) ObserveVirtualServiceChange(action string) {
+	m.changes[action]++
+}
+
+func (m *fakeReconciliationMetrics) ObserveVirtualServiceCreateError() {
+	m.createErrors++
+}
+
+type failingVirtualServiceCreator struct {
+}
+
+func (r failingVirtualServiceCreator) Create(_ *gatewayv1beta1.APIRule) (*networkingv1beta1.VirtualService, error) {
+	return nil, fmt.Errorf("no rules defined")
+}
+
+type invalidVirtualServiceCreator struct {
+}
+
+func (r invalidVirtualServiceCreator) Create(_ *gatewayv1beta1.APIRule) (*networkingv1beta1.VirtualService, error) {
+	return builders.VirtualService().Spec(builders.VirtualServiceSpec().Host("example.com")).Get(), nil
+}
+
 type mockVirtualServiceCreator struct {
 }
 
 func (r mockVirtualServiceCreator) Create(_ *gatewayv1beta1.APIRule) (*networkingv1beta1.VirtualService, error) {
-	return builders.VirtualService().Get(), nil
+	return builders.VirtualService().Spec(builders.VirtualServiceSpec().Host("example.com").Gateway("kyma-system/kyma-gateway")).Get(), nil
+}
+
+type mockGeneratedNameVirtualServiceCreator struct {
+}
+
+func (r mockGeneratedNameVirtualServiceCreator) Create(_ *gatewayv1beta1.APIRule) (*networkingv1beta1.VirtualService, error) {
+	return builders.VirtualService().GenerateName("test-vs-").Spec(builders.VirtualServiceSpec().Host("example.com").Gateway("kyma-system/kyma-gateway")).Get(), nil
+}
+
+type mockLabeledVirtualServiceCreator struct {
+	labels map[string]string
+}
+
+func (r mockLabeledVirtualServiceCreator) Create(_ *gatewayv1beta1.APIRule) (*networkingv1beta1.VirtualService, error) {
+	vsBuilder := builders.VirtualService()
+	for k, v := range r.labels {
+		vsBuilder.Label(k, v)
+	}
+	return vsBuilder.Spec(builders.VirtualServiceSpec().Host("example.com").Gateway("kyma-system/kyma-gateway")).Get(), nil
 }
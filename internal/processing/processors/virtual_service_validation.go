@@ -0,0 +1,36 @@
+package processors
+
+import (
+	"fmt"
+	"strings"
+
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+)
+
+// ValidateVirtualService checks the fields of the Virtual Service that are required by the Istio CRD schema, so a
+// misconfigured Virtual Service is reported before it is rejected by the Istio webhook.
+func ValidateVirtualService(vs *networkingv1beta1.VirtualService) error {
+	var problems []string
+	if len(vs.Spec.Hosts) == 0 {
+		problems = append(problems, "no hosts defined")
+	}
+	for i, host := range vs.Spec.Hosts {
+		if host == "" {
+			problems = append(problems, fmt.Sprintf("hosts[%d] is empty", i))
+		}
+	}
+	if len(vs.Spec.Gateways) == 0 {
+		problems = append(problems, "no gateways defined")
+	}
+	for _, route := range vs.Spec.Http {
+		if len(route.Route) == 0 && route.Redirect == nil && route.DirectResponse == nil {
+			problems = append(problems, fmt.Sprintf("http[%s] defines neither a destination nor a redirect or direct response", routeKey(route)))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("the Virtual Service is invalid: %s", strings.Join(problems, ", "))
+	}
+
+	return nil
+}
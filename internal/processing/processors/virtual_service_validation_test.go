@@ -0,0 +1,54 @@
+package processors_test
+
+import (
+	"github.com/kyma-project/api-gateway/internal/builders"
+	"github.com/kyma-project/api-gateway/internal/processing/processors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+)
+
+var _ = Describe("ValidateVirtualService", func() {
+	DescribeTable("should validate the required fields",
+		func(vs *networkingv1beta1.VirtualService, expectedMessage string) {
+			// when
+			err := processors.ValidateVirtualService(vs)
+
+			// then
+			if expectedMessage == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(expectedMessage))
+			}
+		},
+		Entry("valid Virtual Service", builders.VirtualService().Spec(builders.VirtualServiceSpec().
+			Host("example.com").
+			Gateway("kyma-system/kyma-gateway").
+			HTTP(builders.HTTPRoute().Match(builders.MatchRequest().Uri().Regex("/img")).
+				Route(builders.RouteDestination().Host("example-service.some-namespace.svc.cluster.local").Port(8080))).
+			HTTP(builders.HTTPRoute().Match(builders.MatchRequest().Uri().Regex("/old")).
+				Redirect(builders.HTTPRedirect().Uri("/new"))).
+			HTTP(builders.HTTPRoute().Match(builders.MatchRequest().Uri().Regex("/health")).
+				DirectResponse(builders.HTTPDirectResponse().Status(200)))).Get(),
+			""),
+		Entry("missing host", builders.VirtualService().Spec(builders.VirtualServiceSpec().
+			Gateway("kyma-system/kyma-gateway")).Get(),
+			"the Virtual Service is invalid: no hosts defined"),
+		Entry("empty host", builders.VirtualService().Spec(builders.VirtualServiceSpec().
+			Host("").
+			Gateway("kyma-system/kyma-gateway")).Get(),
+			"the Virtual Service is invalid: hosts[0] is empty"),
+		Entry("missing gateway", builders.VirtualService().Spec(builders.VirtualServiceSpec().
+			Host("example.com")).Get(),
+			"the Virtual Service is invalid: no gateways defined"),
+		Entry("route without destination, redirect or direct response", builders.VirtualService().Spec(builders.VirtualServiceSpec().
+			Host("example.com").
+			Gateway("kyma-system/kyma-gateway").
+			HTTP(builders.HTTPRoute().Match(builders.MatchRequest().Uri().Regex("/img")))).Get(),
+			"the Virtual Service is invalid: http[regex:/img] defines neither a destination nor a redirect or direct response"),
+		Entry("all required fields missing", builders.VirtualService().Spec(builders.VirtualServiceSpec().
+			HTTP(builders.HTTPRoute().Match(builders.MatchRequest().Uri().Regex("/img")))).Get(),
+			"the Virtual Service is invalid: no hosts defined, no gateways defined, http[regex:/img] defines neither a destination nor a redirect or direct response"),
+	)
+})
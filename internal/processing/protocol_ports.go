@@ -0,0 +1,76 @@
+package processing
+
+import (
+	"sort"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+)
+
+// GRPCContentTypePrefix is the prefix of the content type of gRPC requests, e.g. application/grpc+proto.
+const GRPCContentTypePrefix = "application/grpc"
+
+// ExpandProtocolPorts returns the APIRule with each rule that defines protocol ports replaced by one rule per protocol
+// port, so the processors create a separate route for each protocol. The gRPC rule matches the content type of gRPC
+// requests and is placed before the HTTP rule. The given APIRule is not changed, if no rule defines protocol ports it is
+// returned as is.
+func ExpandProtocolPorts(api *gatewayv1beta1.APIRule) *gatewayv1beta1.APIRule {
+	if !hasProtocolPorts(api) {
+		return api
+	}
+
+	expanded := api.DeepCopy()
+	var rules []gatewayv1beta1.Rule
+	for _, rule := range expanded.Spec.Rules {
+		if len(rule.ProtocolPorts) == 0 {
+			rules = append(rules, rule)
+			continue
+		}
+
+		// The service is validated to be defined on the rule or spec level.
+		service := rule.Service
+		if service == nil {
+			service = expanded.Spec.Service
+		}
+
+		protocolPorts := make([]gatewayv1beta1.ProtocolPort, len(rule.ProtocolPorts))
+		copy(protocolPorts, rule.ProtocolPorts)
+		sort.SliceStable(protocolPorts, func(i, j int) bool {
+			return protocolPorts[i].Protocol == gatewayv1beta1.RouteTypeGRPC && protocolPorts[j].Protocol != gatewayv1beta1.RouteTypeGRPC
+		})
+
+		for _, protocolPort := range protocolPorts {
+			protocolRule := *rule.DeepCopy()
+			protocolRule.ProtocolPorts = nil
+			// The path is matched the same way for all protocols, even though gRPC rules are matched by prefix by default.
+			protocolRule.PathMatchType = GetPathMatchType(rule)
+			protocolRule.RouteType = protocolPort.Protocol
+			protocolRule.Service = service.DeepCopy()
+			port := protocolPort.Port
+			protocolRule.Service.Port = &port
+			protocolRule.Service.PortName = ""
+
+			if protocolPort.Protocol == gatewayv1beta1.RouteTypeGRPC {
+				protocolRule.Service.Protocol = gatewayv1beta1.BackendProtocolHTTP2
+				if protocolRule.MatchHeaders == nil {
+					protocolRule.MatchHeaders = make(map[string]gatewayv1beta1.StringMatch)
+				}
+				protocolRule.MatchHeaders["content-type"] = gatewayv1beta1.StringMatch{Prefix: GRPCContentTypePrefix}
+			}
+
+			rules = append(rules, protocolRule)
+		}
+	}
+	expanded.Spec.Rules = rules
+
+	return expanded
+}
+
+func hasProtocolPorts(api *gatewayv1beta1.APIRule) bool {
+	for _, rule := range api.Spec.Rules {
+		if len(rule.ProtocolPorts) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
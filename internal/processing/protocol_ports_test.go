@@ -0,0 +1,108 @@
+package processing_test
+
+import (
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/processing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ExpandProtocolPorts", func() {
+	serviceName := "example-service"
+	servicePort := uint32(8080)
+
+	apiRule := func(rules ...gatewayv1beta1.Rule) *gatewayv1beta1.APIRule {
+		return &gatewayv1beta1.APIRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-apirule", Namespace: "some-namespace"},
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Service: &gatewayv1beta1.Service{Name: &serviceName, Port: &servicePort},
+				Rules:   rules,
+			},
+		}
+	}
+
+	It("should return the APIRule unchanged when no rule defines protocol ports", func() {
+		// given
+		api := apiRule(gatewayv1beta1.Rule{Path: "/headers"})
+
+		// when
+		expanded := processing.ExpandProtocolPorts(api)
+
+		// then
+		Expect(expanded).To(BeIdenticalTo(api))
+	})
+
+	It("should replace the rule with a gRPC rule before an HTTP rule", func() {
+		// given
+		api := apiRule(
+			gatewayv1beta1.Rule{Path: "/before"},
+			gatewayv1beta1.Rule{
+				Path: "/api",
+				ProtocolPorts: []gatewayv1beta1.ProtocolPort{
+					{Protocol: gatewayv1beta1.RouteTypeHTTP, Port: 80},
+					{Protocol: gatewayv1beta1.RouteTypeGRPC, Port: 9090},
+				},
+			},
+			gatewayv1beta1.Rule{Path: "/after"},
+		)
+
+		// when
+		expanded := processing.ExpandProtocolPorts(api)
+
+		// then
+		Expect(expanded.Spec.Rules).To(HaveLen(4))
+		Expect(expanded.Spec.Rules[0].Path).To(Equal("/before"))
+		Expect(expanded.Spec.Rules[3].Path).To(Equal("/after"))
+
+		grpcRule := expanded.Spec.Rules[1]
+		Expect(grpcRule.Path).To(Equal("/api"))
+		Expect(grpcRule.RouteType).To(Equal(gatewayv1beta1.RouteTypeGRPC))
+		Expect(grpcRule.PathMatchType).To(Equal(gatewayv1beta1.PathMatchRegex))
+		Expect(grpcRule.ProtocolPorts).To(BeNil())
+		Expect(*grpcRule.Service.Name).To(Equal(serviceName))
+		Expect(*grpcRule.Service.Port).To(Equal(uint32(9090)))
+		Expect(grpcRule.Service.Protocol).To(Equal(gatewayv1beta1.BackendProtocolHTTP2))
+		Expect(grpcRule.MatchHeaders).To(Equal(map[string]gatewayv1beta1.StringMatch{"content-type": {Prefix: "application/grpc"}}))
+
+		httpRule := expanded.Spec.Rules[2]
+		Expect(httpRule.Path).To(Equal("/api"))
+		Expect(httpRule.RouteType).To(Equal(gatewayv1beta1.RouteTypeHTTP))
+		Expect(httpRule.PathMatchType).To(Equal(gatewayv1beta1.PathMatchRegex))
+		Expect(httpRule.ProtocolPorts).To(BeNil())
+		Expect(*httpRule.Service.Port).To(Equal(uint32(80)))
+		Expect(httpRule.Service.Protocol).To(BeEmpty())
+		Expect(httpRule.MatchHeaders).To(BeEmpty())
+
+		Expect(api.Spec.Rules).To(HaveLen(3))
+		Expect(api.Spec.Rules[1].ProtocolPorts).To(HaveLen(2))
+		Expect(*api.Spec.Service.Port).To(Equal(servicePort))
+	})
+
+	It("should use the rule service and keep its header matches", func() {
+		// given
+		ruleServiceName := "rule-service"
+		api := apiRule(gatewayv1beta1.Rule{
+			Path:         "/api",
+			Service:      &gatewayv1beta1.Service{Name: &ruleServiceName, PortName: "http"},
+			MatchHeaders: map[string]gatewayv1beta1.StringMatch{"x-version": {Exact: "v2"}},
+			ProtocolPorts: []gatewayv1beta1.ProtocolPort{
+				{Protocol: gatewayv1beta1.RouteTypeGRPC, Port: 9090},
+			},
+		})
+
+		// when
+		expanded := processing.ExpandProtocolPorts(api)
+
+		// then
+		Expect(expanded.Spec.Rules).To(HaveLen(1))
+		Expect(*expanded.Spec.Rules[0].Service.Name).To(Equal(ruleServiceName))
+		Expect(*expanded.Spec.Rules[0].Service.Port).To(Equal(uint32(9090)))
+		Expect(expanded.Spec.Rules[0].Service.PortName).To(BeEmpty())
+		Expect(expanded.Spec.Rules[0].MatchHeaders).To(Equal(map[string]gatewayv1beta1.StringMatch{
+			"x-version":    {Exact: "v2"},
+			"content-type": {Prefix: "application/grpc"},
+		}))
+		Expect(api.Spec.Rules[0].MatchHeaders).To(HaveLen(1))
+	})
+})
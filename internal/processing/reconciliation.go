@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/go-logr/logr"
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/helpers"
 	"github.com/kyma-project/api-gateway/internal/validation"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -21,6 +24,9 @@ type ReconciliationCommand interface {
 
 	// GetProcessors returns the processor relevant for the reconciliation of this command.
 	GetProcessors() []ReconciliationProcessor
+
+	// GetDuplicatePathsMode returns how rules that match the same requests as a previous rule are handled.
+	GetDuplicatePathsMode() DuplicatePathsMode
 }
 
 // ReconciliationProcessor provides the evaluation of changes during the reconciliation of API Rule.
@@ -48,12 +54,37 @@ func Reconcile(ctx context.Context, client client.Client, log *logr.Logger, cmd
 		return GenerateStatusFromFailures(validationFailures, statusBase)
 	}
 
+	// The processors create the resources with port numbers, so the named service ports are resolved once for all of them.
+	resolvedApiRule, err := ResolveServicePortNames(ctx, client, apiRule)
+	if err != nil {
+		log.Error(err, "Error during resolving service ports")
+		statusBase := cmd.GetStatusBase(gatewayv1beta1.StatusSkipped)
+		errorMap := map[ResourceSelector][]error{OnApiRule: {err}}
+		return GetStatusForErrorMap(errorMap, statusBase)
+	}
+
+	resolvedApiRule, err = ResolveCorsOrigins(ctx, client, resolvedApiRule)
+	if err != nil {
+		log.Error(err, "Error during resolving CORS origins")
+		statusBase := cmd.GetStatusBase(gatewayv1beta1.StatusSkipped)
+		errorMap := map[ResourceSelector][]error{OnApiRule: {err}}
+		return GetStatusForErrorMap(errorMap, statusBase)
+	}
+
+	resolvedApiRule = ExpandProtocolPorts(NormalizeRuleMethods(resolvedApiRule))
+
+	var virtualService *gatewayv1beta1.ObjectReference
+	virtualServiceDeleted := false
 	for _, processor := range cmd.GetProcessors() {
 
-		objectChanges, err := processor.EvaluateReconciliation(ctx, client, apiRule)
+		objectChanges, err := processor.EvaluateReconciliation(ctx, client, resolvedApiRule)
 		if err != nil {
 			log.Error(err, "Error during reconciliation")
 			statusBase := cmd.GetStatusBase(gatewayv1beta1.StatusSkipped)
+			// Errors of invalid fields are reported like validation failures, so the status points to the fields.
+			if fieldFailures := toFieldFailures(err); len(fieldFailures) > 0 {
+				return GenerateStatusFromFailures(fieldFailures, statusBase)
+			}
 			errorMap := map[ResourceSelector][]error{OnApiRule: {err}}
 			return GetStatusForErrorMap(errorMap, statusBase)
 		}
@@ -64,9 +95,30 @@ func Reconcile(ctx context.Context, client client.Client, log *logr.Logger, cmd
 			statusBase := cmd.GetStatusBase(gatewayv1beta1.StatusOK)
 			return GetStatusForErrorMap(errorMap, statusBase)
 		}
+
+		if ref := findAppliedVirtualService(resolvedApiRule, objectChanges); ref != nil {
+			virtualService = ref
+		} else if hasDeletedVirtualService(resolvedApiRule, objectChanges) {
+			virtualServiceDeleted = true
+		}
 	}
 
 	statusBase := cmd.GetStatusBase(gatewayv1beta1.StatusOK)
+	statusBase.VirtualService = virtualService
+	statusBase.VirtualServiceDeleted = virtualService == nil && virtualServiceDeleted
+	var warnings []string
+	// Overlapping paths are reported as a warning only, since they can be intended, e.g. for a catch-all rule.
+	if overlaps := DetectOverlappingPaths(apiRule.Spec.Rules); len(overlaps) > 0 {
+		warnings = append(warnings, generateOverlappingPathsWarning(overlaps))
+	}
+	if cmd.GetDuplicatePathsMode() == DuplicatePathsReport {
+		if duplicates := DetectDuplicatePaths(apiRule.Spec.Rules); len(duplicates) > 0 {
+			warnings = append(warnings, generateDuplicatePathsWarning(duplicates))
+		}
+	}
+	if len(warnings) > 0 && statusBase.ApiRuleStatus != nil {
+		statusBase.ApiRuleStatus.Description = strings.Join(warnings, "\n")
+	}
 	return GenerateStatusFromFailures([]validation.Failure{}, statusBase)
 }
 
@@ -106,6 +158,47 @@ func applyChange(ctx context.Context, client client.Client, change *ObjectChange
 	return objectToSelector(change.Obj), nil
 }
 
+// findAppliedVirtualService returns the reference of the Virtual Service of the APIRule that was created or updated by
+// the changes. The name is only known after the creation, since the Virtual Service is created with a generated name.
+// The changes can also update the aggregated Virtual Services of other hosts, which are not the Virtual Service of the
+// APIRule.
+func findAppliedVirtualService(api *gatewayv1beta1.APIRule, changes []*ObjectChange) *gatewayv1beta1.ObjectReference {
+	for _, change := range changes {
+		if vs, ok := change.Obj.(*networkingv1beta1.VirtualService); ok && change.Action != delete && isVirtualServiceOf(api, vs) {
+			return &gatewayv1beta1.ObjectReference{Name: change.Obj.GetName(), Namespace: change.Obj.GetNamespace()}
+		}
+	}
+
+	return nil
+}
+
+// isVirtualServiceOf returns true if the Virtual Service is owned by the APIRule, or if it is the aggregated Virtual
+// Service of the hosts and gateways of the APIRule.
+func isVirtualServiceOf(api *gatewayv1beta1.APIRule, vs *networkingv1beta1.VirtualService) bool {
+	if vs.Labels[helpers.AggregatedLabel] == "true" {
+		return vs.Namespace == api.Namespace && vs.Name == GetAggregatedVirtualServiceName(api)
+	}
+
+	return vs.Labels[OwnerLabel] == GetOwnerLabelValue(api)
+}
+
+// hasDeletedVirtualService returns true if the changes delete the Virtual Service that is referenced in the status of
+// the APIRule.
+func hasDeletedVirtualService(api *gatewayv1beta1.APIRule, changes []*ObjectChange) bool {
+	ref := api.Status.VirtualService
+	if ref == nil {
+		return false
+	}
+	for _, change := range changes {
+		if _, ok := change.Obj.(*networkingv1beta1.VirtualService); ok && change.Action == delete &&
+			change.Obj.GetName() == ref.Name && change.Obj.GetNamespace() == ref.Namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
 func objectToSelector(obj client.Object) ResourceSelector {
 	kind := obj.GetObjectKind().GroupVersionKind().Kind
 	switch kind {
@@ -117,6 +210,10 @@ func objectToSelector(obj client.Object) ResourceSelector {
 		return OnRequestAuthentication
 	case OnAuthorizationPolicy.String():
 		return OnAuthorizationPolicy
+	case OnDestinationRule.String():
+		return OnDestinationRule
+	case OnEnvoyFilter.String():
+		return OnEnvoyFilter
 	default:
 		return OnApiRule
 	}
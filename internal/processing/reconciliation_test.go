@@ -7,6 +7,7 @@ import (
 	"github.com/go-logr/logr"
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
 	"github.com/kyma-project/api-gateway/internal/builders"
+	"github.com/kyma-project/api-gateway/internal/helpers"
 	"github.com/kyma-project/api-gateway/internal/processing"
 	oryHandler "github.com/kyma-project/api-gateway/internal/processing/ory"
 	"github.com/kyma-project/api-gateway/internal/validation"
@@ -69,6 +70,58 @@ var _ = Describe("Reconcile", func() {
 
 	})
 
+	It("should return api status error with the rule path when processor reconciliation returns rule error", func() {
+		// given
+		p := MockReconciliationProcessor{
+			evaluate: func() ([]*processing.ObjectChange, error) {
+				return []*processing.ObjectChange{}, processing.NewRuleError("/path", fmt.Errorf("invalid cookie mutator"))
+			},
+		}
+
+		cmd := MockReconciliationCommand{
+			validateMock:   func() ([]validation.Failure, error) { return []validation.Failure{}, nil },
+			processorMocks: func() []processing.ReconciliationProcessor { return []processing.ReconciliationProcessor{p} },
+			getStatusBaseMock: func() processing.ReconciliationStatus {
+				return mockStatusBase(gatewayv1beta1.StatusSkipped)
+			},
+		}
+
+		client := fake.NewClientBuilder().Build()
+
+		// when
+		status := processing.Reconcile(context.TODO(), client, testLogger(), cmd, &gatewayv1beta1.APIRule{})
+
+		// then
+		Expect(status.ApiRuleStatus.Code).To(Equal(gatewayv1beta1.StatusError))
+		Expect(status.ApiRuleStatus.Description).To(Equal("rule with path /path: invalid cookie mutator"))
+	})
+
+	It("should return api status error with the field path when processor reconciliation returns field error", func() {
+		// given
+		p := MockReconciliationProcessor{
+			evaluate: func() ([]*processing.ObjectChange, error) {
+				return []*processing.ObjectChange{}, processing.NewRuleError("/path", processing.NewFieldError(".spec.rules[0].mutators", fmt.Errorf("invalid cookie mutator")))
+			},
+		}
+
+		cmd := MockReconciliationCommand{
+			validateMock:   func() ([]validation.Failure, error) { return []validation.Failure{}, nil },
+			processorMocks: func() []processing.ReconciliationProcessor { return []processing.ReconciliationProcessor{p} },
+			getStatusBaseMock: func() processing.ReconciliationStatus {
+				return mockStatusBase(gatewayv1beta1.StatusSkipped)
+			},
+		}
+
+		client := fake.NewClientBuilder().Build()
+
+		// when
+		status := processing.Reconcile(context.TODO(), client, testLogger(), cmd, &gatewayv1beta1.APIRule{})
+
+		// then
+		Expect(status.ApiRuleStatus.Code).To(Equal(gatewayv1beta1.StatusError))
+		Expect(status.ApiRuleStatus.Description).To(Equal("Validation error: Attribute \".spec.rules[0].mutators\": rule with path /path: invalid cookie mutator"))
+	})
+
 	It("should return api status error and vs/ar status skipped when processor reconciliation returns error", func() {
 		// given
 		p := MockReconciliationProcessor{
@@ -99,6 +152,47 @@ var _ = Describe("Reconcile", func() {
 		Expect(status.RequestAuthenticationStatus).To(BeNil())
 
 	})
+	It("should return api status error and skip the processors when the CORS origins ConfigMap doesn't exist", func() {
+		// given
+		processed := false
+		p := MockReconciliationProcessor{
+			evaluate: func() ([]*processing.ObjectChange, error) {
+				processed = true
+				return []*processing.ObjectChange{}, nil
+			},
+		}
+
+		cmd := MockReconciliationCommand{
+			validateMock:   func() ([]validation.Failure, error) { return []validation.Failure{}, nil },
+			processorMocks: func() []processing.ReconciliationProcessor { return []processing.ReconciliationProcessor{p} },
+			getStatusBaseMock: func() processing.ReconciliationStatus {
+				return mockStatusBase(gatewayv1beta1.StatusSkipped)
+			},
+		}
+
+		apiRule := &gatewayv1beta1.APIRule{
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Rules: []gatewayv1beta1.Rule{{
+					Path: "/headers",
+					CorsPolicy: &gatewayv1beta1.CorsPolicy{
+						AllowOriginsFrom: &gatewayv1beta1.ConfigMapKeyReference{Name: "cors-origins", Key: "origins"},
+					},
+				}},
+			},
+		}
+		apiRule.Namespace = "some-namespace"
+		client := fake.NewClientBuilder().Build()
+
+		// when
+		status := processing.Reconcile(context.TODO(), client, testLogger(), cmd, apiRule)
+
+		// then
+		Expect(processed).To(BeFalse())
+		Expect(status.ApiRuleStatus.Code).To(Equal(gatewayv1beta1.StatusError))
+		Expect(status.ApiRuleStatus.Description).To(ContainSubstring("rule with path /headers: failed to get the CORS origins from configmap some-namespace/cors-origins"))
+		Expect(status.VirtualServiceStatus.Code).To(Equal(gatewayv1beta1.StatusSkipped))
+	})
+
 	Context("when VirtualService is missing kind", func() {
 		It("should return api status error when error happened during apply of changes on VS", func() {
 			// given
@@ -133,6 +227,38 @@ var _ = Describe("Reconcile", func() {
 		})
 	})
 
+	DescribeTable("should report the error of a subresource without status in the api status",
+		func(obj client.Object, kind string) {
+			// given
+			obj.GetObjectKind().SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind().GroupVersion().WithKind(kind))
+			p := MockReconciliationProcessor{
+				evaluate: func() ([]*processing.ObjectChange, error) {
+					return []*processing.ObjectChange{processing.NewObjectCreateAction(obj)}, nil
+				},
+			}
+
+			cmd := MockReconciliationCommand{
+				validateMock:   func() ([]validation.Failure, error) { return []validation.Failure{}, nil },
+				processorMocks: func() []processing.ReconciliationProcessor { return []processing.ReconciliationProcessor{p} },
+				getStatusBaseMock: func() processing.ReconciliationStatus {
+					return mockStatusBase(gatewayv1beta1.StatusOK)
+				},
+			}
+
+			client := fake.NewClientBuilder().Build()
+
+			// when
+			status := processing.Reconcile(context.TODO(), client, testLogger(), cmd, &gatewayv1beta1.APIRule{})
+
+			// then
+			Expect(status.ApiRuleStatus.Code).To(Equal(gatewayv1beta1.StatusError))
+			Expect(status.ApiRuleStatus.Description).To(HavePrefix(fmt.Sprintf("Error has happened on subresource %s: ", kind)))
+			Expect(status.VirtualServiceStatus.Code).To(Equal(gatewayv1beta1.StatusOK))
+		},
+		Entry("DestinationRule", builders.NewDestinationRuleBuilder().Get(), "DestinationRule"),
+		Entry("EnvoyFilter", builders.NewEnvoyFilterBuilder().Get(), "EnvoyFilter"),
+	)
+
 	It("should return status ok for create, update and delete", func() {
 		// given
 		toBeUpdatedVs := builders.VirtualService().Name("toBeUpdated").Get()
@@ -173,6 +299,212 @@ var _ = Describe("Reconcile", func() {
 
 	})
 
+	It("should return the reference of the created VS with the generated name", func() {
+		// given
+		apiRule := &gatewayv1beta1.APIRule{}
+		apiRule.Name = "test-apirule"
+		apiRule.Namespace = "some-namespace"
+		p := MockReconciliationProcessor{
+			evaluate: func() ([]*processing.ObjectChange, error) {
+				return []*processing.ObjectChange{
+					processing.NewObjectCreateAction(builders.VirtualService().GenerateName("test-apirule-").Namespace("some-namespace").
+						Label(processing.OwnerLabel, processing.GetOwnerLabelValue(apiRule)).Get()),
+				}, nil
+			},
+		}
+
+		cmd := MockReconciliationCommand{
+			validateMock:   func() ([]validation.Failure, error) { return []validation.Failure{}, nil },
+			processorMocks: func() []processing.ReconciliationProcessor { return []processing.ReconciliationProcessor{p} },
+			getStatusBaseMock: func() processing.ReconciliationStatus {
+				return mockStatusBase(gatewayv1beta1.StatusOK)
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		err := networkingv1beta1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		client := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		// when
+		status := processing.Reconcile(context.TODO(), client, testLogger(), cmd, apiRule)
+
+		// then
+		Expect(status.ApiRuleStatus.Code).To(Equal(gatewayv1beta1.StatusOK))
+		Expect(status.VirtualService).NotTo(BeNil())
+		Expect(status.VirtualService.Name).To(HavePrefix("test-apirule-"))
+		Expect(status.VirtualService.Name).NotTo(Equal("test-apirule-"))
+		Expect(status.VirtualService.Namespace).To(Equal("some-namespace"))
+	})
+
+	It("should not return a VS reference when the VS was not changed", func() {
+		// given
+		p := MockReconciliationProcessor{
+			evaluate: func() ([]*processing.ObjectChange, error) {
+				return []*processing.ObjectChange{}, nil
+			},
+		}
+
+		cmd := MockReconciliationCommand{
+			validateMock:   func() ([]validation.Failure, error) { return []validation.Failure{}, nil },
+			processorMocks: func() []processing.ReconciliationProcessor { return []processing.ReconciliationProcessor{p} },
+			getStatusBaseMock: func() processing.ReconciliationStatus {
+				return mockStatusBase(gatewayv1beta1.StatusOK)
+			},
+		}
+		client := fake.NewClientBuilder().Build()
+
+		// when
+		status := processing.Reconcile(context.TODO(), client, testLogger(), cmd, &gatewayv1beta1.APIRule{})
+
+		// then
+		Expect(status.ApiRuleStatus.Code).To(Equal(gatewayv1beta1.StatusOK))
+		Expect(status.VirtualService).To(BeNil())
+		Expect(status.VirtualServiceDeleted).To(BeFalse())
+	})
+
+	It("should report the deleted VS without a VS reference", func() {
+		// given
+		toBeDeletedVs := builders.VirtualService().Name("toBeDeleted").Get()
+		p := MockReconciliationProcessor{
+			evaluate: func() ([]*processing.ObjectChange, error) {
+				return []*processing.ObjectChange{processing.NewObjectDeleteAction(toBeDeletedVs)}, nil
+			},
+		}
+
+		cmd := MockReconciliationCommand{
+			validateMock:   func() ([]validation.Failure, error) { return []validation.Failure{}, nil },
+			processorMocks: func() []processing.ReconciliationProcessor { return []processing.ReconciliationProcessor{p} },
+			getStatusBaseMock: func() processing.ReconciliationStatus {
+				return mockStatusBase(gatewayv1beta1.StatusOK)
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		err := networkingv1beta1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(toBeDeletedVs).Build()
+		apiRule := &gatewayv1beta1.APIRule{}
+		apiRule.Status.VirtualService = &gatewayv1beta1.ObjectReference{Name: "toBeDeleted"}
+
+		// when
+		status := processing.Reconcile(context.TODO(), client, testLogger(), cmd, apiRule)
+
+		// then
+		Expect(status.ApiRuleStatus.Code).To(Equal(gatewayv1beta1.StatusOK))
+		Expect(status.VirtualService).To(BeNil())
+		Expect(status.VirtualServiceDeleted).To(BeTrue())
+	})
+
+	It("should not return the aggregated VS of another host that the routes of the APIRule are removed from", func() {
+		// given
+		host := "new.example.com"
+		apiRule := &gatewayv1beta1.APIRule{Spec: gatewayv1beta1.APIRuleSpec{Host: &host}}
+		apiRule.Name = "test-apirule"
+		apiRule.Namespace = "some-namespace"
+		apiRule.Status.VirtualService = &gatewayv1beta1.ObjectReference{Name: "old-example-com", Namespace: "some-namespace"}
+		previousVs := builders.VirtualService().Name("old-example-com").Namespace("some-namespace").Label(helpers.AggregatedLabel, "true").Get()
+		p := MockReconciliationProcessor{
+			evaluate: func() ([]*processing.ObjectChange, error) {
+				return []*processing.ObjectChange{processing.NewObjectUpdateAction(previousVs)}, nil
+			},
+		}
+
+		cmd := MockReconciliationCommand{
+			validateMock:   func() ([]validation.Failure, error) { return []validation.Failure{}, nil },
+			processorMocks: func() []processing.ReconciliationProcessor { return []processing.ReconciliationProcessor{p} },
+			getStatusBaseMock: func() processing.ReconciliationStatus {
+				return mockStatusBase(gatewayv1beta1.StatusOK)
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		err := networkingv1beta1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(previousVs).Build()
+
+		// when
+		status := processing.Reconcile(context.TODO(), client, testLogger(), cmd, apiRule)
+
+		// then
+		Expect(status.ApiRuleStatus.Code).To(Equal(gatewayv1beta1.StatusOK))
+		Expect(status.VirtualService).To(BeNil())
+		Expect(status.VirtualServiceDeleted).To(BeFalse())
+	})
+
+	It("should return status ok with a warning when the rule paths overlap", func() {
+		// given
+		p := MockReconciliationProcessor{
+			evaluate: func() ([]*processing.ObjectChange, error) {
+				return []*processing.ObjectChange{}, nil
+			},
+		}
+
+		cmd := MockReconciliationCommand{
+			validateMock:   func() ([]validation.Failure, error) { return []validation.Failure{}, nil },
+			processorMocks: func() []processing.ReconciliationProcessor { return []processing.ReconciliationProcessor{p} },
+			getStatusBaseMock: func() processing.ReconciliationStatus {
+				return mockStatusBase(gatewayv1beta1.StatusOK)
+			},
+		}
+
+		apiRule := &gatewayv1beta1.APIRule{
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Rules: []gatewayv1beta1.Rule{{Path: "/api/.*"}, {Path: "/api/v1"}},
+			},
+		}
+		client := fake.NewClientBuilder().Build()
+
+		// when
+		status := processing.Reconcile(context.TODO(), client, testLogger(), cmd, apiRule)
+
+		// then
+		Expect(status.ApiRuleStatus.Code).To(Equal(gatewayv1beta1.StatusOK))
+		Expect(status.ApiRuleStatus.Description).To(HavePrefix("Warning: "))
+		Expect(status.ApiRuleStatus.Description).To(HaveSuffix("\n/api/.* and /api/v1"))
+	})
+
+	DescribeTable("should report the duplicated paths depending on the duplicate paths mode",
+		func(mode processing.DuplicatePathsMode, expectedDescription string) {
+			// given
+			p := MockReconciliationProcessor{
+				evaluate: func() ([]*processing.ObjectChange, error) {
+					return []*processing.ObjectChange{}, nil
+				},
+			}
+
+			cmd := MockReconciliationCommand{
+				validateMock:   func() ([]validation.Failure, error) { return []validation.Failure{}, nil },
+				processorMocks: func() []processing.ReconciliationProcessor { return []processing.ReconciliationProcessor{p} },
+				getStatusBaseMock: func() processing.ReconciliationStatus {
+					return mockStatusBase(gatewayv1beta1.StatusOK)
+				},
+				duplicatePathsMode: mode,
+			}
+
+			apiRule := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Rules: []gatewayv1beta1.Rule{
+						{Path: "/headers", Methods: []string{"GET"}},
+						{Path: "/headers", Methods: []string{"POST"}},
+					},
+				},
+			}
+			client := fake.NewClientBuilder().Build()
+
+			// when
+			status := processing.Reconcile(context.TODO(), client, testLogger(), cmd, apiRule)
+
+			// then
+			Expect(status.ApiRuleStatus.Code).To(Equal(gatewayv1beta1.StatusOK))
+			Expect(status.ApiRuleStatus.Description).To(Equal(expectedDescription))
+		},
+		Entry("filter mode", processing.DuplicatePathsFilter, ""),
+		Entry("default mode", processing.DuplicatePathsMode(""), ""),
+		Entry("report mode", processing.DuplicatePathsReport,
+			"Warning: The following rules match the same requests as a previous rule and are merged into its route:\n/headers"),
+	)
+
 	It("should return status error on APIRule and VS for update on non existing VS", func() {
 		// give
 		toBeUpdatedVs := builders.VirtualService().Name("toBeUpdated").Get()
@@ -214,9 +546,10 @@ var _ = Describe("Reconcile", func() {
 })
 
 type MockReconciliationCommand struct {
-	validateMock      func() ([]validation.Failure, error)
-	getStatusBaseMock func() processing.ReconciliationStatus
-	processorMocks    func() []processing.ReconciliationProcessor
+	validateMock       func() ([]validation.Failure, error)
+	getStatusBaseMock  func() processing.ReconciliationStatus
+	processorMocks     func() []processing.ReconciliationProcessor
+	duplicatePathsMode processing.DuplicatePathsMode
 }
 
 func (r MockReconciliationCommand) Validate(_ context.Context, _ client.Client, _ *gatewayv1beta1.APIRule) ([]validation.Failure, error) {
@@ -227,6 +560,10 @@ func (r MockReconciliationCommand) GetProcessors() []processing.ReconciliationPr
 	return r.processorMocks()
 }
 
+func (r MockReconciliationCommand) GetDuplicatePathsMode() processing.DuplicatePathsMode {
+	return r.duplicatePathsMode
+}
+
 type MockReconciliationProcessor struct {
 	evaluate func() ([]*processing.ObjectChange, error)
 }
@@ -0,0 +1,30 @@
+package processing
+
+import (
+	"time"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+)
+
+// GetRuleRetryConfig returns the retry configuration defined on the rule if it exists, otherwise the default retry
+// configuration is returned. A nil result means that no retries should be configured.
+func GetRuleRetryConfig(rule gatewayv1beta1.Rule, defaultConfig *RetryConfig) (*RetryConfig, error) {
+	if rule.Retries == nil {
+		return defaultConfig, nil
+	}
+
+	config := &RetryConfig{
+		Attempts: rule.Retries.Attempts,
+		RetryOn:  rule.Retries.RetryOn,
+	}
+
+	if rule.Retries.PerTryTimeout != nil {
+		perTryTimeout, err := time.ParseDuration(*rule.Retries.PerTryTimeout)
+		if err != nil {
+			return nil, err
+		}
+		config.PerTryTimeout = perTryTimeout
+	}
+
+	return config, nil
+}
@@ -0,0 +1,67 @@
+package processing
+
+import (
+	"context"
+	"fmt"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/helpers"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResolveServicePortNames returns the APIRule with the port names of the spec and rule level services resolved to the
+// port numbers of the target Services. The given APIRule is not modified, a copy is returned if a port name is resolved.
+func ResolveServicePortNames(ctx context.Context, k8sClient client.Client, api *gatewayv1beta1.APIRule) (*gatewayv1beta1.APIRule, error) {
+	if !hasServicePortName(api) {
+		return api, nil
+	}
+
+	resolved := api.DeepCopy()
+	if resolved.Spec.Service != nil && resolved.Spec.Service.PortName != "" {
+		if err := resolveServicePortName(ctx, k8sClient, resolved.Spec.Service, helpers.FindServiceNamespace(resolved, nil)); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range resolved.Spec.Rules {
+		rule := &resolved.Spec.Rules[i]
+		if rule.Service != nil && rule.Service.PortName != "" {
+			if err := resolveServicePortName(ctx, k8sClient, rule.Service, helpers.FindServiceNamespace(resolved, rule)); err != nil {
+				return nil, NewRuleError(rule.Path, err)
+			}
+		}
+	}
+
+	return resolved, nil
+}
+
+func hasServicePortName(api *gatewayv1beta1.APIRule) bool {
+	if api.Spec.Service != nil && api.Spec.Service.PortName != "" {
+		return true
+	}
+	for _, rule := range api.Spec.Rules {
+		if rule.Service != nil && rule.Service.PortName != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func resolveServicePortName(ctx context.Context, k8sClient client.Client, service *gatewayv1beta1.Service, namespace string) error {
+	var svc corev1.Service
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: *service.Name}, &svc); err != nil {
+		return fmt.Errorf("failed to resolve port name %s of service %s/%s: %w", service.PortName, namespace, *service.Name, err)
+	}
+
+	for _, port := range svc.Spec.Ports {
+		if port.Name == service.PortName {
+			number := uint32(port.Port)
+			service.Port = &number
+			return nil
+		}
+	}
+
+	return fmt.Errorf("service %s/%s doesn't define a port with name %s", namespace, *service.Name, service.PortName)
+}
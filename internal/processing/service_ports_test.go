@@ -0,0 +1,108 @@
+package processing_test
+
+import (
+	"context"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/processing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ResolveServicePortNames", func() {
+	serviceName := "example-service"
+	ruleServiceName := "rule-service"
+
+	service := func(name string, ports ...corev1.ServicePort) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "some-namespace"},
+			Spec:       corev1.ServiceSpec{Ports: ports},
+		}
+	}
+
+	apiRule := func(specService *gatewayv1beta1.Service, ruleService *gatewayv1beta1.Service) *gatewayv1beta1.APIRule {
+		return &gatewayv1beta1.APIRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-apirule", Namespace: "some-namespace"},
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Service: specService,
+				Rules:   []gatewayv1beta1.Rule{{Path: "/headers", Service: ruleService}},
+			},
+		}
+	}
+
+	It("should resolve the port names of the spec and rule services", func() {
+		// given
+		client := fake.NewClientBuilder().WithObjects(
+			service(serviceName, corev1.ServicePort{Name: "metrics", Port: 9090}, corev1.ServicePort{Name: "http", Port: 8080}),
+			service(ruleServiceName, corev1.ServicePort{Name: "http-web", Port: 8443}),
+		).Build()
+		api := apiRule(&gatewayv1beta1.Service{Name: &serviceName, PortName: "http"}, &gatewayv1beta1.Service{Name: &ruleServiceName, PortName: "http-web"})
+
+		// when
+		resolved, err := processing.ResolveServicePortNames(context.TODO(), client, api)
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(*resolved.Spec.Service.Port).To(Equal(uint32(8080)))
+		Expect(*resolved.Spec.Rules[0].Service.Port).To(Equal(uint32(8443)))
+		Expect(api.Spec.Service.Port).To(BeNil())
+		Expect(api.Spec.Rules[0].Service.Port).To(BeNil())
+	})
+
+	It("should return the APIRule unchanged when no port name is used", func() {
+		// given
+		port := uint32(8080)
+		client := fake.NewClientBuilder().Build()
+		api := apiRule(&gatewayv1beta1.Service{Name: &serviceName, Port: &port}, nil)
+
+		// when
+		resolved, err := processing.ResolveServicePortNames(context.TODO(), client, api)
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resolved).To(BeIdenticalTo(api))
+	})
+
+	It("should return an error when the service doesn't define the port name", func() {
+		// given
+		client := fake.NewClientBuilder().WithObjects(service(serviceName, corev1.ServicePort{Name: "http", Port: 8080})).Build()
+		api := apiRule(&gatewayv1beta1.Service{Name: &serviceName, PortName: "grpc"}, nil)
+
+		// when
+		_, err := processing.ResolveServicePortNames(context.TODO(), client, api)
+
+		// then
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(Equal("service some-namespace/example-service doesn't define a port with name grpc"))
+	})
+
+	It("should return a rule error when the rule service doesn't define the port name", func() {
+		// given
+		port := uint32(8080)
+		client := fake.NewClientBuilder().WithObjects(service(ruleServiceName, corev1.ServicePort{Name: "http", Port: 8080})).Build()
+		api := apiRule(&gatewayv1beta1.Service{Name: &serviceName, Port: &port}, &gatewayv1beta1.Service{Name: &ruleServiceName, PortName: "grpc"})
+
+		// when
+		_, err := processing.ResolveServicePortNames(context.TODO(), client, api)
+
+		// then
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(Equal("rule with path /headers: service some-namespace/rule-service doesn't define a port with name grpc"))
+	})
+
+	It("should return an error when the service doesn't exist", func() {
+		// given
+		client := fake.NewClientBuilder().Build()
+		api := apiRule(&gatewayv1beta1.Service{Name: &serviceName, PortName: "http"}, nil)
+
+		// when
+		_, err := processing.ResolveServicePortNames(context.TODO(), client, api)
+
+		// then
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed to resolve port name http of service some-namespace/example-service"))
+	})
+})
@@ -0,0 +1,98 @@
+package processing
+
+import (
+	"fmt"
+	"strings"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/builders"
+	"github.com/kyma-project/api-gateway/internal/helpers"
+	"github.com/kyma-project/api-gateway/internal/processing/hashbasedstate"
+	"istio.io/api/security/v1beta1"
+	securityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
+)
+
+// GetAuthorizationPolicyPath returns the path of the rule in the format of the operations of an AuthorizationPolicy.
+func GetAuthorizationPolicyPath(rule gatewayv1beta1.Rule) string {
+	// APIRule and VirtualService supported a regex match. Since AuthorizationPolicy supports only prefix, suffix and wildcard
+	// and we have clusters with "/.*" in APIRule, we need special handling of this case.
+	if rule.Path == "/.*" {
+		return "/*"
+	}
+	// AuthorizationPolicy expresses a prefix match with a trailing wildcard.
+	if GetPathMatchType(rule) == gatewayv1beta1.PathMatchPrefix && !strings.HasSuffix(rule.Path, "*") {
+		return rule.Path + "*"
+	}
+
+	return rule.Path
+}
+
+// AddSourceAuthorizationPolicies adds the AuthorizationPolicies that deny the requests from clients outside the source
+// CIDRs of the rules to the desired state.
+func AddSourceAuthorizationPolicies(state *hashbasedstate.Desired, api *gatewayv1beta1.APIRule, additionalLabels map[string]string) error {
+	for _, rule := range api.Spec.Rules {
+		for index, ap := range getSourceAuthorizationPolicies(api, rule, additionalLabels) {
+			if err := hashbasedstate.AddLabelsToAuthorizationPolicy(ap, index); err != nil {
+				return err
+			}
+
+			h := hashbasedstate.NewAuthorizationPolicy(ap)
+			if err := state.Add(&h); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// getSourceAuthorizationPolicies returns the AuthorizationPolicies that deny the requests of the rule from clients that
+// are not in the source CIDRs of the rule. A policy is returned for each service the requests are routed to, since the
+// policies select the workloads of the services. No policies are returned for rules without source CIDRs and for
+// rules whose requests don't reach a workload, like redirects and direct responses.
+func getSourceAuthorizationPolicies(api *gatewayv1beta1.APIRule, rule gatewayv1beta1.Rule, additionalLabels map[string]string) []*securityv1beta1.AuthorizationPolicy {
+	if len(rule.SourceCIDRs) == 0 || !rule.IsEnabled() || rule.Redirect != nil || rule.DirectResponse != nil {
+		return nil
+	}
+
+	var services []*gatewayv1beta1.Service
+	if len(rule.Destinations) > 0 {
+		for i := range rule.Destinations {
+			services = append(services, &rule.Destinations[i].Service)
+		}
+	} else if rule.Service != nil {
+		services = append(services, rule.Service)
+	} else if api.Spec.Service != nil {
+		services = append(services, api.Spec.Service)
+	}
+
+	var policies []*securityv1beta1.AuthorizationPolicy
+	for _, service := range services {
+		spec := builders.NewAuthorizationPolicySpecBuilder().
+			WithSelector(builders.SelectorFromService(service)).
+			WithAction(v1beta1.AuthorizationPolicy_DENY).
+			WithRule(builders.NewRuleBuilder().
+				WithFrom(builders.NewFromBuilder().WithNotRemoteIpBlocks(rule.SourceCIDRs).Get()).
+				WithTo(builders.NewToBuilder().
+					WithOperation(builders.NewOperationBuilder().
+						WithMethods(rule.Methods).WithPath(GetAuthorizationPolicyPath(rule)).Get()).
+					Get()).
+				Get()).
+			Get()
+
+		apBuilder := builders.NewAuthorizationPolicyBuilder().
+			WithGenerateName(fmt.Sprintf("%s-", api.Name)).
+			WithNamespace(helpers.FindDestinationNamespace(api, service)).
+			WithSpec(spec).
+			WithLabel(OwnerLabel, GetOwnerLabelValue(api)).
+			WithLabel(OwnerLabelv1alpha1, GetOwnerLabelValue(api))
+
+		for k, v := range additionalLabels {
+			apBuilder.WithLabel(k, v)
+		}
+
+		policies = append(policies, apBuilder.Get())
+	}
+
+	return policies
+}
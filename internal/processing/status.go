@@ -13,6 +13,12 @@ type ReconciliationStatus struct {
 	AccessRuleStatus            *gatewayv1beta1.APIRuleResourceStatus
 	RequestAuthenticationStatus *gatewayv1beta1.APIRuleResourceStatus
 	AuthorizationPolicyStatus   *gatewayv1beta1.APIRuleResourceStatus
+	// VirtualService references the Virtual Service that was created or updated in the reconciliation, it's nil if the
+	// Virtual Service was not changed.
+	VirtualService *gatewayv1beta1.ObjectReference
+	// VirtualServiceDeleted is true if the Virtual Service was deleted in the reconciliation without being replaced, e.g.
+	// because the APIRule is paused.
+	VirtualServiceDeleted bool
 }
 
 func (status ReconciliationStatus) HasError() bool {
@@ -42,6 +48,8 @@ const (
 	OnAccessRule
 	OnAuthorizationPolicy
 	OnRequestAuthentication
+	OnDestinationRule
+	OnEnvoyFilter
 )
 
 func (r ResourceSelector) String() string {
@@ -54,6 +62,10 @@ func (r ResourceSelector) String() string {
 		return "RequestAuthentication"
 	case OnAuthorizationPolicy:
 		return "AuthorizationPolicy"
+	case OnDestinationRule:
+		return "DestinationRule"
+	case OnEnvoyFilter:
+		return "EnvoyFilter"
 	default:
 		// If no Kind is resolved from the resource (e.g. subresource CRD is missing)
 		return "APIRule"
@@ -90,14 +102,19 @@ func GetStatusForErrorMap(errorMap map[ResourceSelector][]error, statusBase Reco
 		}
 
 		if key != OnApiRule {
+			description := fmt.Sprintf("Error has happened on subresource %s", key)
+			// Destination Rules and Envoy Filters have no status of their own, so their errors are reported in the status of the APIRule.
+			if key == OnDestinationRule || key == OnEnvoyFilter {
+				description = fmt.Sprintf("%s: %s", description, generateStatusFromErrors(val).Description)
+			}
 			if statusBase.ApiRuleStatus == nil || statusBase.ApiRuleStatus.Code == gatewayv1beta1.StatusOK {
 				statusBase.ApiRuleStatus = &gatewayv1beta1.APIRuleResourceStatus{
 					Code:        gatewayv1beta1.StatusError,
-					Description: fmt.Sprintf("Error has happened on subresource %s", key),
+					Description: description,
 				}
 			} else {
 				statusBase.ApiRuleStatus.Code = gatewayv1beta1.StatusError
-				statusBase.ApiRuleStatus.Description += fmt.Sprintf("\nError has happened on subresource %s", key)
+				statusBase.ApiRuleStatus.Description += "\n" + description
 			}
 		}
 	}
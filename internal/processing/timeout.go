@@ -0,0 +1,90 @@
+package processing
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"github.com/kyma-project/api-gateway/internal/helpers"
+)
+
+// ParseHTTPTimeout parses the default timeout of the routes, which is a duration string with units (e.g. "3m") or a
+// number of seconds. An empty value returns nil, which means that no timeout is configured on the routes, while zero
+// is configured as a timeout of 0s, which disables the timeout in Istio.
+func ParseHTTPTimeout(value string) (*time.Duration, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var timeout time.Duration
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		timeout = time.Duration(seconds) * time.Second
+	} else if timeout, err = time.ParseDuration(value); err != nil {
+		return nil, err
+	}
+	if timeout < 0 {
+		return nil, fmt.Errorf("timeout %s must not be negative", value)
+	}
+
+	return &timeout, nil
+}
+
+// GetRuleTimeout returns the timeout defined on the rule if it exists, otherwise the default timeout is returned.
+// WebSocket rules without a timeout return nil, which means that no timeout should be configured. A timeout defined
+// for a port the requests of the rule are routed to overwrites both, for multiple ports the longest timeout is returned.
+func GetRuleTimeout(api *gatewayv1beta1.APIRule, rule gatewayv1beta1.Rule, defaultTimeout *time.Duration) (*time.Duration, error) {
+	portTimeout, err := getPortTimeout(api, rule)
+	if err != nil {
+		return nil, err
+	}
+	if portTimeout > 0 {
+		return &portTimeout, nil
+	}
+
+	if rule.Timeout == nil {
+		if rule.WebSocket {
+			return nil, nil
+		}
+		return defaultTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(*rule.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &timeout, nil
+}
+
+// getPortTimeout returns the longest timeout defined for the ports the requests of the rule are routed to. Zero is
+// returned if no timeout is defined for these ports.
+func getPortTimeout(api *gatewayv1beta1.APIRule, rule gatewayv1beta1.Rule) (time.Duration, error) {
+	ports, _ := helpers.GetRulePorts(api, &rule)
+
+	var timeout time.Duration
+	for _, portTimeout := range rule.PortTimeouts {
+		if !ports[portTimeout.Port] {
+			continue
+		}
+		duration, err := time.ParseDuration(portTimeout.Timeout)
+		if err != nil {
+			return 0, fmt.Errorf("timeout of port %d: %w", portTimeout.Port, err)
+		}
+		if duration > timeout {
+			timeout = duration
+		}
+	}
+
+	return timeout, nil
+}
+
+// GetRuleIdleTimeout returns the idle timeout of the rule, or the default idle timeout if the rule doesn't define one.
+// A zero duration means that no idle timeout is configured.
+func GetRuleIdleTimeout(rule gatewayv1beta1.Rule, defaultIdleTimeout time.Duration) (time.Duration, error) {
+	if rule.IdleTimeout == nil {
+		return defaultIdleTimeout, nil
+	}
+
+	return time.ParseDuration(*rule.IdleTimeout)
+}
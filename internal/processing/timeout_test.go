@@ -0,0 +1,33 @@
+package processing_test
+
+import (
+	"time"
+
+	"github.com/kyma-project/api-gateway/internal/processing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("ParseHTTPTimeout", func() {
+	DescribeTable("should parse the default timeout of the routes",
+		func(value string, expected *time.Duration) {
+			Expect(processing.ParseHTTPTimeout(value)).To(Equal(expected))
+		},
+		Entry("unset timeout", "", nil),
+		Entry("zero timeout", "0", pointer.Duration(0)),
+		Entry("zero duration", "0s", pointer.Duration(0)),
+		Entry("number of seconds", "180", pointer.Duration(180*time.Second)),
+		Entry("duration with units", "1m30s", pointer.Duration(90*time.Second)),
+	)
+
+	DescribeTable("should reject invalid timeouts",
+		func(value string) {
+			_, err := processing.ParseHTTPTimeout(value)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("negative duration", "-10s"),
+		Entry("duration without units", "1.5"),
+		Entry("invalid duration", "three minutes"),
+	)
+})
@@ -1,7 +1,10 @@
 package processing
 
 import (
+	"time"
+
 	v1beta1 "istio.io/api/networking/v1beta1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -53,19 +56,69 @@ func NewObjectDeleteAction(obj client.Object) *ObjectChange {
 
 // CorsConfig is an internal representation of v1alpha3.CorsPolicy object
 type CorsConfig struct {
-	AllowOrigins []*v1beta1.StringMatch
-	AllowMethods []string
-	AllowHeaders []string
+	AllowOrigins     []*v1beta1.StringMatch
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// RetryConfig is an internal representation of v1beta1.HTTPRetry object
+type RetryConfig struct {
+	Attempts      int
+	PerTryTimeout time.Duration
+	RetryOn       string
 }
 
 type ReconciliationConfig struct {
-	OathkeeperSvc       string
-	OathkeeperSvcPort   uint32
-	CorsConfig          *CorsConfig
-	AdditionalLabels    map[string]string
-	DefaultDomainName   string
-	ServiceBlockList    map[string][]string
-	DomainAllowList     []string
-	HostBlockList       []string
-	HTTPTimeoutDuration int
+	OathkeeperSvc     string
+	OathkeeperSvcPort uint32
+	CorsConfig        *CorsConfig
+	AdditionalLabels  map[string]string
+	DefaultDomainName string
+	ServiceBlockList  map[string][]string
+	// RestrictServiceNamespaces only allows services in the namespace of the APIRule or in ServiceNamespaceAllowList.
+	RestrictServiceNamespaces bool
+	ServiceNamespaceAllowList []string
+	DomainAllowList           []string
+	HostBlockList             []string
+	// HTTPTimeout is the default timeout of the routes. No timeout is configured on the routes if it is nil, while zero
+	// disables the timeout explicitly.
+	HTTPTimeout             *time.Duration
+	RetryConfig             *RetryConfig
+	StrictHostDomain        bool
+	VirtualServiceFixedName bool
+	ValidateServices        bool
+	// VirtualServiceNamePrefix and VirtualServiceNameSuffix are added to the APIRule name in the generated names of the
+	// VirtualServices, they are not used for fixed names.
+	VirtualServiceNamePrefix string
+	VirtualServiceNameSuffix string
+	// CatchAllPathRegex is the regex that matches the requests of rules with the path /*, which are matched by the
+	// prefix / if not set.
+	CatchAllPathRegex string
+	// AggregateByHost merges the routes of the APIRules in a namespace that share the hosts and gateways into a
+	// single VirtualService, instead of creating a VirtualService per APIRule.
+	AggregateByHost bool
+	// ExportTo is the default list of namespaces to which the VirtualServices are exported, e.g. "." for the namespace
+	// of the APIRule. The VirtualServices are exported to all namespaces if not set.
+	ExportTo []string
+	// DuplicatePathsMode defines if rules that match the same requests as a previous rule are reported, they are
+	// filtered silently if not set.
+	DuplicatePathsMode DuplicatePathsMode
+	// LuaFilters enables the Lua scripts of the rules, which are configured in EnvoyFilters that are created in
+	// LuaFilterNamespace, or in DefaultLuaFilterNamespace if not set.
+	LuaFilters         bool
+	LuaFilterNamespace string
+	// MatchedRuleHeader is the name of the response header that is set to the name of the route of the matched rule to
+	// debug the routing. It exposes the names of the APIRules to the clients, so no header is set if it is empty.
+	MatchedRuleHeader string
+	// IdleTimeout is the default idle timeout of the connections to the services, no idle timeout is configured if zero.
+	IdleTimeout time.Duration
+	// UseOwnerIndex looks up the generated objects by the OwnerIndex, which must be registered with the field indexer.
+	UseOwnerIndex bool
+	// Metrics are optional and record the outcomes of the evaluation of the reconciliation.
+	Metrics ReconciliationMetrics
+	// Recorder is optional and records events on the APIRule for the changes of the generated resources.
+	Recorder record.EventRecorder
 }
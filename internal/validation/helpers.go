@@ -19,18 +19,26 @@ func hasPathAndMethodDuplicates(rules []gatewayv1beta1.Rule) bool {
 	if len(rules) > 1 {
 		for _, rule := range rules {
 			if len(rule.Methods) > 0 {
+				// Methods defined more than once in the same rule are reported by the validation of the methods.
+				ruleMethods := map[string]bool{}
 				for _, method := range rule.Methods {
-					tmp := fmt.Sprintf("%s:%s", rule.Path, method)
+					method = strings.ToUpper(strings.TrimSpace(method))
+					if ruleMethods[method] {
+						continue
+					}
+					ruleMethods[method] = true
+
+					tmp := fmt.Sprintf("%s:%s", rule.GetMatchKey(), method)
 					if duplicates[tmp] {
 						return true
 					}
 					duplicates[tmp] = true
 				}
 			} else {
-				if duplicates[rule.Path] {
+				if duplicates[rule.GetMatchKey()] {
 					return true
 				}
-				duplicates[rule.Path] = true
+				duplicates[rule.GetMatchKey()] = true
 			}
 		}
 	}
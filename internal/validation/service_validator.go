@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ServiceExistenceValidator validates that a service referenced by the APIRule exists in the cluster and exposes the port.
+type ServiceExistenceValidator struct {
+	Ctx    context.Context
+	Client client.Client
+}
+
+func (v *ServiceExistenceValidator) Validate(attributePath string, service *gatewayv1beta1.Service, namespace string) ([]Failure, error) {
+	var svc corev1.Service
+	err := v.Client.Get(v.Ctx, client.ObjectKey{Namespace: namespace, Name: *service.Name}, &svc)
+	if apierrs.IsNotFound(err) {
+		return []Failure{{AttributePath: attributePath + ".name", Message: fmt.Sprintf("Service %s in namespace %s doesn't exist", *service.Name, namespace)}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, port := range svc.Spec.Ports {
+		if (service.Port != nil && port.Port == int32(*service.Port)) || (service.PortName != "" && port.Name == service.PortName) {
+			return validateAppProtocol(attributePath, service, port), nil
+		}
+	}
+
+	if service.PortName != "" {
+		return []Failure{{AttributePath: attributePath + ".portName", Message: fmt.Sprintf("Service %s in namespace %s doesn't expose a port with name %s", *service.Name, namespace, service.PortName)}}, nil
+	}
+	return []Failure{{AttributePath: attributePath + ".port", Message: fmt.Sprintf("Service %s in namespace %s doesn't expose port %d", *service.Name, namespace, *service.Port)}}, nil
+}
+
+var http2AppProtocols = []string{"http2", "grpc", "grpc-web", "kubernetes.io/h2c"}
+
+// validateAppProtocol validates that the protocol of the service matches the app protocol of the port, if the port declares one.
+func validateAppProtocol(attributePath string, service *gatewayv1beta1.Service, port corev1.ServicePort) []Failure {
+	if port.AppProtocol == nil || service.Protocol == "" {
+		return nil
+	}
+
+	isHTTP2Port := slices.Contains(http2AppProtocols, strings.ToLower(*port.AppProtocol))
+	if (service.Protocol == gatewayv1beta1.BackendProtocolHTTP2) != isHTTP2Port {
+		return []Failure{{AttributePath: attributePath + ".protocol", Message: fmt.Sprintf("Protocol %s doesn't match the app protocol %s of port %d of service %s", service.Protocol, *port.AppProtocol, port.Port, *service.Name)}}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package validation
+
+import (
+	"context"
+
+	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ServiceExistenceValidator", func() {
+	h2c := "kubernetes.io/h2c"
+	http := "http"
+	existingService := &corev1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: sampleServiceName, Namespace: "some-namespace"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 8080},
+				{Name: "h2c", Port: 8081, AppProtocol: &h2c},
+				{Name: "http-web", Port: 8082, AppProtocol: &http},
+			},
+		},
+	}
+
+	DescribeTable("Should validate the service",
+		func(service *gatewayv1beta1.Service, expectedPath string, expectedMessage string) {
+			//given
+			validator := &ServiceExistenceValidator{
+				Ctx:    context.TODO(),
+				Client: fake.NewClientBuilder().WithObjects(existingService).Build(),
+			}
+
+			//when
+			problems, err := validator.Validate(".spec.service", service, "some-namespace")
+
+			//then
+			Expect(err).ToNot(HaveOccurred())
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(expectedPath))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("existing service and port", getService(sampleServiceName, uint32(8080)), "", ""),
+		Entry("existing service and port name", &gatewayv1beta1.Service{Name: getHost(sampleServiceName), PortName: "http"}, "", ""),
+		Entry("missing service", getService("missing-service", uint32(8080)), ".spec.service.name",
+			"Service missing-service in namespace some-namespace doesn't exist"),
+		Entry("missing port", getService(sampleServiceName, uint32(9090)), ".spec.service.port",
+			"Service some-service in namespace some-namespace doesn't expose port 9090"),
+		Entry("missing port name", &gatewayv1beta1.Service{Name: getHost(sampleServiceName), PortName: "grpc"}, ".spec.service.portName",
+			"Service some-service in namespace some-namespace doesn't expose a port with name grpc"),
+		Entry("HTTP/2 protocol for port without app protocol", &gatewayv1beta1.Service{Name: getHost(sampleServiceName), Port: ptrUint32(8080), Protocol: gatewayv1beta1.BackendProtocolHTTP2}, "", ""),
+		Entry("HTTP/2 protocol for port with HTTP/2 app protocol", &gatewayv1beta1.Service{Name: getHost(sampleServiceName), PortName: "h2c", Protocol: gatewayv1beta1.BackendProtocolHTTP2}, "", ""),
+		Entry("HTTP/1.1 protocol for port with HTTP/1.1 app protocol", &gatewayv1beta1.Service{Name: getHost(sampleServiceName), Port: ptrUint32(8082), Protocol: gatewayv1beta1.BackendProtocolHTTP}, "", ""),
+		Entry("HTTP/2 protocol for port with HTTP/1.1 app protocol", &gatewayv1beta1.Service{Name: getHost(sampleServiceName), Port: ptrUint32(8082), Protocol: gatewayv1beta1.BackendProtocolHTTP2}, ".spec.service.protocol",
+			"Protocol http2 doesn't match the app protocol http of port 8082 of service some-service"),
+		Entry("HTTP/1.1 protocol for port with HTTP/2 app protocol", &gatewayv1beta1.Service{Name: getHost(sampleServiceName), Port: ptrUint32(8081), Protocol: gatewayv1beta1.BackendProtocolHTTP}, ".spec.service.protocol",
+			"Protocol http doesn't match the app protocol kubernetes.io/h2c of port 8081 of service some-service"),
+	)
+
+	It("Should validate the services of the APIRule if the service validator is configured", func() {
+		//given
+		input := &gatewayv1beta1.APIRule{
+			ObjectMeta: v1.ObjectMeta{Namespace: "some-namespace"},
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Service: getService(sampleServiceName, uint32(8080)),
+				Host:    getHost(sampleValidHost),
+				Rules: []gatewayv1beta1.Rule{
+					{
+						Path: "/abc",
+						AccessStrategies: []*gatewayv1beta1.Authenticator{
+							toAuthenticator("allow", nil),
+						},
+						Service: getService("missing-service", uint32(8080)),
+					},
+					{
+						Path: "/external",
+						AccessStrategies: []*gatewayv1beta1.Authenticator{
+							toAuthenticator("allow", nil),
+						},
+						Service: &gatewayv1beta1.Service{Name: getHost("external-service"), Port: ptrUint32(443), IsExternal: ptrBool(true)},
+					},
+				},
+			},
+		}
+
+		//when
+		problems := (&APIRuleValidator{
+			HandlerValidator:          handlerValidatorMock,
+			AccessStrategiesValidator: asValidatorMock,
+			DomainAllowList:           testDomainAllowlist,
+			ServiceValidator: &ServiceExistenceValidator{
+				Ctx:    context.TODO(),
+				Client: fake.NewClientBuilder().WithObjects(existingService).Build(),
+			},
+		}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+		//then
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].service.name"))
+		Expect(problems[0].Message).To(Equal("Service missing-service in namespace some-namespace doesn't exist"))
+	})
+})
+
+func ptrBool(value bool) *bool {
+	return &value
+}
@@ -2,7 +2,12 @@ package validation
 
 import (
 	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kyma-project/api-gateway/internal/builders"
 	"github.com/kyma-project/api-gateway/internal/helpers"
@@ -10,10 +15,21 @@ import (
 
 	gatewayv1alpha1 "github.com/kyma-project/api-gateway/api/v1alpha1"
 	gatewayv1beta1 "github.com/kyma-project/api-gateway/api/v1beta1"
+	"golang.org/x/net/http/httpguts"
 	apiv1beta1 "istio.io/api/type/v1beta1"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/utils/strings/slices"
 )
 
+// maxTimeout is the highest timeout that can be configured for a rule
+const maxTimeout = time.Hour
+
+// httpMethods are the methods that can be defined for a rule
+var httpMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace,
+}
+
 // Validators for AccessStrategies
 var vldNoConfig = &noConfigAccStrValidator{}
 var vldDummy = &dummyHandlerValidator{}
@@ -38,6 +54,10 @@ type rulesValidator interface {
 	Validate(attrPath string, rules []gatewayv1beta1.Rule) []Failure
 }
 
+type serviceValidator interface {
+	Validate(attrPath string, service *gatewayv1beta1.Service, namespace string) ([]Failure, error)
+}
+
 // APIRuleValidator is used to validate github.com/kyma-project/api-gateway/api/v1beta1/APIRule instances
 type APIRuleValidator struct {
 	HandlerValidator          handlerValidator
@@ -45,10 +65,17 @@ type APIRuleValidator struct {
 	MutatorsValidator         mutatorValidator
 	InjectionValidator        injectionValidator
 	RulesValidator            rulesValidator
+	ServiceValidator          serviceValidator
 	ServiceBlockList          map[string][]string
+	// RestrictServiceNamespaces rejects services outside the namespace of the APIRule, unless the namespace of the
+	// service is in the ServiceNamespaceAllowList.
+	RestrictServiceNamespaces bool
+	ServiceNamespaceAllowList []string
 	DomainAllowList           []string
 	HostBlockList             []string
 	DefaultDomainName         string
+	// LuaFilters allows the Lua scripts of the rules, which are rejected if not set.
+	LuaFilters bool
 }
 
 // Failure carries validation failures for a single attribute of an object.
@@ -67,8 +94,40 @@ func (v *APIRuleValidator) Validate(api *gatewayv1beta1.APIRule, vsList networki
 	}
 	//Validate Host
 	res = append(res, v.validateHost(".spec.host", vsList, api)...)
+	//Validate additional Hosts
+	res = append(res, v.validateHosts(".spec.hosts", vsList, api)...)
 	//Validate Gateway
-	res = append(res, v.validateGateway(".spec.gateway", api.Spec.Gateway)...)
+	// An APIRule without a gateway is only exposed within the mesh.
+	if helpers.HasGateway(api) {
+		res = append(res, v.validateGateway(".spec.gateway", api.Spec.Gateway)...)
+	}
+	for i, gateway := range api.Spec.Gateways {
+		res = append(res, v.validateGateway(fmt.Sprintf(".spec.gateways[%d]", i), &gateway)...)
+		// An APIRule that is exposed on the mesh gateway is internal, so an ingress gateway must be the primary gateway
+		// to expose it on the mesh and an ingress gateway at the same time.
+		if helpers.IsMeshInternal(api) && gateway != helpers.MeshGateway {
+			if !helpers.HasGateway(api) {
+				res = append(res, Failure{AttributePath: ".spec.gateway", Message: "Gateway must be defined to expose the APIRule on additional ingress gateways"})
+				break
+			}
+			res = append(res, Failure{AttributePath: fmt.Sprintf(".spec.gateways[%d]", i), Message: "An APIRule exposed on the mesh gateway can't be exposed on additional ingress gateways"})
+		}
+	}
+	//Validate exported namespaces
+	if len(api.Spec.ExportTo) > 0 {
+		res = append(res, validateExportTo(".spec.exportTo", api.Spec.ExportTo)...)
+	}
+	//Validate CORS policy
+	if api.Spec.CorsPolicy != nil {
+		res = append(res, v.validateOriginsRegex(".spec.corsPolicy.allowOriginsRegex", api.Spec.CorsPolicy.AllowOriginsRegex)...)
+		if api.Spec.CorsPolicy.MaxAge != nil {
+			res = append(res, validateCorsMaxAge(".spec.corsPolicy.maxAge", *api.Spec.CorsPolicy.MaxAge)...)
+		}
+	}
+	//Validate maintenance
+	if api.Spec.Maintenance != nil {
+		res = append(res, validateMaintenance(".spec.maintenance", api.Spec.Maintenance)...)
+	}
 	//Validate Rules
 	res = append(res, v.validateRules(".spec.rules", api.Spec.Service == nil, api)...)
 
@@ -103,15 +162,93 @@ func (v *APIRuleValidator) validateHost(attributePath string, vsList networkingv
 		return problems
 	}
 
-	host := *api.Spec.Host
-	if !helpers.HostIncludesDomain(*api.Spec.Host) {
+	host := helpers.NormalizeHost(*api.Spec.Host)
+	if host == "" {
+		problems = append(problems, Failure{
+			AttributePath: attributePath,
+			Message:       "Host was empty",
+		})
+		return problems
+	}
+
+	if hostProblems := validateHostName(attributePath, host); len(hostProblems) > 0 {
+		return hostProblems
+	}
+
+	return v.validateHostValue(attributePath, host, vsList, api)
+}
+
+func (v *APIRuleValidator) validateHosts(attributePath string, vsList networkingv1beta1.VirtualServiceList, api *gatewayv1beta1.APIRule) []Failure {
+	var problems []Failure
+	if api.Spec.Hosts == nil {
+		return problems
+	}
+
+	if len(api.Spec.Hosts) == 0 {
+		problems = append(problems, Failure{
+			AttributePath: attributePath,
+			Message:       "Hosts was empty",
+		})
+		return problems
+	}
+
+	duplicates := make(map[string]bool)
+	if api.Spec.Host != nil {
+		duplicates[helpers.NormalizeHost(*api.Spec.Host)] = true
+	}
+
+	for i, host := range api.Spec.Hosts {
+		attributePathWithIndex := fmt.Sprintf("%s[%d]", attributePath, i)
+		host = helpers.NormalizeHost(host)
+		if hostProblems := validateHostName(attributePathWithIndex, host); len(hostProblems) > 0 {
+			problems = append(problems, hostProblems...)
+			continue
+		}
+
+		if duplicates[host] {
+			problems = append(problems, Failure{
+				AttributePath: attributePathWithIndex,
+				Message:       fmt.Sprintf("Host %s is defined multiple times", host),
+			})
+			continue
+		}
+		duplicates[host] = true
+
+		problems = append(problems, v.validateHostValue(attributePathWithIndex, host, vsList, api)...)
+	}
+
+	return problems
+}
+
+// validateHostName validates that the host is a domain name, which can start with a wildcard label to match all
+// subdomains, e.g. *.apps.example.com.
+func validateHostName(attributePath string, host string) []Failure {
+	if strings.Contains(host, "*") {
+		if !helpers.IsWildcardHost(host) || strings.Count(host, "*") > 1 {
+			return []Failure{{AttributePath: attributePath, Message: "Wildcard is only allowed as the leftmost label of the host"}}
+		}
+		host = strings.TrimPrefix(host, helpers.WildcardHostPrefix)
+	}
+
+	if !ValidateDomainName(host) {
+		return []Failure{{AttributePath: attributePath, Message: "Host is not a valid domain name"}}
+	}
+
+	return nil
+}
+
+func (v *APIRuleValidator) validateHostValue(attributePath string, host string, vsList networkingv1beta1.VirtualServiceList, api *gatewayv1beta1.APIRule) []Failure {
+	var problems []Failure
+
+	hostWithDomain := host
+	if !helpers.HostIncludesDomain(host) {
 		if v.DefaultDomainName == "" {
 			problems = append(problems, Failure{
 				AttributePath: attributePath,
 				Message:       "Host does not contain a domain name and no default domain name is configured",
 			})
 		}
-		host = helpers.GetHostWithDefaultDomain(host, v.DefaultDomainName)
+		hostWithDomain = helpers.GetHostWithDefaultDomain(host, v.DefaultDomainName)
 	} else if len(v.DomainAllowList) > 0 {
 		// Do the allowList check only if the list is actually provided AND the default domain name is not used.
 		domainFound := false
@@ -120,7 +257,7 @@ func (v *APIRuleValidator) validateHost(attributePath string, vsList networkingv
 			// for example `my-lambda.kyma.local.kyma.local`
 			// service host containing allowlisted domain but only as a part of bigger domain should also be rejected
 			// for example `my-lambda.kyma.local.com` when only `kyma.local` is allowlisted
-			if count := strings.Count(host, domain); count == 1 && strings.HasSuffix(host, domain) {
+			if count := strings.Count(hostWithDomain, domain); count == 1 && strings.HasSuffix(hostWithDomain, domain) {
 				domainFound = true
 			}
 		}
@@ -133,9 +270,9 @@ func (v *APIRuleValidator) validateHost(attributePath string, vsList networkingv
 	}
 
 	for _, blockedHost := range v.HostBlockList {
-		host := *api.Spec.Host
-		if blockedHost == host {
-			subdomain := strings.Split(host, ".")[0]
+		// A wildcard host is rejected if one of its subdomains is blocklisted.
+		if blockedHost == host || (helpers.IsWildcardHost(hostWithDomain) && strings.HasSuffix(blockedHost, strings.TrimPrefix(hostWithDomain, "*"))) {
+			subdomain := strings.Split(blockedHost, ".")[0]
 			problems = append(problems, Failure{
 				AttributePath: attributePath,
 				Message:       fmt.Sprintf("The subdomain %s is blocklisted for %s domain", subdomain, v.DefaultDomainName),
@@ -144,7 +281,7 @@ func (v *APIRuleValidator) validateHost(attributePath string, vsList networkingv
 	}
 
 	for _, vs := range vsList.Items {
-		if occupiesHost(vs, host) && !ownedBy(vs, api) {
+		if occupiesHost(vs, hostWithDomain) && !v.ownedBy(vs, api) {
 			problems = append(problems, Failure{
 				AttributePath: attributePath,
 				Message:       "This host is occupied by another Virtual Service",
@@ -156,7 +293,16 @@ func (v *APIRuleValidator) validateHost(attributePath string, vsList networkingv
 }
 
 func (v *APIRuleValidator) validateService(attributePath string, api *gatewayv1beta1.APIRule) []Failure {
-	var problems []Failure
+	if !hasServiceName(api.Spec.Service) {
+		return []Failure{{AttributePath: attributePath + ".name", Message: "Service must define name"}}
+	}
+
+	problems := validateServicePort(attributePath, api.Spec.Service)
+	problems = append(problems, validateLoadBalancer(attributePath+".trafficPolicy.loadBalancer", api.Spec.Service)...)
+	problems = append(problems, v.validateServiceNamespace(attributePath, helpers.FindServiceNamespace(api, nil), api)...)
+	if len(problems) == 0 {
+		problems = append(problems, v.validateServiceExistence(attributePath, api.Spec.Service, helpers.FindServiceNamespace(api, nil))...)
+	}
 
 	for namespace, services := range v.ServiceBlockList {
 		for _, svc := range services {
@@ -172,10 +318,46 @@ func (v *APIRuleValidator) validateService(attributePath string, api *gatewayv1b
 	return problems
 }
 
+// Validates that the gateway is referenced either by name, by namespace/name or by its fully qualified name
 func (v *APIRuleValidator) validateGateway(attributePath string, gateway *string) []Failure {
+	if gateway == nil {
+		return nil
+	}
+
+	if namespace, name, namespaced := strings.Cut(*gateway, "/"); namespaced {
+		if len(k8svalidation.IsDNS1123Label(namespace)) > 0 || len(k8svalidation.IsDNS1123Label(name)) > 0 {
+			return []Failure{{AttributePath: attributePath, Message: fmt.Sprintf("Gateway %s is not a valid namespace/name reference", *gateway)}}
+		}
+		return nil
+	}
+
+	if len(k8svalidation.IsDNS1123Subdomain(*gateway)) > 0 {
+		return []Failure{{AttributePath: attributePath, Message: fmt.Sprintf("Gateway %s is not a valid gateway name", *gateway)}}
+	}
+
 	return nil
 }
 
+// Validates that the VirtualService is exported to the namespace of the APIRule, to all namespaces or to namespaces by name
+func validateExportTo(attributePath string, exportTo []string) []Failure {
+	var problems []Failure
+
+	for i, namespace := range exportTo {
+		if namespace == "." || namespace == "*" {
+			continue
+		}
+		if len(k8svalidation.IsDNS1123Label(namespace)) > 0 {
+			problems = append(problems, Failure{AttributePath: fmt.Sprintf("%s[%d]", attributePath, i), Message: fmt.Sprintf("Namespace %s is not a valid namespace name", namespace)})
+		}
+	}
+	// Istio rejects an export to all namespaces that is combined with other namespaces.
+	if len(exportTo) > 1 && slices.Contains(exportTo, "*") {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Export to all namespaces can't be combined with other namespaces"})
+	}
+
+	return problems
+}
+
 // Validates whether all rules are defined correctly
 // Checks whether all rules have service defined for them if checkForService is true
 func (v *APIRuleValidator) validateRules(attributePath string, checkForService bool, api *gatewayv1beta1.APIRule) []Failure {
@@ -187,17 +369,126 @@ func (v *APIRuleValidator) validateRules(attributePath string, checkForService b
 		return problems
 	}
 
+	// A Virtual Service without routes is rejected by Istio, so at least one rule must remain enabled.
+	hasEnabledRule := false
+	for _, r := range rules {
+		if r.IsEnabled() {
+			hasEnabledRule = true
+			break
+		}
+	}
+	if !hasEnabledRule {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "No enabled rules defined"})
+	}
+
 	if hasPathAndMethodDuplicates(rules) {
 		problems = append(problems, Failure{AttributePath: attributePath, Message: "multiple rules defined for the same path and method"})
 	}
 
+	defaultBackends := 0
+	for _, r := range rules {
+		if r.DefaultBackend {
+			defaultBackends++
+		}
+	}
+	if defaultBackends > 1 {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Only one rule can be the default backend"})
+	}
+
 	for i, r := range rules {
 		attributePathWithRuleIndex := fmt.Sprintf("%s[%d]", attributePath, i)
 		problems = append(problems, v.validateMethods(attributePathWithRuleIndex+".methods", r.Methods)...)
-		if checkForService && r.Service == nil {
+		if r.Timeout != nil {
+			problems = append(problems, v.validateTimeout(attributePathWithRuleIndex+".timeout", *r.Timeout)...)
+		}
+		if len(r.PortTimeouts) > 0 {
+			problems = append(problems, v.validatePortTimeouts(attributePathWithRuleIndex+".portTimeouts", r, api)...)
+		}
+		if r.IdleTimeout != nil {
+			problems = append(problems, v.validateIdleTimeout(attributePathWithRuleIndex+".idleTimeout", r)...)
+		}
+		if r.Deprecation != nil {
+			problems = append(problems, validateDeprecation(attributePathWithRuleIndex+".deprecation", r.Deprecation)...)
+		}
+		if r.MaxRequestHeadersBytes != nil && *r.MaxRequestHeadersBytes%1024 != 0 {
+			problems = append(problems, Failure{AttributePath: attributePathWithRuleIndex + ".maxRequestHeadersBytes", Message: "Max request headers bytes must be a multiple of 1024, since Envoy limits the header size in kilobytes"})
+		}
+		if r.Retries != nil && r.Retries.PerTryTimeout != nil {
+			problems = append(problems, v.validateTimeout(attributePathWithRuleIndex+".retries.perTryTimeout", *r.Retries.PerTryTimeout)...)
+		}
+		if r.CorsPolicy != nil {
+			problems = append(problems, v.validateOriginsRegex(attributePathWithRuleIndex+".corsPolicy.allowOriginsRegex", r.CorsPolicy.AllowOriginsRegex)...)
+			if r.CorsPolicy.MaxAge != nil {
+				problems = append(problems, validateCorsMaxAge(attributePathWithRuleIndex+".corsPolicy.maxAge", *r.CorsPolicy.MaxAge)...)
+			}
+		}
+		if len(r.Destinations) > 0 {
+			problems = append(problems, v.validateDestinations(attributePathWithRuleIndex+".destinations", r, api)...)
+		}
+		if len(r.ProtocolPorts) > 0 {
+			problems = append(problems, validateProtocolPorts(attributePathWithRuleIndex+".protocolPorts", r)...)
+		}
+		if len(r.MatchHeaders) > 0 {
+			problems = append(problems, v.validateMatches(attributePathWithRuleIndex+".matchHeaders", "Header matches", r, r.MatchHeaders, true)...)
+		}
+		if len(r.MatchQueryParams) > 0 {
+			problems = append(problems, v.validateMatches(attributePathWithRuleIndex+".matchQueryParams", "Query parameter matches", r, r.MatchQueryParams, false)...)
+		}
+		if r.Rewrite != nil {
+			if r.Rewrite.URI == "" && r.Rewrite.Authority == "" && r.Rewrite.URIRegex == nil {
+				problems = append(problems, Failure{AttributePath: attributePathWithRuleIndex + ".rewrite", Message: "Rewrite must define uri or authority"})
+			}
+			if r.Rewrite.URIRegex != nil {
+				problems = append(problems, validateRegexRewrite(attributePathWithRuleIndex+".rewrite.uriRegex", r.Rewrite)...)
+			}
+			if r.Redirect != nil {
+				problems = append(problems, Failure{AttributePath: attributePathWithRuleIndex + ".rewrite", Message: "Rewrite is not supported for rules with a redirect"})
+			}
+			if r.DirectResponse != nil {
+				problems = append(problems, Failure{AttributePath: attributePathWithRuleIndex + ".rewrite", Message: "Rewrite is not supported for rules with a direct response"})
+			}
+		}
+		if r.StripPrefix {
+			problems = append(problems, validateStripPrefix(attributePathWithRuleIndex+".stripPrefix", r)...)
+		}
+		// The default backend matches all requests, so any other path would be misleading.
+		if r.DefaultBackend && r.Path != "/*" && r.Path != "/.*" && r.Path != "/" {
+			problems = append(problems, Failure{AttributePath: attributePathWithRuleIndex + ".path", Message: "The default backend rule must use a catch-all path"})
+		}
+		if r.Subset != "" {
+			problems = append(problems, v.validateSubset(attributePathWithRuleIndex+".subset", r, api)...)
+		}
+		if r.Fault != nil && r.Fault.Delay == nil && r.Fault.Abort == nil {
+			problems = append(problems, Failure{AttributePath: attributePathWithRuleIndex + ".fault", Message: "Fault must define delay or abort"})
+		}
+		if r.Mirror != nil {
+			problems = append(problems, v.validateMirror(attributePathWithRuleIndex+".mirror", r, api)...)
+		}
+		if r.Lua != nil {
+			problems = append(problems, v.validateLua(attributePathWithRuleIndex+".lua", api)...)
+		}
+		if len(r.SourceCIDRs) > 0 {
+			problems = append(problems, validateSourceCIDRs(attributePathWithRuleIndex+".sourceCIDRs", r)...)
+		}
+		if r.Redirect != nil && !hasOnlyAllowAccessStrategy(r) {
+			problems = append(problems, Failure{AttributePath: attributePathWithRuleIndex + ".redirect", Message: "Redirect is only supported for rules with the allow access strategy"})
+		}
+		if r.DirectResponse != nil {
+			problems = append(problems, validateDirectResponse(attributePathWithRuleIndex+".directResponse", r)...)
+		}
+		if checkForService && r.Service == nil && len(r.Destinations) == 0 && r.Redirect == nil && r.DirectResponse == nil {
 			problems = append(problems, Failure{AttributePath: attributePathWithRuleIndex + ".service", Message: "No service defined with no main service on spec level"})
 		}
-		if r.Service != nil {
+		if r.Service != nil && !hasServiceName(r.Service) {
+			problems = append(problems, Failure{AttributePath: attributePathWithRuleIndex + ".service.name", Message: "Service must define name"})
+		} else if r.Service != nil {
+			problems = append(problems, v.validateServiceNamespace(attributePathWithRuleIndex+".service", helpers.FindServiceNamespace(api, &r), api)...)
+			problems = append(problems, validateLoadBalancer(attributePathWithRuleIndex+".service.trafficPolicy.loadBalancer", r.Service)...)
+			if portProblems := validateServicePort(attributePathWithRuleIndex+".service", r.Service); len(portProblems) > 0 {
+				problems = append(problems, portProblems...)
+			} else {
+				problems = append(problems, v.validateServiceExistence(attributePathWithRuleIndex+".service", r.Service, helpers.FindServiceNamespace(api, &r))...)
+			}
 			problems = append(problems, v.validateAccessStrategies(attributePathWithRuleIndex+".accessStrategies", r.AccessStrategies, builders.SelectorFromService(r.Service), helpers.FindServiceNamespace(api, &r))...)
 			for namespace, services := range v.ServiceBlockList {
 				for _, svc := range services {
@@ -210,7 +501,7 @@ func (v *APIRuleValidator) validateRules(attributePath string, checkForService b
 					}
 				}
 			}
-		} else if api.Spec.Service != nil {
+		} else if api.Spec.Service != nil && hasServiceName(api.Spec.Service) {
 			problems = append(problems, v.validateAccessStrategies(attributePathWithRuleIndex+".accessStrategies", r.AccessStrategies, builders.SelectorFromService(api.Spec.Service), helpers.FindServiceNamespace(api, &r))...)
 		}
 
@@ -229,9 +520,493 @@ func (v *APIRuleValidator) validateRules(attributePath string, checkForService b
 }
 
 func (v *APIRuleValidator) validateMethods(attributePath string, methods []string) []Failure {
+	var problems []Failure
+
+	// The methods are matched in upper case, so methods that only differ in case are duplicates.
+	defined := make(map[string]bool, len(methods))
+	for i, method := range methods {
+		normalized := strings.ToUpper(strings.TrimSpace(method))
+		attributePathWithIndex := fmt.Sprintf("%s[%d]", attributePath, i)
+		if !slices.Contains(httpMethods, normalized) {
+			problems = append(problems, Failure{AttributePath: attributePathWithIndex, Message: fmt.Sprintf("Method %s is not a valid HTTP method", method)})
+			continue
+		}
+		if defined[normalized] {
+			problems = append(problems, Failure{AttributePath: attributePathWithIndex, Message: fmt.Sprintf("Method %s is defined more than once", method)})
+		}
+		defined[normalized] = true
+	}
+
+	return problems
+}
+
+func (v *APIRuleValidator) validateTimeout(attributePath string, timeout string) []Failure {
+	duration, err := time.ParseDuration(timeout)
+	if err != nil {
+		return []Failure{{AttributePath: attributePath, Message: fmt.Sprintf("Timeout is not a valid duration: %s", err)}}
+	}
+	if duration <= 0 {
+		return []Failure{{AttributePath: attributePath, Message: "Timeout must be a positive duration"}}
+	}
+	if duration > maxTimeout {
+		return []Failure{{AttributePath: attributePath, Message: fmt.Sprintf("Timeout must not exceed %s", maxTimeout)}}
+	}
 	return nil
 }
 
+func (v *APIRuleValidator) validatePortTimeouts(attributePath string, rule gatewayv1beta1.Rule, api *gatewayv1beta1.APIRule) []Failure {
+	var problems []Failure
+
+	ports, resolved := helpers.GetRulePorts(api, &rule)
+	timeoutPorts := make(map[uint32]bool)
+	for i, portTimeout := range rule.PortTimeouts {
+		attributePathWithIndex := fmt.Sprintf("%s[%d]", attributePath, i)
+		problems = append(problems, v.validateTimeout(attributePathWithIndex+".timeout", portTimeout.Timeout)...)
+		if timeoutPorts[portTimeout.Port] {
+			problems = append(problems, Failure{AttributePath: attributePathWithIndex + ".port", Message: fmt.Sprintf("Timeout is defined more than once for port %d", portTimeout.Port)})
+		}
+		timeoutPorts[portTimeout.Port] = true
+		// The port of a service referenced by port name is only known after the port name is resolved.
+		if resolved && !ports[portTimeout.Port] {
+			problems = append(problems, Failure{AttributePath: attributePathWithIndex + ".port", Message: fmt.Sprintf("Port %d is not used by the rule", portTimeout.Port)})
+		}
+	}
+
+	return problems
+}
+
+func (v *APIRuleValidator) validateIdleTimeout(attributePath string, rule gatewayv1beta1.Rule) []Failure {
+	if problems := v.validateTimeout(attributePath, *rule.IdleTimeout); len(problems) > 0 {
+		return problems
+	}
+	if rule.Timeout == nil {
+		return nil
+	}
+
+	// Invalid timeouts are reported by the validation of the timeout.
+	idleTimeout, _ := time.ParseDuration(*rule.IdleTimeout)
+	timeout, err := time.ParseDuration(*rule.Timeout)
+	if err == nil && idleTimeout > timeout {
+		return []Failure{{AttributePath: attributePath, Message: "Idle timeout must not be longer than the timeout of the rule"}}
+	}
+	return nil
+}
+
+func validateDeprecation(attributePath string, deprecation *gatewayv1beta1.Deprecation) []Failure {
+	var problems []Failure
+
+	var since, sunset time.Time
+	var err error
+	if deprecation.Since != nil {
+		if since, err = time.Parse(time.RFC3339, *deprecation.Since); err != nil {
+			problems = append(problems, Failure{AttributePath: attributePath + ".since", Message: fmt.Sprintf("Date is not a valid RFC 3339 date: %s", *deprecation.Since)})
+		}
+	}
+	if deprecation.Sunset != nil {
+		if sunset, err = time.Parse(time.RFC3339, *deprecation.Sunset); err != nil {
+			problems = append(problems, Failure{AttributePath: attributePath + ".sunset", Message: fmt.Sprintf("Date is not a valid RFC 3339 date: %s", *deprecation.Sunset)})
+		}
+	}
+
+	if len(problems) == 0 && deprecation.Since != nil && deprecation.Sunset != nil && !sunset.After(since) {
+		problems = append(problems, Failure{AttributePath: attributePath + ".sunset", Message: "Sunset must be after the date since when the rule is deprecated"})
+	}
+
+	return problems
+}
+
+func (v *APIRuleValidator) validateDestinations(attributePath string, rule gatewayv1beta1.Rule, api *gatewayv1beta1.APIRule) []Failure {
+	var problems []Failure
+
+	if !hasOnlyAllowAccessStrategy(rule) {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Destinations are only supported for rules with the allow access strategy"})
+	}
+
+	var weightSum int32
+	for i, destination := range rule.Destinations {
+		weightSum += destination.Weight
+		if destination.Name == nil || destination.Port == nil {
+			problems = append(problems, Failure{AttributePath: fmt.Sprintf("%s[%d]", attributePath, i), Message: "Destination must define service name and port"})
+			continue
+		}
+		destinationNamespace := helpers.FindDestinationNamespace(api, &destination.Service)
+		problems = append(problems, validateLoadBalancer(fmt.Sprintf("%s[%d].trafficPolicy.loadBalancer", attributePath, i), &destination.Service)...)
+		problems = append(problems, v.validateServiceNamespace(fmt.Sprintf("%s[%d]", attributePath, i), destinationNamespace, api)...)
+		problems = append(problems, v.validateServiceExistence(fmt.Sprintf("%s[%d]", attributePath, i), &destination.Service, destinationNamespace)...)
+		for _, svc := range v.ServiceBlockList[destinationNamespace] {
+			if svc == *destination.Name {
+				problems = append(problems, Failure{
+					AttributePath: fmt.Sprintf("%s[%d].name", attributePath, i),
+					Message:       fmt.Sprintf("Service %s in namespace %s is blocklisted", svc, destinationNamespace),
+				})
+			}
+		}
+	}
+	if weightSum != 100 {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: fmt.Sprintf("Weights of destinations must sum up to 100, but sum up to %d", weightSum)})
+	}
+
+	return problems
+}
+
+// validateStripPrefix validates that the matched prefix can be stripped from the URI of the requests of the rule, which
+// requires a prefix match and a rule that forwards the requests to the service.
+func validateStripPrefix(attributePath string, rule gatewayv1beta1.Rule) []Failure {
+	var problems []Failure
+
+	// gRPC rules match the path by prefix if no path match type is defined.
+	pathMatchType := rule.PathMatchType
+	if pathMatchType == "" && rule.RouteType == gatewayv1beta1.RouteTypeGRPC {
+		pathMatchType = gatewayv1beta1.PathMatchPrefix
+	}
+	if pathMatchType != gatewayv1beta1.PathMatchPrefix {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Strip prefix is only supported for rules with the prefix path match type"})
+	}
+	if rule.DefaultBackend {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Strip prefix is not supported for the default backend rule"})
+	}
+	if rule.Rewrite != nil && (rule.Rewrite.URI != "" || rule.Rewrite.URIRegex != nil) {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Strip prefix can't be combined with a rewrite of the uri"})
+	}
+	if rule.Redirect != nil || rule.DirectResponse != nil {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Strip prefix is not supported for rules with a redirect or a direct response"})
+	}
+	for _, accessStrategy := range rule.AccessStrategies {
+		if accessStrategy.Handler == nil || (accessStrategy.Handler.Name != "allow" && accessStrategy.Handler.Name != "jwt") {
+			problems = append(problems, Failure{AttributePath: attributePath, Message: "Strip prefix is only supported for rules with the allow or jwt access strategy"})
+			break
+		}
+	}
+
+	return problems
+}
+
+func validateProtocolPorts(attributePath string, rule gatewayv1beta1.Rule) []Failure {
+	var problems []Failure
+
+	for _, accessStrategy := range rule.AccessStrategies {
+		if accessStrategy.Handler == nil || (accessStrategy.Handler.Name != "allow" && accessStrategy.Handler.Name != "jwt") {
+			problems = append(problems, Failure{AttributePath: attributePath, Message: "Protocol ports are only supported for rules with the allow or jwt access strategy"})
+			break
+		}
+	}
+	if len(rule.Destinations) > 0 || rule.Redirect != nil || rule.DirectResponse != nil {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Protocol ports are not supported for rules with destinations, a redirect or a direct response"})
+	}
+	if rule.RouteType != "" {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Protocol ports are not supported for rules with a route type, since the protocol defines the route type"})
+	}
+	// The gRPC requests are matched by their content type.
+	for name := range rule.MatchHeaders {
+		if strings.EqualFold(name, "content-type") {
+			problems = append(problems, Failure{AttributePath: attributePath, Message: "Protocol ports are not supported for rules that match the content-type header"})
+		}
+	}
+
+	protocols := make(map[gatewayv1beta1.RouteType]bool)
+	ports := make(map[uint32]bool)
+	for i, protocolPort := range rule.ProtocolPorts {
+		if protocols[protocolPort.Protocol] {
+			problems = append(problems, Failure{AttributePath: fmt.Sprintf("%s[%d].protocol", attributePath, i), Message: fmt.Sprintf("Protocol %s is mapped to more than one port", protocolPort.Protocol)})
+		}
+		if ports[protocolPort.Port] {
+			problems = append(problems, Failure{AttributePath: fmt.Sprintf("%s[%d].port", attributePath, i), Message: fmt.Sprintf("Port %d is mapped to more than one protocol", protocolPort.Port)})
+		}
+		protocols[protocolPort.Protocol] = true
+		ports[protocolPort.Port] = true
+	}
+
+	return problems
+}
+
+func validateSourceCIDRs(attributePath string, rule gatewayv1beta1.Rule) []Failure {
+	var problems []Failure
+
+	// The source CIDRs are enforced by an AuthorizationPolicy for the workload, which doesn't receive these requests.
+	if rule.Redirect != nil || rule.DirectResponse != nil {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Source CIDRs are not supported for rules with a redirect or a direct response"})
+	}
+	for i, cidr := range rule.SourceCIDRs {
+		if net.ParseIP(cidr) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			problems = append(problems, Failure{AttributePath: fmt.Sprintf("%s[%d]", attributePath, i), Message: fmt.Sprintf("Source CIDR %s is not a valid IP address or CIDR range", cidr)})
+		}
+	}
+
+	return problems
+}
+
+func (v *APIRuleValidator) validateMatches(attributePath string, kind string, rule gatewayv1beta1.Rule, matches map[string]gatewayv1beta1.StringMatch, prefixSupported bool) []Failure {
+	var problems []Failure
+
+	for _, accessStrategy := range rule.AccessStrategies {
+		if accessStrategy.Handler == nil || (accessStrategy.Handler.Name != "allow" && accessStrategy.Handler.Name != "jwt") {
+			problems = append(problems, Failure{AttributePath: attributePath, Message: fmt.Sprintf("%s are only supported for rules with the allow or jwt access strategy", kind)})
+			break
+		}
+	}
+
+	for name, match := range matches {
+		if !prefixSupported && match.Prefix != "" {
+			problems = append(problems, Failure{AttributePath: attributePath + "." + name, Message: "Prefix match is not supported, exactly one of exact or regex must be defined"})
+			continue
+		}
+
+		definedMatches := 0
+		for _, value := range []string{match.Exact, match.Prefix, match.Regex} {
+			if value != "" {
+				definedMatches++
+			}
+		}
+		if definedMatches != 1 {
+			if prefixSupported {
+				problems = append(problems, Failure{AttributePath: attributePath + "." + name, Message: "Exactly one of exact, prefix or regex must be defined"})
+			} else {
+				problems = append(problems, Failure{AttributePath: attributePath + "." + name, Message: "Exactly one of exact or regex must be defined"})
+			}
+		}
+	}
+
+	return problems
+}
+
+func (v *APIRuleValidator) validateSubset(attributePath string, rule gatewayv1beta1.Rule, api *gatewayv1beta1.APIRule) []Failure {
+	if len(rule.Destinations) > 0 || rule.Redirect != nil || rule.DirectResponse != nil {
+		return []Failure{{AttributePath: attributePath, Message: "Subset is only supported for rules that route to a single service"}}
+	}
+
+	service := rule.Service
+	if service == nil {
+		service = api.Spec.Service
+	}
+	if service == nil || service.Name == nil {
+		return nil
+	}
+
+	for _, subset := range service.Subsets {
+		if subset.Name == rule.Subset {
+			return nil
+		}
+	}
+
+	return []Failure{{AttributePath: attributePath, Message: fmt.Sprintf("Subset %s is not defined for service %s", rule.Subset, *service.Name)}}
+}
+
+var captureGroupReference = regexp.MustCompile(`\$(\d+)`)
+
+func validateRegexRewrite(attributePath string, rewrite *gatewayv1beta1.Rewrite) []Failure {
+	if rewrite.URI != "" {
+		return []Failure{{AttributePath: attributePath, Message: "Rewrite must define either uri or uriRegex"}}
+	}
+
+	re, err := regexp.Compile(rewrite.URIRegex.Match)
+	if err != nil {
+		return []Failure{{AttributePath: attributePath + ".match", Message: fmt.Sprintf("Match is not a valid regular expression: %s", err)}}
+	}
+	for _, reference := range captureGroupReference.FindAllStringSubmatch(rewrite.URIRegex.Rewrite, -1) {
+		if group, _ := strconv.Atoi(reference[1]); group > re.NumSubexp() {
+			return []Failure{{AttributePath: attributePath + ".rewrite", Message: fmt.Sprintf("Rewrite references the capture group %s, but the match only defines %d", reference[0], re.NumSubexp())}}
+		}
+	}
+
+	// The Istio API of the gateway doesn't provide the uriRegexRewrite yet, and the HTTPRewrite doesn't support captures.
+	return []Failure{{AttributePath: attributePath, Message: "Rewrite with capture groups isn't supported by the Istio version of the gateway"}}
+}
+
+func validateDirectResponse(attributePath string, rule gatewayv1beta1.Rule) []Failure {
+	var problems []Failure
+
+	if !hasOnlyAllowAccessStrategy(rule) {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Direct response is only supported for rules with the allow access strategy"})
+	}
+	if rule.Redirect != nil || len(rule.Destinations) > 0 {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Direct response is not supported for rules with a redirect or destinations"})
+	}
+
+	return problems
+}
+
+func (v *APIRuleValidator) validateLua(attributePath string, api *gatewayv1beta1.APIRule) []Failure {
+	if !v.LuaFilters {
+		return []Failure{{AttributePath: attributePath, Message: "Lua scripts are not enabled for the controller"}}
+	}
+	// The Envoy Filters of the Lua scripts only apply to the ingress gateway.
+	if helpers.IsMeshInternal(api) {
+		return []Failure{{AttributePath: attributePath, Message: "Lua scripts are not supported for APIRules that are only exposed within the mesh"}}
+	}
+
+	return nil
+}
+
+func (v *APIRuleValidator) validateMirror(attributePath string, rule gatewayv1beta1.Rule, api *gatewayv1beta1.APIRule) []Failure {
+	var problems []Failure
+
+	if !hasOnlyAllowAccessStrategy(rule) {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Mirror is only supported for rules with the allow access strategy"})
+	}
+	if rule.Redirect != nil {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Mirror is not supported for rules with a redirect"})
+	}
+	if rule.DirectResponse != nil {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Mirror is not supported for rules with a direct response"})
+	}
+	if rule.Mirror.Name == nil || rule.Mirror.Port == nil {
+		problems = append(problems, Failure{AttributePath: attributePath, Message: "Mirror must define service name and port"})
+		return problems
+	}
+
+	mirrorNamespace := helpers.FindDestinationNamespace(api, &rule.Mirror.Service)
+	problems = append(problems, v.validateServiceNamespace(attributePath, mirrorNamespace, api)...)
+	problems = append(problems, v.validateServiceExistence(attributePath, &rule.Mirror.Service, mirrorNamespace)...)
+	for _, svc := range v.ServiceBlockList[mirrorNamespace] {
+		if svc == *rule.Mirror.Name {
+			problems = append(problems, Failure{
+				AttributePath: attributePath + ".name",
+				Message:       fmt.Sprintf("Service %s in namespace %s is blocklisted", svc, mirrorNamespace),
+			})
+		}
+	}
+
+	return problems
+}
+
+// validateServiceNamespace checks that a service in another namespace than the APIRule is allowed to be referenced.
+func (v *APIRuleValidator) validateServiceNamespace(attributePath string, namespace string, api *gatewayv1beta1.APIRule) []Failure {
+	if !v.RestrictServiceNamespaces || namespace == api.Namespace || slices.Contains(v.ServiceNamespaceAllowList, namespace) {
+		return nil
+	}
+
+	return []Failure{{AttributePath: attributePath + ".namespace", Message: fmt.Sprintf("Services in namespace %s can't be referenced from namespace %s", namespace, api.Namespace)}}
+}
+
+// validateServiceExistence checks that the service exists in the cluster and exposes the port, if a ServiceValidator is
+// configured. External services are not part of the cluster and therefore not checked.
+func (v *APIRuleValidator) validateServiceExistence(attributePath string, service *gatewayv1beta1.Service, namespace string) []Failure {
+	if v.ServiceValidator == nil || (service.IsExternal != nil && *service.IsExternal) {
+		return nil
+	}
+
+	problems, err := v.ServiceValidator.Validate(attributePath, service, namespace)
+	if err != nil {
+		return []Failure{{AttributePath: attributePath, Message: fmt.Sprintf("Could not check the existence of the service, err: %s", err)}}
+	}
+
+	return problems
+}
+
+// validateServicePort checks that the port of the service is defined either by number or by name.
+func validateServicePort(attributePath string, service *gatewayv1beta1.Service) []Failure {
+	if service.Port == nil && service.PortName == "" {
+		return []Failure{{AttributePath: attributePath + ".port", Message: "Service must define port or portName"}}
+	}
+	if service.Port != nil && service.PortName != "" {
+		return []Failure{{AttributePath: attributePath + ".port", Message: "Service must define either port or portName"}}
+	}
+
+	return nil
+}
+
+// hasServiceName checks that the name of the service is defined and not empty.
+func hasServiceName(service *gatewayv1beta1.Service) bool {
+	return service.Name != nil && *service.Name != ""
+}
+
+// validateLoadBalancer validates that the consistent hash of the load balancer of the service defines exactly one valid
+// source of the hash key, and that it is only defined for the consistentHash algorithm.
+func validateLoadBalancer(attributePath string, service *gatewayv1beta1.Service) []Failure {
+	if service.TrafficPolicy == nil || service.TrafficPolicy.LoadBalancer == nil {
+		return nil
+	}
+
+	loadBalancer := service.TrafficPolicy.LoadBalancer
+	hash := loadBalancer.ConsistentHash
+	if loadBalancer.Algorithm != gatewayv1beta1.LoadBalancerConsistentHash {
+		if hash != nil {
+			return []Failure{{AttributePath: attributePath + ".consistentHash", Message: "Consistent hash is only supported for the consistentHash algorithm"}}
+		}
+		return nil
+	}
+	if hash == nil {
+		return []Failure{{AttributePath: attributePath + ".consistentHash", Message: "Consistent hash must be defined for the consistentHash algorithm"}}
+	}
+
+	definedSources := 0
+	for _, defined := range []bool{hash.HTTPHeaderName != "", hash.HTTPCookie != nil, hash.UseSourceIP} {
+		if defined {
+			definedSources++
+		}
+	}
+	if definedSources != 1 {
+		return []Failure{{AttributePath: attributePath + ".consistentHash", Message: "Exactly one of httpHeaderName, httpCookie or useSourceIp must be defined"}}
+	}
+
+	if hash.HTTPHeaderName != "" && !httpguts.ValidHeaderFieldName(hash.HTTPHeaderName) {
+		return []Failure{{AttributePath: attributePath + ".consistentHash.httpHeaderName", Message: fmt.Sprintf("Header %s is not a valid HTTP header name", hash.HTTPHeaderName)}}
+	}
+	// The name of a cookie is a token like the name of a header.
+	if hash.HTTPCookie != nil && !httpguts.ValidHeaderFieldName(hash.HTTPCookie.Name) {
+		return []Failure{{AttributePath: attributePath + ".consistentHash.httpCookie.name", Message: fmt.Sprintf("Cookie %s is not a valid cookie name", hash.HTTPCookie.Name)}}
+	}
+
+	return nil
+}
+
+func hasOnlyAllowAccessStrategy(rule gatewayv1beta1.Rule) bool {
+	if len(rule.Mutators) > 0 {
+		return false
+	}
+	for _, accessStrategy := range rule.AccessStrategies {
+		if accessStrategy.Handler == nil || accessStrategy.Handler.Name != "allow" {
+			return false
+		}
+	}
+	return true
+}
+
+func validateCorsMaxAge(attributePath string, maxAge string) []Failure {
+	duration, err := time.ParseDuration(maxAge)
+	if err != nil {
+		return []Failure{{AttributePath: attributePath, Message: fmt.Sprintf("Max age is not a valid duration: %s", err)}}
+	}
+	if duration < 0 {
+		return []Failure{{AttributePath: attributePath, Message: "Max age must not be negative"}}
+	}
+	return nil
+}
+
+func validateMaintenance(attributePath string, maintenance *gatewayv1beta1.Maintenance) []Failure {
+	var problems []Failure
+
+	if maintenance.Status != nil && (*maintenance.Status < 500 || *maintenance.Status > 599) {
+		problems = append(problems, Failure{AttributePath: attributePath + ".status", Message: fmt.Sprintf("Maintenance status %d is not a server error status code between 500 and 599", *maintenance.Status)})
+	}
+	if maintenance.RetryAfter != nil {
+		// RFC 9110 defines the Retry-After header either as a number of seconds or as an HTTP date.
+		if _, err := strconv.ParseUint(*maintenance.RetryAfter, 10, 32); err != nil {
+			if _, err := http.ParseTime(*maintenance.RetryAfter); err != nil {
+				problems = append(problems, Failure{AttributePath: attributePath + ".retryAfter", Message: fmt.Sprintf("Retry-After %s is neither a number of seconds nor an HTTP date", *maintenance.RetryAfter)})
+			}
+		}
+	}
+
+	return problems
+}
+
+func (v *APIRuleValidator) validateOriginsRegex(attributePath string, origins []string) []Failure {
+	var problems []Failure
+	for i, origin := range origins {
+		if _, err := regexp.Compile(origin); err != nil {
+			problems = append(problems, Failure{
+				AttributePath: fmt.Sprintf("%s[%d]", attributePath, i),
+				Message:       fmt.Sprintf("Origin is not a valid regular expression: %s", err),
+			})
+		}
+	}
+	return problems
+}
+
 func (v *APIRuleValidator) validateAccessStrategies(attributePath string, accessStrategies []*gatewayv1beta1.Authenticator, selector *apiv1beta1.WorkloadSelector, namespace string) []Failure {
 	var problems []Failure
 
@@ -295,14 +1070,28 @@ func occupiesHost(vs *networkingv1beta1.VirtualService, host string) bool {
 	return false
 }
 
+var ownerLabelv1alpha1 = fmt.Sprintf("%s.%s", "apirule", gatewayv1alpha1.GroupVersion.String())
+
 func getOwnerLabels(api *gatewayv1beta1.APIRule) map[string]string {
-	OwnerLabelv1alpha1 := fmt.Sprintf("%s.%s", "apirule", gatewayv1alpha1.GroupVersion.String())
 	labels := make(map[string]string)
-	labels[OwnerLabelv1alpha1] = fmt.Sprintf("%s.%s", api.ObjectMeta.Name, api.ObjectMeta.Namespace)
+	labels[ownerLabelv1alpha1] = fmt.Sprintf("%s.%s", api.ObjectMeta.Name, api.ObjectMeta.Namespace)
 	return labels
 }
 
-func ownedBy(vs *networkingv1beta1.VirtualService, api *gatewayv1beta1.APIRule) bool {
+func (v *APIRuleValidator) ownedBy(vs *networkingv1beta1.VirtualService, api *gatewayv1beta1.APIRule) bool {
+	// The aggregated Virtual Services have no owner label, since they contain the routes of all APIRules in the namespace
+	// that share the hosts and gateways. The APIRule owns the aggregated Virtual Service it is listed in, or the one its
+	// routes are merged into.
+	if vs.Labels[helpers.AggregatedLabel] == "true" {
+		if vs.Namespace != api.Namespace {
+			return false
+		}
+		if _, ok := helpers.GetOwners(vs.Annotations)[getOwnerLabels(api)[ownerLabelv1alpha1]]; ok {
+			return true
+		}
+		return slices.Equal(vs.Spec.Hosts, v.getHostsWithDomain(api)) && slices.Equal(vs.Spec.Gateways, helpers.GetGateways(api))
+	}
+
 	ownerLabels := getOwnerLabels(api)
 	vsLabels := vs.GetLabels()
 
@@ -314,3 +1103,16 @@ func ownedBy(vs *networkingv1beta1.VirtualService, api *gatewayv1beta1.APIRule)
 	}
 	return false
 }
+
+// getHostsWithDomain returns the hosts of the APIRule like in its Virtual Service, where the hosts without a domain
+// are completed with the default domain.
+func (v *APIRuleValidator) getHostsWithDomain(api *gatewayv1beta1.APIRule) []string {
+	var hosts []string
+	for _, host := range helpers.GetHosts(api) {
+		if !helpers.HostIncludesDomain(host) {
+			host = helpers.GetHostWithDefaultDomain(host, v.DefaultDomainName)
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
@@ -203,6 +203,54 @@ var _ = Describe("Validate function", func() {
 		Expect(problems[0].Message).To(Equal("Host is not allowlisted"))
 	})
 
+	DescribeTable("Should validate wildcard hosts",
+		func(host string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(host),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+				HostBlockList:             []string{"api." + testDefaultDomain},
+				DefaultDomainName:         testDefaultDomain,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.host"))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("wildcard host with an allowlisted domain", "*.apps."+allowlistedDomain, ""),
+		Entry("wildcard host completed with the default domain", "*.apps", ""),
+		Entry("wildcard host with upper case letters", "*.Apps."+allowlistedDomain, ""),
+		Entry("wildcard host with a domain that is not allowlisted", "*.apps."+notAllowlistedDomain, "Host is not allowlisted"),
+		Entry("wildcard host matching a blocklisted subdomain", "*."+testDefaultDomain, "The subdomain api is blocklisted for foo.bar domain"),
+		Entry("wildcard only", "*", "Wildcard is only allowed as the leftmost label of the host"),
+		Entry("wildcard in another label", "apps.*."+allowlistedDomain, "Wildcard is only allowed as the leftmost label of the host"),
+		Entry("wildcard as part of a label", "app*.apps."+allowlistedDomain, "Wildcard is only allowed as the leftmost label of the host"),
+		Entry("multiple wildcards", "*.*."+allowlistedDomain, "Wildcard is only allowed as the leftmost label of the host"),
+		Entry("wildcard with an invalid domain", "*.-apps."+allowlistedDomain, "Host is not a valid domain name"),
+	)
+
 	It("Should fail for blocklisted subdomain with default domainName (FQDN)", func() {
 		//given
 		blocklistedSubdomain := "api"
@@ -527,6 +575,86 @@ var _ = Describe("Validate function", func() {
 		Expect(problems).To(HaveLen(0))
 	})
 
+	Context("with Virtual Services aggregated by host", func() {
+		occupiedHost := "occupied-host." + allowlistedDomain
+		getAPIRule := func(name, namespace string) *gatewayv1beta1.APIRule {
+			return &gatewayv1beta1.APIRule{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(occupiedHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("noop", emptyConfig()),
+							},
+						},
+					},
+				},
+			}
+		}
+		getAggregatedVS := func(namespace string, owners string) *networkingv1beta1.VirtualService {
+			vs := &networkingv1beta1.VirtualService{}
+			vs.Namespace = namespace
+			vs.Labels = map[string]string{helpers.AggregatedLabel: "true"}
+			vs.Annotations = map[string]string{helpers.OwnersAnnotation: owners}
+			vs.Spec.Hosts = []string{occupiedHost}
+			vs.Spec.Gateways = helpers.GetGateways(getAPIRule("first", namespace))
+			return vs
+		}
+		validate := func(api *gatewayv1beta1.APIRule, vs *networkingv1beta1.VirtualService) []Failure {
+			return (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(api, networkingv1beta1.VirtualServiceList{Items: []*networkingv1beta1.VirtualService{vs}})
+		}
+
+		It("Should NOT fail for the hosts of two APIRules that share the aggregated VS", func() {
+			//given
+			existingVS := getAggregatedVS("default", `{"first.default":["first-0-abc"]}`)
+
+			//when
+			firstProblems := validate(getAPIRule("first", "default"), existingVS)
+			secondProblems := validate(getAPIRule("second", "default"), existingVS)
+
+			//then
+			Expect(firstProblems).To(BeEmpty())
+			Expect(secondProblems).To(BeEmpty())
+		})
+
+		It("Should NOT fail for the host of an owner of the aggregated VS that changes the gateway", func() {
+			//given
+			existingVS := getAggregatedVS("default", `{"first.default":["first-0-abc"]}`)
+			api := getAPIRule("first", "default")
+			gateway := "kyma-system/other-gateway"
+			api.Spec.Gateway = &gateway
+
+			//when
+			problems := validate(api, existingVS)
+
+			//then
+			Expect(problems).To(BeEmpty())
+		})
+
+		It("Should fail for a host that is occupied by an aggregated VS in another namespace", func() {
+			//given
+			existingVS := getAggregatedVS("other", `{"first.other":["first-0-abc"]}`)
+
+			//when
+			problems := validate(getAPIRule("first", "default"), existingVS)
+
+			//then
+			Expect(problems).To(HaveLen(1))
+			Expect(problems[0].AttributePath).To(Equal(".spec.host"))
+			Expect(problems[0].Message).To(Equal("This host is occupied by another Virtual Service"))
+		})
+	})
+
 	It("Should return an error when no service is defined for rule with no service on spec level", func() {
 		//given
 		input := &gatewayv1beta1.APIRule{
@@ -554,6 +682,52 @@ var _ = Describe("Validate function", func() {
 		Expect(problems[0].Message).To(Equal("No service defined with no main service on spec level"))
 	})
 
+	DescribeTable("Should validate that every rule has a service on rule or spec level",
+		func(specService *gatewayv1beta1.Service, ruleService *gatewayv1beta1.Service, expectedPath string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Host:    getHost(sampleValidHost),
+					Service: specService,
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+							Service: ruleService,
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(expectedPath))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("service on rule level only", nil, getService(sampleServiceName, uint32(8080)), "", ""),
+		Entry("service on spec level only", getService(sampleServiceName, uint32(8080)), nil, "", ""),
+		Entry("service on rule and spec level", getService(sampleServiceName, uint32(8080)), getService("other-service", uint32(8080)), "", ""),
+		Entry("neither service on rule nor on spec level", nil, nil, ".spec.rules[0].service",
+			"No service defined with no main service on spec level"),
+		Entry("service on spec level without name", &gatewayv1beta1.Service{Port: ptrUint32(8080)}, nil, ".spec.service.name",
+			"Service must define name"),
+		Entry("service on rule level without name", nil, &gatewayv1beta1.Service{Port: ptrUint32(8080)}, ".spec.rules[0].service.name",
+			"Service must define name"),
+	)
+
 	It("Should return an error when rule is defined with blocklisted service", func() {
 		//given
 		sampleBlocklistedService := "kubernetes"
@@ -795,135 +969,1890 @@ var _ = Describe("Validate function", func() {
 		Expect(problems).To(HaveLen(0))
 	})
 
-	It("Should succeed for the same path but different methods", func() {
+	It("Should fail for an empty list of additional hosts", func() {
 		//given
-		occupiedHost := "occupied-host" + allowlistedDomain
-		notOccupiedHost := "not-occupied-host" + allowlistedDomain
+		input := &gatewayv1beta1.APIRule{
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Service: getService(sampleServiceName, uint32(8080)),
+				Host:    getHost(sampleValidHost),
+				Hosts:   []string{},
+				Rules: []gatewayv1beta1.Rule{
+					{
+						Path: "/abc",
+						AccessStrategies: []*gatewayv1beta1.Authenticator{
+							toAuthenticator("allow", nil),
+						},
+					},
+				},
+			},
+		}
+
+		//when
+		problems := (&APIRuleValidator{
+			HandlerValidator:          handlerValidatorMock,
+			AccessStrategiesValidator: asValidatorMock,
+			DomainAllowList:           testDomainAllowlist,
+		}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+		//then
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].AttributePath).To(Equal(".spec.hosts"))
+		Expect(problems[0].Message).To(Equal("Hosts was empty"))
+	})
+
+	It("Should fail for duplicated hosts", func() {
+		//given
+		otherHost := "other-service." + allowlistedDomain
+		input := &gatewayv1beta1.APIRule{
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Service: getService(sampleServiceName, uint32(8080)),
+				Host:    getHost(sampleValidHost),
+				Hosts:   []string{otherHost, sampleValidHost, otherHost},
+				Rules: []gatewayv1beta1.Rule{
+					{
+						Path: "/abc",
+						AccessStrategies: []*gatewayv1beta1.Authenticator{
+							toAuthenticator("allow", nil),
+						},
+					},
+				},
+			},
+		}
+
+		//when
+		problems := (&APIRuleValidator{
+			HandlerValidator:          handlerValidatorMock,
+			AccessStrategiesValidator: asValidatorMock,
+			DomainAllowList:           testDomainAllowlist,
+		}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+		//then
+		Expect(problems).To(HaveLen(2))
+		Expect(problems[0].AttributePath).To(Equal(".spec.hosts[1]"))
+		Expect(problems[0].Message).To(Equal(fmt.Sprintf("Host %s is defined multiple times", sampleValidHost)))
+		Expect(problems[1].AttributePath).To(Equal(".spec.hosts[2]"))
+		Expect(problems[1].Message).To(Equal(fmt.Sprintf("Host %s is defined multiple times", otherHost)))
+	})
+
+	It("Should fail for additional host that is not allowlisted or occupied", func() {
+		//given
+		occupiedHost := "occupied-host." + allowlistedDomain
 		existingVS := networkingv1beta1.VirtualService{}
 		existingVS.Spec.Hosts = []string{occupiedHost}
 
 		input := &gatewayv1beta1.APIRule{
-			ObjectMeta: v1.ObjectMeta{
-				UID: "67890",
-			},
 			Spec: gatewayv1beta1.APIRuleSpec{
 				Service: getService(sampleServiceName, uint32(8080)),
-				Host:    getHost(notOccupiedHost),
+				Host:    getHost(sampleValidHost),
+				Hosts:   []string{sampleServiceName + "." + notAllowlistedDomain, occupiedHost},
 				Rules: []gatewayv1beta1.Rule{
 					{
 						Path: "/abc",
 						AccessStrategies: []*gatewayv1beta1.Authenticator{
-							toAuthenticator("jwt", simpleJWTConfig()),
-							toAuthenticator("noop", emptyConfig()),
+							toAuthenticator("allow", nil),
 						},
-						Methods: []string{"POST"},
 					},
+				},
+			},
+		}
+
+		//when
+		problems := (&APIRuleValidator{
+			HandlerValidator:          handlerValidatorMock,
+			AccessStrategiesValidator: asValidatorMock,
+			DomainAllowList:           testDomainAllowlist,
+		}).Validate(input, networkingv1beta1.VirtualServiceList{Items: []*networkingv1beta1.VirtualService{&existingVS}})
+
+		//then
+		Expect(problems).To(HaveLen(2))
+		Expect(problems[0].AttributePath).To(Equal(".spec.hosts[0]"))
+		Expect(problems[0].Message).To(Equal("Host is not allowlisted"))
+		Expect(problems[1].AttributePath).To(Equal(".spec.hosts[1]"))
+		Expect(problems[1].Message).To(Equal("This host is occupied by another Virtual Service"))
+	})
+
+	It("Should succeed for multiple distinct hosts", func() {
+		//given
+		input := &gatewayv1beta1.APIRule{
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Service: getService(sampleServiceName, uint32(8080)),
+				Host:    getHost(sampleValidHost),
+				Hosts:   []string{"other-service." + allowlistedDomain, "short-host"},
+				Rules: []gatewayv1beta1.Rule{
 					{
 						Path: "/abc",
 						AccessStrategies: []*gatewayv1beta1.Authenticator{
-							toAuthenticator("jwt", simpleJWTConfig()),
-							toAuthenticator("noop", emptyConfig()),
+							toAuthenticator("allow", nil),
 						},
-						Methods: []string{"GET"},
 					},
 				},
 			},
 		}
+
 		//when
 		problems := (&APIRuleValidator{
 			HandlerValidator:          handlerValidatorMock,
 			AccessStrategiesValidator: asValidatorMock,
 			DomainAllowList:           testDomainAllowlist,
-		}).Validate(input, networkingv1beta1.VirtualServiceList{Items: []*networkingv1beta1.VirtualService{&existingVS}})
+			DefaultDomainName:         testDefaultDomain,
+		}).Validate(input, networkingv1beta1.VirtualServiceList{})
 
 		//then
 		Expect(problems).To(HaveLen(0))
 	})
-})
 
-var _ = Describe("Validator for", func() {
-	Describe("NoConfig access strategy", func() {
-		It("Should fail with non-empty config", func() {
+	DescribeTable("Should validate the load balancer of the service",
+		func(loadBalancer *gatewayv1beta1.LoadBalancer, expectedPath string, expectedMessage string) {
 			//given
-			handler := &gatewayv1beta1.Handler{Name: "noop", Config: simpleJWTConfig("http://atgo.org")}
+			service := getService(sampleServiceName, uint32(8080))
+			service.TrafficPolicy = &gatewayv1beta1.TrafficPolicy{LoadBalancer: loadBalancer}
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: service,
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+						},
+					},
+				},
+			}
 
 			//when
-			problems := (&noConfigAccStrValidator{}).Validate("some.attribute", handler)
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
 
 			//then
-			Expect(problems).To(HaveLen(1))
-			Expect(problems[0].AttributePath).To(Equal("some.attribute.config"))
-			Expect(problems[0].Message).To(Equal("strategy: noop does not support configuration"))
-		})
-
-		It("Should succeed with empty config: {}", func() {
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.service.trafficPolicy.loadBalancer." + expectedPath))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("round robin", &gatewayv1beta1.LoadBalancer{Algorithm: gatewayv1beta1.LoadBalancerRoundRobin}, "", ""),
+		Entry("least connections", &gatewayv1beta1.LoadBalancer{Algorithm: gatewayv1beta1.LoadBalancerLeastConn}, "", ""),
+		Entry("consistent hash on a header",
+			&gatewayv1beta1.LoadBalancer{Algorithm: gatewayv1beta1.LoadBalancerConsistentHash, ConsistentHash: &gatewayv1beta1.ConsistentHash{HTTPHeaderName: "x-user"}},
+			"", ""),
+		Entry("consistent hash on a cookie",
+			&gatewayv1beta1.LoadBalancer{Algorithm: gatewayv1beta1.LoadBalancerConsistentHash, ConsistentHash: &gatewayv1beta1.ConsistentHash{HTTPCookie: &gatewayv1beta1.HTTPCookie{Name: "session", TTL: "1h"}}},
+			"", ""),
+		Entry("consistent hash on the source IP",
+			&gatewayv1beta1.LoadBalancer{Algorithm: gatewayv1beta1.LoadBalancerConsistentHash, ConsistentHash: &gatewayv1beta1.ConsistentHash{UseSourceIP: true}},
+			"", ""),
+		Entry("consistent hash without source of the hash key",
+			&gatewayv1beta1.LoadBalancer{Algorithm: gatewayv1beta1.LoadBalancerConsistentHash},
+			"consistentHash", "Consistent hash must be defined for the consistentHash algorithm"),
+		Entry("consistent hash with an empty source of the hash key",
+			&gatewayv1beta1.LoadBalancer{Algorithm: gatewayv1beta1.LoadBalancerConsistentHash, ConsistentHash: &gatewayv1beta1.ConsistentHash{}},
+			"consistentHash", "Exactly one of httpHeaderName, httpCookie or useSourceIp must be defined"),
+		Entry("consistent hash with multiple sources of the hash key",
+			&gatewayv1beta1.LoadBalancer{Algorithm: gatewayv1beta1.LoadBalancerConsistentHash, ConsistentHash: &gatewayv1beta1.ConsistentHash{HTTPHeaderName: "x-user", UseSourceIP: true}},
+			"consistentHash", "Exactly one of httpHeaderName, httpCookie or useSourceIp must be defined"),
+		Entry("consistent hash on an invalid header",
+			&gatewayv1beta1.LoadBalancer{Algorithm: gatewayv1beta1.LoadBalancerConsistentHash, ConsistentHash: &gatewayv1beta1.ConsistentHash{HTTPHeaderName: "x user"}},
+			"consistentHash.httpHeaderName", "Header x user is not a valid HTTP header name"),
+		Entry("consistent hash on an invalid cookie",
+			&gatewayv1beta1.LoadBalancer{Algorithm: gatewayv1beta1.LoadBalancerConsistentHash, ConsistentHash: &gatewayv1beta1.ConsistentHash{HTTPCookie: &gatewayv1beta1.HTTPCookie{Name: "session;id", TTL: "1h"}}},
+			"consistentHash.httpCookie.name", "Cookie session;id is not a valid cookie name"),
+		Entry("consistent hash for another algorithm",
+			&gatewayv1beta1.LoadBalancer{Algorithm: gatewayv1beta1.LoadBalancerRoundRobin, ConsistentHash: &gatewayv1beta1.ConsistentHash{UseSourceIP: true}},
+			"consistentHash", "Consistent hash is only supported for the consistentHash algorithm"),
+	)
+
+	DescribeTable("Should validate the rule timeout",
+		func(timeout string, expectedMessage string) {
 			//given
-			handler := &gatewayv1beta1.Handler{Name: "noop", Config: emptyConfig()}
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+							Timeout: &timeout,
+						},
+					},
+				},
+			}
 
 			//when
-			problems := (&noConfigAccStrValidator{}).Validate("some.attribute", handler)
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
 
 			//then
-			Expect(problems).To(HaveLen(0))
-		})
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].timeout"))
+				Expect(problems[0].Message).To(ContainSubstring(expectedMessage))
+			}
+		},
+		Entry("valid timeout", "1m30s", ""),
+		Entry("invalid duration", "10 seconds", "Timeout is not a valid duration"),
+		Entry("zero timeout", "0s", "Timeout must be a positive duration"),
+		Entry("timeout above maximum", "2h", "Timeout must not exceed 1h0m0s"),
+	)
+
+	DescribeTable("Should validate the rule idle timeout",
+		func(idleTimeout string, timeout *string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+							Timeout:     timeout,
+							IdleTimeout: &idleTimeout,
+						},
+					},
+				},
+			}
 
-		It("Should succeed with null config", func() {
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].idleTimeout"))
+				Expect(problems[0].Message).To(ContainSubstring(expectedMessage))
+			}
+		},
+		Entry("valid idle timeout without timeout", "5m", nil, ""),
+		Entry("idle timeout shorter than the timeout", "30s", ptrString("1m"), ""),
+		Entry("idle timeout equal to the timeout", "1m", ptrString("1m"), ""),
+		Entry("idle timeout longer than the timeout", "2m", ptrString("1m"), "Idle timeout must not be longer than the timeout of the rule"),
+		Entry("invalid duration", "10 seconds", nil, "Timeout is not a valid duration"),
+		Entry("zero idle timeout", "0s", nil, "Timeout must be a positive duration"),
+	)
+
+	DescribeTable("Should validate the rule port timeouts",
+		func(portTimeouts []gatewayv1beta1.PortTimeout, modify func(rule *gatewayv1beta1.Rule), expectedPath string, expectedMessage string) {
 			//given
-			handler := &gatewayv1beta1.Handler{Name: "noop", Config: nil}
+			rule := gatewayv1beta1.Rule{
+				Path: "/abc",
+				AccessStrategies: []*gatewayv1beta1.Authenticator{
+					toAuthenticator("allow", nil),
+				},
+				PortTimeouts: portTimeouts,
+			}
+			if modify != nil {
+				modify(&rule)
+			}
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules:   []gatewayv1beta1.Rule{rule},
+				},
+			}
 
 			//when
-			problems := (&noConfigAccStrValidator{}).Validate("some.attribute", handler)
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
 
 			//then
-			Expect(problems).To(HaveLen(0))
-		})
-	})
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(expectedPath))
+				Expect(problems[0].Message).To(ContainSubstring(expectedMessage))
+			}
+		},
+		Entry("timeout for the port of the service", []gatewayv1beta1.PortTimeout{{Port: 8080, Timeout: "1m"}}, nil, "", ""),
+		Entry("timeout for the port of the rule service",
+			[]gatewayv1beta1.PortTimeout{{Port: 9090, Timeout: "1m"}},
+			func(rule *gatewayv1beta1.Rule) { rule.Service = getService(sampleServiceName, uint32(9090)) },
+			"", ""),
+		Entry("timeouts for the protocol ports",
+			[]gatewayv1beta1.PortTimeout{{Port: 80, Timeout: "10s"}, {Port: 9090, Timeout: "1m"}},
+			func(rule *gatewayv1beta1.Rule) {
+				rule.ProtocolPorts = []gatewayv1beta1.ProtocolPort{{Protocol: gatewayv1beta1.RouteTypeGRPC, Port: 9090}, {Protocol: gatewayv1beta1.RouteTypeHTTP, Port: 80}}
+			},
+			"", ""),
+		Entry("timeout for a port of a destination",
+			[]gatewayv1beta1.PortTimeout{{Port: 8081, Timeout: "1m"}},
+			func(rule *gatewayv1beta1.Rule) {
+				rule.Destinations = []gatewayv1beta1.WeightedService{
+					{Service: *getService("service-1", uint32(8081)), Weight: 50},
+					{Service: *getService("service-2", uint32(8082)), Weight: 50},
+				}
+			},
+			"", ""),
+		Entry("timeout for a port of a service referenced by port name",
+			[]gatewayv1beta1.PortTimeout{{Port: 9090, Timeout: "1m"}},
+			func(rule *gatewayv1beta1.Rule) {
+				rule.Service = &gatewayv1beta1.Service{Name: getHost(sampleServiceName), PortName: "http"}
+			},
+			"", ""),
+		Entry("timeout for a port that is not used by the rule", []gatewayv1beta1.PortTimeout{{Port: 9090, Timeout: "1m"}}, nil,
+			".spec.rules[0].portTimeouts[0].port", "Port 9090 is not used by the rule"),
+		Entry("multiple timeouts for the same port", []gatewayv1beta1.PortTimeout{{Port: 8080, Timeout: "1m"}, {Port: 8080, Timeout: "2m"}}, nil,
+			".spec.rules[0].portTimeouts[1].port", "Timeout is defined more than once for port 8080"),
+		Entry("invalid timeout", []gatewayv1beta1.PortTimeout{{Port: 8080, Timeout: "10 seconds"}}, nil,
+			".spec.rules[0].portTimeouts[0].timeout", "Timeout is not a valid duration"),
+	)
+
+	DescribeTable("Should validate the rule protocol ports",
+		func(handler string, protocolPorts []gatewayv1beta1.ProtocolPort, modify func(rule *gatewayv1beta1.Rule), expectedPath string, expectedMessage string) {
+			//given
+			rule := gatewayv1beta1.Rule{
+				Path: "/abc",
+				AccessStrategies: []*gatewayv1beta1.Authenticator{
+					toAuthenticator(handler, nil),
+				},
+				ProtocolPorts: protocolPorts,
+			}
+			if modify != nil {
+				modify(&rule)
+			}
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules:   []gatewayv1beta1.Rule{rule},
+				},
+			}
 
-})
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
 
-func emptyConfig() *runtime.RawExtension {
-	return getRawConfig(
-		&ory.JWTAccStrConfig{})
-}
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(expectedPath))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("HTTP and gRPC ports", "allow", []gatewayv1beta1.ProtocolPort{{Protocol: "http", Port: 80}, {Protocol: "grpc", Port: 9090}}, nil, "", ""),
+		Entry("only a gRPC port", "allow", []gatewayv1beta1.ProtocolPort{{Protocol: "grpc", Port: 9090}}, nil, "", ""),
+		Entry("not supported access strategy", "noop", []gatewayv1beta1.ProtocolPort{{Protocol: "grpc", Port: 9090}}, nil,
+			".spec.rules[0].protocolPorts", "Protocol ports are only supported for rules with the allow or jwt access strategy"),
+		Entry("protocol mapped to two ports", "allow", []gatewayv1beta1.ProtocolPort{{Protocol: "grpc", Port: 9090}, {Protocol: "grpc", Port: 9091}}, nil,
+			".spec.rules[0].protocolPorts[1].protocol", "Protocol grpc is mapped to more than one port"),
+		Entry("port mapped to two protocols", "allow", []gatewayv1beta1.ProtocolPort{{Protocol: "http", Port: 8080}, {Protocol: "grpc", Port: 8080}}, nil,
+			".spec.rules[0].protocolPorts[1].port", "Port 8080 is mapped to more than one protocol"),
+		Entry("with route type", "allow", []gatewayv1beta1.ProtocolPort{{Protocol: "grpc", Port: 9090}}, func(rule *gatewayv1beta1.Rule) { rule.RouteType = "grpc" },
+			".spec.rules[0].protocolPorts", "Protocol ports are not supported for rules with a route type, since the protocol defines the route type"),
+		Entry("with redirect", "allow", []gatewayv1beta1.ProtocolPort{{Protocol: "grpc", Port: 9090}}, func(rule *gatewayv1beta1.Rule) {
+			rule.Redirect = &gatewayv1beta1.Redirect{URI: "/new"}
+		},
+			".spec.rules[0].protocolPorts", "Protocol ports are not supported for rules with destinations, a redirect or a direct response"),
+		Entry("with content type header match", "allow", []gatewayv1beta1.ProtocolPort{{Protocol: "grpc", Port: 9090}}, func(rule *gatewayv1beta1.Rule) {
+			rule.MatchHeaders = map[string]gatewayv1beta1.StringMatch{"Content-Type": {Exact: "application/json"}}
+		},
+			".spec.rules[0].protocolPorts", "Protocol ports are not supported for rules that match the content-type header"),
+	)
 
-func simpleJWTConfig(trustedIssuers ...string) *runtime.RawExtension {
-	return getRawConfig(
-		&ory.JWTAccStrConfig{
-			JWKSUrls:       trustedIssuers,
-			TrustedIssuers: trustedIssuers,
-			RequiredScopes: []string{"atgo"},
-		})
-}
+	DescribeTable("Should validate the rule methods",
+		func(methods []string, expectedPath string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path:    "/abc",
+							Methods: methods,
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+						},
+					},
+				},
+			}
 
-func getRawConfig(config *ory.JWTAccStrConfig) *runtime.RawExtension {
-	bytes, err := json.Marshal(config)
-	Expect(err).To(BeNil())
-	return &runtime.RawExtension{
-		Raw: bytes,
-	}
-}
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
 
-func toAuthenticator(name string, config *runtime.RawExtension) *gatewayv1beta1.Authenticator {
-	return &gatewayv1beta1.Authenticator{
-		Handler: &gatewayv1beta1.Handler{
-			Name:   name,
-			Config: config,
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(expectedPath))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
 		},
-	}
-}
+		Entry("upper case methods", []string{"GET", "POST", "OPTIONS"}, "", ""),
+		Entry("lower case methods", []string{"get", "Post"}, "", ""),
+		Entry("invalid method", []string{"GET", "FETCH"}, ".spec.rules[0].methods[1]", "Method FETCH is not a valid HTTP method"),
+		Entry("duplicated method", []string{"GET", "POST", "GET"}, ".spec.rules[0].methods[2]", "Method GET is defined more than once"),
+		Entry("method duplicated in different case", []string{"GET", "get"}, ".spec.rules[0].methods[1]", "Method get is defined more than once"),
+	)
+
+	It("Should fail for the same path and a method that differs only in case", func() {
+		//given
+		input := &gatewayv1beta1.APIRule{
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Service: getService(sampleServiceName, uint32(8080)),
+				Host:    getHost(sampleValidHost),
+				Rules: []gatewayv1beta1.Rule{
+					{
+						Path:    "/abc",
+						Methods: []string{"GET"},
+						AccessStrategies: []*gatewayv1beta1.Authenticator{
+							toAuthenticator("allow", nil),
+						},
+					},
+					{
+						Path:    "/abc",
+						Methods: []string{"get"},
+						AccessStrategies: []*gatewayv1beta1.Authenticator{
+							toAuthenticator("allow", nil),
+						},
+					},
+				},
+			},
+		}
 
-func getService(serviceName string, servicePort uint32, namespace ...*string) *gatewayv1beta1.Service {
-	var serviceNamespace *string
-	if len(namespace) > 0 {
-		serviceNamespace = namespace[0]
-	}
-	return &gatewayv1beta1.Service{
+		//when
+		problems := (&APIRuleValidator{
+			HandlerValidator:          handlerValidatorMock,
+			AccessStrategiesValidator: asValidatorMock,
+			DomainAllowList:           testDomainAllowlist,
+		}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+		//then
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].AttributePath).To(Equal(".spec.rules"))
+	})
+
+	DescribeTable("Should validate the CORS max age",
+		func(specMaxAge *string, ruleMaxAge *string, expectedPath string, expectedMessage string) {
+			//given
+			rule := gatewayv1beta1.Rule{
+				Path: "/abc",
+				AccessStrategies: []*gatewayv1beta1.Authenticator{
+					toAuthenticator("allow", nil),
+				},
+			}
+			if ruleMaxAge != nil {
+				rule.CorsPolicy = &gatewayv1beta1.CorsPolicy{MaxAge: ruleMaxAge}
+			}
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules:   []gatewayv1beta1.Rule{rule},
+				},
+			}
+			if specMaxAge != nil {
+				input.Spec.CorsPolicy = &gatewayv1beta1.CorsPolicy{MaxAge: specMaxAge}
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(expectedPath))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("valid max age of the spec and the rule", ptrString("1h"), ptrString("10m"), "", ""),
+		Entry("zero max age", nil, ptrString("0s"), "", ""),
+		Entry("negative max age of the rule", nil, ptrString("-10m"), ".spec.rules[0].corsPolicy.maxAge", "Max age must not be negative"),
+		Entry("negative max age of the spec", ptrString("-1h"), nil, ".spec.corsPolicy.maxAge", "Max age must not be negative"),
+		Entry("invalid max age", nil, ptrString("10 minutes"), ".spec.rules[0].corsPolicy.maxAge",
+			"Max age is not a valid duration: time: unknown unit \" minutes\" in duration \"10 minutes\""),
+	)
+
+	DescribeTable("Should validate the Lua scripts of the rules",
+		func(luaFilters bool, gateway *string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Gateway: gateway,
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+							Lua: &gatewayv1beta1.Lua{InlineCode: "function envoy_on_request(handle) end"},
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+				LuaFilters:                luaFilters,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].lua"))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("enabled Lua filters", true, ptrString("kyma-system/kyma-gateway"), ""),
+		Entry("disabled Lua filters", false, ptrString("kyma-system/kyma-gateway"), "Lua scripts are not enabled for the controller"),
+		Entry("APIRule exposed within the mesh", true, ptrString("mesh"), "Lua scripts are not supported for APIRules that are only exposed within the mesh"),
+	)
+
+	DescribeTable("Should validate the maintenance",
+		func(status *uint32, retryAfter *string, expectedPath string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+						},
+					},
+					Maintenance: &gatewayv1beta1.Maintenance{Enabled: true, Status: status, RetryAfter: retryAfter},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(expectedPath))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("default status without Retry-After", nil, nil, "", ""),
+		Entry("server error status", ptrUint32(500), nil, "", ""),
+		Entry("Retry-After in seconds", nil, ptrString("120"), "", ""),
+		Entry("Retry-After as HTTP date", nil, ptrString("Wed, 21 Oct 2026 07:28:00 GMT"), "", ""),
+		Entry("status that is not a server error", ptrUint32(200), nil, ".spec.maintenance.status",
+			"Maintenance status 200 is not a server error status code between 500 and 599"),
+		Entry("negative Retry-After", nil, ptrString("-1"), ".spec.maintenance.retryAfter",
+			"Retry-After -1 is neither a number of seconds nor an HTTP date"),
+		Entry("Retry-After as duration", nil, ptrString("2m"), ".spec.maintenance.retryAfter",
+			"Retry-After 2m is neither a number of seconds nor an HTTP date"),
+	)
+
+	DescribeTable("Should validate the rule source CIDRs",
+		func(sourceCIDRs []string, modify func(rule *gatewayv1beta1.Rule), expectedPath string, expectedMessage string) {
+			//given
+			rule := gatewayv1beta1.Rule{
+				Path: "/abc",
+				AccessStrategies: []*gatewayv1beta1.Authenticator{
+					toAuthenticator("allow", nil),
+				},
+				SourceCIDRs: sourceCIDRs,
+			}
+			if modify != nil {
+				modify(&rule)
+			}
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules:   []gatewayv1beta1.Rule{rule},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(expectedPath))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("IPv4 CIDR", []string{"10.0.0.0/8"}, nil, "", ""),
+		Entry("IPv6 CIDR", []string{"2001:db8::/32"}, nil, "", ""),
+		Entry("single IP address", []string{"192.168.1.1"}, nil, "", ""),
+		Entry("invalid prefix length", []string{"10.0.0.0/8", "10.0.0.0/33"}, nil,
+			".spec.rules[0].sourceCIDRs[1]", "Source CIDR 10.0.0.0/33 is not a valid IP address or CIDR range"),
+		Entry("invalid address", []string{"10.0.0/8"}, nil,
+			".spec.rules[0].sourceCIDRs[0]", "Source CIDR 10.0.0/8 is not a valid IP address or CIDR range"),
+		Entry("host name", []string{"example.com"}, nil,
+			".spec.rules[0].sourceCIDRs[0]", "Source CIDR example.com is not a valid IP address or CIDR range"),
+		Entry("with direct response", []string{"10.0.0.0/8"}, func(rule *gatewayv1beta1.Rule) {
+			rule.DirectResponse = &gatewayv1beta1.DirectResponse{Status: 200}
+		},
+			".spec.rules[0].sourceCIDRs", "Source CIDRs are not supported for rules with a redirect or a direct response"),
+	)
+
+	DescribeTable("Should validate the rule deprecation",
+		func(since *string, sunset *string, expectedPath string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+							Deprecation: &gatewayv1beta1.Deprecation{Since: since, Sunset: sunset},
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(expectedPath))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("no dates", nil, nil, "", ""),
+		Entry("valid dates", ptrString("2024-01-01T00:00:00Z"), ptrString("2024-06-30T12:00:00+02:00"), "", ""),
+		Entry("only sunset", nil, ptrString("2024-06-30T00:00:00Z"), "", ""),
+		Entry("invalid since", ptrString("2024-01-01"), nil, ".spec.rules[0].deprecation.since", "Date is not a valid RFC 3339 date: 2024-01-01"),
+		Entry("invalid sunset", nil, ptrString("30 Jun 2024"), ".spec.rules[0].deprecation.sunset", "Date is not a valid RFC 3339 date: 30 Jun 2024"),
+		Entry("sunset before since", ptrString("2024-06-30T00:00:00Z"), ptrString("2024-01-01T00:00:00Z"), ".spec.rules[0].deprecation.sunset", "Sunset must be after the date since when the rule is deprecated"),
+	)
+
+	DescribeTable("Should validate the rule max request headers bytes",
+		func(maxRequestHeadersBytes uint32, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+							MaxRequestHeadersBytes: &maxRequestHeadersBytes,
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].maxRequestHeadersBytes"))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("multiple of a kilobyte", uint32(8192), ""),
+		Entry("not a multiple of a kilobyte", uint32(8000), "Max request headers bytes must be a multiple of 1024, since Envoy limits the header size in kilobytes"),
+	)
+
+	DescribeTable("Should validate the rule destinations",
+		func(handler string, weights []int32, expectedMessage string) {
+			//given
+			var destinations []gatewayv1beta1.WeightedService
+			for i, weight := range weights {
+				destinations = append(destinations, gatewayv1beta1.WeightedService{
+					Service: *getService(fmt.Sprintf("service-%d", i), uint32(8080)),
+					Weight:  weight,
+				})
+			}
+
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Host: getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator(handler, nil),
+							},
+							Destinations: destinations,
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].destinations"))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("weights summing up to 100", "allow", []int32{90, 10}, ""),
+		Entry("weights not summing up to 100", "allow", []int32{90, 20}, "Weights of destinations must sum up to 100, but sum up to 110"),
+		Entry("access strategy other than allow", "noop", []int32{100}, "Destinations are only supported for rules with the allow access strategy"),
+	)
+
+	It("Should succeed for the same path and method but different header matches", func() {
+		//given
+		input := &gatewayv1beta1.APIRule{
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Service: getService(sampleServiceName, uint32(8080)),
+				Host:    getHost(sampleValidHost),
+				Rules: []gatewayv1beta1.Rule{
+					{
+						Path:    "/abc",
+						Methods: []string{"GET"},
+						AccessStrategies: []*gatewayv1beta1.Authenticator{
+							toAuthenticator("allow", nil),
+						},
+						MatchHeaders: map[string]gatewayv1beta1.StringMatch{"X-Api-Version": {Exact: "v2"}},
+					},
+					{
+						Path:    "/abc",
+						Methods: []string{"GET"},
+						AccessStrategies: []*gatewayv1beta1.Authenticator{
+							toAuthenticator("allow", nil),
+						},
+					},
+				},
+			},
+		}
+
+		//when
+		problems := (&APIRuleValidator{
+			HandlerValidator:          handlerValidatorMock,
+			AccessStrategiesValidator: asValidatorMock,
+			DomainAllowList:           testDomainAllowlist,
+		}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+		//then
+		Expect(problems).To(HaveLen(0))
+	})
+
+	DescribeTable("Should validate the rule header matches",
+		func(handler string, match gatewayv1beta1.StringMatch, expectedPath string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator(handler, nil),
+							},
+							MatchHeaders: map[string]gatewayv1beta1.StringMatch{"X-Api-Version": match},
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(expectedPath))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("exact match", "allow", gatewayv1beta1.StringMatch{Exact: "v2"}, "", ""),
+		Entry("prefix match", "allow", gatewayv1beta1.StringMatch{Prefix: "v"}, "", ""),
+		Entry("no match defined", "allow", gatewayv1beta1.StringMatch{}, ".spec.rules[0].matchHeaders.X-Api-Version", "Exactly one of exact, prefix or regex must be defined"),
+		Entry("multiple matches defined", "allow", gatewayv1beta1.StringMatch{Exact: "v2", Prefix: "v"}, ".spec.rules[0].matchHeaders.X-Api-Version", "Exactly one of exact, prefix or regex must be defined"),
+		Entry("access strategy handled by oathkeeper", "noop", gatewayv1beta1.StringMatch{Exact: "v2"}, ".spec.rules[0].matchHeaders", "Header matches are only supported for rules with the allow or jwt access strategy"),
+	)
+
+	DescribeTable("Should validate the rule query parameter matches",
+		func(match gatewayv1beta1.StringMatch, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+							MatchQueryParams: map[string]gatewayv1beta1.StringMatch{"version": match},
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].matchQueryParams.version"))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("exact match", gatewayv1beta1.StringMatch{Exact: "2"}, ""),
+		Entry("regex match", gatewayv1beta1.StringMatch{Regex: "[0-9]+"}, ""),
+		Entry("prefix match", gatewayv1beta1.StringMatch{Prefix: "2"}, "Prefix match is not supported, exactly one of exact or regex must be defined"),
+		Entry("no match defined", gatewayv1beta1.StringMatch{}, "Exactly one of exact or regex must be defined"),
+	)
+
+	DescribeTable("Should validate the rule mirror",
+		func(handler string, mirror *gatewayv1beta1.Mirror, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator(handler, nil),
+							},
+							Mirror: mirror,
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].mirror"))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("mirror with service", "allow", &gatewayv1beta1.Mirror{Service: *getService("mirror-service", uint32(8080))}, ""),
+		Entry("mirror without service port", "allow", &gatewayv1beta1.Mirror{Service: gatewayv1beta1.Service{Name: getHost("mirror-service")}}, "Mirror must define service name and port"),
+		Entry("access strategy other than allow", "noop", &gatewayv1beta1.Mirror{Service: *getService("mirror-service", uint32(8080))}, "Mirror is only supported for rules with the allow access strategy"),
+	)
+
+	DescribeTable("Should validate the rule fault",
+		func(fault *gatewayv1beta1.Fault, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+							Fault: fault,
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].fault"))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("fault with delay", &gatewayv1beta1.Fault{Delay: &gatewayv1beta1.FaultDelay{FixedDelay: "5s", Percentage: 10}}, ""),
+		Entry("fault with abort", &gatewayv1beta1.Fault{Abort: &gatewayv1beta1.FaultAbort{HTTPStatus: 503, Percentage: 10}}, ""),
+		Entry("fault without delay and abort", &gatewayv1beta1.Fault{}, "Fault must define delay or abort"),
+	)
+
+	DescribeTable("Should validate the gateway references",
+		func(gateway string, gateways []string, expectedPath string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service:  getService(sampleServiceName, uint32(8080)),
+					Host:     getHost(sampleValidHost),
+					Gateway:  &gateway,
+					Gateways: gateways,
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(expectedPath))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("gateway name", "kyma-gateway", nil, "", ""),
+		Entry("namespaced gateway", "kyma-system/kyma-gateway", nil, "", ""),
+		Entry("fully qualified gateway", "kyma-gateway.kyma-system.svc.cluster.local", nil, "", ""),
+		Entry("multiple gateways", "kyma-system/kyma-gateway", []string{"some-namespace/internal-gateway", "mesh"}, "", ""),
+		Entry("mesh gateway", "mesh", nil, "", ""),
+		Entry("mesh gateway with additional ingress gateway", "mesh", []string{"mesh", "kyma-system/kyma-gateway"}, ".spec.gateways[1]",
+			"An APIRule exposed on the mesh gateway can't be exposed on additional ingress gateways"),
+		Entry("gateway without name", "kyma-system/", nil, ".spec.gateway", "Gateway kyma-system/ is not a valid namespace/name reference"),
+		Entry("gateway with multiple namespaces", "kyma-system/other/kyma-gateway", nil, ".spec.gateway",
+			"Gateway kyma-system/other/kyma-gateway is not a valid namespace/name reference"),
+		Entry("malformed gateway name", "Kyma_Gateway", nil, ".spec.gateway", "Gateway Kyma_Gateway is not a valid gateway name"),
+		Entry("malformed additional gateway", "kyma-system/kyma-gateway", []string{"mesh", "/internal-gateway"}, ".spec.gateways[1]",
+			"Gateway /internal-gateway is not a valid namespace/name reference"),
+	)
+
+	DescribeTable("Should validate the gateway references of an APIRule without gateway",
+		func(gateways []string, expectedPath string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service:  getService(sampleServiceName, uint32(8080)),
+					Host:     getHost(sampleValidHost),
+					Gateways: gateways,
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(expectedPath))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("no gateways", nil, "", ""),
+		Entry("additional mesh gateway", []string{"mesh"}, "", ""),
+		Entry("additional ingress gateways", []string{"kyma-system/kyma-gateway", "some-namespace/internal-gateway"}, ".spec.gateway",
+			"Gateway must be defined to expose the APIRule on additional ingress gateways"),
+	)
+
+	DescribeTable("Should validate the exported namespaces",
+		func(exportTo []string, expectedPath string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service:  getService(sampleServiceName, uint32(8080)),
+					Host:     getHost(sampleValidHost),
+					ExportTo: exportTo,
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(expectedPath))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("namespace of the APIRule", []string{"."}, "", ""),
+		Entry("all namespaces", []string{"*"}, "", ""),
+		Entry("namespace of the APIRule and other namespaces", []string{".", "other-namespace"}, "", ""),
+		Entry("invalid namespace name", []string{".", "Other_Namespace"}, ".spec.exportTo[1]", "Namespace Other_Namespace is not a valid namespace name"),
+		Entry("all namespaces combined with other namespaces", []string{"*", "other-namespace"}, ".spec.exportTo",
+			"Export to all namespaces can't be combined with other namespaces"),
+	)
+
+	It("Should fail validation when all rules are disabled", func() {
+		//given
+		input := &gatewayv1beta1.APIRule{
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Service: getService(sampleServiceName, uint32(8080)),
+				Host:    getHost(sampleValidHost),
+				Rules: []gatewayv1beta1.Rule{
+					{
+						Path: "/abc",
+						AccessStrategies: []*gatewayv1beta1.Authenticator{
+							toAuthenticator("allow", nil),
+						},
+						Enabled: ptrBool(false),
+					},
+				},
+			},
+		}
+
+		//when
+		problems := (&APIRuleValidator{
+			HandlerValidator:          handlerValidatorMock,
+			AccessStrategiesValidator: asValidatorMock,
+			DomainAllowList:           testDomainAllowlist,
+		}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+		//then
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].AttributePath).To(Equal(".spec.rules"))
+		Expect(problems[0].Message).To(Equal("No enabled rules defined"))
+	})
+
+	DescribeTable("Should validate the host",
+		func(host *string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    host,
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.host"))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("valid host", getHost(sampleValidHost), ""),
+		Entry("valid host with upper case characters and a trailing dot", getHost("Some-Service.Foo.Bar."), ""),
+		Entry("nil host", nil, "Host was nil"),
+		Entry("empty host", getHost(""), "Host was empty"),
+		Entry("host with only a trailing dot", getHost("."), "Host was empty"),
+		Entry("host with invalid characters", getHost("some service!.foo.bar"), "Host is not a valid domain name"),
+	)
+
+	DescribeTable("Should validate the default backend rules",
+		func(defaultBackendPaths []string, expectedPath string, expectedMessage string) {
+			//given
+			var rules []gatewayv1beta1.Rule
+			for _, path := range defaultBackendPaths {
+				rules = append(rules, gatewayv1beta1.Rule{
+					Path: path,
+					AccessStrategies: []*gatewayv1beta1.Authenticator{
+						toAuthenticator("allow", nil),
+					},
+					DefaultBackend: true,
+				})
+			}
+			rules = append(rules, gatewayv1beta1.Rule{
+				Path: "/abc",
+				AccessStrategies: []*gatewayv1beta1.Authenticator{
+					toAuthenticator("allow", nil),
+				},
+			})
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules:   rules,
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(expectedPath))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("default backend with /*", []string{"/*"}, "", ""),
+		Entry("default backend with /.*", []string{"/.*"}, "", ""),
+		Entry("default backend without a catch-all path", []string{"/def"}, ".spec.rules[0].path", "The default backend rule must use a catch-all path"),
+		Entry("multiple default backends", []string{"/*", "/.*"}, ".spec.rules", "Only one rule can be the default backend"),
+	)
+
+	DescribeTable("Should validate the subset of the rule",
+		func(ruleService *gatewayv1beta1.Service, destinations []gatewayv1beta1.WeightedService, expectedMessage string) {
+			//given
+			specService := getService(sampleServiceName, uint32(8080))
+			specService.Subsets = []gatewayv1beta1.Subset{{Name: "v1", Labels: map[string]string{"version": "v1"}}}
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: specService,
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+							Service:      ruleService,
+							Destinations: destinations,
+							Subset:       "v1",
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].subset"))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("subset defined on the spec service", nil, nil, ""),
+		Entry("subset defined on the rule service", &gatewayv1beta1.Service{
+			Name:    getHost("other-service"),
+			Port:    ptrUint32(8080),
+			Subsets: []gatewayv1beta1.Subset{{Name: "v1", Labels: map[string]string{"version": "v1"}}},
+		}, nil, ""),
+		Entry("subset not defined on the rule service", getService("other-service", uint32(8080)), nil,
+			"Subset v1 is not defined for service other-service"),
+		Entry("subset with destinations", nil, []gatewayv1beta1.WeightedService{
+			{Service: *getService("other-service", uint32(8080)), Weight: 100},
+		}, "Subset is only supported for rules that route to a single service"),
+	)
+
+	DescribeTable("Should validate the port of the rule service",
+		func(service *gatewayv1beta1.Service, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+							Service: service,
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].service.port"))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("service with port number", getService("rule-service", uint32(8080)), ""),
+		Entry("service with port name", &gatewayv1beta1.Service{Name: getHost("rule-service"), PortName: "http"}, ""),
+		Entry("service without port", &gatewayv1beta1.Service{Name: getHost("rule-service")}, "Service must define port or portName"),
+		Entry("service with port number and name", &gatewayv1beta1.Service{Name: getHost("rule-service"), Port: ptrUint32(8080), PortName: "http"}, "Service must define either port or portName"),
+	)
+
+	DescribeTable("Should validate the namespace of the rule service",
+		func(restrict bool, allowList []string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				ObjectMeta: v1.ObjectMeta{Namespace: "some-namespace"},
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+							Service: getService("rule-service", uint32(8080), getHost("other-namespace")),
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+				RestrictServiceNamespaces: restrict,
+				ServiceNamespaceAllowList: allowList,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].service.namespace"))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("cross-namespace service without restriction", false, nil, ""),
+		Entry("cross-namespace service with restriction", true, nil, "Services in namespace other-namespace can't be referenced from namespace some-namespace"),
+		Entry("allowlisted cross-namespace service with restriction", true, []string{"other-namespace"}, ""),
+	)
+
+	It("Should allow services in the same namespace if cross-namespace services are restricted", func() {
+		//given
+		input := &gatewayv1beta1.APIRule{
+			ObjectMeta: v1.ObjectMeta{Namespace: "some-namespace"},
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Service: getService(sampleServiceName, uint32(8080), getHost("some-namespace")),
+				Host:    getHost(sampleValidHost),
+				Rules: []gatewayv1beta1.Rule{
+					{
+						Path: "/abc",
+						AccessStrategies: []*gatewayv1beta1.Authenticator{
+							toAuthenticator("allow", nil),
+						},
+						Mirror: &gatewayv1beta1.Mirror{Service: *getService("mirror-service", uint32(8080))},
+					},
+				},
+			},
+		}
+
+		//when
+		problems := (&APIRuleValidator{
+			HandlerValidator:          handlerValidatorMock,
+			AccessStrategiesValidator: asValidatorMock,
+			DomainAllowList:           testDomainAllowlist,
+			RestrictServiceNamespaces: true,
+		}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+		//then
+		Expect(problems).To(HaveLen(0))
+	})
+
+	It("Should fail for spec service without port", func() {
+		//given
+		input := &gatewayv1beta1.APIRule{
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Service: &gatewayv1beta1.Service{Name: getHost(sampleServiceName)},
+				Host:    getHost(sampleValidHost),
+				Rules: []gatewayv1beta1.Rule{
+					{
+						Path: "/abc",
+						AccessStrategies: []*gatewayv1beta1.Authenticator{
+							toAuthenticator("allow", nil),
+						},
+					},
+				},
+			},
+		}
+
+		//when
+		problems := (&APIRuleValidator{
+			HandlerValidator:          handlerValidatorMock,
+			AccessStrategiesValidator: asValidatorMock,
+			DomainAllowList:           testDomainAllowlist,
+		}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+		//then
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].AttributePath).To(Equal(".spec.service.port"))
+		Expect(problems[0].Message).To(Equal("Service must define port or portName"))
+	})
+
+	DescribeTable("Should validate the rule redirect",
+		func(handler string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Host: getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator(handler, nil),
+							},
+							Redirect: &gatewayv1beta1.Redirect{Scheme: "https"},
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].redirect"))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("allow access strategy without service", "allow", ""),
+		Entry("access strategy other than allow", "noop", "Redirect is only supported for rules with the allow access strategy"),
+	)
+
+	DescribeTable("Should validate the rule direct response",
+		func(handler string, redirect *gatewayv1beta1.Redirect, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Host: getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/healthz",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator(handler, nil),
+							},
+							Redirect:       redirect,
+							DirectResponse: &gatewayv1beta1.DirectResponse{Status: 200, Body: "ok"},
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].directResponse"))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("allow access strategy without service", "allow", nil, ""),
+		Entry("access strategy other than allow", "noop", nil, "Direct response is only supported for rules with the allow access strategy"),
+		Entry("direct response with redirect", "allow", &gatewayv1beta1.Redirect{Scheme: "https"}, "Direct response is not supported for rules with a redirect or destinations"),
+	)
+
+	DescribeTable("Should validate the rule rewrite",
+		func(rewrite *gatewayv1beta1.Rewrite, redirect *gatewayv1beta1.Redirect, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+							Rewrite:  rewrite,
+							Redirect: redirect,
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].rewrite"))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("rewrite with uri", &gatewayv1beta1.Rewrite{URI: "/foo"}, nil, ""),
+		Entry("rewrite with authority", &gatewayv1beta1.Rewrite{Authority: "example.com"}, nil, ""),
+		Entry("empty rewrite", &gatewayv1beta1.Rewrite{}, nil, "Rewrite must define uri or authority"),
+		Entry("rewrite with redirect", &gatewayv1beta1.Rewrite{URI: "/foo"}, &gatewayv1beta1.Redirect{Scheme: "https"}, "Rewrite is not supported for rules with a redirect"),
+	)
+
+	DescribeTable("Should validate the rule strip prefix",
+		func(pathMatchType gatewayv1beta1.PathMatchType, handler string, rewrite *gatewayv1beta1.Rewrite, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path:          "/abc",
+							PathMatchType: pathMatchType,
+							StripPrefix:   true,
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator(handler, nil),
+							},
+							Rewrite: rewrite,
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].stripPrefix"))
+				Expect(problems[0].Message).To(Equal(expectedMessage))
+			}
+		},
+		Entry("prefix match", gatewayv1beta1.PathMatchPrefix, "allow", nil, ""),
+		Entry("prefix match with a rewrite of the authority", gatewayv1beta1.PathMatchPrefix, "allow", &gatewayv1beta1.Rewrite{Authority: "example.com"}, ""),
+		Entry("exact match", gatewayv1beta1.PathMatchExact, "allow", nil, "Strip prefix is only supported for rules with the prefix path match type"),
+		Entry("regex match", gatewayv1beta1.PathMatchRegex, "allow", nil, "Strip prefix is only supported for rules with the prefix path match type"),
+		Entry("undefined match", gatewayv1beta1.PathMatchType(""), "allow", nil, "Strip prefix is only supported for rules with the prefix path match type"),
+		Entry("rewrite of the uri", gatewayv1beta1.PathMatchPrefix, "allow", &gatewayv1beta1.Rewrite{URI: "/foo"}, "Strip prefix can't be combined with a rewrite of the uri"),
+		Entry("oathkeeper access strategy", gatewayv1beta1.PathMatchPrefix, "noop", nil, "Strip prefix is only supported for rules with the allow or jwt access strategy"),
+	)
+
+	DescribeTable("Should validate the rule rewrite with capture groups",
+		func(rewrite *gatewayv1beta1.Rewrite, expectedPath string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/user/.*",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+							Rewrite: rewrite,
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			Expect(problems).To(HaveLen(1))
+			Expect(problems[0].AttributePath).To(Equal(expectedPath))
+			Expect(problems[0].Message).To(HavePrefix(expectedMessage))
+		},
+		Entry("valid capture rewrite that isn't supported by Istio",
+			&gatewayv1beta1.Rewrite{URIRegex: &gatewayv1beta1.RegexRewrite{Match: `/user/(\d+)`, Rewrite: "/u?id=$1"}},
+			".spec.rules[0].rewrite.uriRegex", "Rewrite with capture groups isn't supported by the Istio version of the gateway"),
+		Entry("capture rewrite with uri",
+			&gatewayv1beta1.Rewrite{URI: "/u", URIRegex: &gatewayv1beta1.RegexRewrite{Match: `/user/(\d+)`, Rewrite: "/u?id=$1"}},
+			".spec.rules[0].rewrite.uriRegex", "Rewrite must define either uri or uriRegex"),
+		Entry("invalid match",
+			&gatewayv1beta1.Rewrite{URIRegex: &gatewayv1beta1.RegexRewrite{Match: `/user/(\d+`, Rewrite: "/u?id=$1"}},
+			".spec.rules[0].rewrite.uriRegex.match", "Match is not a valid regular expression"),
+		Entry("reference to an undefined capture group",
+			&gatewayv1beta1.Rewrite{URIRegex: &gatewayv1beta1.RegexRewrite{Match: `/user/(\d+)`, Rewrite: "/u?id=$1&name=$2"}},
+			".spec.rules[0].rewrite.uriRegex.rewrite", "Rewrite references the capture group $2, but the match only defines 1"),
+	)
+
+	DescribeTable("Should validate the rule CORS origin regex",
+		func(origin string, expectedMessage string) {
+			//given
+			input := &gatewayv1beta1.APIRule{
+				Spec: gatewayv1beta1.APIRuleSpec{
+					Service: getService(sampleServiceName, uint32(8080)),
+					Host:    getHost(sampleValidHost),
+					Rules: []gatewayv1beta1.Rule{
+						{
+							Path: "/abc",
+							AccessStrategies: []*gatewayv1beta1.Authenticator{
+								toAuthenticator("allow", nil),
+							},
+							CorsPolicy: &gatewayv1beta1.CorsPolicy{
+								AllowOriginsRegex: []string{origin},
+							},
+						},
+					},
+				},
+			}
+
+			//when
+			problems := (&APIRuleValidator{
+				HandlerValidator:          handlerValidatorMock,
+				AccessStrategiesValidator: asValidatorMock,
+				DomainAllowList:           testDomainAllowlist,
+			}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+			//then
+			if expectedMessage == "" {
+				Expect(problems).To(HaveLen(0))
+			} else {
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].AttributePath).To(Equal(".spec.rules[0].corsPolicy.allowOriginsRegex[0]"))
+				Expect(problems[0].Message).To(ContainSubstring(expectedMessage))
+			}
+		},
+		Entry("valid regex", `https://.*\.example\.com`, ""),
+		Entry("invalid regex", "https://(example.com", "Origin is not a valid regular expression"),
+	)
+
+	It("Should validate the CORS origin regex of the spec", func() {
+		//given
+		input := &gatewayv1beta1.APIRule{
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Service: getService(sampleServiceName, uint32(8080)),
+				Host:    getHost(sampleValidHost),
+				CorsPolicy: &gatewayv1beta1.CorsPolicy{
+					AllowOriginsRegex: []string{`https://.*\.example\.com`, "https://(example.com"},
+				},
+				Rules: []gatewayv1beta1.Rule{
+					{
+						Path: "/abc",
+						AccessStrategies: []*gatewayv1beta1.Authenticator{
+							toAuthenticator("allow", nil),
+						},
+					},
+				},
+			},
+		}
+
+		//when
+		problems := (&APIRuleValidator{
+			HandlerValidator:          handlerValidatorMock,
+			AccessStrategiesValidator: asValidatorMock,
+			DomainAllowList:           testDomainAllowlist,
+		}).Validate(input, networkingv1beta1.VirtualServiceList{})
+
+		//then
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].AttributePath).To(Equal(".spec.corsPolicy.allowOriginsRegex[1]"))
+		Expect(problems[0].Message).To(ContainSubstring("Origin is not a valid regular expression"))
+	})
+
+	It("Should succeed for the same path but different methods", func() {
+		//given
+		occupiedHost := "occupied-host" + allowlistedDomain
+		notOccupiedHost := "not-occupied-host" + allowlistedDomain
+		existingVS := networkingv1beta1.VirtualService{}
+		existingVS.Spec.Hosts = []string{occupiedHost}
+
+		input := &gatewayv1beta1.APIRule{
+			ObjectMeta: v1.ObjectMeta{
+				UID: "67890",
+			},
+			Spec: gatewayv1beta1.APIRuleSpec{
+				Service: getService(sampleServiceName, uint32(8080)),
+				Host:    getHost(notOccupiedHost),
+				Rules: []gatewayv1beta1.Rule{
+					{
+						Path: "/abc",
+						AccessStrategies: []*gatewayv1beta1.Authenticator{
+							toAuthenticator("jwt", simpleJWTConfig()),
+							toAuthenticator("noop", emptyConfig()),
+						},
+						Methods: []string{"POST"},
+					},
+					{
+						Path: "/abc",
+						AccessStrategies: []*gatewayv1beta1.Authenticator{
+							toAuthenticator("jwt", simpleJWTConfig()),
+							toAuthenticator("noop", emptyConfig()),
+						},
+						Methods: []string{"GET"},
+					},
+				},
+			},
+		}
+		//when
+		problems := (&APIRuleValidator{
+			HandlerValidator:          handlerValidatorMock,
+			AccessStrategiesValidator: asValidatorMock,
+			DomainAllowList:           testDomainAllowlist,
+		}).Validate(input, networkingv1beta1.VirtualServiceList{Items: []*networkingv1beta1.VirtualService{&existingVS}})
+
+		//then
+		Expect(problems).To(HaveLen(0))
+	})
+})
+
+var _ = Describe("Validator for", func() {
+	Describe("NoConfig access strategy", func() {
+		It("Should fail with non-empty config", func() {
+			//given
+			handler := &gatewayv1beta1.Handler{Name: "noop", Config: simpleJWTConfig("http://atgo.org")}
+
+			//when
+			problems := (&noConfigAccStrValidator{}).Validate("some.attribute", handler)
+
+			//then
+			Expect(problems).To(HaveLen(1))
+			Expect(problems[0].AttributePath).To(Equal("some.attribute.config"))
+			Expect(problems[0].Message).To(Equal("strategy: noop does not support configuration"))
+		})
+
+		It("Should succeed with empty config: {}", func() {
+			//given
+			handler := &gatewayv1beta1.Handler{Name: "noop", Config: emptyConfig()}
+
+			//when
+			problems := (&noConfigAccStrValidator{}).Validate("some.attribute", handler)
+
+			//then
+			Expect(problems).To(HaveLen(0))
+		})
+
+		It("Should succeed with null config", func() {
+			//given
+			handler := &gatewayv1beta1.Handler{Name: "noop", Config: nil}
+
+			//when
+			problems := (&noConfigAccStrValidator{}).Validate("some.attribute", handler)
+
+			//then
+			Expect(problems).To(HaveLen(0))
+		})
+	})
+
+})
+
+func emptyConfig() *runtime.RawExtension {
+	return getRawConfig(
+		&ory.JWTAccStrConfig{})
+}
+
+func simpleJWTConfig(trustedIssuers ...string) *runtime.RawExtension {
+	return getRawConfig(
+		&ory.JWTAccStrConfig{
+			JWKSUrls:       trustedIssuers,
+			TrustedIssuers: trustedIssuers,
+			RequiredScopes: []string{"atgo"},
+		})
+}
+
+func getRawConfig(config *ory.JWTAccStrConfig) *runtime.RawExtension {
+	bytes, err := json.Marshal(config)
+	Expect(err).To(BeNil())
+	return &runtime.RawExtension{
+		Raw: bytes,
+	}
+}
+
+func toAuthenticator(name string, config *runtime.RawExtension) *gatewayv1beta1.Authenticator {
+	return &gatewayv1beta1.Authenticator{
+		Handler: &gatewayv1beta1.Handler{
+			Name:   name,
+			Config: config,
+		},
+	}
+}
+
+func getService(serviceName string, servicePort uint32, namespace ...*string) *gatewayv1beta1.Service {
+	var serviceNamespace *string
+	if len(namespace) > 0 {
+		serviceNamespace = namespace[0]
+	}
+	return &gatewayv1beta1.Service{
 		Name:      &serviceName,
 		Namespace: serviceNamespace,
 		Port:      &servicePort,
 	}
 }
 
+func ptrUint32(value uint32) *uint32 {
+	return &value
+}
+
+func ptrString(value string) *string {
+	return &value
+}
+
 func getHost(host string) *string {
 	return &host
 }
@@ -20,9 +20,12 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
+	"golang.org/x/net/http/httpguts"
+
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 
@@ -34,11 +37,13 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	"istio.io/api/networking/v1beta1"
 
 	rulev1alpha1 "github.com/ory/oathkeeper-maester/api/v1alpha1"
 	"github.com/vrischmann/envconfig"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
 	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 	securityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
 
@@ -71,6 +76,7 @@ func init() {
 	utilruntime.Must(gatewayv1beta1.AddToScheme(scheme))
 
 	utilruntime.Must(networkingv1beta1.AddToScheme(scheme))
+	utilruntime.Must(networkingv1alpha3.AddToScheme(scheme))
 	utilruntime.Must(rulev1alpha1.AddToScheme(scheme))
 	utilruntime.Must(securityv1beta1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
@@ -83,12 +89,33 @@ func main() {
 	var oathkeeperSvcAddr string
 	var oathkeeperSvcPort uint
 	var blockListedServices string
+	var restrictServiceNamespaces bool
+	var allowListedServiceNamespaces string
 	var allowListedDomains string
 	var domainName string
-	var corsAllowOrigins, corsAllowMethods, corsAllowHeaders string
+	var strictHostDomain bool
+	var vsFixedName bool
+	var vsNamePrefix, vsNameSuffix string
+	var catchAllPathRegex string
+	var aggregateVSByHost bool
+	var vsExportTo string
+	var validateServiceExistence bool
+	var duplicatePathsMode string
+	var httpTimeout string
+	var idleTimeout time.Duration
+	var luaFilters bool
+	var luaFilterNamespace string
+	var enableMatchedRuleHeader bool
+	var matchedRuleHeaderName string
+	var corsAllowOrigins, corsAllowMethods, corsAllowHeaders, corsExposeHeaders string
+	var corsAllowCredentials bool
+	var corsMaxAge time.Duration
 	var generatedObjectsLabels string
 	var reconciliationPeriod uint
 	var errorReconciliationPeriod uint
+	var retryAttempts uint
+	var retryPerTryTimeout time.Duration
+	var retryOn string
 
 	const blockListedSubdomains string = "api"
 
@@ -99,14 +126,37 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&blockListedServices, "service-blocklist", "kubernetes.default,kube-dns.kube-system", "List of services to be blocklisted from exposure.")
+	flag.BoolVar(&restrictServiceNamespaces, "restrict-service-namespaces", false, "Reject APIRules that reference services in other namespaces than the APIRule namespace.")
+	flag.StringVar(&allowListedServiceNamespaces, "service-namespace-allowlist", "", "List of namespaces whose services can be referenced from all namespaces if restrict-service-namespaces is enabled.")
 	flag.StringVar(&allowListedDomains, "domain-allowlist", "", "List of domains to be allowed.")
 	flag.StringVar(&domainName, "default-domain-name", "", "A default domain name for hostnames with no domain provided. Optional.")
+	flag.BoolVar(&strictHostDomain, "strict-host-domain", false, "Reject hosts that are not fully qualified if no default domain name is provided.")
+	flag.BoolVar(&vsFixedName, "virtual-service-fixed-name", false, "Create VirtualServices with the fixed name <apirule-name>-vs instead of a generated name.")
+	flag.StringVar(&vsNamePrefix, "virtual-service-name-prefix", "", "Prefix that is added to the APIRule name in the generated names of the VirtualServices. Optional.")
+	flag.StringVar(&vsNameSuffix, "virtual-service-name-suffix", "", "Suffix that is added to the APIRule name in the generated names of the VirtualServices. Optional.")
+	flag.StringVar(&catchAllPathRegex, "catch-all-path-regex", "", "Regex that matches the requests of rules with the path /*, the prefix / is used if not set. Optional.")
+	flag.BoolVar(&aggregateVSByHost, "aggregate-virtual-services-by-host", false, "Merge the routes of the APIRules in a namespace that share the hosts and gateways into a single VirtualService. Optional.")
+	flag.StringVar(&vsExportTo, "virtual-service-export-to", "", "List of namespaces to which the VirtualServices are exported, e.g. . for the namespace of the APIRule, they are exported to all namespaces if empty. Optional.")
+	flag.BoolVar(&validateServiceExistence, "validate-service-existence", false, "Reject APIRules that reference services or ports that don't exist in the cluster.")
+	flag.StringVar(&duplicatePathsMode, "duplicate-paths-mode", string(processing.DuplicatePathsFilter), "Handling of rules that match the same requests as a previous rule, either filter to merge them silently or report to also add a warning to the APIRule status.")
+	flag.StringVar(&httpTimeout, "http-timeout", fmt.Sprintf("%ds", helpers.DEFAULT_HTTP_TIMEOUT), "Default timeout of the routes as a duration (e.g. 3m) or a number of seconds, 0 disables the timeout and no timeout is configured on the routes if empty.")
+	flag.DurationVar(&idleTimeout, "idle-timeout", 0, "Default idle timeout of the connections to the services, which is configured in a DestinationRule for each service. Optional.")
+	flag.BoolVar(&luaFilters, "enable-lua-filters", false, "Allow Lua scripts in the rules, which are configured in EnvoyFilters for the ingress gateway. Optional.")
+	flag.StringVar(&luaFilterNamespace, "lua-filter-namespace", processing.DefaultLuaFilterNamespace, "Namespace of the ingress gateway in which the EnvoyFilters of the Lua scripts are created.")
+	flag.BoolVar(&enableMatchedRuleHeader, "enable-matched-rule-header", false, "Set a response header with the name of the route of the matched rule to debug the routing. It exposes the names of the APIRules to the clients. Optional.")
+	flag.StringVar(&matchedRuleHeaderName, "matched-rule-header-name", processing.DefaultMatchedRuleHeader, "Name of the response header with the name of the route of the matched rule if enable-matched-rule-header is set.")
 	flag.StringVar(&corsAllowOrigins, "cors-allow-origins", "regex:.*", "list of allowed origins")
-	flag.StringVar(&corsAllowMethods, "cors-allow-methods", "GET,POST,PUT,DELETE", "list of allowed methods")
+	flag.StringVar(&corsAllowMethods, "cors-allow-methods", "GET,POST,PUT,DELETE", "list of allowed methods, the methods of the rules are allowed if empty")
 	flag.StringVar(&corsAllowHeaders, "cors-allow-headers", "JwtAuthorization,Content-Type,*", "list of allowed headers")
+	flag.StringVar(&corsExposeHeaders, "cors-expose-headers", "", "list of headers exposed to the browser. Optional.")
+	flag.BoolVar(&corsAllowCredentials, "cors-allow-credentials", false, "Allow credentials for CORS requests")
+	flag.DurationVar(&corsMaxAge, "cors-max-age", 0, "Duration for which the results of a preflight request can be cached. Optional.")
 	flag.StringVar(&generatedObjectsLabels, "generated-objects-labels", "", "Comma-separated list of key=value pairs used to label generated objects")
 	flag.UintVar(&reconciliationPeriod, "reconciliation-period", 0, "Default reconciliation period when no error happened in the previous run [s]")
 	flag.UintVar(&errorReconciliationPeriod, "error-reconciliation-period", 0, "Reconciliation period after an error happened in the previous run (e.g. VirtualService confict) [s]")
+	flag.UintVar(&retryAttempts, "retry-attempts", 0, "Default number of retries for failed requests, 0 disables retries")
+	flag.DurationVar(&retryPerTryTimeout, "retry-per-try-timeout", 0, "Default timeout per retry attempt. Optional.")
+	flag.StringVar(&retryOn, "retry-on", "", "Default comma-separated list of conditions under which a retry takes place. Optional.")
 
 	flag.Parse()
 
@@ -129,6 +179,36 @@ func main() {
 		}
 	}
 
+	if duplicatePathsMode != string(processing.DuplicatePathsFilter) && duplicatePathsMode != string(processing.DuplicatePathsReport) {
+		setupLog.Error(fmt.Errorf("duplicate-paths-mode must be filter or report"), "unable to create controller", "controller", "Api")
+		os.Exit(1)
+	}
+
+	if err := processing.ValidateVirtualServiceNameAffixes(vsNamePrefix, vsNameSuffix); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Api")
+		os.Exit(1)
+	}
+
+	defaultHTTPTimeout, err := processing.ParseHTTPTimeout(httpTimeout)
+	if err != nil {
+		setupLog.Error(fmt.Errorf("http-timeout is not a valid timeout: %w", err), "unable to create controller", "controller", "Api")
+		os.Exit(1)
+	}
+
+	if _, err := regexp.Compile(catchAllPathRegex); err != nil {
+		setupLog.Error(fmt.Errorf("catch-all-path-regex is not a valid regular expression: %w", err), "unable to create controller", "controller", "Api")
+		os.Exit(1)
+	}
+
+	var matchedRuleHeader string
+	if enableMatchedRuleHeader {
+		if !httpguts.ValidHeaderFieldName(matchedRuleHeaderName) {
+			setupLog.Error(fmt.Errorf("matched-rule-header-name is not a valid header name"), "unable to create controller", "controller", "Api")
+			os.Exit(1)
+		}
+		matchedRuleHeader = matchedRuleHeaderName
+	}
+
 	opts := zap.Options{
 		Development: true,
 	}
@@ -163,20 +243,57 @@ func main() {
 		os.Exit(1)
 	}
 
+	reconciliationMetrics, err := processing.NewReconciliationMetrics(metrics.Registry)
+	if err != nil {
+		setupLog.Error(err, "unable to register metrics")
+		os.Exit(1)
+	}
+
+	var retryConfig *processing.RetryConfig
+	if retryAttempts > 0 {
+		retryConfig = &processing.RetryConfig{
+			Attempts:      int(retryAttempts),
+			PerTryTimeout: retryPerTryTimeout,
+			RetryOn:       retryOn,
+		}
+	}
+
 	if err = (&controllers.APIRuleReconciler{
-		Client:            mgr.GetClient(),
-		Log:               ctrl.Log.WithName("controllers").WithName("Api"),
-		OathkeeperSvc:     oathkeeperSvcAddr,
-		OathkeeperSvcPort: uint32(oathkeeperSvcPort),
-		ServiceBlockList:  getNamespaceServiceMap(blockListedServices),
-		DomainAllowList:   getList(allowListedDomains),
-		HostBlockList:     getHostBlockListFrom(blockListedSubdomains, domainName),
-		DefaultDomainName: domainName,
+		Client:                    mgr.GetClient(),
+		Log:                       ctrl.Log.WithName("controllers").WithName("Api"),
+		OathkeeperSvc:             oathkeeperSvcAddr,
+		OathkeeperSvcPort:         uint32(oathkeeperSvcPort),
+		ServiceBlockList:          getNamespaceServiceMap(blockListedServices),
+		RestrictServiceNamespaces: restrictServiceNamespaces,
+		ServiceNamespaceAllowList: getList(allowListedServiceNamespaces),
+		DomainAllowList:           getList(allowListedDomains),
+		HostBlockList:             getHostBlockListFrom(blockListedSubdomains, domainName),
+		DefaultDomainName:         domainName,
+		StrictHostDomain:          strictHostDomain,
+		VSFixedName:               vsFixedName,
+		VSNamePrefix:              vsNamePrefix,
+		VSNameSuffix:              vsNameSuffix,
+		CatchAllPathRegex:         catchAllPathRegex,
+		AggregateVSByHost:         aggregateVSByHost,
+		VSExportTo:                getList(vsExportTo),
+		ValidateServices:          validateServiceExistence,
+		DuplicatePathsMode:        processing.DuplicatePathsMode(duplicatePathsMode),
+		HTTPTimeout:               defaultHTTPTimeout,
+		IdleTimeout:               idleTimeout,
+		LuaFilters:                luaFilters,
+		LuaFilterNamespace:        luaFilterNamespace,
+		MatchedRuleHeader:         matchedRuleHeader,
+		Metrics:                   reconciliationMetrics,
+		Recorder:                  mgr.GetEventRecorderFor("apirule-controller"),
 		CorsConfig: &processing.CorsConfig{
-			AllowHeaders: getList(corsAllowHeaders),
-			AllowMethods: getList(corsAllowMethods),
-			AllowOrigins: getStringMatch(corsAllowOrigins),
+			AllowHeaders:     getList(corsAllowHeaders),
+			AllowMethods:     getList(corsAllowMethods),
+			AllowOrigins:     getStringMatch(corsAllowOrigins),
+			ExposeHeaders:    getList(corsExposeHeaders),
+			AllowCredentials: corsAllowCredentials,
+			MaxAge:           corsMaxAge,
 		},
+		RetryConfig:            retryConfig,
 		GeneratedObjectsLabels: additionalLabels,
 		Scheme:                 mgr.GetScheme(),
 		Config:                 &helpers.Config{},
@@ -7,40 +7,179 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"sort"
+	"strings"
+	"time"
 
 	"golang.org/x/net/publicsuffix"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 )
 
+const defaultTokenType = "Bearer"
+
+// GetAccessToken returns an access token using the client credentials grant. The optional token type is requested
+// with the token_format endpoint parameter.
 func GetAccessToken(oauth2Cfg clientcredentials.Config, config *Config, tokenType ...string) (string, error) {
-	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	params := make(url.Values)
+	if len(tokenType) > 0 {
+		params.Add("token_format", tokenType[0])
+	}
+
+	return GetAccessTokenWithParams(oauth2Cfg, config, params)
+}
+
+// Token is the token issued by the provider.
+type Token struct {
+	AccessToken  string
+	TokenType    string
+	RefreshToken string
+	// Expiry is zero if the provider didn't return the expiry of the token
+	Expiry time.Time
+}
+
+// GetAccessTokenWithParams returns an access token using the client credentials grant, sending the given parameters
+// in addition to the endpoint parameters of the oauth2 configuration.
+func GetAccessTokenWithParams(oauth2Cfg clientcredentials.Config, config *Config, params url.Values) (string, error) {
+	token, err := GetToken(oauth2Cfg, config, params)
 	if err != nil {
 		return "", err
 	}
-	httpClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
-		Timeout: config.ClientConfig.ClientTimeout,
-		Jar:     jar,
+	return token.AccessToken, nil
+}
+
+// GetToken returns the token issued using the client credentials grant, including the expiry and the refresh token.
+// The given parameters are sent in addition to the endpoint parameters of the oauth2 configuration.
+func GetToken(oauth2Cfg clientcredentials.Config, config *Config, params url.Values) (*Token, error) {
+	httpClient, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(tokenType) > 0 {
-		oauth2Cfg.EndpointParams = make(url.Values)
-		oauth2Cfg.EndpointParams.Add("token_format", tokenType[0])
+	return getToken(httpClient, oauth2Cfg, config, params)
+}
+
+// AudienceConfig is the client credentials configuration of the token for an audience. The audience is sent with the
+// audience endpoint parameter in addition to the given parameters.
+type AudienceConfig struct {
+	Audience     string
+	OAuth2Config clientcredentials.Config
+	Params       url.Values
+}
+
+// AudienceTokenError is returned if no access token could be issued for some of the audiences. Errors contains the
+// error of each failed audience.
+type AudienceTokenError struct {
+	Errors map[string]error
+}
+
+func (e *AudienceTokenError) Error() string {
+	audiences := make([]string, 0, len(e.Errors))
+	for audience := range e.Errors {
+		audiences = append(audiences, audience)
 	}
+	sort.Strings(audiences)
+
+	messages := make([]string, 0, len(audiences))
+	for _, audience := range audiences {
+		messages = append(messages, fmt.Sprintf("audience %q: %s", audience, e.Errors[audience]))
+	}
+	return fmt.Sprintf("failed to get access tokens for %d of the audiences: %s", len(audiences), strings.Join(messages, "; "))
+}
+
+// GetAccessTokens returns the access tokens by audience using the client credentials grant, all tokens are requested
+// with the same HTTP client. If some of the tokens can't be issued, the tokens of the other audiences are returned with
+// an *AudienceTokenError.
+func GetAccessTokens(audienceConfigs []AudienceConfig, config *Config) (map[string]string, error) {
+	httpClient, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	audiences := make(map[string]bool, len(audienceConfigs))
+	for _, audienceConfig := range audienceConfigs {
+		if audienceConfig.Audience == "" {
+			return nil, fmt.Errorf("audience can't be empty")
+		}
+		if audiences[audienceConfig.Audience] {
+			return nil, fmt.Errorf("audience %q is configured more than once", audienceConfig.Audience)
+		}
+		audiences[audienceConfig.Audience] = true
+	}
+
+	tokens := make(map[string]string, len(audienceConfigs))
+	failures := make(map[string]error)
+	for _, audienceConfig := range audienceConfigs {
+
+		params := make(url.Values)
+		for k, v := range audienceConfig.Params {
+			params[k] = v
+		}
+		params.Set("audience", audienceConfig.Audience)
+
+		token, err := getToken(httpClient, audienceConfig.OAuth2Config, config, params)
+		if err != nil {
+			failures[audienceConfig.Audience] = err
+			continue
+		}
+		tokens[audienceConfig.Audience] = token.AccessToken
+	}
+
+	if len(failures) > 0 {
+		return tokens, &AudienceTokenError{Errors: failures}
+	}
+	return tokens, nil
+}
+
+func getToken(httpClient *http.Client, oauth2Cfg clientcredentials.Config, config *Config, params url.Values) (*Token, error) {
+	endpointParams := make(url.Values)
+	for k, v := range oauth2Cfg.EndpointParams {
+		endpointParams[k] = append(endpointParams[k], v...)
+	}
+	for k, v := range params {
+		endpointParams[k] = v
+	}
+	oauth2Cfg.EndpointParams = endpointParams
 
 	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
 	token, err := oauth2Cfg.Token(ctx)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if !token.Valid() {
-		return "", fmt.Errorf("token invalid. got: %#v", token)
+		return nil, fmt.Errorf("token invalid. got: %#v", token)
 	}
-	if token.TokenType != "Bearer" {
-		return "", fmt.Errorf("token type = %q; want %q", token.TokenType, "Bearer")
+
+	expectedTokenType := config.ExpectedTokenType
+	if expectedTokenType == "" {
+		expectedTokenType = defaultTokenType
 	}
-	return token.AccessToken, nil
+	if token.TokenType != expectedTokenType {
+		return nil, fmt.Errorf("token type = %q; want %q", token.TokenType, expectedTokenType)
+	}
+
+	return &Token{
+		AccessToken:  token.AccessToken,
+		TokenType:    token.TokenType,
+		RefreshToken: token.RefreshToken,
+		Expiry:       token.Expiry,
+	}, nil
+}
+
+func newHTTPClient(config *Config) (*http.Client, error) {
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:            config.ClientConfig.RootCAs,
+				InsecureSkipVerify: config.ClientConfig.InsecureSkipVerify,
+			},
+		},
+		Timeout: config.ClientConfig.ClientTimeout,
+		Jar:     jar,
+	}, nil
 }
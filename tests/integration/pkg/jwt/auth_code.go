@@ -0,0 +1,117 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// AuthCodeTokens are the tokens issued by the authorization code flow.
+type AuthCodeTokens struct {
+	AccessToken string
+	// IDToken is only set if the provider issued an id token, e.g. for the openid scope
+	IDToken string
+}
+
+// GetAccessTokenAuthCode returns the tokens issued using the authorization code flow with PKCE. The authorization
+// server must issue the code without user interaction, e.g. by an existing session. The optional parameters are added
+// to the authorization request.
+func GetAccessTokenAuthCode(oauth2Cfg oauth2.Config, config *Config, params url.Values) (*AuthCodeTokens, error) {
+	httpClient, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// The redirect to the redirect URL carries the code, so it's not followed as there is no client listening on it.
+	httpClient.CheckRedirect = func(req *http.Request, _ []*http.Request) error {
+		if strings.HasPrefix(req.URL.String(), oauth2Cfg.RedirectURL) {
+			return http.ErrUseLastResponse
+		}
+		return nil
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	authOptions := []oauth2.AuthCodeOption{
+		oauth2.SetAuthURLParam("code_challenge", codeChallenge(verifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	}
+	for k := range params {
+		authOptions = append(authOptions, oauth2.SetAuthURLParam(k, params.Get(k)))
+	}
+
+	res, err := httpClient.Get(oauth2Cfg.AuthCodeURL(state, authOptions...))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	code, err := codeFromRedirect(res, state)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+	token, err := oauth2Cfg.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid() {
+		return nil, fmt.Errorf("token invalid. got: %#v", token)
+	}
+
+	tokens := &AuthCodeTokens{AccessToken: token.AccessToken}
+	if idToken, ok := token.Extra("id_token").(string); ok {
+		tokens.IDToken = idToken
+	}
+	return tokens, nil
+}
+
+func codeFromRedirect(res *http.Response, state string) (string, error) {
+	location, err := res.Location()
+	if err != nil {
+		return "", fmt.Errorf("authorization request wasn't redirected, got status %d", res.StatusCode)
+	}
+
+	query := location.Query()
+	if authErr := query.Get("error"); authErr != "" {
+		return "", fmt.Errorf("authorization failed: %s", authErr)
+	}
+	if query.Get("state") != state {
+		return "", errors.New("state of the authorization response doesn't match the request")
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		return "", errors.New("authorization response doesn't contain a code")
+	}
+	return code, nil
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,120 @@
+package jwt_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/kyma-project/api-gateway/tests/integration/pkg/jwt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/oauth2"
+)
+
+// newAuthorizationServer returns an authorization server that issues a code for every authorization request without
+// user interaction. With the authError the authorization request is redirected with the error instead.
+func newAuthorizationServer(authError string, issueIDToken bool) *httptest.Server {
+	var codeChallenge string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		redirect, _ := url.Parse(query.Get("redirect_uri"))
+		params := url.Values{"state": {query.Get("state")}}
+		if authError != "" {
+			params.Set("error", authError)
+		} else if query.Get("code_challenge_method") != "S256" {
+			params.Set("error", "invalid_request")
+		} else {
+			codeChallenge = query.Get("code_challenge")
+			params.Set("code", "test-code")
+		}
+		redirect.RawQuery = params.Encode()
+		http.Redirect(w, r, redirect.String(), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if r.Form.Get("code") != "test-code" || base64.RawURLEncoding.EncodeToString(sum[:]) != codeChallenge {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		response := map[string]interface{}{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		}
+		if issueIDToken {
+			response["id_token"] = "id-token"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+
+	return httptest.NewTLSServer(mux)
+}
+
+var _ = Describe("GetAccessTokenAuthCode", func() {
+	getOauth2Config := func(server *httptest.Server) oauth2.Config {
+		return oauth2.Config{
+			ClientID:    "client",
+			RedirectURL: server.URL + "/callback",
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  server.URL + "/authorize",
+				TokenURL: server.URL + "/token",
+			},
+			Scopes: []string{"openid"},
+		}
+	}
+
+	It("should return the access and id token", func() {
+		// given
+		server := newAuthorizationServer("", true)
+		defer server.Close()
+
+		// when
+		tokens, err := jwt.GetAccessTokenAuthCode(getOauth2Config(server), getConfig(server), nil)
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(tokens.AccessToken).To(Equal("access-token"))
+		Expect(tokens.IDToken).To(Equal("id-token"))
+	})
+
+	It("should return the access token without id token", func() {
+		// given
+		server := newAuthorizationServer("", false)
+		defer server.Close()
+
+		// when
+		tokens, err := jwt.GetAccessTokenAuthCode(getOauth2Config(server), getConfig(server), nil)
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(tokens.AccessToken).To(Equal("access-token"))
+		Expect(tokens.IDToken).To(BeEmpty())
+	})
+
+	It("should return an error if the authorization fails", func() {
+		// given
+		server := newAuthorizationServer("access_denied", true)
+		defer server.Close()
+
+		// when
+		_, err := jwt.GetAccessTokenAuthCode(getOauth2Config(server), getConfig(server), nil)
+
+		// then
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(Equal("authorization failed: access_denied"))
+	})
+})
@@ -0,0 +1,231 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/kyma-project/api-gateway/tests/integration/pkg/jwt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/oauth2/clientcredentials"
+	"k8s.io/utils/strings/slices"
+)
+
+// newTokenServer returns a token server that issues tokens of the requested token_format and returns the received
+// form parameters as the access token.
+func newTokenServer() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		tokenType := "Bearer"
+		if r.Form.Get("token_format") == "opaque" {
+			tokenType = "Opaque"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  r.Form.Encode(),
+			"token_type":    tokenType,
+			"expires_in":    3600,
+			"refresh_token": "refresh-token",
+		})
+	}))
+}
+
+var _ = Describe("GetAccessToken", func() {
+	var server *httptest.Server
+	var oauth2Cfg clientcredentials.Config
+
+	BeforeEach(func() {
+		server = newTokenServer()
+		oauth2Cfg = clientcredentials.Config{
+			ClientID:     "client",
+			ClientSecret: "secret",
+			TokenURL:     server.URL + "/token",
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should return a JWT token", func() {
+		// given
+		config := getConfig(server)
+
+		// when
+		token, err := jwt.GetAccessToken(oauth2Cfg, config, "jwt")
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		params, err := url.ParseQuery(token)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(params.Get("token_format")).To(Equal("jwt"))
+		Expect(params.Get("grant_type")).To(Equal("client_credentials"))
+	})
+
+	It("should return an opaque token if the expected token type is configured", func() {
+		// given
+		config := getConfig(server)
+		config.ExpectedTokenType = "Opaque"
+
+		// when
+		token, err := jwt.GetAccessToken(oauth2Cfg, config, "opaque")
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		params, err := url.ParseQuery(token)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(params.Get("token_format")).To(Equal("opaque"))
+	})
+
+	It("should return an error if the token type isn't the expected one", func() {
+		// given
+		config := getConfig(server)
+
+		// when
+		_, err := jwt.GetAccessToken(oauth2Cfg, config, "opaque")
+
+		// then
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(Equal(`token type = "Opaque"; want "Bearer"`))
+	})
+
+	It("should send additional endpoint parameters", func() {
+		// given
+		config := getConfig(server)
+		oauth2Cfg.EndpointParams = url.Values{"audience": {"example"}}
+
+		// when
+		token, err := jwt.GetAccessTokenWithParams(oauth2Cfg, config, url.Values{"token_format": {"jwt"}, "resource": {"https://example.com"}})
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		params, err := url.ParseQuery(token)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(params.Get("audience")).To(Equal("example"))
+		Expect(params.Get("resource")).To(Equal("https://example.com"))
+		Expect(params.Get("token_format")).To(Equal("jwt"))
+	})
+})
+
+var _ = Describe("GetToken", func() {
+	It("should return the expiry, refresh token and token type", func() {
+		// given
+		server := newTokenServer()
+		defer server.Close()
+		oauth2Cfg := clientcredentials.Config{
+			ClientID:     "client",
+			ClientSecret: "secret",
+			TokenURL:     server.URL + "/token",
+		}
+
+		// when
+		token, err := jwt.GetToken(oauth2Cfg, getConfig(server), url.Values{"token_format": {"jwt"}})
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		params, err := url.ParseQuery(token.AccessToken)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(params.Get("token_format")).To(Equal("jwt"))
+		Expect(token.TokenType).To(Equal("Bearer"))
+		Expect(token.RefreshToken).To(Equal("refresh-token"))
+		Expect(token.Expiry).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+	})
+})
+
+// newAudienceTokenServer returns a token server that issues the token <audience>-token for the given audiences and
+// rejects the requests for other audiences.
+func newAudienceTokenServer(audiences ...string) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		audience := r.Form.Get("audience")
+		w.Header().Set("Content-Type", "application/json")
+		if !slices.Contains(audiences, audience) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"error": "invalid_target"})
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": audience + "-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+}
+
+var _ = Describe("GetAccessTokens", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		server = newAudienceTokenServer("orders", "payments")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	audienceConfig := func(audience string) jwt.AudienceConfig {
+		return jwt.AudienceConfig{
+			Audience: audience,
+			OAuth2Config: clientcredentials.Config{
+				ClientID:     "client",
+				ClientSecret: "secret",
+				TokenURL:     server.URL + "/token",
+			},
+		}
+	}
+
+	It("should return the tokens by audience", func() {
+		// when
+		tokens, err := jwt.GetAccessTokens([]jwt.AudienceConfig{audienceConfig("orders"), audienceConfig("payments")}, getConfig(server))
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(tokens).To(Equal(map[string]string{"orders": "orders-token", "payments": "payments-token"}))
+	})
+
+	It("should return the issued tokens and the errors of the failed audiences", func() {
+		// when
+		tokens, err := jwt.GetAccessTokens([]jwt.AudienceConfig{audienceConfig("orders"), audienceConfig("unknown"), audienceConfig("payments")}, getConfig(server))
+
+		// then
+		Expect(err).Should(HaveOccurred())
+		var audienceErr *jwt.AudienceTokenError
+		Expect(errors.As(err, &audienceErr)).To(BeTrue())
+		Expect(audienceErr.Errors).To(HaveLen(1))
+		Expect(audienceErr.Errors).To(HaveKey("unknown"))
+		Expect(err.Error()).To(HavePrefix(`failed to get access tokens for 1 of the audiences: audience "unknown": `))
+		Expect(tokens).To(Equal(map[string]string{"orders": "orders-token", "payments": "payments-token"}))
+	})
+
+	It("should return an error if an audience is configured more than once", func() {
+		// when
+		_, err := jwt.GetAccessTokens([]jwt.AudienceConfig{audienceConfig("orders"), audienceConfig("orders")}, getConfig(server))
+
+		// then
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(Equal(`audience "orders" is configured more than once`))
+	})
+
+	It("should return an error if the audience is empty", func() {
+		// when
+		_, err := jwt.GetAccessTokens([]jwt.AudienceConfig{audienceConfig("")}, getConfig(server))
+
+		// then
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(Equal("audience can't be empty"))
+	})
+})
@@ -1,6 +1,9 @@
 package jwt
 
 import (
+	"crypto/x509"
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/pkg/errors"
@@ -11,14 +14,22 @@ import (
 type Config struct {
 	EnvConfig    envConfig
 	ClientConfig clientConfig
+	// ExpectedTokenType is the type the token returned by the provider must have, defaults to Bearer
+	ExpectedTokenType string
 }
 
 type clientConfig struct {
 	ClientTimeout time.Duration
+	// RootCAs are used to verify the certificate of the provider, the system CAs are used if not set
+	RootCAs *x509.CertPool
+	// InsecureSkipVerify disables the verification of the certificate of the provider
+	InsecureSkipVerify bool
 }
 
 type envConfig struct {
-	ClientTimeout time.Duration `envconfig:"TEST_CLIENT_TIMEOUT,default=10s"` //Don't forget the unit!
+	ClientTimeout      time.Duration `envconfig:"TEST_CLIENT_TIMEOUT,default=10s"` //Don't forget the unit!
+	CAFile             string        `envconfig:"TEST_CA_FILE,optional"`
+	InsecureSkipVerify bool          `envconfig:"TEST_INSECURE_SKIP_VERIFY,default=false"`
 }
 
 func NewJwtConfig() (Config, error) {
@@ -29,7 +40,30 @@ func NewJwtConfig() (Config, error) {
 	}
 
 	config := Config{EnvConfig: env}
-	config.ClientConfig = clientConfig(env)
+	config.ClientConfig = clientConfig{
+		ClientTimeout:      env.ClientTimeout,
+		InsecureSkipVerify: env.InsecureSkipVerify,
+	}
+
+	if env.CAFile != "" {
+		config.ClientConfig.RootCAs, err = loadCertPool(env.CAFile)
+		if err != nil {
+			return Config{}, errors.Wrap(err, "while loading the CA file")
+		}
+	}
 
 	return config, nil
 }
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", caFile)
+	}
+	return pool, nil
+}
@@ -0,0 +1,89 @@
+package jwt_test
+
+import (
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kyma-project/api-gateway/tests/integration/pkg/jwt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+var _ = Describe("TLS verification", func() {
+	var server *httptest.Server
+	var oauth2Cfg clientcredentials.Config
+
+	BeforeEach(func() {
+		server = newTokenServer()
+		oauth2Cfg = clientcredentials.Config{
+			ClientID:     "client",
+			ClientSecret: "secret",
+			TokenURL:     server.URL + "/token",
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should return a token if the certificate is signed by the CA from the CA file", func() {
+		// given
+		caFile := filepath.Join(GinkgoT().TempDir(), "ca.crt")
+		Expect(os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600)).To(Succeed())
+		GinkgoT().Setenv("TEST_CA_FILE", caFile)
+
+		config, err := jwt.NewJwtConfig()
+		Expect(err).ShouldNot(HaveOccurred())
+
+		// when
+		_, err = jwt.GetAccessToken(oauth2Cfg, &config, "jwt")
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("should return an error if the certificate isn't signed by a trusted CA", func() {
+		// given
+		config := &jwt.Config{}
+		config.ClientConfig.ClientTimeout = 5 * time.Second
+
+		// when
+		_, err := jwt.GetAccessToken(oauth2Cfg, config, "jwt")
+
+		// then
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("certificate"))
+	})
+
+	It("should return a token without verification if insecure skip verify is enabled", func() {
+		// given
+		GinkgoT().Setenv("TEST_INSECURE_SKIP_VERIFY", "true")
+
+		config, err := jwt.NewJwtConfig()
+		Expect(err).ShouldNot(HaveOccurred())
+
+		// when
+		_, err = jwt.GetAccessToken(oauth2Cfg, &config, "jwt")
+
+		// then
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("should return an error if the CA file doesn't contain a certificate", func() {
+		// given
+		caFile := filepath.Join(GinkgoT().TempDir(), "ca.crt")
+		Expect(os.WriteFile(caFile, []byte("no certificate"), 0600)).To(Succeed())
+		GinkgoT().Setenv("TEST_CA_FILE", caFile)
+
+		// when
+		_, err := jwt.NewJwtConfig()
+
+		// then
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("no certificates found"))
+	})
+})
@@ -0,0 +1,29 @@
+package jwt_test
+
+import (
+	"crypto/x509"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/api-gateway/tests/integration/pkg/jwt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestJwt(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "JWT Suite")
+}
+
+// getConfig returns the configuration trusting the certificate of the given test server.
+func getConfig(server *httptest.Server) *jwt.Config {
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	config := &jwt.Config{}
+	config.ClientConfig.ClientTimeout = 5 * time.Second
+	config.ClientConfig.RootCAs = pool
+	return config
+}